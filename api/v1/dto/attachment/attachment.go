@@ -0,0 +1,15 @@
+// Package attachment 提供附件相关的数据传输对象
+package attachment
+
+import "todo/internal/models"
+
+// ListResponse 附件列表响应
+type ListResponse struct {
+	Total int64                `json:"total"` // 总数
+	Items []*models.Attachment `json:"items"` // 附件列表
+}
+
+// DeleteResponse 删除附件响应
+type DeleteResponse struct {
+	Message string `json:"message"` // 响应消息
+}
@@ -20,6 +20,7 @@ type UserInfo struct {
 	ID        uint      `json:"id"`                    // 用户ID
 	Username  string    `json:"username"`              // 用户名
 	Email     string    `json:"email"`                 // 邮箱
+	WorkspaceID uint    `json:"workspaceId"`           // 当前工作空间ID
 	CreatedAt string    `json:"createdAt"`            // 创建时间
 	UpdatedAt string    `json:"updatedAt"`            // 更新时间
 }
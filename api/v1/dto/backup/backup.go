@@ -0,0 +1,84 @@
+// Package backup 定义账户数据导出与导入的归档格式和响应
+package backup
+
+import "time"
+
+// 归档格式标识和版本；格式不兼容地改变时递增版本，导入时拒绝高于当前版本的归档
+const (
+	ArchiveFormat  = "todo-archive"
+	ArchiveVersion = 1
+)
+
+// Archive 账户数据归档
+// 归档中的ID只在归档内部有效，用于表示分类、待办事项、提醒和评论之间的关联，导入时会重新分配
+type Archive struct {
+	Format     string     `json:"format"`     // 格式标识，固定为 todo-archive
+	Version    int        `json:"version"`    // 格式版本
+	ExportedAt time.Time  `json:"exportedAt"` // 导出时间
+	User       User       `json:"user"`       // 用户资料
+	Categories []Category `json:"categories"` // 分类，上级分类可能排在下级分类之后
+	Todos      []Todo     `json:"todos"`      // 待办事项，包括已归档的，不包括回收站中的
+	Reminders  []Reminder `json:"reminders"`  // 提醒
+	Comments   []Comment  `json:"comments"`   // 评论，按发表时间排列
+}
+
+// User 归档中的用户资料，导入时只作参考，不会修改导入账户的资料
+type User struct {
+	Username  string    `json:"username"`  // 用户名
+	Email     string    `json:"email"`     // 邮箱
+	CreatedAt time.Time `json:"createdAt"` // 注册时间
+}
+
+// Category 归档中的分类
+type Category struct {
+	ID        uint      `json:"id"`        // 归档内的分类ID
+	ParentID  *uint     `json:"parentId"`  // 上级分类在归档内的ID，为空表示顶级分类
+	Name      string    `json:"name"`      // 分类名称
+	Color     string    `json:"color"`     // 分类颜色
+	CreatedAt time.Time `json:"createdAt"` // 创建时间
+}
+
+// Todo 归档中的待办事项
+type Todo struct {
+	ID          uint       `json:"id"`          // 归档内的待办事项ID
+	CategoryID  *uint      `json:"categoryId"`  // 所属分类在归档内的ID，为空表示未分类
+	Title       string     `json:"title"`       // 标题
+	Description string     `json:"description"` // 描述
+	Priority    string     `json:"priority"`    // 优先级：low、medium、high
+	Completed   bool       `json:"completed"`   // 是否已完成
+	CompletedAt *time.Time `json:"completedAt"` // 完成时间
+	Archived    bool       `json:"archived"`    // 是否已归档
+	ArchivedAt  *time.Time `json:"archivedAt"`  // 归档时间
+	DueDate     *time.Time `json:"dueDate"`     // 截止时间
+	Estimate    *int       `json:"estimate"`    // 预估耗时（分钟）
+	Position    string     `json:"position"`    // 在分类内的手动排序位置
+	Tags        []string   `json:"tags"`        // 标签名
+	CreatedAt   time.Time  `json:"createdAt"`   // 创建时间
+}
+
+// Reminder 归档中的提醒
+type Reminder struct {
+	TodoID     uint      `json:"todoId"`     // 所属待办事项在归档内的ID
+	RemindAt   time.Time `json:"remindAt"`   // 提醒时间
+	RemindType string    `json:"remindType"` // 提醒类型：once、daily、weekly
+	NotifyType string    `json:"notifyType"` // 通知类型：email、push
+	Status     bool      `json:"status"`     // 是否已提醒
+}
+
+// Comment 归档中的评论
+// 待办事项上其他成员发表的评论也会导出，导入后作者均为导入账户，原作者保留在 Author 中
+type Comment struct {
+	TodoID    uint       `json:"todoId"`    // 所属待办事项在归档内的ID
+	Author    string     `json:"author"`    // 作者用户名
+	Body      string     `json:"body"`      // Markdown 正文
+	CreatedAt time.Time  `json:"createdAt"` // 发表时间
+	EditedAt  *time.Time `json:"editedAt"`  // 最后编辑时间
+}
+
+// ImportResponse 导入归档的结果
+type ImportResponse struct {
+	Categories int `json:"categories"` // 创建的分类数量
+	Todos      int `json:"todos"`      // 创建的待办事项数量
+	Reminders  int `json:"reminders"`  // 创建的提醒数量
+	Comments   int `json:"comments"`   // 创建的评论数量
+}
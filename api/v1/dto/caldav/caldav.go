@@ -0,0 +1,24 @@
+package caldav
+
+// Collection CalDAV 日历集合，对应一个分类或所有未分类的待办事项
+type Collection struct {
+	Name        string // 集合在地址中的名称：分类ID，未分类为 uncategorized
+	DisplayName string // 显示名称
+	CTag        string // 集合标签，集合中任何对象变化时改变
+}
+
+// Object CalDAV 日历对象，对应一个待办事项
+type Object struct {
+	Name string // 资源名，例如 todo-1.ics；通过 CalDAV 创建的待办事项使用客户端指定的名称
+	ETag string // 实体标签（含双引号），内容变化时改变
+	Data []byte // 只包含一个 VTODO 的 iCalendar 数据
+}
+
+// PutRequest 创建或更新日历对象请求
+type PutRequest struct {
+	Collection  string // 集合名称
+	Name        string // 资源名
+	IfMatch     string // If-Match 请求头，非空时只有当前 ETag 匹配才写入
+	IfNoneMatch string // If-None-Match 请求头，为 * 时只允许创建新对象
+	Data        []byte // iCalendar 数据
+}
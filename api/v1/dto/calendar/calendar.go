@@ -0,0 +1,16 @@
+package calendar
+
+import "time"
+
+// FeedResponse 日历订阅响应
+type FeedResponse struct {
+	URL       string    `json:"url"`       // 订阅地址，在日历客户端中添加该地址即可订阅
+	Token     string    `json:"token"`     // 订阅令牌，持有令牌即可读取日历，应像密码一样保管
+	CreatedAt time.Time `json:"createdAt"` // 订阅创建时间
+	UpdatedAt time.Time `json:"updatedAt"` // 令牌最后生成时间
+}
+
+// RevokeResponse 撤销日历订阅响应
+type RevokeResponse struct {
+	Message string `json:"message"` // 响应消息
+}
@@ -4,6 +4,8 @@ package category
 type CreateRequest struct {
 	Name  string `json:"name" binding:"required,max=32"`
 	Color string `json:"color" binding:"omitempty,max=7"`
+	// ParentID 上级分类ID，为空表示顶级分类
+	ParentID *uint `json:"parentId"`
 }
 
 // CreateResponse 创建分类响应
@@ -0,0 +1,49 @@
+package category
+
+import (
+	"strconv"
+	"strings"
+	"todo/pkg/errors"
+)
+
+// 删除分类时其下待办事项的处理策略
+const (
+	StrategyUncategorize = "uncategorize" // 取消待办事项的分类（默认）
+	StrategyMoveTo       = "move_to"      // 移动到另一个分类，写作 move_to=<分类ID>
+	StrategyDeleteTodos  = "delete_todos" // 将待办事项移入回收站
+)
+
+// DeleteRequest 删除分类请求
+type DeleteRequest struct {
+	// Strategy 其下待办事项的处理策略：uncategorize（默认）、move_to=<分类ID>、delete_todos
+	Strategy string `form:"strategy"`
+}
+
+// Parse 解析处理策略
+//
+// Returns:
+//   - string: 策略名称
+//   - uint: move_to 策略的目标分类ID，其他策略为 0
+//   - error: 策略无效时返回 ErrInvalidParameter
+func (r *DeleteRequest) Parse() (string, uint, error) {
+	switch {
+	case r.Strategy == "" || r.Strategy == StrategyUncategorize:
+		return StrategyUncategorize, 0, nil
+	case r.Strategy == StrategyDeleteTodos:
+		return StrategyDeleteTodos, 0, nil
+	case strings.HasPrefix(r.Strategy, StrategyMoveTo+"="):
+		id, err := strconv.ParseUint(strings.TrimPrefix(r.Strategy, StrategyMoveTo+"="), 10, 32)
+		if err != nil || id == 0 {
+			return "", 0, errors.ErrInvalidParameter
+		}
+		return StrategyMoveTo, uint(id), nil
+	default:
+		return "", 0, errors.ErrInvalidParameter
+	}
+}
+
+// DeleteResponse 删除分类响应
+type DeleteResponse struct {
+	Message  string `json:"message"`  // 响应消息
+	Affected int    `json:"affected"` // 受影响的待办事项数量
+}
@@ -2,6 +2,18 @@ package category
 
 import "todo/internal/models"
 
+// ListRequest 分类列表查询参数
+type ListRequest struct {
+	// Tree 为 true 时以树形结构返回，Items 只包含顶级分类，下级分类在 children 中
+	Tree bool `form:"tree"`
+}
+
+// MoveRequest 移动分类请求，整棵子树随之移动
+type MoveRequest struct {
+	// ParentID 新的上级分类ID，为 null 时移动到顶级
+	ParentID *uint `json:"parentId"`
+}
+
 // ListResponse 分类列表响应
 type ListResponse struct {
 	Total int64              `json:"total"`     // 总数
@@ -0,0 +1,37 @@
+// Package comment 提供评论相关的数据传输对象
+package comment
+
+import "todo/internal/models"
+
+// CreateRequest 创建评论请求
+type CreateRequest struct {
+	// Body Markdown 格式的评论正文，可使用 @用户名 提及工作空间成员
+	// Required: true
+	// Max Length: 10000
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+// UpdateRequest 更新评论请求
+type UpdateRequest struct {
+	// Body 新的评论正文
+	// Required: true
+	// Max Length: 10000
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+// ListResponse 评论列表响应
+type ListResponse struct {
+	Total int64             `json:"total"` // 总数
+	Items []*models.Comment `json:"items"` // 评论列表，按创建时间倒序
+}
+
+// HistoryResponse 评论编辑历史响应
+type HistoryResponse struct {
+	Total int64                     `json:"total"` // 总数
+	Items []*models.CommentRevision `json:"items"` // 编辑历史，按时间正序
+}
+
+// DeleteResponse 删除评论响应
+type DeleteResponse struct {
+	Message string `json:"message"` // 响应消息
+}
@@ -0,0 +1,23 @@
+// Package dependency 提供待办事项依赖关系相关的数据传输对象
+package dependency
+
+import "todo/internal/models"
+
+// AddRequest 添加阻塞项请求
+type AddRequest struct {
+	// BlockerID 阻塞当前待办事项的待办事项ID，它完成之前当前待办事项不能完成
+	// Required: true
+	BlockerID uint `json:"blockerId" binding:"required"`
+}
+
+// ListResponse 待办事项的依赖关系
+type ListResponse struct {
+	Blocked   bool           `json:"blocked"`   // 是否仍有未完成的阻塞项
+	BlockedBy []*models.Todo `json:"blockedBy"` // 阻塞当前待办事项的所有待办事项
+	Blocking  []*models.Todo `json:"blocking"`  // 被当前待办事项阻塞的所有待办事项
+}
+
+// DeleteResponse 移除阻塞项响应
+type DeleteResponse struct {
+	Message string `json:"message"` // 响应消息
+}
@@ -0,0 +1,36 @@
+// Package filter 提供保存的过滤条件（智能列表）相关的数据传输对象
+package filter
+
+import "todo/internal/models"
+
+// CreateRequest 保存过滤条件请求
+type CreateRequest struct {
+	// Name 名称，例如"本周到期的高优先级工作"
+	// Required: true
+	Name string `json:"name" binding:"required,max=64"`
+
+	// Definition 过滤条件，字段与待办事项列表的查询参数一致
+	Definition models.FilterDefinition `json:"definition"`
+}
+
+// UpdateRequest 更新过滤条件请求，只更新提供的字段
+type UpdateRequest struct {
+	Name       *string                  `json:"name" binding:"omitempty,max=64"` // 名称
+	Definition *models.FilterDefinition `json:"definition"`                      // 过滤条件，整体替换
+}
+
+// Item 过滤条件及当前匹配的待办事项数量
+type Item struct {
+	*models.SavedFilter
+	Count int64 `json:"count"` // 当前匹配的待办事项数量
+}
+
+// ListResponse 过滤条件列表响应
+type ListResponse struct {
+	Items []*Item `json:"items"`
+}
+
+// DeleteResponse 删除过滤条件响应
+type DeleteResponse struct {
+	Message string `json:"message"` // 响应消息
+}
@@ -0,0 +1,10 @@
+// Package history 提供变更历史相关的数据传输对象
+package history
+
+import "todo/internal/models"
+
+// ListResponse 变更历史列表响应
+type ListResponse struct {
+	Total int64               `json:"total"` // 总数
+	Items []*models.ChangeLog `json:"items"` // 变更记录，按时间倒序
+}
@@ -0,0 +1,54 @@
+// Package search 提供全文搜索相关的数据传输对象
+package search
+
+import "todo/internal/models"
+
+// Request 全文搜索请求，过滤参数与待办事项列表一致
+type Request struct {
+	// Q 搜索文本，匹配标题、描述和评论
+	// Required: true
+	Q string `form:"q" binding:"required,max=128"`
+
+	// Archived 归档状态过滤：false（默认，排除已归档）、true（只看已归档）、all（全部）
+	Archived string `form:"archived" binding:"omitempty,oneof=true false all"`
+
+	// Completed 完成状态过滤，为空时不过滤
+	Completed *bool `form:"completed"`
+
+	// Query 查询语言表达的附加过滤条件，语法与待办事项列表的 query 参数一致
+	Query string `form:"query" binding:"omitempty,max=256"`
+
+	// Priority 优先级过滤
+	Priority string `form:"priority" binding:"omitempty,oneof=low medium high"`
+
+	// Due 截止时间过滤：overdue、today、this_week、none
+	Due string `form:"due" binding:"omitempty,oneof=overdue today this_week none"`
+
+	// CategoryID 所属分类ID
+	CategoryID *uint `form:"category_id"`
+
+	// IncludeDescendants 为 true 时同时包含 CategoryID 所有下级分类中的待办事项
+	IncludeDescendants bool `form:"include_descendants"`
+
+	// Page 页码，默认 1
+	Page int `form:"page" binding:"omitempty,min=1"`
+
+	// PageSize 每页数量，默认 20，最大 100
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// Item 一条搜索结果
+type Item struct {
+	Todo  *models.Todo `json:"todo"`  // 命中的待办事项
+	Score float64      `json:"score"` // 相关度，越大越相关
+
+	// Highlights 各字段命中位置附近的摘要，键为 title、description、comment
+	// 摘要已做 HTML 转义，命中的词用 <mark> 标签包裹
+	Highlights map[string]string `json:"highlights"`
+}
+
+// Response 全文搜索响应
+type Response struct {
+	Total int64   `json:"total"` // 命中总数
+	Items []*Item `json:"items"` // 当前页结果，按相关度降序
+}
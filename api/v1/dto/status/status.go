@@ -0,0 +1,71 @@
+// Package status 提供看板工作流状态相关的数据传输对象
+package status
+
+import "todo/internal/models"
+
+// CreateRequest 创建工作流状态请求，新状态排在看板最右侧
+type CreateRequest struct {
+	// Name 状态名，例如 Backlog、In Progress、Review、Done
+	// Required: true
+	Name string `json:"name" binding:"required,max=32"`
+
+	// Terminal 是否为终止状态，移入终止状态的待办事项自动标记为已完成
+	Terminal bool `json:"terminal"`
+
+	// WIPLimit 在制品上限，0 表示不限制
+	WIPLimit int `json:"wipLimit" binding:"min=0"`
+}
+
+// UpdateRequest 更新工作流状态请求，只更新提供的字段
+type UpdateRequest struct {
+	Name     *string `json:"name" binding:"omitempty,max=32"`    // 状态名
+	Terminal *bool   `json:"terminal"`                           // 是否为终止状态，修改后同步其中待办事项的完成状态
+	WIPLimit *int    `json:"wipLimit" binding:"omitempty,min=0"` // 在制品上限，0 表示不限制
+}
+
+// ReorderRequest 调整看板列顺序请求
+type ReorderRequest struct {
+	// IDs 用户所有工作流状态的ID，按新的顺序排列
+	IDs []uint `json:"ids" binding:"required,min=1"`
+}
+
+// ListResponse 工作流状态列表响应
+type ListResponse struct {
+	Items []*models.Status `json:"items"`
+}
+
+// DeleteRequest 删除工作流状态请求
+type DeleteRequest struct {
+	// MoveTo 其中待办事项移动到的状态ID，为空时清除待办事项的状态（完成状态不变）
+	MoveTo *uint `form:"move_to"`
+}
+
+// DeleteResponse 删除工作流状态响应
+type DeleteResponse struct {
+	Message  string `json:"message"`  // 响应消息
+	Affected int    `json:"affected"` // 受影响的待办事项数量
+}
+
+// BoardRequest 看板查询参数
+type BoardRequest struct {
+	// CategoryID 只显示该分类中的待办事项
+	CategoryID *uint `form:"category_id"`
+
+	// IncludeDescendants 为 true 时同时包含 CategoryID 所有下级分类中的待办事项
+	IncludeDescendants bool `form:"include_descendants"`
+}
+
+// Column 看板中的一列
+type Column struct {
+	Status    *models.Status `json:"status"`    // 工作流状态，为空表示尚未设置状态的待办事项
+	Count     int            `json:"count"`     // 本列显示的待办事项数量
+	WIPCount  int64          `json:"wipCount"`  // 计入在制品上限的待办事项数量（所有分类中未归档的）
+	WIPLimit  int            `json:"wipLimit"`  // 在制品上限，0 表示不限制
+	OverLimit bool           `json:"overLimit"` // 是否超过在制品上限（上限在待办事项移入后被调低时可能出现）
+	Todos     []*models.Todo `json:"todos"`     // 待办事项，按手动排序位置排列
+}
+
+// BoardResponse 看板响应
+type BoardResponse struct {
+	Columns []*Column `json:"columns"`
+}
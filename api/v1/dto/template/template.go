@@ -0,0 +1,67 @@
+// Package template 提供待办事项模板相关的数据传输对象
+package template
+
+import (
+	"time"
+	"todo/internal/models"
+)
+
+// CreateRequest 创建模板请求
+type CreateRequest struct {
+	// Name 名称，例如"发布检查清单"
+	// Required: true
+	Name string `json:"name" binding:"required,max=64"`
+
+	// Description 说明
+	// Required: false
+	Description string `json:"description" binding:"max=256"`
+
+	// Items 待办事项，包括各级子任务在内不超过 100 个
+	// Required: true
+	Items models.TemplateItems `json:"items" binding:"required,min=1,dive"`
+}
+
+// UpdateRequest 更新模板请求，只更新提供的字段
+type UpdateRequest struct {
+	Name        *string               `json:"name" binding:"omitempty,max=64"`         // 名称
+	Description *string               `json:"description" binding:"omitempty,max=256"` // 说明
+	Items       *models.TemplateItems `json:"items" binding:"omitempty,min=1,dive"`    // 待办事项，整体替换
+}
+
+// FromTodoRequest 将待办事项保存为模板请求
+// 待办事项的阻塞项（递归）保存为子任务，截止时间和提醒换算为相对基准时间的偏移
+type FromTodoRequest struct {
+	// Name 模板名称
+	// Required: true
+	Name string `json:"name" binding:"required,max=64"`
+
+	// Description 说明
+	// Required: false
+	Description string `json:"description" binding:"max=256"`
+
+	// BaseDate 换算偏移时使用的基准时间，为空时使用待办事项的创建时间
+	// Required: false
+	BaseDate *time.Time `json:"baseDate"`
+}
+
+// InstantiateRequest 实例化模板请求
+type InstantiateRequest struct {
+	// BaseDate 基准时间，RFC3339 格式；截止时间和提醒时间按模板中的偏移从它推算
+	// Required: true
+	BaseDate time.Time `json:"baseDate" binding:"required"`
+}
+
+// InstantiateResponse 实例化模板响应
+type InstantiateResponse struct {
+	IDs []uint `json:"ids"` // 新建的待办事项ID，父任务排在其子任务之前
+}
+
+// ListResponse 模板列表响应
+type ListResponse struct {
+	Items []*models.Template `json:"items"`
+}
+
+// DeleteResponse 删除模板响应
+type DeleteResponse struct {
+	Message string `json:"message"` // 响应消息
+}
@@ -0,0 +1,68 @@
+// Package timeentry 提供时间记录相关的数据传输对象
+package timeentry
+
+import (
+	"time"
+	"todo/internal/models"
+)
+
+// StartRequest 启动计时器请求，请求体可以为空
+type StartRequest struct {
+	// Note 备注
+	// Required: false
+	Note string `json:"note" binding:"max=256"`
+}
+
+// CreateRequest 手动录入时间请求
+type CreateRequest struct {
+	// StartedAt 开始时间，RFC3339 格式，不能晚于当前时间
+	// Required: true
+	StartedAt time.Time `json:"startedAt" binding:"required"`
+
+	// Minutes 时长（分钟），单条记录不超过 24 小时
+	// Required: true
+	Minutes int `json:"minutes" binding:"required,min=1,max=1440"`
+
+	// Note 备注
+	// Required: false
+	Note string `json:"note" binding:"max=256"`
+}
+
+// ListResponse 待办事项的时间记录列表响应
+type ListResponse struct {
+	Items        []*models.TimeEntry `json:"items"`        // 时间记录，按开始时间倒序
+	TotalSeconds int64               `json:"totalSeconds"` // 总时长（秒），包括正在运行的计时器已经过的时间
+	Estimate     *int                `json:"estimate"`     // 预估耗时（分钟）
+	Running      bool                `json:"running"`      // 是否有正在运行的计时器
+}
+
+// DeleteResponse 删除时间记录响应
+type DeleteResponse struct {
+	Message string `json:"message"` // 响应消息
+}
+
+// ReportRequest 时间报表查询参数
+type ReportRequest struct {
+	// From 起始日期（含），格式 2006-01-02
+	From time.Time `form:"from" time_format:"2006-01-02" binding:"required"`
+
+	// To 结束日期（含），格式 2006-01-02
+	To time.Time `form:"to" time_format:"2006-01-02" binding:"required"`
+}
+
+// CategoryTotal 某个分类下记录的时间
+type CategoryTotal struct {
+	CategoryID   *uint  `json:"categoryId"`   // 分类ID，为空表示未分类
+	CategoryName string `json:"categoryName"` // 分类名，分类已删除时为空
+	Seconds      int64  `json:"seconds"`      // 总时长（秒）
+	Entries      int64  `json:"entries"`      // 时间记录数量
+}
+
+// ReportResponse 时间报表响应
+// 按开始时间统计范围内已结束的时间记录，正在运行的计时器不计入
+type ReportResponse struct {
+	From         time.Time       `json:"from"`         // 起始时间（含）
+	To           time.Time       `json:"to"`           // 结束时间（不含）
+	TotalSeconds int64           `json:"totalSeconds"` // 总时长（秒）
+	Categories   []CategoryTotal `json:"categories"`   // 各分类的汇总，按时长倒序
+}
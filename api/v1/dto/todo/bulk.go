@@ -0,0 +1,58 @@
+package todo
+
+import "todo/internal/models"
+
+// 批量操作类型
+const (
+	BulkComplete     = "complete"      // 标记为已完成
+	BulkUncomplete   = "uncomplete"    // 标记为未完成
+	BulkDelete       = "delete"        // 移入回收站
+	BulkMoveCategory = "move_category" // 移动到 CategoryID 指定的分类，为空时取消分类
+	BulkSetPriority  = "set_priority"  // 设置优先级
+	BulkAddTags      = "add_tags"      // 添加标签
+	BulkRemoveTags   = "remove_tags"   // 移除标签
+)
+
+// BulkLimit 单次批量操作最多处理的待办事项数量
+const BulkLimit = 500
+
+// BulkRequest 批量操作请求
+// IDs 和 Filter 二选一：按ID列表或按过滤条件选择待办事项
+type BulkRequest struct {
+	// IDs 待办事项ID列表
+	IDs []uint `json:"ids" binding:"omitempty,max=500"`
+
+	// Filter 过滤条件，字段与保存的过滤条件一致
+	Filter *models.FilterDefinition `json:"filter"`
+
+	// Action 操作类型
+	// Required: true
+	// Enum: [complete uncomplete delete move_category set_priority add_tags remove_tags]
+	Action string `json:"action" binding:"required,oneof=complete uncomplete delete move_category set_priority add_tags remove_tags"`
+
+	// CategoryID move_category 的目标分类，为空时取消分类
+	CategoryID *uint `json:"categoryId"`
+
+	// Priority set_priority 的目标优先级
+	Priority string `json:"priority" binding:"omitempty,oneof=low medium high"`
+
+	// Tags add_tags、remove_tags 的标签名列表
+	Tags []string `json:"tags" binding:"omitempty,max=20,dive,required,max=32"`
+}
+
+// BulkItemResult 单个待办事项的处理结果
+type BulkItemResult struct {
+	ID      uint   `json:"id"`              // 待办事项ID
+	Success bool   `json:"success"`         // 是否处理成功
+	Error   string `json:"error,omitempty"` // 失败原因
+}
+
+// BulkResponse 批量操作响应
+// 所有待办事项在同一事务中处理，任一失败时整体回滚，Applied 为 false
+type BulkResponse struct {
+	Action    string            `json:"action"`    // 操作类型
+	Applied   bool              `json:"applied"`   // 变更是否已提交
+	Succeeded int               `json:"succeeded"` // 处理成功的数量
+	Failed    int               `json:"failed"`    // 处理失败的数量
+	Items     []*BulkItemResult `json:"items"`     // 每个待办事项的处理结果
+}
@@ -1,5 +1,7 @@
 package todo
 
+import "time"
+
 // CreateRequest 创建待办事项请求
 type CreateRequest struct {
 	// Title 待办事项标题
@@ -21,6 +23,22 @@ type CreateRequest struct {
 	// CategoryID 所属分类ID
 	// Required: false
 	CategoryID  *uint  `json:"categoryId" binding:"omitempty"`
+
+	// DueDate 截止时间，RFC3339 格式
+	// Required: false
+	DueDate *time.Time `json:"dueDate" binding:"omitempty"`
+
+	// Tags 标签名列表，不存在的标签会自动创建
+	// Required: false
+	Tags []string `json:"tags" binding:"omitempty,max=20,dive,required,max=32"`
+
+	// StatusID 看板工作流状态ID，为空时使用第一个非终止状态（用户定义了状态时）
+	// Required: false
+	StatusID *uint `json:"statusId" binding:"omitempty"`
+
+	// Estimate 预估耗时（分钟）
+	// Required: false
+	Estimate *int `json:"estimate" binding:"omitempty,min=0"`
 }
 
 // CreateResponse 创建待办事项响应
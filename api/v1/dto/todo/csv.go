@@ -0,0 +1,39 @@
+package todo
+
+// 导入文件的格式
+const (
+	ImportFormatCSV      = "csv"      // CSV，第一行为表头
+	ImportFormatTodoTxt  = "todotxt"  // todo.txt，每行一个待办事项
+	ImportFormatMarkdown = "markdown" // GitHub 风格的 Markdown 任务列表
+)
+
+// ImportRequest 导入参数，文件内容通过请求体或 multipart 表单中的 file 字段上传
+type ImportRequest struct {
+	// Format 文件格式：csv（默认）、todotxt、markdown
+	Format string `form:"format" binding:"omitempty,oneof=csv todotxt markdown"`
+
+	// DryRun 为 true 时只校验每一行并返回结果，不创建任何数据
+	DryRun bool `form:"dry_run"`
+
+	// Mapping CSV 的表头映射，格式为 "CSV列名=字段,..."，例如 "Task=title,Due=due_date"。
+	// 可用字段：title、description、priority、completed、due_date、category、tags、estimate；
+	// 未映射的列按列名（不区分大小写）匹配同名字段，无法匹配的列被忽略
+	Mapping string `form:"mapping" binding:"max=1024"`
+}
+
+// ImportError 导入时某一行的错误
+type ImportError struct {
+	Row     int    `json:"row"`              // 行号，从 1 开始，CSV 的表头为第 1 行
+	Column  string `json:"column,omitempty"` // 出错的字段
+	Message string `json:"message"`          // 错误信息
+}
+
+// ImportResponse 导入结果
+type ImportResponse struct {
+	DryRun            bool          `json:"dryRun"`            // 是否为试运行
+	Rows              int           `json:"rows"`              // 数据行数，不包括 CSV 表头、空行和非任务内容
+	Imported          int           `json:"imported"`          // 导入成功的行数，试运行时为校验通过的行数
+	Failed            int           `json:"failed"`            // 校验失败而跳过的行数
+	CreatedCategories []string      `json:"createdCategories"` // 按名称新建的分类，试运行时为将要新建的分类
+	Errors            []ImportError `json:"errors"`            // 每一行的校验错误，最多返回前 100 条
+}
@@ -4,5 +4,10 @@ import "todo/internal/models"
 
 // DetailResponse 待办事项详情响应
 type DetailResponse struct {
-    *models.Todo
-} 
\ No newline at end of file
+	*models.Todo
+	LatestComments []*models.Comment `json:"latestComments"` // 最新的若干条评论，按创建时间倒序
+	Blocked        bool              `json:"blocked"`        // 是否被未完成的待办事项阻塞
+	BlockedBy      []*models.Todo    `json:"blockedBy"`      // 阻塞它且尚未完成的待办事项
+	TrackedSeconds int64             `json:"trackedSeconds"` // 已记录的总时长（秒），包括正在运行的计时器已经过的时间
+	TimerRunning   bool              `json:"timerRunning"`   // 是否有正在运行的计时器
+}
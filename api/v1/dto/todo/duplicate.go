@@ -0,0 +1,7 @@
+package todo
+
+// DuplicateRequest 复制待办事项请求，请求体可以为空
+type DuplicateRequest struct {
+	// ShiftMinutes 副本的截止时间和提醒时间相对原待办事项平移的分钟数，可以为负数，0 表示不平移
+	ShiftMinutes int `json:"shiftMinutes"`
+}
@@ -2,6 +2,52 @@ package todo
 
 import "todo/internal/models"
 
+// 归档状态过滤取值
+const (
+	ArchivedExclude = "false" // 排除已归档（默认）
+	ArchivedOnly    = "true"  // 只看已归档
+	ArchivedAll     = "all"   // 包含已归档
+)
+
+// SortPosition 按手动排序位置排列：先按分类分组，分类内按拖动后的顺序
+const SortPosition = "position"
+
+// ListRequest 待办事项列表查询参数
+type ListRequest struct {
+	// Archived 归档状态过滤：false（默认，排除已归档）、true（只看已归档）、all（全部）
+	Archived string `form:"archived" binding:"omitempty,oneof=true false all"`
+
+	// Completed 完成状态过滤，为空时不过滤
+	Completed *bool `form:"completed"`
+
+	// Keyword 在标题和描述中搜索的关键字
+	Keyword string `form:"q" binding:"omitempty,max=128"`
+
+	// Query 查询语言表达的过滤条件，例如 priority:high due<7d -completed tag:release，语法见 internal/query 包
+	Query string `form:"query" binding:"omitempty,max=256"`
+
+	// Priority 优先级过滤
+	Priority string `form:"priority" binding:"omitempty,oneof=low medium high"`
+
+	// Due 截止时间过滤：overdue（已逾期且未完成）、today、this_week、none（没有截止时间）
+	Due string `form:"due" binding:"omitempty,oneof=overdue today this_week none"`
+
+	// CategoryID 所属分类ID
+	CategoryID *uint `form:"category_id"`
+
+	// IncludeDescendants 为 true 时同时包含 CategoryID 所有下级分类中的待办事项
+	IncludeDescendants bool `form:"include_descendants"`
+
+	// StatusID 看板工作流状态ID
+	StatusID *uint `form:"status_id"`
+
+	// Sort 排序方式，position 表示按手动排序位置
+	Sort string `form:"sort" binding:"omitempty,oneof=position"`
+
+	// FilterID 保存的过滤条件ID，指定时使用保存的条件，忽略其他过滤参数
+	FilterID *uint `form:"filter_id"`
+}
+
 // ListResponse 待办事项列表响应
 type ListResponse struct {
 	// 总记录数
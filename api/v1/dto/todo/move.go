@@ -0,0 +1,10 @@
+package todo
+
+// MoveRequest 拖动待办事项请求，before 和 after 至少提供一个
+type MoveRequest struct {
+	// Before 放到该待办事项之前
+	Before *uint `json:"before"`
+
+	// After 放到该待办事项之后
+	After *uint `json:"after"`
+}
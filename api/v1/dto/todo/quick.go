@@ -0,0 +1,65 @@
+package todo
+
+import "time"
+
+// QuickAddRequest 快速添加待办事项请求
+type QuickAddRequest struct {
+	// Text 一行自然语言文本，如 "Call Bob tomorrow 3pm !high #work remind 30m before"
+	// 或 "明天下午3点给老王打电话 !高 #工作 提前半小时提醒"
+	// Required: true
+	// Max Length: 256
+	Text string `json:"text" binding:"required,max=256"`
+
+	// Timezone 计算今天、明天等相对日期使用的 IANA 时区，如 Asia/Shanghai，为空时使用服务器时区
+	// Required: false
+	Timezone string `json:"timezone" binding:"omitempty,max=64"`
+
+	// DryRun 为 true 时只返回解析结果，不创建待办事项，用于让用户确认
+	// Required: false
+	DryRun bool `json:"dryRun"`
+}
+
+// QuickAddToken 从文本中识别出的一个片段
+type QuickAddToken struct {
+	// Kind 片段类型：due、priority、hashtag、reminder
+	Kind string `json:"kind"`
+
+	// Text 文本中的原文
+	Text string `json:"text"`
+}
+
+// QuickAddResponse 快速添加的解析结果
+type QuickAddResponse struct {
+	// ID 新创建的待办事项ID，试运行时为 0
+	ID uint `json:"id,omitempty"`
+
+	// DryRun 是否为试运行
+	DryRun bool `json:"dryRun"`
+
+	// Title 解析出的标题
+	Title string `json:"title"`
+
+	// DueDate 解析出的截止时间
+	DueDate *time.Time `json:"dueDate"`
+
+	// Priority 优先级，未指定时为默认的中优先级
+	Priority string `json:"priority"`
+
+	// CategoryID 第一个与现有分类同名的 #标签对应的分类ID
+	CategoryID *uint `json:"categoryId"`
+
+	// Category 分类名
+	Category string `json:"category,omitempty"`
+
+	// Tags 其余 #标签
+	Tags []string `json:"tags"`
+
+	// RemindAt 提醒时间
+	RemindAt *time.Time `json:"remindAt"`
+
+	// Tokens 识别出的片段，按在文本中出现的顺序
+	Tokens []QuickAddToken `json:"tokens"`
+
+	// Warnings 被忽略的内容，如没有截止时间时的提醒
+	Warnings []string `json:"warnings"`
+}
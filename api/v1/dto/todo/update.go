@@ -1,5 +1,7 @@
 package todo
 
+import "time"
+
 // UpdateRequest 更新待办事项请求
 type UpdateRequest struct {
 	Title       *string `json:"title,omitempty" binding:"omitempty,max=128"`       // 标题
@@ -7,6 +9,13 @@ type UpdateRequest struct {
 	Completed   *bool   `json:"completed,omitempty"`                               // 完成状态
 	Priority    *string `json:"priority,omitempty" binding:"omitempty,oneof=low medium high"` // 优先级
 	CategoryID  *uint   `json:"categoryId,omitempty"`                              // 分类ID
+	DueDate     *time.Time `json:"dueDate,omitempty"`                              // 截止时间
+	ClearDueDate bool      `json:"clearDueDate,omitempty"`                         // 为 true 时清除截止时间
+	Tags        *[]string  `json:"tags,omitempty" binding:"omitempty,max=20,dive,required,max=32"` // 标签名列表，整体替换；传空数组清除所有标签
+	StatusID    *uint      `json:"statusId,omitempty"`                             // 看板工作流状态ID，同时决定完成状态
+	ClearStatus bool       `json:"clearStatus,omitempty"`                          // 为 true 时清除工作流状态，完成状态不变
+	Estimate    *int       `json:"estimate,omitempty" binding:"omitempty,min=0"`   // 预估耗时（分钟）
+	ClearEstimate bool     `json:"clearEstimate,omitempty"`                        // 为 true 时清除预估耗时
 }
 
 // UpdateResponse 更新待办事项响应
@@ -0,0 +1,65 @@
+// Package webhook 提供 webhook 端点管理相关的数据传输对象和投递的请求体格式
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+	"todo/internal/models"
+)
+
+// 投递请求的头部
+// 签名为 HMAC-SHA256(密钥, 时间戳 + "." + 请求体) 的十六进制，接收方应同时校验时间戳以防重放
+const (
+	HeaderEvent     = "X-Webhook-Event"     // 事件类型
+	HeaderDelivery  = "X-Webhook-Delivery"  // 投递ID，同一事件的重试相同，可用于去重
+	HeaderTimestamp = "X-Webhook-Timestamp" // 发送时间的 Unix 秒数
+	HeaderSignature = "X-Webhook-Signature" // 签名，格式为 sha256=<hex>
+)
+
+// CreateRequest 注册 webhook 端点请求
+type CreateRequest struct {
+	// URL 接收事件的 http 或 https 地址
+	// Required: true
+	URL string `json:"url" binding:"required,url,max=512"`
+
+	// Events 订阅的事件类型，如 todo.created、todo.completed、reminder.fired，"*" 表示全部事件
+	// Required: true
+	Events []string `json:"events" binding:"required,min=1,max=20,dive,required"`
+}
+
+// UpdateRequest 更新 webhook 端点请求，只更新提供的字段
+type UpdateRequest struct {
+	URL     *string  `json:"url" binding:"omitempty,url,max=512"`                   // 接收事件的地址
+	Events  []string `json:"events" binding:"omitempty,min=1,max=20,dive,required"` // 订阅的事件类型，整体替换
+	Enabled *bool    `json:"enabled"`                                               // 是否启用，重新启用时清零失败次数
+}
+
+// CreateResponse 注册 webhook 端点响应
+type CreateResponse struct {
+	*models.Webhook
+	Secret string `json:"secret"` // 签名密钥，只在注册时返回一次
+}
+
+// ListResponse webhook 端点列表响应
+type ListResponse struct {
+	Items []*models.Webhook `json:"items"`
+}
+
+// DeliveryListResponse 投递日志响应
+type DeliveryListResponse struct {
+	Items []*models.WebhookDelivery `json:"items"`
+}
+
+// Event 投递的请求体
+type Event struct {
+	ID          string          `json:"id"`          // 投递ID
+	Event       string          `json:"event"`       // 事件类型
+	CreatedAt   time.Time       `json:"createdAt"`   // 事件发生时间
+	WorkspaceID uint            `json:"workspaceId"` // 事件所在的工作空间ID
+	Data        json.RawMessage `json:"data"`        // 事件数据：待办事项或提醒，测试投递时为端点信息
+}
+
+// DeleteResponse 删除 webhook 端点响应
+type DeleteResponse struct {
+	Message string `json:"message"` // 响应消息
+}
@@ -0,0 +1,24 @@
+// Package workspace 提供工作空间相关的数据传输对象
+package workspace
+
+import "todo/internal/models"
+
+// CreateRequest 创建工作空间请求
+type CreateRequest struct {
+	// Name 工作空间名称
+	// Required: true
+	// Max Length: 64
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+// ListResponse 工作空间列表响应
+type ListResponse struct {
+	Total int64               `json:"total"` // 总数
+	Items []*models.Workspace `json:"items"` // 工作空间列表
+}
+
+// SwitchResponse 切换工作空间响应
+type SwitchResponse struct {
+	Token       string `json:"token"`       // 携带新工作空间的JWT令牌
+	WorkspaceID uint   `json:"workspaceId"` // 切换后的工作空间ID
+}
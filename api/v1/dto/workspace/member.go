@@ -0,0 +1,26 @@
+package workspace
+
+import "todo/internal/models"
+
+// InviteRequest 邀请成员请求
+type InviteRequest struct {
+	// Email 被邀请人邮箱，为空时任何持有邀请令牌的用户都可加入
+	// Required: false
+	Email string `json:"email" binding:"omitempty,email,max=128"`
+
+	// Role 加入后的角色
+	// Required: false
+	// Enum: [admin member]
+	Role string `json:"role" binding:"omitempty,oneof=admin member"`
+}
+
+// MemberListResponse 成员列表响应
+type MemberListResponse struct {
+	Total int64                     `json:"total"` // 总数
+	Items []*models.WorkspaceMember `json:"items"` // 成员列表
+}
+
+// MessageResponse 通用消息响应
+type MessageResponse struct {
+	Message string `json:"message"` // 响应消息
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo/internal/service"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ArchiveTodo 归档待办事项
+// @Summary 归档待办事项
+// @Description 归档待办事项，归档后默认不出现在列表中，可通过 archived=true 查询
+// @Tags 待办事项管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=models.Todo} "归档成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "待办事项不存在"
+// @Router /todos/{id}/archive [post]
+func ArchiveTodo(todoService service.TodoService) gin.HandlerFunc {
+	return setArchived(todoService, true)
+}
+
+// UnarchiveTodo 取消归档待办事项
+// @Summary 取消归档待办事项
+// @Description 取消归档，待办事项重新出现在默认列表中
+// @Tags 待办事项管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=models.Todo} "取消归档成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "待办事项不存在"
+// @Router /todos/{id}/unarchive [post]
+func UnarchiveTodo(todoService service.TodoService) gin.HandlerFunc {
+	return setArchived(todoService, false)
+}
+
+// setArchived 返回修改归档状态的处理器
+func setArchived(todoService service.TodoService, archived bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		userID := c.GetUint("userID")
+		if archived {
+			err = todoService.Archive(c.Request.Context(), uint(id), userID)
+		} else {
+			err = todoService.Unarchive(c.Request.Context(), uint(id), userID)
+		}
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		todoItem, err := todoService.Get(c.Request.Context(), uint(id), userID)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(todoItem))
+	}
+}
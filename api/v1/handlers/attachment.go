@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/attachment"
+	"todo/internal/service"
+	"todo/pkg/errors"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead 上传请求体中除文件内容外 multipart 边界和表单头部允许占用的字节数
+const multipartOverhead = 64 << 10
+
+// UploadAttachment 上传附件
+// @Summary 上传附件
+// @Description 为待办事项上传附件，大小和类型受配置限制，文件类型根据内容识别
+// @Tags 附件管理
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param file formData file true "附件文件"
+// @Success 200 {object} response.Response{data=models.Attachment} "上传成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 413 {object} response.Response "附件过大"
+// @Failure 415 {object} response.Response "不支持的附件类型"
+// @Router /todos/{id}/attachments [post]
+func UploadAttachment(attachmentService service.AttachmentService, maxSize int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		// 解析表单前限制请求体大小，避免超大的请求体被完整读入内存或临时文件
+		if maxSize > 0 {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+		}
+		fileHeader, err := c.FormFile("file")
+		var tooLarge *http.MaxBytesError
+		if stderrors.As(err, &tooLarge) {
+			writeAttachmentError(c, errors.ErrAttachmentTooLarge)
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "缺少上传文件"))
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+		defer file.Close()
+
+		created, err := attachmentService.Upload(c.Request.Context(), c.GetUint("userID"), uint(todoID),
+			fileHeader.Filename, fileHeader.Size, file)
+		if err != nil {
+			writeAttachmentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(created))
+	}
+}
+
+// ListAttachments 获取附件列表
+// @Summary 获取附件列表
+// @Description 获取待办事项的所有附件
+// @Tags 附件管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=attachment.ListResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /todos/{id}/attachments [get]
+func ListAttachments(attachmentService service.AttachmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		attachments, err := attachmentService.List(c.Request.Context(), c.GetUint("userID"), uint(todoID))
+		if err != nil {
+			writeAttachmentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(attachment.ListResponse{
+			Total: int64(len(attachments)),
+			Items: attachments,
+		}))
+	}
+}
+
+// DownloadAttachment 下载附件
+// @Summary 下载附件
+// @Description 下载附件的原始文件
+// @Tags 附件管理
+// @Produce octet-stream
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param attachment_id path int true "附件ID"
+// @Success 200 {file} file "附件内容"
+// @Failure 404 {object} response.Response "附件不存在"
+// @Router /todos/{id}/attachments/{attachment_id} [get]
+func DownloadAttachment(attachmentService service.AttachmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, attachmentID, ok := parseAttachmentPath(c)
+		if !ok {
+			return
+		}
+
+		att, rc, err := attachmentService.Open(c.Request.Context(), c.GetUint("userID"), todoID, attachmentID)
+		if err != nil {
+			writeAttachmentError(c, err)
+			return
+		}
+		defer rc.Close()
+
+		c.DataFromReader(http.StatusOK, att.Size, att.ContentType, rc, map[string]string{
+			"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": att.FileName}),
+			"X-Content-Type-Options": "nosniff",
+		})
+	}
+}
+
+// DeleteAttachment 删除附件
+// @Summary 删除附件
+// @Description 删除附件及其存储的文件
+// @Tags 附件管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param attachment_id path int true "附件ID"
+// @Success 200 {object} response.Response{data=attachment.DeleteResponse} "删除成功"
+// @Failure 404 {object} response.Response "附件不存在"
+// @Router /todos/{id}/attachments/{attachment_id} [delete]
+func DeleteAttachment(attachmentService service.AttachmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, attachmentID, ok := parseAttachmentPath(c)
+		if !ok {
+			return
+		}
+
+		if err := attachmentService.Delete(c.Request.Context(), c.GetUint("userID"), todoID, attachmentID); err != nil {
+			writeAttachmentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(attachment.DeleteResponse{
+			Message: "附件已删除",
+		}))
+	}
+}
+
+// parseAttachmentPath 解析路径中的待办事项ID和附件ID，解析失败时直接写入错误响应
+func parseAttachmentPath(c *gin.Context) (uint, uint, bool) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+		return 0, 0, false
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachment_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid attachment ID"))
+		return 0, 0, false
+	}
+	return uint(todoID), uint(attachmentID), true
+}
+
+// writeAttachmentError 将附件相关的业务错误映射为HTTP状态码
+func writeAttachmentError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, response.Error(http.StatusForbidden, err.Error()))
+	case errors.ErrTodoNotFound, errors.ErrAttachmentNotFound:
+		c.JSON(http.StatusNotFound, response.Error(http.StatusNotFound, err.Error()))
+	case errors.ErrAttachmentTooLarge:
+		c.JSON(http.StatusRequestEntityTooLarge, response.Error(http.StatusRequestEntityTooLarge, err.Error()))
+	case errors.ErrAttachmentType:
+		c.JSON(http.StatusUnsupportedMediaType, response.Error(http.StatusUnsupportedMediaType, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+	}
+}
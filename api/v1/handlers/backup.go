@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"todo/internal/service"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StartDataExport 导出账户数据
+// @Summary 导出账户数据
+// @Description 在后台生成当前用户在当前工作空间中的全部数据（用户资料、分类、待办事项及其标签、提醒和评论）的 JSON 归档。
+// @Description 已有未完成的导出时直接返回该导出；否则上一次的导出及其归档被删除。通过 GET /backup/export 查询进度，完成后下载
+// @Tags 数据备份
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 202 {object} response.Response{data=models.DataExport} "导出已开始"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /backup/export [post]
+func StartDataExport(backupService service.BackupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		export, err := backupService.StartExport(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, response.Success(export))
+	}
+}
+
+// GetDataExport 获取账户数据导出进度
+// @Summary 获取账户数据导出进度
+// @Description 获取最近一次导出的状态：pending、running、done 或 failed
+// @Tags 数据备份
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 200 {object} response.Response{data=models.DataExport} "获取成功"
+// @Failure 404 {object} response.Response "尚未导出"
+// @Router /backup/export [get]
+func GetDataExport(backupService service.BackupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		export, err := backupService.GetExport(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(export))
+	}
+}
+
+// DownloadDataExport 下载账户数据归档
+// @Summary 下载账户数据归档
+// @Description 下载最近一次导出生成的 JSON 归档
+// @Tags 数据备份
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 200 {object} backup.Archive "归档文件"
+// @Failure 404 {object} response.Response "尚未导出"
+// @Failure 409 {object} response.Response "导出尚未完成或已失败"
+// @Router /backup/export/download [get]
+func DownloadDataExport(backupService service.BackupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		export, rc, err := backupService.OpenExport(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+		defer rc.Close()
+
+		filename := fmt.Sprintf("todo-export-%s.json", export.FinishedAt.Format("20060102-150405"))
+		c.DataFromReader(http.StatusOK, export.Size, "application/json", rc, map[string]string{
+			"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": filename}),
+		})
+	}
+}
+
+// ImportDataArchive 导入账户数据
+// @Summary 导入账户数据
+// @Description 以请求体或 multipart 表单的 file 字段上传导出的 JSON 归档，导入到当前用户在当前工作空间中的账户。
+// @Description 只能导入到没有分类和待办事项的空账户；归档中的ID被重新分配，所有数据在一个事务中创建，任何一项无效时不导入任何数据
+// @Tags 数据备份
+// @Accept json
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param file formData file false "归档文件（multipart 上传时）"
+// @Success 200 {object} response.Response{data=backup.ImportResponse} "导入成功"
+// @Failure 400 {object} response.Response "归档无效"
+// @Failure 409 {object} response.Response "账户不为空"
+// @Failure 413 {object} response.Response "上传内容过大"
+// @Router /backup/import [post]
+func ImportDataArchive(backupService service.BackupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r, err := uploadReader(c)
+		if err != nil {
+			if !uploadTooLarge(c, err) {
+				c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "缺少上传文件"))
+			}
+			return
+		}
+
+		result, err := backupService.Import(c.Request.Context(), c.GetUint("userID"), r)
+		if err != nil {
+			if !uploadTooLarge(c, err) {
+				writeTodoError(c, err)
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(result))
+	}
+}
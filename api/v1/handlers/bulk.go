@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"todo/api/v1/dto/todo"
+	"todo/internal/service"
+	"todo/pkg/errors"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkTodos 批量操作待办事项
+// @Summary 批量操作待办事项
+// @Description 按ID列表或过滤条件选择待办事项，执行完成、取消完成、删除、移动分类、设置优先级、添加或移除标签。
+// @Description 所有待办事项在同一事务中处理并逐个校验所有权，任一失败时整体回滚并返回 422 及每个待办事项的处理结果
+// @Tags 待办事项管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param request body todo.BulkRequest true "批量操作请求"
+// @Success 200 {object} response.Response{data=todo.BulkResponse} "全部处理成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 422 {object} response.Response{data=todo.BulkResponse} "部分待办事项处理失败，已全部回滚"
+// @Router /todos/bulk [post]
+func BulkTodos(todoService service.TodoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req todo.BulkRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		result, err := todoService.Bulk(c.Request.Context(), c.GetUint("userID"), &req)
+		switch err {
+		case nil:
+			c.JSON(http.StatusOK, response.Success(result))
+		case errors.ErrBulkFailed:
+			c.JSON(http.StatusUnprocessableEntity, response.NewResponse(http.StatusUnprocessableEntity, err.Error(), result))
+		case errors.ErrInvalidParameter, errors.ErrBulkTooMany:
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+		default:
+			writeTodoError(c, err)
+		}
+	}
+}
@@ -0,0 +1,533 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"todo/api/v1/dto/caldav"
+	"todo/internal/service"
+	"todo/pkg/errors"
+	"todo/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalDAV 使用的 XML 命名空间
+const (
+	davNS    = "DAV:"
+	calDAVNS = "urn:ietf:params:xml:ns:caldav"
+	csNS     = "http://calendarserver.org/ns/"
+)
+
+const (
+	calDAVPrefix          = "/caldav/"                                      // CalDAV 服务的根路径
+	calDAVObjectType      = "text/calendar; charset=utf-8; component=VTODO" // 日历对象的内容类型
+	maxCalendarObjectSize = 1 << 20                                         // 上传的日历对象的最大字节数
+)
+
+// CalDAVMethods CalDAV 路由需要注册的请求方法
+var CalDAVMethods = []string{http.MethodOptions, "PROPFIND", "REPORT", http.MethodGet, http.MethodHead,
+	http.MethodPut, http.MethodDelete}
+
+// davPrefixes 输出时使用的命名空间前缀
+var davPrefixes = map[string]string{davNS: "d", calDAVNS: "c", csNS: "cs"}
+
+// davProp 一个 WebDAV 属性，inner 为已经转义的元素内容
+type davProp struct {
+	name  xml.Name
+	inner string
+}
+
+// davResponse multistatus 中的一个资源；status 非零时表示整个资源的状态（例如不存在）
+type davResponse struct {
+	href   string
+	props  []davProp
+	status int
+}
+
+// davPropNames 请求体中 prop 元素包含的属性名
+type davPropNames []xml.Name
+
+// UnmarshalXML 收集 prop 元素的所有子元素名
+func (p *davPropNames) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			*p = append(*p, t.Name)
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// davPropfind PROPFIND 请求体
+type davPropfind struct {
+	XMLName xml.Name      `xml:"DAV: propfind"`
+	Prop    *davPropNames `xml:"DAV: prop"`
+}
+
+// davReport REPORT 请求体，calendar-query 的过滤条件被忽略，总是返回集合中的所有待办事项
+type davReport struct {
+	XMLName xml.Name
+	Prop    *davPropNames `xml:"DAV: prop"`
+	Hrefs   []string      `xml:"DAV: href"`
+}
+
+// CalDAVWellKnown 将 /.well-known/caldav 重定向到 CalDAV 服务的根路径（RFC 6764）
+func CalDAVWellKnown(c *gin.Context) {
+	c.Redirect(http.StatusMovedPermanently, calDAVPrefix)
+}
+
+// CalDAV CalDAV 服务
+// 地址结构为 /caldav/<用户名>/<集合>/<资源名>：用户目录同时是用户主体和日历主目录，
+// 每个分类是一个只包含 VTODO 的日历集合（名称为分类ID），未分类的待办事项在 uncategorized 集合中。
+// 支持 PROPFIND、REPORT（calendar-query 和 calendar-multiget）、GET、PUT 和 DELETE，
+// 写操作支持 If-Match 和 If-None-Match。使用账号的用户名和密码进行 Basic 认证，同步默认工作空间中的数据
+func CalDAV(calDAVService service.CalDAVService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodOptions {
+			c.Header("DAV", "1, 3, calendar-access")
+			c.Header("Allow", strings.Join(CalDAVMethods, ", "))
+			c.Status(http.StatusOK)
+			return
+		}
+
+		var segments []string
+		if p := strings.Trim(c.Param("path"), "/"); p != "" {
+			segments = strings.Split(p, "/")
+		}
+		username := c.GetString("username")
+		if len(segments) > 0 && segments[0] != username {
+			c.String(http.StatusForbidden, errors.ErrForbidden.Error())
+			return
+		}
+
+		h := &calDAVHandler{svc: calDAVService, c: c, userID: c.GetUint("userID"), username: username}
+		switch len(segments) {
+		case 0:
+			h.root()
+		case 1:
+			h.home()
+		case 2:
+			h.collection(segments[1])
+		case 3:
+			h.object(segments[1], segments[2])
+		default:
+			c.Status(http.StatusNotFound)
+		}
+	}
+}
+
+// calDAVHandler 处理一个 CalDAV 请求
+type calDAVHandler struct {
+	svc      service.CalDAVService
+	c        *gin.Context
+	userID   uint
+	username string
+}
+
+// root 处理根路径，只用于发现当前用户的主体地址
+func (h *calDAVHandler) root() {
+	if h.c.Request.Method != "PROPFIND" {
+		h.c.Status(http.StatusMethodNotAllowed)
+		return
+	}
+	requested, ok := h.propfind()
+	if !ok {
+		return
+	}
+	responses := []davResponse{{href: calDAVPrefix, props: []davProp{
+		{xml.Name{Space: davNS, Local: "resourcetype"}, "<d:collection/>"},
+		{xml.Name{Space: davNS, Local: "current-user-principal"}, davHref(h.homeHref())},
+	}}}
+	if h.depth() > 0 {
+		responses = append(responses, davResponse{href: h.homeHref(), props: h.homeProps()})
+	}
+	writeMultistatus(h.c, responses, requested)
+}
+
+// home 处理用户目录，Depth 为 1 时列出所有日历集合
+func (h *calDAVHandler) home() {
+	if h.c.Request.Method != "PROPFIND" {
+		h.c.Status(http.StatusMethodNotAllowed)
+		return
+	}
+	requested, ok := h.propfind()
+	if !ok {
+		return
+	}
+	responses := []davResponse{{href: h.homeHref(), props: h.homeProps()}}
+	if h.depth() > 0 {
+		collections, err := h.svc.Collections(h.c.Request.Context(), h.userID)
+		if err != nil {
+			writeCalDAVError(h.c, err)
+			return
+		}
+		for _, collection := range collections {
+			responses = append(responses, davResponse{href: h.collectionHref(collection.Name), props: h.collectionProps(collection)})
+		}
+	}
+	writeMultistatus(h.c, responses, requested)
+}
+
+// collection 处理日历集合
+func (h *calDAVHandler) collection(name string) {
+	ctx := h.c.Request.Context()
+	switch h.c.Request.Method {
+	case "PROPFIND":
+		requested, ok := h.propfind()
+		if !ok {
+			return
+		}
+		collection, err := h.svc.Collection(ctx, h.userID, name)
+		if err != nil {
+			writeCalDAVError(h.c, err)
+			return
+		}
+		responses := []davResponse{{href: h.collectionHref(name), props: h.collectionProps(collection)}}
+		if h.depth() > 0 {
+			objects, err := h.svc.Objects(ctx, h.userID, name)
+			if err != nil {
+				writeCalDAVError(h.c, err)
+				return
+			}
+			for _, object := range objects {
+				responses = append(responses, davResponse{href: h.objectHref(name, object.Name), props: objectProps(object, false)})
+			}
+		}
+		writeMultistatus(h.c, responses, requested)
+	case "REPORT":
+		h.report(name)
+	default:
+		h.c.Status(http.StatusMethodNotAllowed)
+	}
+}
+
+// report 处理日历集合上的 calendar-query 和 calendar-multiget
+func (h *calDAVHandler) report(name string) {
+	var req davReport
+	if err := xml.NewDecoder(h.c.Request.Body).Decode(&req); err != nil {
+		h.c.String(http.StatusBadRequest, "无效的 REPORT 请求体")
+		return
+	}
+	if req.XMLName.Space != calDAVNS || (req.XMLName.Local != "calendar-query" && req.XMLName.Local != "calendar-multiget") {
+		h.c.String(http.StatusForbidden, "不支持的 REPORT: "+req.XMLName.Local)
+		return
+	}
+	var requested []xml.Name
+	if req.Prop != nil {
+		requested = *req.Prop
+	}
+
+	objects, err := h.svc.Objects(h.c.Request.Context(), h.userID, name)
+	if err != nil {
+		writeCalDAVError(h.c, err)
+		return
+	}
+	var responses []davResponse
+	if req.XMLName.Local == "calendar-query" {
+		for _, object := range objects {
+			responses = append(responses, davResponse{href: h.objectHref(name, object.Name), props: objectProps(object, true)})
+		}
+		writeMultistatus(h.c, responses, requested)
+		return
+	}
+
+	byName := make(map[string]*caldav.Object, len(objects))
+	for _, object := range objects {
+		byName[object.Name] = object
+	}
+	prefix := h.collectionHref(name)
+	for _, href := range req.Hrefs {
+		href = strings.TrimSpace(href)
+		object := byName[objectNameFromHref(href, prefix)]
+		if object == nil {
+			responses = append(responses, davResponse{href: href, status: http.StatusNotFound})
+			continue
+		}
+		responses = append(responses, davResponse{href: href, props: objectProps(object, true)})
+	}
+	writeMultistatus(h.c, responses, requested)
+}
+
+// object 处理日历对象
+func (h *calDAVHandler) object(collection, name string) {
+	ctx := h.c.Request.Context()
+	switch h.c.Request.Method {
+	case "PROPFIND":
+		requested, ok := h.propfind()
+		if !ok {
+			return
+		}
+		object, err := h.svc.Object(ctx, h.userID, collection, name)
+		if err != nil {
+			writeCalDAVError(h.c, err)
+			return
+		}
+		writeMultistatus(h.c, []davResponse{{href: h.objectHref(collection, name), props: objectProps(object, false)}}, requested)
+	case http.MethodGet, http.MethodHead:
+		object, err := h.svc.Object(ctx, h.userID, collection, name)
+		if err != nil {
+			writeCalDAVError(h.c, err)
+			return
+		}
+		h.c.Header("ETag", object.ETag)
+		if etagMatches(h.c.GetHeader("If-None-Match"), object.ETag) {
+			h.c.Status(http.StatusNotModified)
+			return
+		}
+		h.c.Data(http.StatusOK, calDAVObjectType, object.Data)
+	case http.MethodPut:
+		data, err := io.ReadAll(http.MaxBytesReader(h.c.Writer, h.c.Request.Body, maxCalendarObjectSize))
+		if err != nil {
+			h.c.String(http.StatusRequestEntityTooLarge, "日历对象过大")
+			return
+		}
+		object, created, err := h.svc.Put(ctx, h.userID, &caldav.PutRequest{
+			Collection:  collection,
+			Name:        name,
+			IfMatch:     h.c.GetHeader("If-Match"),
+			IfNoneMatch: strings.TrimSpace(h.c.GetHeader("If-None-Match")),
+			Data:        data,
+		})
+		if err != nil {
+			writeCalDAVError(h.c, err)
+			return
+		}
+		// 服务器会规范化内容，因此客户端需要重新获取；仍然返回新的 ETag 以便客户端比较
+		h.c.Header("ETag", object.ETag)
+		if created {
+			h.c.Status(http.StatusCreated)
+			return
+		}
+		h.c.Status(http.StatusNoContent)
+	case http.MethodDelete:
+		if err := h.svc.Delete(ctx, h.userID, collection, name, h.c.GetHeader("If-Match")); err != nil {
+			writeCalDAVError(h.c, err)
+			return
+		}
+		h.c.Status(http.StatusNoContent)
+	default:
+		h.c.Status(http.StatusMethodNotAllowed)
+	}
+}
+
+// propfind 解析 PROPFIND 请求体，返回请求的属性名；allprop、propname 或空请求体时返回 nil 表示全部属性
+func (h *calDAVHandler) propfind() ([]xml.Name, bool) {
+	var req davPropfind
+	if err := xml.NewDecoder(h.c.Request.Body).Decode(&req); err != nil && err != io.EOF {
+		h.c.String(http.StatusBadRequest, "无效的 PROPFIND 请求体")
+		return nil, false
+	}
+	if req.Prop == nil {
+		return nil, true
+	}
+	return *req.Prop, true
+}
+
+// depth 返回 Depth 请求头，infinity 按 1 处理
+func (h *calDAVHandler) depth() int {
+	if h.c.GetHeader("Depth") == "0" {
+		return 0
+	}
+	return 1
+}
+
+func (h *calDAVHandler) homeHref() string {
+	return calDAVPrefix + url.PathEscape(h.username) + "/"
+}
+
+func (h *calDAVHandler) collectionHref(name string) string {
+	return h.homeHref() + url.PathEscape(name) + "/"
+}
+
+func (h *calDAVHandler) objectHref(collection, name string) string {
+	return h.collectionHref(collection) + url.PathEscape(name)
+}
+
+// homeProps 用户目录的属性，用户目录同时是用户主体和日历主目录
+func (h *calDAVHandler) homeProps() []davProp {
+	home := davHref(h.homeHref())
+	return []davProp{
+		{xml.Name{Space: davNS, Local: "resourcetype"}, "<d:collection/><d:principal/>"},
+		{xml.Name{Space: davNS, Local: "displayname"}, davText(h.username)},
+		{xml.Name{Space: davNS, Local: "current-user-principal"}, home},
+		{xml.Name{Space: davNS, Local: "principal-URL"}, home},
+		{xml.Name{Space: calDAVNS, Local: "calendar-home-set"}, home},
+	}
+}
+
+// collectionProps 日历集合的属性
+func (h *calDAVHandler) collectionProps(collection *caldav.Collection) []davProp {
+	home := davHref(h.homeHref())
+	return []davProp{
+		{xml.Name{Space: davNS, Local: "resourcetype"}, "<d:collection/><c:calendar/>"},
+		{xml.Name{Space: davNS, Local: "displayname"}, davText(collection.DisplayName)},
+		{xml.Name{Space: davNS, Local: "current-user-principal"}, home},
+		{xml.Name{Space: davNS, Local: "owner"}, home},
+		{xml.Name{Space: davNS, Local: "current-user-privilege-set"},
+			"<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>" +
+				"<d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege>" +
+				"<d:privilege><d:unbind/></d:privilege>"},
+		{xml.Name{Space: davNS, Local: "supported-report-set"},
+			"<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+				"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>"},
+		{xml.Name{Space: calDAVNS, Local: "supported-calendar-component-set"}, `<c:comp name="VTODO"/>`},
+		{xml.Name{Space: csNS, Local: "getctag"}, davText(collection.CTag)},
+	}
+}
+
+// objectProps 日历对象的属性，withData 为 true 时包含 calendar-data（用于 REPORT）
+func objectProps(object *caldav.Object, withData bool) []davProp {
+	props := []davProp{
+		{xml.Name{Space: davNS, Local: "resourcetype"}, ""},
+		{xml.Name{Space: davNS, Local: "getetag"}, davText(object.ETag)},
+		{xml.Name{Space: davNS, Local: "getcontenttype"}, calDAVObjectType},
+	}
+	if withData {
+		props = append(props, davProp{xml.Name{Space: calDAVNS, Local: "calendar-data"}, davText(string(object.Data))})
+	}
+	return props
+}
+
+// objectNameFromHref 从 calendar-multiget 中的地址取出资源名，不属于集合时返回空字符串
+// 地址可以是绝对URL，也可以是路径
+func objectNameFromHref(href, collectionHref string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	rest, ok := strings.CutPrefix(u.Path, collectionHref)
+	if !ok {
+		// 客户端可能对集合路径使用了不同的转义
+		collectionPath, err := url.PathUnescape(collectionHref)
+		if err != nil {
+			return ""
+		}
+		if rest, ok = strings.CutPrefix(u.Path, collectionPath); !ok {
+			return ""
+		}
+	}
+	if rest == "" || strings.Contains(rest, "/") {
+		return ""
+	}
+	return rest
+}
+
+// writeMultistatus 写出 207 Multi-Status 响应
+// requested 为空时返回资源的所有属性，否则找到的属性放在 200 propstat 中，其余放在 404 propstat 中
+func writeMultistatus(c *gin.Context, responses []davResponse, requested []xml.Name) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	b.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+	for _, r := range responses {
+		b.WriteString("<d:response>")
+		b.WriteString(davHref(r.href))
+		if r.status != 0 {
+			b.WriteString("<d:status>" + davStatus(r.status) + "</d:status></d:response>")
+			continue
+		}
+
+		found, missing := r.props, []xml.Name(nil)
+		if len(requested) > 0 {
+			found = nil
+			for _, name := range requested {
+				prop, ok := lookupProp(r.props, name)
+				if ok {
+					found = append(found, prop)
+				} else {
+					missing = append(missing, name)
+				}
+			}
+		}
+		if len(found) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, p := range found {
+				b.WriteString(davElement(p.name, p.inner))
+			}
+			b.WriteString("</d:prop><d:status>" + davStatus(http.StatusOK) + "</d:status></d:propstat>")
+		}
+		if len(missing) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, name := range missing {
+				b.WriteString(davElement(name, ""))
+			}
+			b.WriteString("</d:prop><d:status>" + davStatus(http.StatusNotFound) + "</d:status></d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	b.WriteString("</d:multistatus>")
+	c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", []byte(b.String()))
+}
+
+// lookupProp 在资源的属性中查找指定名称的属性
+func lookupProp(props []davProp, name xml.Name) (davProp, bool) {
+	for _, p := range props {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return davProp{}, false
+}
+
+// davElement 生成属性元素，已知命名空间使用固定前缀，其他命名空间在元素上声明
+func davElement(name xml.Name, inner string) string {
+	tag := name.Local
+	attrs := ""
+	if prefix, ok := davPrefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		tag = "x:" + name.Local
+		attrs = ` xmlns:x="` + davText(name.Space) + `"`
+	}
+	if inner == "" {
+		return "<" + tag + attrs + "/>"
+	}
+	return "<" + tag + attrs + ">" + inner + "</" + tag + ">"
+}
+
+// davHref 生成 href 元素
+func davHref(href string) string {
+	return "<d:href>" + davText(href) + "</d:href>"
+}
+
+// davText 转义 XML 文本
+func davText(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// davStatus 生成 multistatus 中的状态行
+func davStatus(code int) string {
+	return "HTTP/1.1 " + strconv.Itoa(code) + " " + http.StatusText(code)
+}
+
+// writeCalDAVError 将 CalDAV 相关的业务错误映射为HTTP状态码，响应体为纯文本
+func writeCalDAVError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrTodoNotFound, errors.ErrCategoryNotFound:
+		c.String(http.StatusNotFound, err.Error())
+	case errors.ErrPreconditionFailed:
+		c.String(http.StatusPreconditionFailed, err.Error())
+	case errors.ErrInvalidICalendar, errors.ErrInvalidParameter:
+		c.String(http.StatusBadRequest, err.Error())
+	case errors.ErrForbidden:
+		c.String(http.StatusForbidden, err.Error())
+	case errors.ErrTodoBlocked, errors.ErrWIPLimit:
+		c.String(http.StatusConflict, err.Error())
+	default:
+		logger.Error().Err(err).Str("path", c.Request.URL.Path).Msg("CalDAV 请求失败")
+		c.String(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"strings"
+	"todo/api/v1/dto/calendar"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/service"
+	"todo/pkg/errors"
+	"todo/pkg/logger"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// calendarContentType iCalendar 的内容类型
+const calendarContentType = "text/calendar; charset=utf-8"
+
+// ExportTodosICal 导出待办事项为 iCalendar
+// @Summary 导出待办事项为 iCalendar
+// @Description 按与列表接口相同的过滤参数导出所有匹配的待办事项（不分页），每个待办事项为一个 VTODO，其提醒为 VALARM，可以导入日历应用
+// @Tags 待办事项管理
+// @Produce text/calendar
+// @Param Authorization header string true "Bearer JWT"
+// @Param archived query string false "归档状态过滤：false（默认）、true、all"
+// @Param completed query bool false "完成状态过滤"
+// @Param q query string false "标题或描述中的关键字"
+// @Param category_id query int false "所属分类ID"
+// @Param include_descendants query bool false "是否包含所有下级分类中的待办事项"
+// @Param query query string false "查询语言表达的过滤条件"
+// @Param priority query string false "优先级：low、medium、high"
+// @Param due query string false "截止时间：overdue、today、this_week、none"
+// @Param status_id query int false "看板工作流状态ID"
+// @Param filter_id query int false "保存的过滤条件ID，指定时忽略其他过滤参数"
+// @Success 200 {file} file "iCalendar 文件"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "过滤条件或分类不存在"
+// @Router /todos/export.ics [get]
+func ExportTodosICal(calendarService service.CalendarService, filterService service.FilterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req todo.ListRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		userID := c.GetUint("userID")
+		if req.FilterID != nil {
+			saved, err := filterService.Request(c.Request.Context(), *req.FilterID, userID)
+			if err != nil {
+				writeTodoError(c, err)
+				return
+			}
+			req = *saved
+		}
+
+		c.Header("Content-Type", calendarContentType)
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "todos.ics"}))
+		if err := calendarService.Export(c.Request.Context(), userID, &req, c.Writer); err != nil {
+			// 已经开始写出时无法再返回错误响应，只能中断并记录日志
+			if c.Writer.Written() {
+				logger.Warn().Err(err).Uint("user_id", userID).Msg("导出 iCalendar 中断")
+				return
+			}
+			c.Writer.Header().Del("Content-Disposition")
+			writeTodoError(c, err)
+		}
+	}
+}
+
+// CalendarFeed 订阅日历
+// @Summary 订阅日历
+// @Description 日历客户端定期拉取的订阅地址，无需登录，地址中的令牌即为凭据。返回订阅者未归档的所有待办事项。
+// @Description 响应带有 ETag，请求携带匹配的 If-None-Match 时返回 304
+// @Tags 日历订阅
+// @Produce text/calendar
+// @Param token path string true "订阅令牌，后接 .ics"
+// @Param If-None-Match header string false "上次响应的 ETag"
+// @Success 200 {file} file "iCalendar 文件"
+// @Success 304 "内容没有变化"
+// @Failure 404 {object} response.Response "订阅不存在或已撤销"
+// @Router /ical/{token}.ics [get]
+func CalendarFeed(calendarService service.CalendarService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutSuffix(c.Param("file"), ".ics")
+		if !ok || token == "" {
+			c.JSON(http.StatusNotFound, response.Error(http.StatusNotFound, errors.ErrCalendarFeedNotFound.Error()))
+			return
+		}
+
+		// 需要完整内容才能计算 ETag，因此先写入缓冲区
+		var buf bytes.Buffer
+		if err := calendarService.Feed(c.Request.Context(), token, &buf); err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		sum := sha256.Sum256(buf.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		c.Header("ETag", etag)
+		c.Header("Cache-Control", "private, no-cache")
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+		c.Data(http.StatusOK, calendarContentType, buf.Bytes())
+	}
+}
+
+// GetCalendarFeed 获取日历订阅
+// @Summary 获取日历订阅
+// @Description 获取当前用户在当前工作空间中的日历订阅地址
+// @Tags 日历订阅
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 200 {object} response.Response{data=calendar.FeedResponse} "获取成功"
+// @Failure 404 {object} response.Response "尚未创建订阅"
+// @Router /calendar/feed [get]
+func GetCalendarFeed(calendarService service.CalendarService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		feed, err := calendarService.GetFeed(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(feedResponse(c, feed)))
+	}
+}
+
+// RotateCalendarFeed 生成日历订阅地址
+// @Summary 生成日历订阅地址
+// @Description 尚未订阅时创建订阅，否则重新生成令牌，旧的订阅地址立即失效
+// @Tags 日历订阅
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 200 {object} response.Response{data=calendar.FeedResponse} "生成成功"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /calendar/feed [post]
+func RotateCalendarFeed(calendarService service.CalendarService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		feed, err := calendarService.RotateFeed(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(feedResponse(c, feed)))
+	}
+}
+
+// RevokeCalendarFeed 撤销日历订阅
+// @Summary 撤销日历订阅
+// @Description 撤销后订阅地址立即失效
+// @Tags 日历订阅
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 200 {object} response.Response{data=calendar.RevokeResponse} "撤销成功"
+// @Failure 404 {object} response.Response "尚未创建订阅"
+// @Router /calendar/feed [delete]
+func RevokeCalendarFeed(calendarService service.CalendarService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := calendarService.RevokeFeed(c.Request.Context(), c.GetUint("userID")); err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(calendar.RevokeResponse{
+			Message: "Calendar feed revoked successfully",
+		}))
+	}
+}
+
+// feedResponse 生成日历订阅响应，订阅地址使用当前请求的协议和主机
+func feedResponse(c *gin.Context, feed *models.CalendarFeed) calendar.FeedResponse {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return calendar.FeedResponse{
+		URL:       scheme + "://" + c.Request.Host + "/api/v1/ical/" + feed.Token + ".ics",
+		Token:     feed.Token,
+		CreatedAt: feed.CreatedAt,
+		UpdatedAt: feed.UpdatedAt,
+	}
+}
+
+// etagMatches 判断 If-None-Match 请求头是否匹配 etag
+// 请求头可以是 *、逗号分隔的多个值，也可以带弱校验前缀 W/
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
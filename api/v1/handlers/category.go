@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"strconv"
 	"todo/api/v1/dto/category"
+	"todo/internal/models"
 	"todo/internal/service"
+	"todo/pkg/errors"
 	"todo/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -50,18 +52,31 @@ func CreateCategory(categoryService service.CategoryService) gin.HandlerFunc {
 
 // ListCategories 获取分类列表
 // @Summary 获取分类列表
-// @Description 获取当前用户的所有分类
+// @Description 获取当前用户的所有分类；tree=true 时以树形结构返回，Items 只包含顶级分类
 // @Tags 分类管理
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer JWT"
+// @Param tree query bool false "是否以树形结构返回"
 // @Success 200 {object} response.Response{data=category.ListResponse} "获取成功"
 // @Failure 401 {object} response.Response "未授权访问"
 // @Router /categories [get]
 func ListCategories(categoryService service.CategoryService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		var req category.ListRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
 		userID := c.GetUint("userID")
-		categories, err := categoryService.List(c.Request.Context(), userID)
+		var categories []*models.Category
+		var err error
+		if req.Tree {
+			categories, err = categoryService.Tree(c.Request.Context(), userID)
+		} else {
+			categories, err = categoryService.List(c.Request.Context(), userID)
+		}
 		if err != nil {
 			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
 			return
@@ -120,13 +135,14 @@ func UpdateCategory(categoryService service.CategoryService) gin.HandlerFunc {
 
 // DeleteCategory 删除分类
 // @Summary 删除分类
-// @Description 删除指定的分类
+// @Description 删除指定的分类，并在同一事务中按策略处理其下的待办事项
 // @Tags 分类管理
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer JWT"
 // @Param id path int true "分类ID"
-// @Success 200 {object} response.Response{data=category.UpdateResponse} "删除成功"
+// @Param strategy query string false "待办事项处理策略：uncategorize（默认）、move_to=<分类ID>、delete_todos"
+// @Success 200 {object} response.Response{data=category.DeleteResponse} "删除成功"
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "未授权访问"
 // @Router /categories/{id} [delete]
@@ -138,14 +154,81 @@ func DeleteCategory(categoryService service.CategoryService) gin.HandlerFunc {
 			return
 		}
 
+		var req category.DeleteRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
 		userID := c.GetUint("userID")
-		if err := categoryService.Delete(c.Request.Context(), uint(id), userID); err != nil {
-			c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+		affected, err := categoryService.Delete(c.Request.Context(), uint(id), userID, &req)
+		if err != nil {
+			writeCategoryError(c, err)
 			return
 		}
 
-		c.JSON(http.StatusOK, response.Success(category.UpdateResponse{
-			Message: "Category deleted successfully",
+		c.JSON(http.StatusOK, response.Success(category.DeleteResponse{
+			Message:  "Category deleted successfully",
+			Affected: affected,
 		}))
 	}
 }
+
+// MoveCategory 移动分类
+// @Summary 移动分类
+// @Description 将分类连同其所有下级分类移动到新的上级分类下，parentId 为 null 时移动到顶级
+// @Tags 分类管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "分类ID"
+// @Param request body category.MoveRequest true "新的上级分类"
+// @Success 200 {object} response.Response{data=models.Category} "移动成功"
+// @Failure 400 {object} response.Response "请求参数错误或形成循环"
+// @Failure 404 {object} response.Response "分类不存在"
+// @Router /categories/{id}/move [post]
+func MoveCategory(categoryService service.CategoryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		var req category.MoveRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		userID := c.GetUint("userID")
+		if err := categoryService.Move(c.Request.Context(), uint(id), userID, req.ParentID); err != nil {
+			writeCategoryError(c, err)
+			return
+		}
+
+		moved, err := categoryService.Get(c.Request.Context(), uint(id), userID)
+		if err != nil {
+			writeCategoryError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(moved))
+	}
+}
+
+// writeCategoryError 将分类相关的业务错误映射为HTTP状态码
+func writeCategoryError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrInvalidParameter:
+		c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid delete strategy"))
+	case errors.ErrCategoryCycle:
+		c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, response.Error(http.StatusForbidden, err.Error()))
+	case errors.ErrCategoryNotFound:
+		c.JSON(http.StatusNotFound, response.Error(http.StatusNotFound, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/comment"
+	"todo/internal/service"
+	"todo/pkg/errors"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateComment 发表评论
+// @Summary 发表评论
+// @Description 在待办事项下发表 Markdown 格式的评论，正文中的 @用户名 会通知对应的工作空间成员
+// @Tags 评论管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param request body comment.CreateRequest true "评论内容"
+// @Success 200 {object} response.Response{data=models.Comment} "发表成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /todos/{id}/comments [post]
+func CreateComment(commentService service.CommentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req comment.CreateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		created, err := commentService.Create(c.Request.Context(), c.GetUint("userID"), uint(todoID), &req)
+		if err != nil {
+			writeCommentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(created))
+	}
+}
+
+// ListComments 获取评论列表
+// @Summary 获取评论列表
+// @Description 获取待办事项下的所有评论，按创建时间倒序
+// @Tags 评论管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=comment.ListResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /todos/{id}/comments [get]
+func ListComments(commentService service.CommentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		comments, err := commentService.List(c.Request.Context(), c.GetUint("userID"), uint(todoID))
+		if err != nil {
+			writeCommentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(comment.ListResponse{
+			Total: int64(len(comments)),
+			Items: comments,
+		}))
+	}
+}
+
+// UpdateComment 编辑评论
+// @Summary 编辑评论
+// @Description 编辑自己发表的评论，旧内容会保存在编辑历史中
+// @Tags 评论管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param comment_id path int true "评论ID"
+// @Param request body comment.UpdateRequest true "新的评论内容"
+// @Success 200 {object} response.Response{data=models.Comment} "编辑成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 403 {object} response.Response "只能编辑自己的评论"
+// @Router /todos/{id}/comments/{comment_id} [put]
+func UpdateComment(commentService service.CommentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req comment.UpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		todoID, commentID, ok := parseCommentPath(c)
+		if !ok {
+			return
+		}
+
+		updated, err := commentService.Update(c.Request.Context(), c.GetUint("userID"), todoID, commentID, &req)
+		if err != nil {
+			writeCommentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(updated))
+	}
+}
+
+// DeleteComment 删除评论
+// @Summary 删除评论
+// @Description 删除自己发表的评论
+// @Tags 评论管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param comment_id path int true "评论ID"
+// @Success 200 {object} response.Response{data=comment.DeleteResponse} "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 403 {object} response.Response "只能删除自己的评论"
+// @Router /todos/{id}/comments/{comment_id} [delete]
+func DeleteComment(commentService service.CommentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, commentID, ok := parseCommentPath(c)
+		if !ok {
+			return
+		}
+
+		if err := commentService.Delete(c.Request.Context(), c.GetUint("userID"), todoID, commentID); err != nil {
+			writeCommentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(comment.DeleteResponse{
+			Message: "评论已删除",
+		}))
+	}
+}
+
+// GetCommentHistory 获取评论编辑历史
+// @Summary 获取评论编辑历史
+// @Description 获取评论每次编辑前的内容，按时间正序
+// @Tags 评论管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param comment_id path int true "评论ID"
+// @Success 200 {object} response.Response{data=comment.HistoryResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /todos/{id}/comments/{comment_id}/history [get]
+func GetCommentHistory(commentService service.CommentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, commentID, ok := parseCommentPath(c)
+		if !ok {
+			return
+		}
+
+		revisions, err := commentService.History(c.Request.Context(), c.GetUint("userID"), todoID, commentID)
+		if err != nil {
+			writeCommentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(comment.HistoryResponse{
+			Total: int64(len(revisions)),
+			Items: revisions,
+		}))
+	}
+}
+
+// parseCommentPath 解析路径中的待办事项ID和评论ID，解析失败时直接写入错误响应
+func parseCommentPath(c *gin.Context) (uint, uint, bool) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+		return 0, 0, false
+	}
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid comment ID"))
+		return 0, 0, false
+	}
+	return uint(todoID), uint(commentID), true
+}
+
+// writeCommentError 将评论相关的业务错误映射为HTTP状态码
+func writeCommentError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, response.Error(http.StatusForbidden, err.Error()))
+	case errors.ErrTodoNotFound, errors.ErrCommentNotFound:
+		c.JSON(http.StatusNotFound, response.Error(http.StatusNotFound, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"todo/api/v1/dto/todo"
+	"todo/internal/service"
+	"todo/pkg/logger"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize 导入时上传内容（包括 multipart 边界和表单头部）的最大字节数
+const maxImportSize = 32 << 20
+
+// ExportTodosCSV 导出待办事项为 CSV
+// @Summary 导出待办事项为 CSV
+// @Description 按与列表接口相同的过滤参数导出所有匹配的待办事项（不分页），边读取边写出。
+// @Description 列依次为 title、description、priority、completed、due_date、category、tags（以 ; 分隔）、estimate、created_at、completed_at，导出的文件可以直接导入
+// @Tags 待办事项管理
+// @Produce text/csv
+// @Param Authorization header string true "Bearer JWT"
+// @Param archived query string false "归档状态过滤：false（默认）、true、all"
+// @Param completed query bool false "完成状态过滤"
+// @Param q query string false "标题或描述中的关键字"
+// @Param category_id query int false "所属分类ID"
+// @Param include_descendants query bool false "是否包含所有下级分类中的待办事项"
+// @Param query query string false "查询语言表达的过滤条件"
+// @Param priority query string false "优先级：low、medium、high"
+// @Param due query string false "截止时间：overdue、today、this_week、none"
+// @Param status_id query int false "看板工作流状态ID"
+// @Param sort query string false "排序方式：position 按手动排序位置，默认按创建顺序"
+// @Param filter_id query int false "保存的过滤条件ID，指定时忽略其他过滤参数"
+// @Success 200 {file} file "CSV 文件"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "过滤条件或分类不存在"
+// @Router /todos/export.csv [get]
+func ExportTodosCSV(todoService service.TodoService, filterService service.FilterService) gin.HandlerFunc {
+	return exportTodos(filterService, "text/csv; charset=utf-8", "todos.csv", todoService.ExportCSV)
+}
+
+// ExportTodosTxt 导出待办事项为 todo.txt
+// @Summary 导出待办事项为 todo.txt
+// @Description 按与列表接口相同的过滤参数导出所有匹配的待办事项（不分页），每行一个待办事项。
+// @Description 优先级 high、medium、low 写作 (A)、(B)、(C)，分类写作 +分类，标签写作 @标签（空白替换为下划线），截止时间写作 due:2006-01-02。
+// @Description todo.txt 没有描述字段，描述不会导出；导出的文件可以直接以 format=todotxt 导入
+// @Tags 待办事项管理
+// @Produce text/plain
+// @Param Authorization header string true "Bearer JWT"
+// @Param archived query string false "归档状态过滤：false（默认）、true、all"
+// @Param completed query bool false "完成状态过滤"
+// @Param q query string false "标题或描述中的关键字"
+// @Param category_id query int false "所属分类ID"
+// @Param include_descendants query bool false "是否包含所有下级分类中的待办事项"
+// @Param query query string false "查询语言表达的过滤条件"
+// @Param priority query string false "优先级：low、medium、high"
+// @Param due query string false "截止时间：overdue、today、this_week、none"
+// @Param status_id query int false "看板工作流状态ID"
+// @Param sort query string false "排序方式：position 按手动排序位置，默认按创建顺序"
+// @Param filter_id query int false "保存的过滤条件ID，指定时忽略其他过滤参数"
+// @Success 200 {file} file "todo.txt 文件"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "过滤条件或分类不存在"
+// @Router /todos/export.txt [get]
+func ExportTodosTxt(todoService service.TodoService, filterService service.FilterService) gin.HandlerFunc {
+	return exportTodos(filterService, "text/plain; charset=utf-8", "todo.txt", todoService.ExportTodoTxt)
+}
+
+// ExportTodosMarkdown 导出待办事项为 Markdown 任务列表
+// @Summary 导出待办事项为 Markdown 任务列表
+// @Description 按与列表接口相同的过滤参数导出所有匹配的待办事项（不分页），格式为 GitHub 风格的任务列表（- [ ] / - [x]）。
+// @Description 未分类的待办事项在最前，其余每个分类一个二级标题；描述缩进写在任务下方，标签写作 #标签，
+// @Description 高、低优先级写作 ⏫、🔽，截止时间写作 📅 2006-01-02。导出的文件可以直接以 format=markdown 导入
+// @Tags 待办事项管理
+// @Produce text/markdown
+// @Param Authorization header string true "Bearer JWT"
+// @Param archived query string false "归档状态过滤：false（默认）、true、all"
+// @Param completed query bool false "完成状态过滤"
+// @Param q query string false "标题或描述中的关键字"
+// @Param category_id query int false "所属分类ID"
+// @Param include_descendants query bool false "是否包含所有下级分类中的待办事项"
+// @Param query query string false "查询语言表达的过滤条件"
+// @Param priority query string false "优先级：low、medium、high"
+// @Param due query string false "截止时间：overdue、today、this_week、none"
+// @Param status_id query int false "看板工作流状态ID"
+// @Param sort query string false "排序方式：position 按手动排序位置，默认按创建顺序"
+// @Param filter_id query int false "保存的过滤条件ID，指定时忽略其他过滤参数"
+// @Success 200 {file} file "Markdown 文件"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "过滤条件或分类不存在"
+// @Router /todos/export.md [get]
+func ExportTodosMarkdown(todoService service.TodoService, filterService service.FilterService) gin.HandlerFunc {
+	return exportTodos(filterService, "text/markdown; charset=utf-8", "todos.md", todoService.ExportMarkdown)
+}
+
+// exportTodos 解析列表查询参数（或保存的过滤条件）并以附件形式流式写出导出结果
+func exportTodos(filterService service.FilterService, contentType, filename string,
+	export func(ctx context.Context, userID uint, req *todo.ListRequest, w io.Writer) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req todo.ListRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		userID := c.GetUint("userID")
+		if req.FilterID != nil {
+			saved, err := filterService.Request(c.Request.Context(), *req.FilterID, userID)
+			if err != nil {
+				writeTodoError(c, err)
+				return
+			}
+			saved.Sort = req.Sort
+			req = *saved
+		}
+
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+		if err := export(c.Request.Context(), userID, &req, c.Writer); err != nil {
+			// 已经开始写出时无法再返回错误响应，只能中断并记录日志
+			if c.Writer.Written() {
+				logger.Warn().Err(err).Uint("user_id", userID).Str("file", filename).Msg("导出中断")
+				return
+			}
+			c.Writer.Header().Del("Content-Disposition")
+			writeTodoError(c, err)
+		}
+	}
+}
+
+// ImportTodos 导入待办事项
+// @Summary 从 CSV、todo.txt 或 Markdown 导入待办事项
+// @Description 以请求体或 multipart 表单的 file 字段上传文件，逐行读取而不缓存整个文件；format 指定文件格式，默认为 CSV。
+// @Description CSV 第一行为表头，category 列按名称匹配分类，不存在时自动新建；todo.txt 的第一个 +project 作为分类，@context 作为标签；
+// @Description Markdown 中二级及以下标题作为分类，任务下方缩进的文字作为描述，#标签、⏫/🔽 优先级和 📅 截止时间被解析到对应字段。
+// @Description 每个待办事项单独创建，校验失败的行被跳过并在结果中列出。dry_run=true 时只校验不创建
+// @Tags 待办事项管理
+// @Accept text/csv
+// @Accept text/plain
+// @Accept text/markdown
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param format query string false "文件格式：csv（默认）、todotxt、markdown"
+// @Param dry_run query bool false "只校验不创建"
+// @Param mapping query string false "CSV 表头映射，例如 Task=title,Due=due_date"
+// @Param file formData file false "上传的文件（multipart 上传时）"
+// @Success 200 {object} response.Response{data=todo.ImportResponse} "导入完成"
+// @Failure 400 {object} response.Response "参数错误，或 CSV 缺少表头、没有 title 列、映射无效"
+// @Failure 413 {object} response.Response "上传内容过大"
+// @Router /todos/import [post]
+func ImportTodos(todoService service.TodoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req todo.ImportRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		body, err := uploadReader(c)
+		if err != nil {
+			if !uploadTooLarge(c, err) {
+				c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "缺少上传文件"))
+			}
+			return
+		}
+
+		ctx, userID := c.Request.Context(), c.GetUint("userID")
+		var result *todo.ImportResponse
+		switch req.Format {
+		case todo.ImportFormatTodoTxt:
+			result, err = todoService.ImportTodoTxt(ctx, userID, body, &req)
+		case todo.ImportFormatMarkdown:
+			result, err = todoService.ImportMarkdown(ctx, userID, body, &req)
+		default:
+			result, err = todoService.ImportCSV(ctx, userID, body, &req)
+		}
+		if err != nil {
+			if !uploadTooLarge(c, err) {
+				writeTodoError(c, err)
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(result))
+	}
+}
+
+// uploadReader 返回上传内容的读取器：multipart 请求取 file 字段，否则直接读取请求体
+// 两种方式都以流的形式读取，不会把整个文件缓存到内存或临时文件；请求体超过 maxImportSize 时读取返回 *http.MaxBytesError
+func uploadReader(c *gin.Context) (io.Reader, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		return c.Request.Body, nil
+	}
+	mr, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+	}
+}
+
+// uploadTooLarge 在上传内容超过 maxImportSize 时返回 413 并返回 true，其他错误返回 false 交给调用方处理
+func uploadTooLarge(c *gin.Context, err error) bool {
+	var tooLarge *http.MaxBytesError
+	if !stderrors.As(err, &tooLarge) {
+		return false
+	}
+	c.JSON(http.StatusRequestEntityTooLarge, response.Error(http.StatusRequestEntityTooLarge, "上传内容过大"))
+	return true
+}
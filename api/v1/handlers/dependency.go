@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/dependency"
+	"todo/internal/service"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddDependency 添加阻塞项
+// @Summary 添加阻塞项
+// @Description 声明当前待办事项被另一个待办事项阻塞：阻塞项完成之前当前待办事项不能标记为已完成。依赖关系不能形成环
+// @Tags 依赖关系管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "被阻塞的待办事项ID"
+// @Param request body dependency.AddRequest true "阻塞项"
+// @Success 200 {object} response.Response{data=models.Dependency} "添加成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "待办事项不存在"
+// @Failure 409 {object} response.Response "依赖关系已存在或会形成环"
+// @Router /todos/{id}/dependencies [post]
+func AddDependency(dependencyService service.DependencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		var req dependency.AddRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		dep, err := dependencyService.Add(c.Request.Context(), c.GetUint("userID"), uint(todoID), req.BlockerID)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(dep))
+	}
+}
+
+// ListDependencies 获取依赖关系
+// @Summary 获取依赖关系
+// @Description 获取阻塞当前待办事项的待办事项、被它阻塞的待办事项，以及它当前是否被阻塞
+// @Tags 依赖关系管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=dependency.ListResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "待办事项不存在"
+// @Router /todos/{id}/dependencies [get]
+func ListDependencies(dependencyService service.DependencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		deps, err := dependencyService.List(c.Request.Context(), c.GetUint("userID"), uint(todoID))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(deps))
+	}
+}
+
+// RemoveDependency 移除阻塞项
+// @Summary 移除阻塞项
+// @Description 移除阻塞关系；待办事项因此不再被阻塞时通知其所有者
+// @Tags 依赖关系管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "被阻塞的待办事项ID"
+// @Param blocker_id path int true "阻塞项的待办事项ID"
+// @Success 200 {object} response.Response{data=dependency.DeleteResponse} "移除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "依赖关系不存在"
+// @Router /todos/{id}/dependencies/{blocker_id} [delete]
+func RemoveDependency(dependencyService service.DependencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+		blockerID, err := strconv.ParseUint(c.Param("blocker_id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid blocker ID"))
+			return
+		}
+
+		if err := dependencyService.Remove(c.Request.Context(), c.GetUint("userID"), uint(todoID), uint(blockerID)); err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(dependency.DeleteResponse{
+			Message: "Dependency removed successfully",
+		}))
+	}
+}
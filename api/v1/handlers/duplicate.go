@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/todo"
+	"todo/internal/service"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DuplicateTodo 复制待办事项
+// @Summary 复制待办事项
+// @Description 复制待办事项及其提醒、分类、标签和预估耗时，副本为未完成状态并排在所在分类的末尾。
+// @Description shiftMinutes 可以把副本的截止时间和提醒时间整体平移；依赖关系、评论、附件和时间记录不会复制
+// @Tags 待办事项管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param request body todo.DuplicateRequest false "时间平移量"
+// @Success 200 {object} response.Response{data=models.Todo} "复制成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 403 {object} response.Response "无权访问"
+// @Failure 404 {object} response.Response "待办事项不存在"
+// @Failure 409 {object} response.Response "工作流状态已达到在制品上限"
+// @Router /todos/{id}/duplicate [post]
+func DuplicateTodo(todoService service.TodoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		// 请求体可以为空
+		var req todo.DuplicateRequest
+		if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		copied, err := todoService.Duplicate(c.Request.Context(), uint(id), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(copied))
+	}
+}
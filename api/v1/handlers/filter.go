@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/filter"
+	"todo/internal/service"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateFilter 保存过滤条件
+// @Summary 保存过滤条件
+// @Description 为常用的列表查询命名保存，之后可以通过 GET /todos?filter_id= 直接使用
+// @Tags 过滤条件管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param request body filter.CreateRequest true "名称和过滤条件"
+// @Success 200 {object} response.Response{data=models.SavedFilter} "保存成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "分类不存在"
+// @Router /filters [post]
+func CreateFilter(filterService service.FilterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req filter.CreateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		saved, err := filterService.Create(c.Request.Context(), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(saved))
+	}
+}
+
+// ListFilters 获取保存的过滤条件
+// @Summary 获取保存的过滤条件
+// @Description 获取当前用户保存的过滤条件，每个条件附带当前匹配的待办事项数量
+// @Tags 过滤条件管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 200 {object} response.Response{data=filter.ListResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /filters [get]
+func ListFilters(filterService service.FilterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := filterService.List(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(filter.ListResponse{Items: items}))
+	}
+}
+
+// UpdateFilter 更新过滤条件
+// @Summary 更新过滤条件
+// @Description 更新过滤条件的名称或条件，条件整体替换
+// @Tags 过滤条件管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "过滤条件ID"
+// @Param request body filter.UpdateRequest true "更新内容"
+// @Success 200 {object} response.Response{data=models.SavedFilter} "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "过滤条件不存在"
+// @Router /filters/{id} [put]
+func UpdateFilter(filterService service.FilterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		var req filter.UpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		saved, err := filterService.Update(c.Request.Context(), uint(id), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(saved))
+	}
+}
+
+// DeleteFilter 删除过滤条件
+// @Summary 删除过滤条件
+// @Description 删除保存的过滤条件，不影响待办事项
+// @Tags 过滤条件管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "过滤条件ID"
+// @Success 200 {object} response.Response{data=filter.DeleteResponse} "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "过滤条件不存在"
+// @Router /filters/{id} [delete]
+func DeleteFilter(filterService service.FilterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		if err := filterService.Delete(c.Request.Context(), uint(id), c.GetUint("userID")); err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(filter.DeleteResponse{
+			Message: "Filter deleted successfully",
+		}))
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/history"
+	"todo/internal/service"
+	"todo/pkg/errors"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTodoHistory 获取待办事项变更历史
+// @Summary 获取待办事项变更历史
+// @Description 获取待办事项每次创建、修改、删除和回滚的字段级变更记录，按时间倒序
+// @Tags 待办事项管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=history.ListResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /todos/{id}/history [get]
+func GetTodoHistory(todoService service.TodoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		entries, err := todoService.History(c.Request.Context(), uint(id), c.GetUint("userID"))
+		if err != nil {
+			writeHistoryError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(history.ListResponse{
+			Total: int64(len(entries)),
+			Items: entries,
+		}))
+	}
+}
+
+// RevertTodo 回滚待办事项
+// @Summary 回滚待办事项
+// @Description 将待办事项恢复到指定变更记录完成后的版本，回滚本身也会记录到变更历史
+// @Tags 待办事项管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param change_id path int true "变更记录ID"
+// @Success 200 {object} response.Response{data=models.Todo} "回滚成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "变更记录不存在"
+// @Router /todos/{id}/history/{change_id}/revert [post]
+func RevertTodo(todoService service.TodoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+		changeID, err := strconv.ParseUint(c.Param("change_id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid change ID"))
+			return
+		}
+
+		userID := c.GetUint("userID")
+		if err := todoService.Revert(c.Request.Context(), uint(id), userID, uint(changeID)); err != nil {
+			writeHistoryError(c, err)
+			return
+		}
+
+		todoItem, err := todoService.Get(c.Request.Context(), uint(id), userID)
+		if err != nil {
+			writeHistoryError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(todoItem))
+	}
+}
+
+// writeHistoryError 将变更历史相关的业务错误映射为HTTP状态码
+func writeHistoryError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, response.Error(http.StatusForbidden, err.Error()))
+	case errors.ErrTodoNotFound, errors.ErrChangeNotFound:
+		c.JSON(http.StatusNotFound, response.Error(http.StatusNotFound, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/todo"
+	"todo/internal/service"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MoveTodo 拖动待办事项
+// @Summary 拖动待办事项
+// @Description 将待办事项移动到同一分类中另一个待办事项之前或之后。before 和 after 至少提供一个，同时提供时两者必须相邻。
+// @Description 使用 sort=position 获取列表即可得到手动排序后的顺序
+// @Tags 待办事项管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param request body todo.MoveRequest true "位置锚点"
+// @Success 200 {object} response.Response{data=models.Todo} "移动成功"
+// @Failure 400 {object} response.Response "请求参数错误或锚点不在同一分类"
+// @Failure 403 {object} response.Response "无权访问"
+// @Failure 404 {object} response.Response "待办事项不存在"
+// @Router /todos/{id}/move [post]
+func MoveTodo(todoService service.TodoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req todo.MoveRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		userID := c.GetUint("userID")
+		if err := todoService.Move(c.Request.Context(), uint(id), userID, &req); err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		movedTodo, err := todoService.Get(c.Request.Context(), uint(id), userID)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, response.Success(movedTodo))
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"todo/api/v1/dto/todo"
+	"todo/internal/service"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuickAddTodo 用一行自然语言文本快速添加待办事项
+// @Summary 快速添加待办事项
+// @Description 解析如 "Call Bob tomorrow 3pm !high #work remind 30m before" 或 "明天下午3点给老王打电话 !高 #工作 提前半小时提醒" 的文本，
+// @Description 识别出标题、截止时间（按 timezone 计算今天、明天等相对日期）、优先级、分类、标签和提前提醒。
+// @Description 第一个与现有分类同名的 #标签作为分类，其余作为标签。dryRun 为 true 时只返回解析结果供用户确认，不创建待办事项
+// @Tags 待办事项管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param request body todo.QuickAddRequest true "文本"
+// @Success 200 {object} response.Response{data=todo.QuickAddResponse} "解析结果"
+// @Failure 400 {object} response.Response "文本或时区无效"
+// @Failure 409 {object} response.Response "工作流状态已达到在制品上限"
+// @Router /todos/quick [post]
+func QuickAddTodo(todoService service.TodoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req todo.QuickAddRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		resp, err := todoService.QuickAdd(c.Request.Context(), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(resp))
+	}
+}
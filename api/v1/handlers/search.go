@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"todo/api/v1/dto/search"
+	"todo/internal/service"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Search 全文搜索待办事项
+// @Summary 全文搜索待办事项
+// @Description 在标题、描述和评论中搜索，按相关度降序返回，附带命中位置的高亮摘要；过滤参数与待办事项列表一致
+// @Tags 待办事项管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param q query string true "搜索文本"
+// @Param archived query string false "归档状态：false（默认）、true、all"
+// @Param completed query bool false "完成状态"
+// @Param category_id query int false "分类ID"
+// @Param include_descendants query bool false "是否包含下级分类"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} response.Response{data=search.Response} "搜索成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /search [get]
+func Search(searchService service.SearchService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req search.Request
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		result, err := searchService.Search(c.Request.Context(), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(result))
+	}
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/status"
+	"todo/internal/service"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateStatus 创建看板工作流状态
+// @Summary 创建工作流状态
+// @Description 创建看板中的一列，新状态排在最右侧。移入终止状态的待办事项自动标记为已完成；wipLimit 大于 0 时限制其中未归档的待办事项数量
+// @Tags 看板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param request body status.CreateRequest true "状态名、是否为终止状态和在制品上限"
+// @Success 200 {object} response.Response{data=models.Status} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 409 {object} response.Response "同名状态已存在"
+// @Router /statuses [post]
+func CreateStatus(statusService service.StatusService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req status.CreateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		created, err := statusService.Create(c.Request.Context(), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(created))
+	}
+}
+
+// ListStatuses 获取看板工作流状态
+// @Summary 获取工作流状态
+// @Description 获取当前用户的工作流状态，按看板顺序排列
+// @Tags 看板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 200 {object} response.Response{data=status.ListResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /statuses [get]
+func ListStatuses(statusService service.StatusService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := statusService.List(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(status.ListResponse{Items: items}))
+	}
+}
+
+// UpdateStatus 更新看板工作流状态
+// @Summary 更新工作流状态
+// @Description 更新状态名、是否为终止状态或在制品上限。修改是否为终止状态时，其中所有待办事项的完成状态随之同步；调低在制品上限不影响已有的待办事项
+// @Tags 看板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "工作流状态ID"
+// @Param request body status.UpdateRequest true "更新内容"
+// @Success 200 {object} response.Response{data=models.Status} "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "工作流状态不存在"
+// @Failure 409 {object} response.Response "同名状态已存在"
+// @Router /statuses/{id} [put]
+func UpdateStatus(statusService service.StatusService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		var req status.UpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		updated, err := statusService.Update(c.Request.Context(), uint(id), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(updated))
+	}
+}
+
+// ReorderStatuses 调整看板列顺序
+// @Summary 调整看板列顺序
+// @Description 按给定顺序重新排列工作流状态，ids 必须恰好包含当前用户的所有状态
+// @Tags 看板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param request body status.ReorderRequest true "新的状态顺序"
+// @Success 200 {object} response.Response{data=status.ListResponse} "调整成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Router /statuses/order [put]
+func ReorderStatuses(statusService service.StatusService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req status.ReorderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		items, err := statusService.Reorder(c.Request.Context(), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(status.ListResponse{Items: items}))
+	}
+}
+
+// DeleteStatus 删除看板工作流状态
+// @Summary 删除工作流状态
+// @Description 删除工作流状态。指定 move_to 时其中的待办事项移动到该状态（完成状态随之同步），否则清除它们的状态
+// @Tags 看板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "工作流状态ID"
+// @Param move_to query int false "其中待办事项移动到的状态ID"
+// @Success 200 {object} response.Response{data=status.DeleteResponse} "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "工作流状态不存在"
+// @Failure 409 {object} response.Response "目标状态已达到在制品上限"
+// @Router /statuses/{id} [delete]
+func DeleteStatus(statusService service.StatusService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		var req status.DeleteRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		affected, err := statusService.Delete(c.Request.Context(), uint(id), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(status.DeleteResponse{
+			Message:  "Status deleted successfully",
+			Affected: affected,
+		}))
+	}
+}
+
+// GetBoard 获取看板
+// @Summary 获取看板
+// @Description 按工作流状态分列返回未归档的待办事项，列内按手动排序位置排列；未设置状态的待办事项排在最左侧状态为空的一列。
+// @Description 设置了在制品上限的列返回计入上限的数量以及是否超限
+// @Tags 看板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param category_id query int false "只显示该分类中的待办事项"
+// @Param include_descendants query bool false "是否包含所有下级分类中的待办事项"
+// @Success 200 {object} response.Response{data=status.BoardResponse} "获取成功"
+// @Failure 404 {object} response.Response "分类不存在"
+// @Router /board [get]
+func GetBoard(statusService service.StatusService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req status.BoardRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		board, err := statusService.Board(c.Request.Context(), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(board))
+	}
+}
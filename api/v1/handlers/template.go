@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/template"
+	"todo/internal/service"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateTemplate 创建模板
+// @Summary 创建模板
+// @Description 保存一组经常重复出现的待办事项，时间以相对基准时间的偏移（分钟）表示，子任务实例化为阻塞父任务的待办事项
+// @Tags 模板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param request body template.CreateRequest true "模板"
+// @Success 200 {object} response.Response{data=models.Template} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误或待办事项超过上限"
+// @Failure 404 {object} response.Response "分类不存在"
+// @Router /templates [post]
+func CreateTemplate(templateService service.TemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req template.CreateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		tmpl, err := templateService.Create(c.Request.Context(), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(tmpl))
+	}
+}
+
+// ListTemplates 获取模板列表
+// @Summary 获取模板列表
+// @Description 获取当前用户的模板，按名称排列
+// @Tags 模板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 200 {object} response.Response{data=template.ListResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /templates [get]
+func ListTemplates(templateService service.TemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := templateService.List(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(template.ListResponse{Items: items}))
+	}
+}
+
+// GetTemplate 获取模板详情
+// @Summary 获取模板详情
+// @Tags 模板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "模板ID"
+// @Success 200 {object} response.Response{data=models.Template} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "模板不存在"
+// @Router /templates/{id} [get]
+func GetTemplate(templateService service.TemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		tmpl, err := templateService.Get(c.Request.Context(), uint(id), c.GetUint("userID"))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(tmpl))
+	}
+}
+
+// UpdateTemplate 更新模板
+// @Summary 更新模板
+// @Description 更新模板的名称、说明或待办事项，待办事项整体替换；已经创建的待办事项不受影响
+// @Tags 模板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "模板ID"
+// @Param request body template.UpdateRequest true "更新内容"
+// @Success 200 {object} response.Response{data=models.Template} "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误或待办事项超过上限"
+// @Failure 404 {object} response.Response "模板不存在"
+// @Router /templates/{id} [put]
+func UpdateTemplate(templateService service.TemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		var req template.UpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		tmpl, err := templateService.Update(c.Request.Context(), uint(id), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(tmpl))
+	}
+}
+
+// DeleteTemplate 删除模板
+// @Summary 删除模板
+// @Description 删除模板，已经创建的待办事项不受影响
+// @Tags 模板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "模板ID"
+// @Success 200 {object} response.Response{data=template.DeleteResponse} "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "模板不存在"
+// @Router /templates/{id} [delete]
+func DeleteTemplate(templateService service.TemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		if err := templateService.Delete(c.Request.Context(), uint(id), c.GetUint("userID")); err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(template.DeleteResponse{
+			Message: "Template deleted successfully",
+		}))
+	}
+}
+
+// InstantiateTemplate 实例化模板
+// @Summary 实例化模板
+// @Description 以基准时间推算截止时间和提醒时间，在一个事务中创建模板中的所有待办事项；任何一个创建失败时全部回滚
+// @Tags 模板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "模板ID"
+// @Param request body template.InstantiateRequest true "基准时间"
+// @Success 200 {object} response.Response{data=template.InstantiateResponse} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "模板或其引用的分类不存在"
+// @Router /templates/{id}/instantiate [post]
+func InstantiateTemplate(templateService service.TemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		var req template.InstantiateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		ids, err := templateService.Instantiate(c.Request.Context(), uint(id), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(template.InstantiateResponse{IDs: ids}))
+	}
+}
+
+// SaveTodoAsTemplate 将待办事项保存为模板
+// @Summary 将待办事项保存为模板
+// @Description 将待办事项保存为模板，它的阻塞项（递归）保存为子任务，截止时间和提醒换算为相对基准时间的偏移
+// @Tags 模板管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param request body template.FromTodoRequest true "模板名称和基准时间"
+// @Success 200 {object} response.Response{data=models.Template} "保存成功"
+// @Failure 400 {object} response.Response "请求参数错误或待办事项超过上限"
+// @Failure 404 {object} response.Response "待办事项不存在"
+// @Router /todos/{id}/template [post]
+func SaveTodoAsTemplate(templateService service.TemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		var req template.FromTodoRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		tmpl, err := templateService.FromTodo(c.Request.Context(), c.GetUint("userID"), uint(id), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(tmpl))
+	}
+}
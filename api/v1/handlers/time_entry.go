@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/timeentry"
+	"todo/internal/service"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StartTimer 启动计时器
+// @Summary 启动计时器
+// @Description 为待办事项启动计时器。每个用户同一时间只有一个正在运行的计时器，正在运行的其他计时器会先被停止；该待办事项的计时器已在运行时直接返回它
+// @Tags 时间记录
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param request body timeentry.StartRequest false "备注"
+// @Success 200 {object} response.Response{data=models.TimeEntry} "启动成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "待办事项不存在"
+// @Router /todos/{id}/timer/start [post]
+func StartTimer(timeEntryService service.TimeEntryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		// 请求体可以为空
+		var req timeentry.StartRequest
+		if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		entry, err := timeEntryService.Start(c.Request.Context(), c.GetUint("userID"), uint(todoID), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(entry))
+	}
+}
+
+// StopTimer 停止计时器
+// @Summary 停止计时器
+// @Description 停止待办事项正在运行的计时器，返回已完成的时间记录
+// @Tags 时间记录
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=models.TimeEntry} "停止成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "待办事项不存在"
+// @Failure 409 {object} response.Response "该待办事项没有正在运行的计时器"
+// @Router /todos/{id}/timer/stop [post]
+func StopTimer(timeEntryService service.TimeEntryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		entry, err := timeEntryService.Stop(c.Request.Context(), c.GetUint("userID"), uint(todoID))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(entry))
+	}
+}
+
+// GetRunningTimer 获取正在运行的计时器
+// @Summary 获取正在运行的计时器
+// @Description 获取当前用户正在运行的计时器，没有时 data 为 null
+// @Tags 时间记录
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 200 {object} response.Response{data=models.TimeEntry} "获取成功"
+// @Router /timer [get]
+func GetRunningTimer(timeEntryService service.TimeEntryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entry, err := timeEntryService.Running(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(entry))
+	}
+}
+
+// CreateTimeEntry 手动录入时间
+// @Summary 手动录入时间
+// @Description 为待办事项补录一段已经结束的时间
+// @Tags 时间记录
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param request body timeentry.CreateRequest true "时间记录"
+// @Success 200 {object} response.Response{data=models.TimeEntry} "录入成功"
+// @Failure 400 {object} response.Response "请求参数错误，或结束时间晚于当前时间"
+// @Failure 404 {object} response.Response "待办事项不存在"
+// @Router /todos/{id}/time-entries [post]
+func CreateTimeEntry(timeEntryService service.TimeEntryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		var req timeentry.CreateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		entry, err := timeEntryService.Add(c.Request.Context(), c.GetUint("userID"), uint(todoID), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(entry))
+	}
+}
+
+// ListTimeEntries 获取时间记录
+// @Summary 获取时间记录
+// @Description 获取待办事项的时间记录、总时长和预估耗时
+// @Tags 时间记录
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=timeentry.ListResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "待办事项不存在"
+// @Router /todos/{id}/time-entries [get]
+func ListTimeEntries(timeEntryService service.TimeEntryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		entries, err := timeEntryService.List(c.Request.Context(), c.GetUint("userID"), uint(todoID))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(entries))
+	}
+}
+
+// DeleteTimeEntry 删除时间记录
+// @Summary 删除时间记录
+// @Description 删除一条时间记录；删除正在运行的计时器相当于放弃本次计时
+// @Tags 时间记录
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Param entry_id path int true "时间记录ID"
+// @Success 200 {object} response.Response{data=timeentry.DeleteResponse} "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "时间记录不存在"
+// @Router /todos/{id}/time-entries/{entry_id} [delete]
+func DeleteTimeEntry(timeEntryService service.TimeEntryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+		entryID, err := strconv.ParseUint(c.Param("entry_id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid entry ID"))
+			return
+		}
+
+		if err := timeEntryService.Delete(c.Request.Context(), c.GetUint("userID"), uint(todoID), uint(entryID)); err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(timeentry.DeleteResponse{
+			Message: "Time entry deleted successfully",
+		}))
+	}
+}
+
+// GetTimeReport 时间报表
+// @Summary 时间报表
+// @Description 按分类汇总日期范围内（含两端，按服务器时区）记录的时间，正在运行的计时器不计入，范围最多 366 天
+// @Tags 时间记录
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param from query string true "起始日期，如 2024-05-01"
+// @Param to query string true "结束日期，如 2024-05-31"
+// @Success 200 {object} response.Response{data=timeentry.ReportResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Router /reports/time [get]
+func GetTimeReport(timeEntryService service.TimeEntryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req timeentry.ReportRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		report, err := timeEntryService.Report(c.Request.Context(), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(report))
+	}
+}
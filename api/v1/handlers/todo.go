@@ -21,6 +21,7 @@ import (
 // @Success 200 {object} response.Response{data=todo.DetailResponse} "创建成功返回待办事项信息"
 // @Failure 400 {object} response.Response "参数验证失败或业务错误"
 // @Failure 401 {object} response.Response "未授权访问"
+// @Failure 409 {object} response.Response "目标工作流状态已达到在制品上限"
 // @Router /todos [post]
 func CreateTodo(todoService service.TodoService, categoryService service.CategoryService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -47,7 +48,7 @@ func CreateTodo(todoService service.TodoService, categoryService service.Categor
 		userID := c.GetUint("userID")
 		id, err := todoService.Create(c.Request.Context(), userID, &req)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+			writeTodoError(c, err)
 			return
 		}
 
@@ -64,20 +65,48 @@ func CreateTodo(todoService service.TodoService, categoryService service.Categor
 
 // ListTodos 获取待办事项列表
 // @Summary 获取待办事项列表
-// @Description 获取当前用户的所有待办事项
+// @Description 获取当前用户的待办事项，默认不包含已归档的待办事项
 // @Tags 待办事项管理
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer JWT"
+// @Param archived query string false "归档状态过滤：false（默认）、true、all"
+// @Param completed query bool false "完成状态过滤"
+// @Param q query string false "标题或描述中的关键字"
+// @Param category_id query int false "所属分类ID"
+// @Param include_descendants query bool false "是否包含所有下级分类中的待办事项"
+// @Param query query string false "查询语言表达的过滤条件，例如 priority:high due<7d -completed tag:release category:\"Work\""
+// @Param priority query string false "优先级：low、medium、high"
+// @Param due query string false "截止时间：overdue、today、this_week、none"
+// @Param status_id query int false "看板工作流状态ID"
+// @Param sort query string false "排序方式：position 按分类分组后按手动排序位置排列"
+// @Param filter_id query int false "保存的过滤条件ID，指定时忽略其他过滤参数"
 // @Success 200 {object} response.Response{data=todo.ListResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "未授权访问"
+// @Failure 404 {object} response.Response "过滤条件不存在"
 // @Router /todos [get]
-func ListTodos(todoService service.TodoService) gin.HandlerFunc {
+func ListTodos(todoService service.TodoService, filterService service.FilterService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		var req todo.ListRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
 		userID := c.GetUint("userID")
-		todos, err := todoService.List(c.Request.Context(), userID)
+		if req.FilterID != nil {
+			saved, err := filterService.Request(c.Request.Context(), *req.FilterID, userID)
+			if err != nil {
+				writeTodoError(c, err)
+				return
+			}
+			req = *saved
+		}
+
+		todos, err := todoService.List(c.Request.Context(), userID, &req)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+			writeTodoError(c, err)
 			return
 		}
 
@@ -88,9 +117,12 @@ func ListTodos(todoService service.TodoService) gin.HandlerFunc {
 	}
 }
 
+// latestCommentsLimit 待办事项详情中附带的最新评论数量
+const latestCommentsLimit = 5
+
 // GetTodo 获取待办事项详情
 // @Summary 获取待办事项详情
-// @Description 获取指定的待办事项详情
+// @Description 获取指定的待办事项详情，附带最新的几条评论、是否被未完成的待办事项阻塞，以及已记录的总时长
 // @Tags 待办事项管理
 // @Accept json
 // @Produce json
@@ -100,7 +132,8 @@ func ListTodos(todoService service.TodoService) gin.HandlerFunc {
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "未授权访问"
 // @Router /todos/{id} [get]
-func GetTodo(todoService service.TodoService) gin.HandlerFunc {
+func GetTodo(todoService service.TodoService, commentService service.CommentService,
+	dependencyService service.DependencyService, timeEntryService service.TimeEntryService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
@@ -115,8 +148,31 @@ func GetTodo(todoService service.TodoService) gin.HandlerFunc {
 			return
 		}
 
+		comments, err := commentService.Latest(c.Request.Context(), userID, todoItem.ID, latestCommentsLimit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
+		blockers, err := dependencyService.OpenBlockers(c.Request.Context(), userID, todoItem.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
+		tracked, err := timeEntryService.List(c.Request.Context(), userID, todoItem.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
 		c.JSON(http.StatusOK, response.Success(todo.DetailResponse{
-			Todo: todoItem,
+			Todo:           todoItem,
+			LatestComments: comments,
+			Blocked:        len(blockers) > 0,
+			BlockedBy:      blockers,
+			TrackedSeconds: tracked.TotalSeconds,
+			TimerRunning:   tracked.Running,
 		}))
 	}
 }
@@ -133,6 +189,7 @@ func GetTodo(todoService service.TodoService) gin.HandlerFunc {
 // @Success 200 {object} response.Response{data=todo.DetailResponse} "更新成功"
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "未授权访问"
+// @Failure 409 {object} response.Response "目标工作流状态已达到在制品上限，或被未完成的待办事项阻塞时标记为已完成"
 // @Router /todos/{id} [put]
 func UpdateTodo(todoService service.TodoService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -150,7 +207,7 @@ func UpdateTodo(todoService service.TodoService) gin.HandlerFunc {
 
 		userID := c.GetUint("userID")
 		if err := todoService.Update(c.Request.Context(), uint(id), userID, &req); err != nil {
-			c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+			writeTodoError(c, err)
 			return
 		}
 
@@ -167,12 +224,13 @@ func UpdateTodo(todoService service.TodoService) gin.HandlerFunc {
 
 // DeleteTodo 删除待办事项
 // @Summary 删除待办事项
-// @Description 删除指定的待办事项
+// @Description 将待办事项移入回收站；permanent=true 时永久删除待办事项及其评论、附件和提醒
 // @Tags 待办事项管理
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer JWT"
 // @Param id path int true "待办事项ID"
+// @Param permanent query bool false "是否永久删除"
 // @Success 200 {object} response.Response{data=todo.UpdateResponse} "删除成功"
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "未授权访问"
@@ -186,6 +244,17 @@ func DeleteTodo(todoService service.TodoService) gin.HandlerFunc {
 		}
 
 		userID := c.GetUint("userID")
+		if c.Query("permanent") == "true" {
+			if err := todoService.Purge(c.Request.Context(), uint(id), userID); err != nil {
+				writeTodoError(c, err)
+				return
+			}
+			c.JSON(http.StatusOK, response.Success(todo.UpdateResponse{
+				Message: "Todo permanently deleted",
+			}))
+			return
+		}
+
 		if err := todoService.Delete(c.Request.Context(), uint(id), userID); err != nil {
 			c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
 			return
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/todo"
+	"todo/internal/query"
+	"todo/internal/service"
+	"todo/pkg/errors"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTrash 获取回收站
+// @Summary 获取回收站
+// @Description 获取当前用户已删除但尚未永久删除的待办事项，按删除时间倒序
+// @Tags 待办事项管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 200 {object} response.Response{data=todo.ListResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /todos/trash [get]
+func ListTrash(todoService service.TodoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		todos, err := todoService.ListTrash(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(todo.ListResponse{
+			Total: int64(len(todos)),
+			Items: todos,
+		}))
+	}
+}
+
+// RestoreTodo 恢复待办事项
+// @Summary 恢复待办事项
+// @Description 从回收站恢复待办事项，随它一起删除的提醒也会恢复
+// @Tags 待办事项管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=models.Todo} "恢复成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "回收站中不存在该待办事项"
+// @Router /todos/{id}/restore [post]
+func RestoreTodo(todoService service.TodoService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		userID := c.GetUint("userID")
+		if err := todoService.Restore(c.Request.Context(), uint(id), userID); err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		restored, err := todoService.Get(c.Request.Context(), uint(id), userID)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(restored))
+	}
+}
+
+// writeTodoError 将待办事项相关的业务错误映射为HTTP状态码
+func writeTodoError(c *gin.Context, err error) {
+	if _, ok := err.(*query.SyntaxError); ok {
+		c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+		return
+	}
+	switch err {
+	case errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, response.Error(http.StatusForbidden, err.Error()))
+	case errors.ErrTodoNotFound, errors.ErrCategoryNotFound, errors.ErrFilterNotFound, errors.ErrStatusNotFound,
+		errors.ErrDependencyNotFound, errors.ErrTimeEntryNotFound, errors.ErrTemplateNotFound,
+		errors.ErrCalendarFeedNotFound, errors.ErrDataExportNotFound, errors.ErrWebhookNotFound:
+		c.JSON(http.StatusNotFound, response.Error(http.StatusNotFound, err.Error()))
+	case errors.ErrInvalidParameter, errors.ErrTemplateTooLarge, errors.ErrInvalidCSV, errors.ErrInvalidArchive,
+		errors.ErrInvalidWebhook, errors.ErrTooManyTags:
+		c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+	case errors.ErrWIPLimit, errors.ErrStatusExists, errors.ErrTodoBlocked,
+		errors.ErrDependencyExists, errors.ErrDependencyCycle, errors.ErrTimerNotRunning,
+		errors.ErrDataExportNotReady, errors.ErrAccountNotEmpty:
+		c.JSON(http.StatusConflict, response.Error(http.StatusConflict, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+	}
+}
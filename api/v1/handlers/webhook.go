@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/webhook"
+	"todo/internal/service"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateWebhook 注册 webhook 端点
+// @Summary 注册 webhook 端点
+// @Description 订阅的事件发生后向 url 发送 POST 请求，请求体为 webhook.Event。
+// @Description 请求头 X-Webhook-Signature 为 sha256=HMAC-SHA256(secret, X-Webhook-Timestamp + "." + 请求体) 的十六进制；
+// @Description 响应不是 2xx 时按指数退避重试，重试耗尽记为一次失败，连续失败达到上限后端点被自动停用。secret 只在注册时返回
+// @Tags Webhook 管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param request body webhook.CreateRequest true "地址和订阅的事件"
+// @Success 200 {object} response.Response{data=webhook.CreateResponse} "注册成功"
+// @Failure 400 {object} response.Response "地址或事件类型无效"
+// @Router /webhooks [post]
+func CreateWebhook(webhookService service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req webhook.CreateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		created, err := webhookService.Create(c.Request.Context(), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(created))
+	}
+}
+
+// ListWebhooks 获取 webhook 端点
+// @Summary 获取 webhook 端点
+// @Description 获取当前用户在当前工作空间中注册的 webhook 端点，包括已停用的
+// @Tags Webhook 管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 200 {object} response.Response{data=webhook.ListResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /webhooks [get]
+func ListWebhooks(webhookService service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := webhookService.List(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(webhook.ListResponse{Items: items}))
+	}
+}
+
+// UpdateWebhook 更新 webhook 端点
+// @Summary 更新 webhook 端点
+// @Description 修改地址、订阅的事件或启用状态；重新启用被自动停用的端点时清零失败次数
+// @Tags Webhook 管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "端点ID"
+// @Param request body webhook.UpdateRequest true "更新内容"
+// @Success 200 {object} response.Response{data=models.Webhook} "更新成功"
+// @Failure 400 {object} response.Response "地址或事件类型无效"
+// @Failure 403 {object} response.Response "无权访问"
+// @Failure 404 {object} response.Response "端点不存在"
+// @Router /webhooks/{id} [put]
+func UpdateWebhook(webhookService service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		var req webhook.UpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		updated, err := webhookService.Update(c.Request.Context(), uint(id), c.GetUint("userID"), &req)
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(updated))
+	}
+}
+
+// DeleteWebhook 删除 webhook 端点
+// @Summary 删除 webhook 端点
+// @Description 删除 webhook 端点及其投递日志，尚未完成的投递不再进行
+// @Tags Webhook 管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "端点ID"
+// @Success 200 {object} response.Response{data=webhook.DeleteResponse} "删除成功"
+// @Failure 403 {object} response.Response "无权访问"
+// @Failure 404 {object} response.Response "端点不存在"
+// @Router /webhooks/{id} [delete]
+func DeleteWebhook(webhookService service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		if err := webhookService.Delete(c.Request.Context(), uint(id), c.GetUint("userID")); err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(webhook.DeleteResponse{
+			Message: "Webhook deleted successfully",
+		}))
+	}
+}
+
+// ListWebhookDeliveries 获取 webhook 投递日志
+// @Summary 获取 webhook 投递日志
+// @Description 获取端点最近 50 次投递尝试，按时间倒序；同一事件的多次重试具有相同的 deliveryId
+// @Tags Webhook 管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "端点ID"
+// @Success 200 {object} response.Response{data=webhook.DeliveryListResponse} "获取成功"
+// @Failure 403 {object} response.Response "无权访问"
+// @Failure 404 {object} response.Response "端点不存在"
+// @Router /webhooks/{id}/deliveries [get]
+func ListWebhookDeliveries(webhookService service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		items, err := webhookService.Deliveries(c.Request.Context(), uint(id), c.GetUint("userID"))
+		if err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(webhook.DeliveryListResponse{Items: items}))
+	}
+}
+
+// PingWebhook 发送测试事件
+// @Summary 发送测试事件
+// @Description 在后台向端点发送一次 webhook.ping 事件，不受订阅的事件和启用状态限制，结果记录在投递日志中
+// @Tags Webhook 管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "端点ID"
+// @Success 202 {object} response.Response "已加入投递队列"
+// @Failure 403 {object} response.Response "无权访问"
+// @Failure 404 {object} response.Response "端点不存在"
+// @Router /webhooks/{id}/ping [post]
+func PingWebhook(webhookService service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		if err := webhookService.Ping(c.Request.Context(), uint(id), c.GetUint("userID")); err != nil {
+			writeTodoError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, response.Success(nil))
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo/api/v1/dto/workspace"
+	"todo/internal/service"
+	"todo/pkg/errors"
+	"todo/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateWorkspace 创建工作空间
+// @Summary 创建工作空间
+// @Description 创建一个新的工作空间，创建者自动成为所有者
+// @Tags 工作空间管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param request body workspace.CreateRequest true "创建工作空间请求参数"
+// @Success 200 {object} response.Response{data=models.Workspace} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /workspaces [post]
+func CreateWorkspace(workspaceService service.WorkspaceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req workspace.CreateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		ws, err := workspaceService.Create(c.Request.Context(), c.GetUint("userID"), &req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(ws))
+	}
+}
+
+// ListWorkspaces 获取工作空间列表
+// @Summary 获取工作空间列表
+// @Description 获取当前用户加入的所有工作空间
+// @Tags 工作空间管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Success 200 {object} response.Response{data=workspace.ListResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权访问"
+// @Router /workspaces [get]
+func ListWorkspaces(workspaceService service.WorkspaceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		workspaces, err := workspaceService.List(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(workspace.ListResponse{
+			Total: int64(len(workspaces)),
+			Items: workspaces,
+		}))
+	}
+}
+
+// SwitchWorkspace 切换工作空间
+// @Summary 切换工作空间
+// @Description 切换到指定的工作空间，返回新的JWT令牌，之后的请求需使用新令牌
+// @Tags 工作空间管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "工作空间ID"
+// @Success 200 {object} response.Response{data=workspace.SwitchResponse} "切换成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 403 {object} response.Response "不是该工作空间的成员"
+// @Router /workspaces/{id}/switch [post]
+func SwitchWorkspace(workspaceService service.WorkspaceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		token, err := workspaceService.Switch(c.Request.Context(), c.GetUint("userID"), uint(id))
+		if err != nil {
+			writeWorkspaceError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(workspace.SwitchResponse{
+			Token:       token,
+			WorkspaceID: uint(id),
+		}))
+	}
+}
+
+// InviteMember 邀请成员
+// @Summary 邀请成员加入工作空间
+// @Description 生成邀请令牌，被邀请人登录后使用令牌接受邀请，只有所有者和管理员可以邀请
+// @Tags 工作空间管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "工作空间ID"
+// @Param request body workspace.InviteRequest true "邀请参数"
+// @Success 200 {object} response.Response{data=models.WorkspaceInvite} "邀请成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 403 {object} response.Response "无权限邀请"
+// @Router /workspaces/{id}/invites [post]
+func InviteMember(workspaceService service.WorkspaceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req workspace.InviteRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		invite, err := workspaceService.Invite(c.Request.Context(), c.GetUint("userID"), uint(id), &req)
+		if err != nil {
+			writeWorkspaceError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(invite))
+	}
+}
+
+// AcceptInvite 接受邀请
+// @Summary 接受工作空间邀请
+// @Description 使用邀请令牌加入工作空间
+// @Tags 工作空间管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param token path string true "邀请令牌"
+// @Success 200 {object} response.Response{data=models.Workspace} "加入成功"
+// @Failure 400 {object} response.Response "邀请无效或已过期"
+// @Failure 403 {object} response.Response "邀请不属于当前用户"
+// @Router /workspaces/invites/{token}/accept [post]
+func AcceptInvite(workspaceService service.WorkspaceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ws, err := workspaceService.AcceptInvite(c.Request.Context(), c.GetUint("userID"), c.Param("token"))
+		if err != nil {
+			writeWorkspaceError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(ws))
+	}
+}
+
+// ListWorkspaceMembers 获取成员列表
+// @Summary 获取工作空间成员列表
+// @Description 获取指定工作空间的所有成员及其角色
+// @Tags 工作空间管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "工作空间ID"
+// @Success 200 {object} response.Response{data=workspace.MemberListResponse} "获取成功"
+// @Failure 403 {object} response.Response "不是该工作空间的成员"
+// @Router /workspaces/{id}/members [get]
+func ListWorkspaceMembers(workspaceService service.WorkspaceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+
+		members, err := workspaceService.ListMembers(c.Request.Context(), c.GetUint("userID"), uint(id))
+		if err != nil {
+			writeWorkspaceError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(workspace.MemberListResponse{
+			Total: int64(len(members)),
+			Items: members,
+		}))
+	}
+}
+
+// RemoveWorkspaceMember 移除成员
+// @Summary 移除工作空间成员
+// @Description 所有者和管理员可以移除成员，成员也可以移除自己以退出工作空间
+// @Tags 工作空间管理
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT"
+// @Param id path int true "工作空间ID"
+// @Param user_id path int true "成员用户ID"
+// @Success 200 {object} response.Response{data=workspace.MessageResponse} "移除成功"
+// @Failure 403 {object} response.Response "无权限移除"
+// @Router /workspaces/{id}/members/{user_id} [delete]
+func RemoveWorkspaceMember(workspaceService service.WorkspaceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid ID"))
+			return
+		}
+		memberID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, "Invalid user ID"))
+			return
+		}
+
+		if err := workspaceService.RemoveMember(c.Request.Context(), c.GetUint("userID"), uint(id), uint(memberID)); err != nil {
+			writeWorkspaceError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response.Success(workspace.MessageResponse{
+			Message: "成员已移除",
+		}))
+	}
+}
+
+// writeWorkspaceError 将工作空间相关的业务错误映射为HTTP状态码
+func writeWorkspaceError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrNotWorkspaceMember, errors.ErrForbidden:
+		c.JSON(http.StatusForbidden, response.Error(http.StatusForbidden, err.Error()))
+	case errors.ErrWorkspaceNotFound, errors.ErrInviteNotFound, errors.ErrInviteExpired:
+		c.JSON(http.StatusBadRequest, response.Error(http.StatusBadRequest, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, response.Error(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -11,14 +11,19 @@ import (
 	"syscall"
 	"time"
 	_ "todo/docs" // 导入swagger文档，用于API文档生成
+	"todo/internal/job"
 	"todo/internal/models"
 	routes "todo/internal/router"
 	"todo/internal/service"
 	"todo/pkg/cache"
 	"todo/pkg/config"
 	"todo/pkg/database"
+	"todo/pkg/lock"
 	"todo/pkg/logger"
 	"todo/pkg/middleware"
+	"todo/pkg/notify"
+	"todo/pkg/queue"
+	"todo/pkg/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
@@ -82,12 +87,16 @@ func run() error {
 	}
 
 	// 在初始化数据库连接后添加
-	if err := db.AutoMigrate(&models.User{}, &models.Todo{}, &models.Category{}, &models.Reminder{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.Todo{}, &models.Category{}, &models.Reminder{},
+		&models.Workspace{}, &models.WorkspaceMember{}, &models.WorkspaceInvite{},
+		&models.Comment{}, &models.CommentRevision{}, &models.Attachment{}, &models.ChangeLog{}, &models.SavedFilter{}, &models.Tag{},
+		&models.Status{}, &models.Dependency{}, &models.TimeEntry{}, &models.Template{},
+		&models.CalendarFeed{}, &models.DataExport{}, &models.Webhook{}, &models.WebhookDelivery{}); err != nil {
 		return fmt.Errorf("数据库迁移失败: %v", err)
 	}
 
 	// 验证索引是否存在
-	for _, model := range []string{"users", "todos", "categories", "reminders"} {
+	for _, model := range []string{"users", "todos", "categories", "reminders", "workspaces", "workspace_members"} {
 		var count int64
 		if err := db.Raw(`
 			SELECT count(*) 
@@ -108,9 +117,30 @@ func run() error {
 		return fmt.Errorf("初始化Redis失败: %w", err)
 	}
 
+	// 初始化附件存储
+	blobs, err := storage.New(&cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("初始化附件存储失败: %w", err)
+	}
+
+	// 启动后台任务，服务关闭时通过 cancel 停止
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
+
+	// 启动任务队列，用于执行数据导出、webhook 投递等耗时的异步任务
+	tasks := queue.NewTaskQueue(cfg.TaskQueue.BufferSize, cfg.TaskQueue.Workers)
+	tasks.Start(jobCtx)
+
 	// 5. 初始化各个服务
 	// 创建认证、待办事项、分类、提醒等服务的实例
-	services := initServices(db, rdb, &cfg.JWT)
+	services := initServices(db, rdb, cfg, blobs, tasks)
+
+	jobLock := lock.NewDistributedLock(rdb)
+	go job.NewTrashPurger(services.todo, jobLock, cfg.Trash.Retention, cfg.Trash.PurgeInterval).Run(jobCtx)
+	if cfg.Archive.AutoAfter > 0 {
+		go job.NewAutoArchiver(services.todo, jobLock, cfg.Archive.AutoAfter, cfg.Archive.Interval).Run(jobCtx)
+	}
+	go job.NewReminderFirer(services.reminder, jobLock, cfg.Reminder.Interval).Run(jobCtx)
 
 	// 6. 设置Gin框架的运行模式
 	log.Printf("设置 Gin 模式之前: %s", cfg.Server.Mode)
@@ -130,7 +160,10 @@ func run() error {
 
 	// 初始化路由
 	// 设置所有的API路由规则
-	r = routes.InitRouter(cfg, services.auth, services.todo, services.category, services.reminder)
+	r = routes.InitRouter(cfg, services.auth, services.todo, services.category, services.reminder,
+		services.workspace, services.comment, services.attachment, services.search,
+		services.filter, services.status, services.dependency, services.timeEntry, services.template,
+		services.calendar, services.caldav, services.backup, services.webhook)
 
 	// 8. 配置HTTP服务器
 	srv := &http.Server{
@@ -151,6 +184,8 @@ func run() error {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("正在关闭服务器...")
+	cancelJobs()
+	tasks.Stop()
 
 	// 设置5秒的超时时间来处理剩余请求
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -176,15 +211,49 @@ type services struct {
 	todo     service.TodoService     // 待办事项服务
 	category service.CategoryService // 分类服务
 	reminder service.ReminderService // 提醒服务
+	workspace service.WorkspaceService // 工作空间服务
+	comment   service.CommentService   // 评论服务
+	attachment service.AttachmentService // 附件服务
+	search     service.SearchService     // 全文搜索服务
+	filter     service.FilterService     // 过滤条件服务
+	status     service.StatusService     // 看板工作流状态服务
+	dependency service.DependencyService // 依赖关系服务
+	timeEntry  service.TimeEntryService  // 时间记录服务
+	template   service.TemplateService   // 模板服务
+	calendar   service.CalendarService   // 日历导出与订阅服务
+	caldav     service.CalDAVService     // CalDAV 同步服务
+	backup     service.BackupService     // 账户数据导出与导入服务
+	webhook    service.WebhookService    // webhook 端点管理与事件投递服务
 }
 
 // initServices 初始化所有服务
 // 创建并返回各个服务的实例
-func initServices(db *gorm.DB, rdb *redis.Client, jwtCfg *config.JWTConfig) *services {
+func initServices(db *gorm.DB, rdb *redis.Client, cfg *config.Config, blobs storage.BlobStore, tasks *queue.TaskQueue) *services {
+	// 尚未接入邮件或推送渠道，通知先写入日志
+	notifier := notify.NewLogNotifier()
+	// 待办事项和提醒的变更通过 webhook 投递给用户注册的端点
+	webhook := service.NewWebhookService(db, tasks, &cfg.Webhook)
+	attachment := service.NewAttachmentService(db, blobs, &cfg.Attachment)
+	comment := service.NewCommentService(db, notifier)
+	timeEntry := service.NewTimeEntryService(db)
+
 	return &services{
-		auth:     service.NewAuthService(db, rdb, jwtCfg),
-		todo:     service.NewTodoService(db),
-		category: service.NewCategoryService(db),
-		reminder: service.NewReminderService(db),
+		auth:     service.NewAuthService(db, rdb, &cfg.JWT),
+		todo:     service.NewTodoService(db, notifier, webhook, comment, attachment, timeEntry),
+		category: service.NewCategoryService(db, notifier, webhook),
+		reminder: service.NewReminderService(db, webhook),
+		workspace: service.NewWorkspaceService(db, &cfg.JWT),
+		comment:   comment,
+		attachment: attachment,
+		search:     service.NewSearchService(db),
+		filter:     service.NewFilterService(db),
+		status:     service.NewStatusService(db, notifier, webhook),
+		dependency: service.NewDependencyService(db, notifier),
+		timeEntry:  timeEntry,
+		template:   service.NewTemplateService(db, notifier, webhook),
+		calendar:   service.NewCalendarService(db),
+		caldav:     service.NewCalDAVService(db, notifier, webhook),
+		backup:     service.NewBackupService(db, blobs, tasks, notifier),
+		webhook:    webhook,
 	}
 }
@@ -0,0 +1,48 @@
+package job
+
+import (
+	"context"
+	"time"
+	"todo/pkg/lock"
+	"todo/pkg/logger"
+)
+
+// AutoArchiveService 自动归档任务依赖的服务
+type AutoArchiveService interface {
+	ArchiveCompleted(ctx context.Context, before time.Time) (int, error)
+}
+
+// AutoArchiver 定期归档完成时间超过指定时长的待办事项
+type AutoArchiver struct {
+	periodic
+	svc   AutoArchiveService
+	after time.Duration
+}
+
+// NewAutoArchiver 创建自动归档任务
+//
+// Parameters:
+//   - svc: 执行归档的服务
+//   - lock: 分布式锁，避免多个实例重复执行
+//   - after: 完成多久之后自动归档
+//   - interval: 执行间隔
+//
+// Returns:
+//   - *AutoArchiver: 返回自动归档任务实例
+func NewAutoArchiver(svc AutoArchiveService, lock *lock.DistributedLock, after, interval time.Duration) *AutoArchiver {
+	a := &AutoArchiver{svc: svc, after: after}
+	a.periodic = periodic{name: "auto_archive", lock: lock, interval: interval, run: a.archive}
+	return a
+}
+
+// archive 执行一次自动归档
+func (a *AutoArchiver) archive(ctx context.Context) {
+	archived, err := a.svc.ArchiveCompleted(ctx, time.Now().Add(-a.after))
+	if err != nil {
+		logger.Error().Err(err).Msg("自动归档失败")
+		return
+	}
+	if archived > 0 {
+		logger.Info().Int("count", archived).Msg("已自动归档完成的待办事项")
+	}
+}
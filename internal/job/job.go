@@ -0,0 +1,46 @@
+// Package job 实现后台定时任务
+package job
+
+import (
+	"context"
+	"time"
+	"todo/pkg/lock"
+	"todo/pkg/logger"
+)
+
+// periodic 按固定间隔执行任务，多实例部署时通过分布式锁保证同一周期只有一个实例执行
+type periodic struct {
+	name     string
+	lock     *lock.DistributedLock
+	interval time.Duration
+	run      func(ctx context.Context)
+}
+
+// Run 按间隔执行任务，直到 ctx 被取消
+func (p *periodic) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.runOnce(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runOnce 获取锁并执行一次任务
+func (p *periodic) runOnce(ctx context.Context) {
+	ok, err := p.lock.Lock(ctx, "job:"+p.name, p.interval)
+	if err != nil {
+		logger.Warn().Err(err).Str("job", p.name).Msg("获取后台任务锁失败")
+		return
+	}
+	if !ok {
+		return
+	}
+	// 不主动释放锁：锁在一个间隔后过期，避免其他实例在同一周期内重复执行
+	p.run(ctx)
+}
@@ -0,0 +1,46 @@
+package job
+
+import (
+	"context"
+	"time"
+	"todo/pkg/lock"
+	"todo/pkg/logger"
+)
+
+// ReminderFireService 提醒发送任务依赖的服务
+type ReminderFireService interface {
+	FireDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// ReminderFirer 定期发送到期的提醒
+type ReminderFirer struct {
+	periodic
+	svc ReminderFireService
+}
+
+// NewReminderFirer 创建提醒发送任务
+//
+// Parameters:
+//   - svc: 执行发送的服务
+//   - lock: 分布式锁，避免多个实例重复发送
+//   - interval: 检查到期提醒的间隔
+//
+// Returns:
+//   - *ReminderFirer: 返回提醒发送任务实例
+func NewReminderFirer(svc ReminderFireService, lock *lock.DistributedLock, interval time.Duration) *ReminderFirer {
+	f := &ReminderFirer{svc: svc}
+	f.periodic = periodic{name: "reminder_fire", lock: lock, interval: interval, run: f.fire}
+	return f
+}
+
+// fire 执行一次提醒发送
+func (f *ReminderFirer) fire(ctx context.Context) {
+	fired, err := f.svc.FireDue(ctx, time.Now())
+	if err != nil {
+		logger.Error().Err(err).Msg("发送到期提醒失败")
+		return
+	}
+	if fired > 0 {
+		logger.Info().Int("count", fired).Msg("已发送到期的提醒")
+	}
+}
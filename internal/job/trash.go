@@ -0,0 +1,48 @@
+package job
+
+import (
+	"context"
+	"time"
+	"todo/pkg/lock"
+	"todo/pkg/logger"
+)
+
+// TrashPurgeService 回收站清理任务依赖的服务
+type TrashPurgeService interface {
+	PurgeExpired(ctx context.Context, before time.Time) (int, error)
+}
+
+// TrashPurger 定期永久删除回收站中超过保留期的待办事项
+type TrashPurger struct {
+	periodic
+	svc       TrashPurgeService
+	retention time.Duration
+}
+
+// NewTrashPurger 创建回收站清理任务
+//
+// Parameters:
+//   - svc: 执行清理的服务
+//   - lock: 分布式锁，避免多个实例重复清理
+//   - retention: 回收站保留时长
+//   - interval: 执行间隔
+//
+// Returns:
+//   - *TrashPurger: 返回清理任务实例
+func NewTrashPurger(svc TrashPurgeService, lock *lock.DistributedLock, retention, interval time.Duration) *TrashPurger {
+	p := &TrashPurger{svc: svc, retention: retention}
+	p.periodic = periodic{name: "trash_purge", lock: lock, interval: interval, run: p.purge}
+	return p
+}
+
+// purge 执行一次清理
+func (p *TrashPurger) purge(ctx context.Context) {
+	purged, err := p.svc.PurgeExpired(ctx, time.Now().Add(-p.retention))
+	if err != nil {
+		logger.Error().Err(err).Msg("清理回收站失败")
+		return
+	}
+	if purged > 0 {
+		logger.Info().Int("count", purged).Msg("已永久删除回收站中过期的待办事项")
+	}
+}
@@ -1,20 +1,60 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
+	"todo/internal/tenant"
 	"todo/pkg/config"
+	"todo/pkg/errors"
 	"todo/pkg/utils"
 	"todo/pkg/response"
 
 	"github.com/gin-gonic/gin"
 )
 
+// memberCacheTTL 成员资格校验结果的缓存时间，成员被移除后已签发的令牌最多在此时间后失效
+const memberCacheTTL = 30 * time.Second
+
+// memberCacheLimit 成员资格缓存的最大条目数，超过后清空重建
+const memberCacheLimit = 10000
+
+// MemberChecker 校验用户是否仍是工作空间成员
+type MemberChecker interface {
+	// CheckMember 不是成员时返回 ErrNotWorkspaceMember
+	CheckMember(ctx context.Context, workspaceID, userID uint) error
+}
+
+// memberCache 缓存最近校验通过的成员资格，避免每个请求都查询数据库
+type memberCache struct {
+	mu      sync.Mutex
+	expires map[[2]uint]time.Time
+}
+
+func (m *memberCache) valid(workspaceID, userID uint) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return time.Now().Before(m.expires[[2]uint{workspaceID, userID}])
+}
+
+func (m *memberCache) add(workspaceID, userID uint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.expires) >= memberCacheLimit {
+		m.expires = make(map[[2]uint]time.Time)
+	}
+	m.expires[[2]uint{workspaceID, userID}] = time.Now().Add(memberCacheTTL)
+}
+
 // AuthMiddleware JWT认证中间件
 // 用于验证请求头中的JWT令牌,确保API的安全访问
+// 每次请求都会校验用户是否仍是令牌中工作空间的成员，被移除的成员持有的令牌随之失效
 //
 // Parameters:
 //   - cfg: JWT配置信息,包含密钥等配置
+//   - members: 成员资格校验
 //
 // Returns:
 //   - gin.HandlerFunc: 返回Gin中间件处理函数
@@ -28,7 +68,8 @@ import (
 // @Success 200 {object} interface{} "验证成功"
 // @Failure 401 {object} errors.Error "未授权访问"
 // @Router /auth/middleware [get]
-func AuthMiddleware(cfg *config.JWTConfig) gin.HandlerFunc {
+func AuthMiddleware(cfg *config.JWTConfig, members MemberChecker) gin.HandlerFunc {
+	cache := &memberCache{expires: make(map[[2]uint]time.Time)}
 	return func(c *gin.Context) {
 		token := extractToken(c)
 		if token == "" {
@@ -44,7 +85,31 @@ func AuthMiddleware(cfg *config.JWTConfig) gin.HandlerFunc {
 			return
 		}
 
+		if claims.WorkspaceID == 0 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Error(
+				http.StatusUnauthorized, "访问令牌缺少工作空间信息，请重新登录"))
+			return
+		}
+
+		if !cache.valid(claims.WorkspaceID, claims.UserID) {
+			err := members.CheckMember(c.Request.Context(), claims.WorkspaceID, claims.UserID)
+			if err == errors.ErrNotWorkspaceMember {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, response.Error(
+					http.StatusUnauthorized, "已不是该工作空间的成员，请重新登录"))
+				return
+			}
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error(
+					http.StatusInternalServerError, "校验工作空间成员失败"))
+				return
+			}
+			cache.add(claims.WorkspaceID, claims.UserID)
+		}
+
 		c.Set("userID", claims.UserID)
+		c.Set("workspaceID", claims.WorkspaceID)
+		// 将工作空间写入请求上下文，仓储层据此限定所有查询的租户范围
+		c.Request = c.Request.WithContext(tenant.WithWorkspaceID(c.Request.Context(), claims.WorkspaceID))
 		c.Next()
 	}
 }
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo/pkg/config"
+	"todo/pkg/errors"
+	"todo/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mockMembers 模拟成员资格校验，记录查询次数
+type mockMembers struct {
+	members map[[2]uint]bool
+	calls   int
+}
+
+func (m *mockMembers) CheckMember(ctx context.Context, workspaceID, userID uint) error {
+	m.calls++
+	if !m.members[[2]uint{workspaceID, userID}] {
+		return errors.ErrNotWorkspaceMember
+	}
+	return nil
+}
+
+// TestAuthMiddleware_RemovedMember 测试成员被移除后，持有的旧令牌在缓存过期后被拒绝
+func TestAuthMiddleware_RemovedMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.JWTConfig{Secret: "test_secret", ExpireHours: 1}
+	members := &mockMembers{members: map[[2]uint]bool{{1, 2}: true}}
+	r := gin.New()
+	r.Use(AuthMiddleware(cfg, members))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	token, _ := utils.GenerateToken(2, 1, cfg)
+	do := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := do(); code != http.StatusOK {
+		t.Fatalf("成员请求状态码 = %d, 期望 %d", code, http.StatusOK)
+	}
+	if code := do(); code != http.StatusOK || members.calls != 1 {
+		t.Errorf("缓存期内状态码 = %d, 查询次数 = %d, 期望只查询 1 次", code, members.calls)
+	}
+
+	// 移除成员，缓存期内的请求仍然通过
+	delete(members.members, [2]uint{1, 2})
+	if code := do(); code != http.StatusOK {
+		t.Errorf("缓存期内状态码 = %d, 期望 %d", code, http.StatusOK)
+	}
+	// 重新创建中间件即清空缓存，等同于缓存过期
+	r = gin.New()
+	r.Use(AuthMiddleware(cfg, members))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	if code := do(); code != http.StatusUnauthorized {
+		t.Errorf("移除后状态码 = %d, 期望 %d", code, http.StatusUnauthorized)
+	}
+}
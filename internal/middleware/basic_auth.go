@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"todo/internal/service"
+	"todo/internal/tenant"
+	"todo/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BasicAuthMiddleware HTTP Basic 认证中间件
+// 用于 CalDAV 等无法使用JWT的客户端，使用账号的用户名和密码认证，请求限定在用户的默认工作空间内
+//
+// Parameters:
+//   - authService: 认证服务，用于校验用户名和密码
+//   - members: 成员资格校验，已不是默认工作空间成员时拒绝请求
+//   - realm: 认证失败时在 WWW-Authenticate 中返回的领域名称
+//
+// Returns:
+//   - gin.HandlerFunc: 返回Gin中间件处理函数
+func BasicAuthMiddleware(authService service.AuthService, members MemberChecker, realm string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		username, password, ok := c.Request.BasicAuth()
+		if !ok {
+			unauthorized(c, realm)
+			return
+		}
+
+		user, err := authService.Authenticate(c.Request.Context(), username, password)
+		if err == errors.ErrInvalidCredentials {
+			unauthorized(c, realm)
+			return
+		}
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		err = members.CheckMember(c.Request.Context(), user.DefaultWorkspaceID, user.ID)
+		if err == errors.ErrNotWorkspaceMember {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.Set("userID", user.ID)
+		c.Set("username", user.Username)
+		c.Set("workspaceID", user.DefaultWorkspaceID)
+		// 将工作空间写入请求上下文，仓储层据此限定所有查询的租户范围
+		c.Request = c.Request.WithContext(tenant.WithWorkspaceID(c.Request.Context(), user.DefaultWorkspaceID))
+		c.Next()
+	}
+}
+
+// unauthorized 返回 401 并要求客户端使用 Basic 认证
+func unauthorized(c *gin.Context, realm string) {
+	c.Header("WWW-Authenticate", `Basic realm="`+realm+`", charset="UTF-8"`)
+	c.AbortWithStatus(http.StatusUnauthorized)
+}
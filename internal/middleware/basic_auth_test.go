@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo/api/v1/dto/auth"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mockAuthService 模拟认证服务，只支持用户名和密码校验
+type mockAuthService struct {
+	user *models.User
+}
+
+func (m *mockAuthService) Register(ctx context.Context, req *auth.RegisterRequest) error {
+	return nil
+}
+
+func (m *mockAuthService) Login(ctx context.Context, req *auth.LoginRequest) (string, *auth.UserInfo, error) {
+	return "", nil, nil
+}
+
+func (m *mockAuthService) Authenticate(ctx context.Context, username, password string) (*models.User, error) {
+	if username != m.user.Username || password != "password123" {
+		return nil, errors.ErrInvalidCredentials
+	}
+	return m.user, nil
+}
+
+// TestBasicAuthMiddleware_RemovedMember 测试已不是默认工作空间成员的用户不能继续同步
+func TestBasicAuthMiddleware_RemovedMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService := &mockAuthService{user: &models.User{Base: models.Base{ID: 2}, Username: "guest", DefaultWorkspaceID: 1}}
+	members := &mockMembers{members: map[[2]uint]bool{{1, 2}: true}}
+	r := gin.New()
+	r.Use(BasicAuthMiddleware(authService, members, "todo"))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(password string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth("guest", password)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := do("wrong"); code != http.StatusUnauthorized {
+		t.Errorf("密码错误状态码 = %d, 期望 %d", code, http.StatusUnauthorized)
+	}
+	if code := do("password123"); code != http.StatusOK {
+		t.Errorf("成员请求状态码 = %d, 期望 %d", code, http.StatusOK)
+	}
+	delete(members.members, [2]uint{1, 2})
+	if code := do("password123"); code != http.StatusForbidden {
+		t.Errorf("移除后状态码 = %d, 期望 %d", code, http.StatusForbidden)
+	}
+}
@@ -19,7 +19,8 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		// 对于OPTIONS预检请求,直接返回204状态码
-		if c.Request.Method == "OPTIONS" {
+		// 只有带 Access-Control-Request-Method 的才是预检请求，CalDAV 客户端的 OPTIONS 请求需要交给路由处理
+		if c.Request.Method == "OPTIONS" && c.GetHeader("Access-Control-Request-Method") != "" {
 			c.AbortWithStatus(204)
 			return
 		}
@@ -0,0 +1,14 @@
+package models
+
+// Attachment 附件模型
+// 只保存附件的元数据，文件内容保存在对象存储中，通过 StorageKey 关联
+type Attachment struct {
+	Base
+	WorkspaceID uint   `json:"workspaceId" gorm:"not null;index"`      // 所属工作空间ID
+	TodoID      uint   `json:"todoId" gorm:"not null;index"`           // 关联的待办事项ID
+	UserID      uint   `json:"userId" gorm:"not null"`                 // 上传者ID
+	FileName    string `json:"fileName" gorm:"size:255;not null"`      // 原始文件名
+	ContentType string `json:"contentType" gorm:"size:128;not null"`   // MIME 类型
+	Size        int64  `json:"size" gorm:"not null"`                   // 文件大小（字节）
+	StorageKey  string `json:"-" gorm:"size:255;not null;uniqueIndex"` // 对象存储中的键
+}
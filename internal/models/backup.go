@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// 数据导出任务状态
+const (
+	DataExportPending = "pending" // 等待后台任务执行
+	DataExportRunning = "running" // 正在生成归档
+	DataExportDone    = "done"    // 归档已生成，可以下载
+	DataExportFailed  = "failed"  // 生成失败
+)
+
+// DataExport 数据导出任务
+// 由后台任务队列异步生成用户在工作空间中的全部数据的 JSON 归档，归档文件保存在对象存储中；
+// 每个用户在每个工作空间中只保留最近一次导出
+type DataExport struct {
+	Base
+	WorkspaceID uint       `json:"workspaceId" gorm:"not null;index:idx_data_exports_owner,priority:1"` // 所属工作空间ID
+	UserID      uint       `json:"userId" gorm:"not null;index:idx_data_exports_owner,priority:2"`      // 所属用户ID
+	Status      string     `json:"status" gorm:"size:16;not null"`                                      // 任务状态
+	StorageKey  string     `json:"-" gorm:"size:255"`                                                   // 归档在对象存储中的键，生成完成后才有值
+	Size        int64      `json:"size"`                                                                // 归档大小（字节）
+	Error       string     `json:"error,omitempty" gorm:"size:255"`                                     // 生成失败的原因
+	FinishedAt  *time.Time `json:"finishedAt"`                                                          // 生成完成或失败的时间
+}
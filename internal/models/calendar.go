@@ -0,0 +1,11 @@
+package models
+
+// CalendarFeed 日历订阅
+// 日历客户端通过包含随机令牌的地址定期拉取用户在工作空间中的待办事项，无需登录；
+// 每个用户在每个工作空间中最多有一个订阅，重新生成令牌后旧地址立即失效
+type CalendarFeed struct {
+	Base
+	WorkspaceID uint   `json:"workspaceId" gorm:"not null;uniqueIndex:idx_calendar_feeds_owner,priority:1"` // 所属工作空间ID
+	UserID      uint   `json:"userId" gorm:"not null;uniqueIndex:idx_calendar_feeds_owner,priority:2"`      // 所属用户ID
+	Token       string `json:"token" gorm:"size:64;not null;uniqueIndex"`                                   // 订阅令牌
+}
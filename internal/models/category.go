@@ -3,9 +3,13 @@ package models
 // Category 分类模型
 // 用于对待办事项进行分类管理
 // 每个分类都属于特定用户，包含名称和颜色信息
+// 分类可以通过 ParentID 嵌套，形成 领域 → 项目 → 子项目 这样的层级
 type Category struct {
 	Base
 	Name   string `json:"name" gorm:"size:32;not null"`      // 分类名称，不超过32字符
 	Color  string `json:"color" gorm:"size:7"`               // 分类颜色，使用十六进制颜色码(如 #FF0000)
+	WorkspaceID uint `json:"workspaceId" gorm:"not null;index"`  // 所属工作空间ID
 	UserID uint   `json:"userId" gorm:"not null;column:user_id"` // 所属用户ID
+	ParentID *uint `json:"parentId" gorm:"index"`                 // 上级分类ID，为空表示顶级分类
+	Children []*Category `json:"children,omitempty" gorm:"-"`     // 下级分类，仅在树形查询时填充
 }
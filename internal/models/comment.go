@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Comment 评论模型
+// 待办事项下的讨论记录，正文使用 Markdown 格式保存原文，由客户端负责渲染
+type Comment struct {
+	Base
+	WorkspaceID uint              `json:"workspaceId" gorm:"not null;index"`                                                                  // 所属工作空间ID
+	TodoID      uint              `json:"todoId" gorm:"not null;index"`                                                                       // 关联的待办事项ID
+	UserID      uint              `json:"userId" gorm:"not null;index"`                                                                       // 评论作者ID
+	Body        string            `json:"body" gorm:"type:text;not null;index:idx_comments_fulltext,class:FULLTEXT,option:WITH PARSER ngram"` // Markdown 正文
+	EditedAt    *time.Time        `json:"editedAt"`                                                                                           // 最后编辑时间，为空表示未编辑过
+	User        *User             `json:"user,omitempty" gorm:"foreignKey:UserID"`                                                            // 评论作者
+	Revisions   []CommentRevision `json:"revisions,omitempty" gorm:"foreignKey:CommentID"`                                                    // 编辑历史
+}
+
+// CommentRevision 评论编辑历史
+// 每次编辑评论时保存被替换掉的旧正文
+type CommentRevision struct {
+	Base
+	WorkspaceID uint   `json:"workspaceId" gorm:"not null;index"` // 所属工作空间ID
+	CommentID   uint   `json:"commentId" gorm:"not null;index"`   // 关联的评论ID
+	Body        string `json:"body" gorm:"type:text;not null"`    // 编辑前的正文
+	EditedBy    uint   `json:"editedBy" gorm:"not null"`          // 编辑人ID
+}
@@ -0,0 +1,10 @@
+package models
+
+// Dependency 待办事项之间的阻塞关系：TodoID 被 BlockerID 阻塞
+// BlockerID 完成之前 TodoID 不能完成；同一对待办事项只能有一条记录，且所有关系不能形成环
+type Dependency struct {
+	Base
+	WorkspaceID uint `json:"workspaceId" gorm:"not null;index"`                                 // 所属工作空间ID
+	TodoID      uint `json:"todoId" gorm:"not null;uniqueIndex:idx_dependencies_pair"`          // 被阻塞的待办事项ID
+	BlockerID   uint `json:"blockerId" gorm:"not null;uniqueIndex:idx_dependencies_pair;index"` // 阻塞它的待办事项ID
+}
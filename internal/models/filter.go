@@ -0,0 +1,47 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// 截止时间过滤取值
+const (
+	DueOverdue  = "overdue"   // 已逾期且未完成
+	DueToday    = "today"     // 今天到期
+	DueThisWeek = "this_week" // 本周（周一至周日）到期
+	DueNone     = "none"      // 没有截止时间
+)
+
+// FilterDefinition 保存的过滤条件，字段与待办事项列表的查询参数一一对应
+// 截止时间使用 today、this_week 等相对取值，每次求值时按当前时间计算
+type FilterDefinition struct {
+	Archived           string `json:"archived,omitempty" binding:"omitempty,oneof=true false all"`          // 归档状态：false（默认）、true、all
+	Completed          *bool  `json:"completed,omitempty"`                                                  // 完成状态，为空时不过滤
+	Keyword            string `json:"q,omitempty" binding:"omitempty,max=128"`                              // 标题或描述中的关键字
+	Query              string `json:"query,omitempty" binding:"omitempty,max=256"`                          // 查询语言表达的条件
+	Priority           string `json:"priority,omitempty" binding:"omitempty,oneof=low medium high"`         // 优先级
+	Due                string `json:"due,omitempty" binding:"omitempty,oneof=overdue today this_week none"` // 截止时间范围
+	CategoryID         *uint  `json:"categoryId,omitempty"`                                                 // 所属分类ID
+	IncludeDescendants bool   `json:"includeDescendants,omitempty"`                                         // 是否包含下级分类
+}
+
+// Value 实现 driver.Valuer 接口
+func (d FilterDefinition) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (d *FilterDefinition) Scan(value interface{}) error {
+	return scanJSON(value, d)
+}
+
+// SavedFilter 保存的过滤条件（智能列表）
+// 用户为常用的查询命名保存，之后可以通过 filter_id 直接列出匹配的待办事项
+type SavedFilter struct {
+	Base
+	WorkspaceID uint             `json:"workspaceId" gorm:"not null;index"` // 所属工作空间ID
+	UserID      uint             `json:"userId" gorm:"not null;index"`      // 所属用户ID
+	Name        string           `json:"name" gorm:"size:64;not null"`      // 名称
+	Definition  FilterDefinition `json:"definition" gorm:"type:json"`       // 过滤条件
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// 变更历史记录的实体类型
+const (
+	EntityTodo     = "todo"
+	EntityCategory = "category"
+	EntityReminder = "reminder"
+)
+
+// 变更历史记录的操作类型
+const (
+	ChangeActionCreate  = "create"
+	ChangeActionUpdate  = "update"
+	ChangeActionDelete  = "delete"
+	ChangeActionRevert  = "revert"
+	ChangeActionRestore = "restore"
+	ChangeActionPurge   = "purge"
+)
+
+// FieldChange 单个字段的变更
+type FieldChange struct {
+	Field string      `json:"field"` // 字段名（与 JSON 字段名一致）
+	Old   interface{} `json:"old"`   // 变更前的值
+	New   interface{} `json:"new"`   // 变更后的值
+}
+
+// FieldChanges 字段变更列表，以 JSON 形式存储
+type FieldChanges []FieldChange
+
+// Value 实现 driver.Valuer 接口
+func (c FieldChanges) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (c *FieldChanges) Scan(value interface{}) error {
+	return scanJSON(value, c)
+}
+
+// Snapshot 实体在某一版本的完整字段快照，以 JSON 形式存储
+type Snapshot map[string]interface{}
+
+// Value 实现 driver.Valuer 接口
+func (s Snapshot) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (s *Snapshot) Scan(value interface{}) error {
+	return scanJSON(value, s)
+}
+
+// ChangeLog 变更历史模型
+// 只追加不修改：每次创建、更新、删除或回滚都新增一条记录，
+// 记录操作人、字段级的新旧值以及操作后的完整快照（删除时为删除前的快照）
+type ChangeLog struct {
+	ID          uint         `json:"id" gorm:"primarykey"`                                            // 主键ID，同时作为版本号
+	CreatedAt   time.Time    `json:"createdAt" gorm:"column:created_at"`                              // 变更时间
+	WorkspaceID uint         `json:"workspaceId" gorm:"not null;index"`                               // 所属工作空间ID
+	EntityType  string       `json:"entityType" gorm:"size:16;not null;index:idx_change_logs_entity"` // 实体类型
+	EntityID    uint         `json:"entityId" gorm:"not null;index:idx_change_logs_entity"`           // 实体ID
+	Action      string       `json:"action" gorm:"size:16;not null"`                                  // 操作类型
+	ActorID     uint         `json:"actorId" gorm:"not null"`                                         // 操作人ID
+	Changes     FieldChanges `json:"changes" gorm:"type:json"`                                        // 字段级变更
+	Snapshot    Snapshot     `json:"snapshot" gorm:"type:json"`                                       // 完整字段快照
+}
+
+// scanJSON 将数据库中的 JSON 列解析到目标值
+func scanJSON(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	default:
+		return errors.New("无法解析的 JSON 列类型")
+	}
+}
@@ -28,6 +28,7 @@ type Reminder struct {
 	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`         // 创建时间
 	UpdatedAt time.Time `json:"updatedAt" gorm:"column:updated_at"`         // 更新时间
 	DeletedAt gorm.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deleted_at;index"` // 软删除时间
+	WorkspaceID uint    `json:"workspaceId" gorm:"column:workspace_id;not null;index"`                                    // 所属工作空间ID
 	TodoID    uint      `json:"todoId" gorm:"column:todo_id;not null;type:bigint unsigned;index:idx_reminders_todo_id"`    // 关联的待办事项ID
 	RemindAt  time.Time `json:"remindAt" gorm:"column:remind_at;not null;type:datetime;index:idx_reminders_time"`          // 提醒时间
 	RemindType string   `json:"remindType" gorm:"column:remind_type;not null;type:varchar(10)"`   // 提醒类型
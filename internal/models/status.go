@@ -0,0 +1,13 @@
+package models
+
+// Status 看板工作流状态，即看板中的一列（如 Backlog、In Progress、Review、Done）
+// 状态名在同一工作空间的同一用户下唯一；处于终止状态的待办事项视为已完成
+type Status struct {
+	Base
+	WorkspaceID uint   `json:"workspaceId" gorm:"not null;uniqueIndex:idx_statuses_owner_name"`  // 所属工作空间ID
+	UserID      uint   `json:"userId" gorm:"not null;uniqueIndex:idx_statuses_owner_name"`       // 所属用户ID
+	Name        string `json:"name" gorm:"size:32;not null;uniqueIndex:idx_statuses_owner_name"` // 状态名
+	Position    int    `json:"position" gorm:"not null;default:0"`                               // 在看板中的顺序，从小到大排列
+	Terminal    bool   `json:"terminal" gorm:"not null;default:false"`                           // 是否为终止状态
+	WIPLimit    int    `json:"wipLimit" gorm:"not null;default:0"`                               // 在制品上限，0 表示不限制
+}
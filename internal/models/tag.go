@@ -0,0 +1,10 @@
+package models
+
+// Tag 标签模型
+// 标签名在同一工作空间的同一用户下唯一，通过 todo_tags 关联表与待办事项多对多关联
+type Tag struct {
+	Base
+	WorkspaceID uint   `json:"workspaceId" gorm:"not null;uniqueIndex:idx_tags_owner_name"`  // 所属工作空间ID
+	UserID      uint   `json:"userId" gorm:"not null;uniqueIndex:idx_tags_owner_name"`       // 所属用户ID
+	Name        string `json:"name" gorm:"size:32;not null;uniqueIndex:idx_tags_owner_name"` // 标签名
+}
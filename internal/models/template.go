@@ -0,0 +1,60 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// TemplateReminder 模板中的相对提醒
+type TemplateReminder struct {
+	Offset     int    `json:"offset"`                                                // 相对待办事项截止时间的偏移（分钟），负数表示截止前；没有截止时间时相对基准时间
+	RemindType string `json:"remindType" binding:"required,oneof=once daily weekly"` // 提醒类型
+	NotifyType string `json:"notifyType" binding:"required,oneof=email push"`        // 通知方式
+}
+
+// TemplateItem 模板中的一个待办事项
+// 时间都以相对实例化时基准时间的偏移表示，子任务实例化为阻塞该待办事项的待办事项
+type TemplateItem struct {
+	Title       string             `json:"title" binding:"required,max=128"`                               // 标题
+	Description string             `json:"description,omitempty" binding:"max=1024"`                       // 描述
+	Priority    string             `json:"priority,omitempty" binding:"omitempty,oneof=low medium high"`   // 优先级，为空时使用中优先级
+	CategoryID  *uint              `json:"categoryId,omitempty"`                                           // 所属分类ID
+	Tags        []string           `json:"tags,omitempty" binding:"omitempty,max=20,dive,required,max=32"` // 标签名列表
+	Estimate    *int               `json:"estimate,omitempty" binding:"omitempty,min=0"`                   // 预估耗时（分钟）
+	DueOffset   *int               `json:"dueOffset,omitempty"`                                            // 截止时间相对基准时间的偏移（分钟），为空表示没有截止时间
+	Reminders   []TemplateReminder `json:"reminders,omitempty" binding:"omitempty,max=10,dive"`            // 相对提醒
+	Subtasks    []TemplateItem     `json:"subtasks,omitempty" binding:"omitempty,max=50,dive"`             // 子任务，全部完成之前该待办事项不能完成
+}
+
+// TemplateItems 模板中的待办事项列表，以 JSON 形式存储
+type TemplateItems []TemplateItem
+
+// Value 实现 driver.Valuer 接口
+func (items TemplateItems) Value() (driver.Value, error) {
+	return json.Marshal(items)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (items *TemplateItems) Scan(value interface{}) error {
+	return scanJSON(value, items)
+}
+
+// Count 返回模板中待办事项的总数，包括各级子任务
+func (items TemplateItems) Count() int {
+	n := 0
+	for _, item := range items {
+		n += 1 + TemplateItems(item.Subtasks).Count()
+	}
+	return n
+}
+
+// Template 待办事项模板
+// 保存一组经常重复出现的待办事项（例如发布检查清单），可以按基准时间一次性全部创建
+type Template struct {
+	Base
+	WorkspaceID uint          `json:"workspaceId" gorm:"not null;index"` // 所属工作空间ID
+	UserID      uint          `json:"userId" gorm:"not null;index"`      // 所属用户ID
+	Name        string        `json:"name" gorm:"size:64;not null"`      // 名称
+	Description string        `json:"description" gorm:"size:256"`       // 说明
+	Items       TemplateItems `json:"items" gorm:"type:json"`            // 待办事项
+}
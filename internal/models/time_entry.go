@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// TimeEntry 待办事项的时间记录
+// 计时器启动后 EndedAt 为空，停止时写入 EndedAt 和 Seconds；手动录入的记录直接包含两者
+// 每个用户同一时间最多只有一个正在运行的计时器
+type TimeEntry struct {
+	Base
+	WorkspaceID uint       `json:"workspaceId" gorm:"not null;index"`                                        // 所属工作空间ID
+	UserID      uint       `json:"userId" gorm:"not null;index:idx_time_entries_user_started,priority:1"`    // 记录时间的用户ID
+	TodoID      uint       `json:"todoId" gorm:"not null;index"`                                             // 待办事项ID
+	StartedAt   time.Time  `json:"startedAt" gorm:"not null;index:idx_time_entries_user_started,priority:2"` // 开始时间
+	EndedAt     *time.Time `json:"endedAt"`                                                                  // 结束时间，计时器运行中时为空
+	Seconds     int64      `json:"seconds" gorm:"not null;default:0"`                                        // 时长（秒），计时器运行中时为 0
+	Note        string     `json:"note" gorm:"size:256"`                                                     // 备注
+}
+
+// Running 计时器是否正在运行
+func (e *TimeEntry) Running() bool {
+	return e.EndedAt == nil
+}
+
+// Elapsed 返回截至 now 的时长（秒），正在运行的计时器按已经过的时间计算
+func (e *TimeEntry) Elapsed(now time.Time) int64 {
+	if e.Running() {
+		if now.Before(e.StartedAt) {
+			return 0
+		}
+		return int64(now.Sub(e.StartedAt) / time.Second)
+	}
+	return e.Seconds
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Priority 优先级类型
 // 用于定义待办事项的优先级别
 type Priority string
@@ -15,13 +17,24 @@ const (
 // 通过外键关联用户和分类信息
 type Todo struct {
 	Base
-	Title       string     `json:"title" gorm:"size:128;not null;index"`                  // 待办事项标题，不超过128字符
-	Description string     `json:"description" gorm:"size:1024"`                    // 待办事项描述，不超过1024字符
+	Title       string     `json:"title" gorm:"size:128;not null;index;index:idx_todos_fulltext,class:FULLTEXT,option:WITH PARSER ngram"` // 待办事项标题，不超过128字符
+	Description string     `json:"description" gorm:"size:1024;index:idx_todos_fulltext,class:FULLTEXT,option:WITH PARSER ngram"`        // 待办事项描述，不超过1024字符
 	Completed   bool       `json:"completed" gorm:"default:false;index"`                  // 完成状态，默认为未完成
 	Priority    Priority   `json:"priority" gorm:"default:medium;index"`                       // 优先级，默认为中优先级
+	CompletedAt *time.Time `json:"completedAt"`                                     // 完成时间，未完成时为空
+	Archived    bool       `json:"archived" gorm:"default:false;index"`             // 是否已归档，归档后默认不出现在列表中
+	ArchivedAt  *time.Time `json:"archivedAt"`                                      // 归档时间
+	DueDate     *time.Time `json:"dueDate" gorm:"index"`                            // 截止时间，允许为空
+	WorkspaceID uint       `json:"workspaceId" gorm:"not null;index"`        // 所属工作空间ID
 	UserID      uint       `json:"userId" gorm:"not null;index"`             // 所属用户ID
 	User        User       `gorm:"foreignKey:UserID" json:"-"`                      // 关联的用户信息，json序列化时忽略
-	CategoryID  *uint      `json:"categoryId" gorm:"index"`                 // 所属分类ID，允许为空
+	CategoryID  *uint      `json:"categoryId" gorm:"index;index:idx_todos_category_position,priority:1"` // 所属分类ID，允许为空
+	Position    string     `json:"position" gorm:"size:64;index:idx_todos_category_position,priority:2"` // 在分类内的手动排序位置，字典序排名
+	StatusID    *uint      `json:"statusId" gorm:"index"`                           // 看板工作流状态ID，允许为空；终止状态与 Completed 保持一致
+	Estimate    *int       `json:"estimate"`                                        // 预估耗时（分钟），允许为空
+	CalendarUID  string    `json:"-" gorm:"size:255"`                               // 通过 CalDAV 创建时客户端指定的 UID，为空时使用默认 UID
+	CalendarName string    `json:"-" gorm:"size:255;index"`                         // 通过 CalDAV 创建时的资源名，为空时使用默认资源名
 	Category    *Category  `json:"category,omitempty" gorm:"foreignKey:CategoryID"` // 关联的分类信息
 	Reminders   []Reminder `json:"reminders,omitempty" gorm:"foreignKey:TodoID"`    // 关联的提醒列表
+	Tags        []Tag      `json:"tags,omitempty" gorm:"many2many:todo_tags"`       // 标签
 }
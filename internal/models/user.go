@@ -9,9 +9,10 @@ import (
 // 密码以加密形式存储，使用bcrypt加密算法
 type User struct {
 	Base
-	Username string `json:"username" gorm:"uniqueIndex;size:32"`
-	Password string `json:"-" gorm:"size:128"`
-	Email    string `json:"email" gorm:"size:128"`
+	Username           string `json:"username" gorm:"uniqueIndex;size:32"`
+	Password           string `json:"-" gorm:"size:128"`
+	Email              string `json:"email" gorm:"size:128"`
+	DefaultWorkspaceID uint   `json:"defaultWorkspaceId" gorm:"index"` // 登录时默认进入的工作空间
 }
 
 // SetPassword 设置用户密码
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// Webhook 事件类型
+const (
+	WebhookEventAll             = "*"                // 订阅全部事件
+	WebhookEventTodoCreated     = "todo.created"     // 创建待办事项
+	WebhookEventTodoUpdated     = "todo.updated"     // 修改待办事项（包括完成、归档和回滚）
+	WebhookEventTodoCompleted   = "todo.completed"   // 待办事项由未完成变为已完成
+	WebhookEventTodoDeleted     = "todo.deleted"     // 待办事项移入回收站
+	WebhookEventTodoRestored    = "todo.restored"    // 待办事项从回收站恢复
+	WebhookEventReminderCreated = "reminder.created" // 创建提醒
+	WebhookEventReminderUpdated = "reminder.updated" // 修改提醒
+	WebhookEventReminderDeleted = "reminder.deleted" // 删除提醒
+	WebhookEventReminderFired   = "reminder.fired"   // 提醒时间已到
+	WebhookEventPing            = "webhook.ping"     // 测试投递，总是发送给被测试的端点
+)
+
+// WebhookEvents 可以订阅的事件类型
+var WebhookEvents = []string{
+	WebhookEventTodoCreated, WebhookEventTodoUpdated, WebhookEventTodoCompleted, WebhookEventTodoDeleted,
+	WebhookEventTodoRestored, WebhookEventReminderCreated, WebhookEventReminderUpdated,
+	WebhookEventReminderDeleted, WebhookEventReminderFired,
+}
+
+// WebhookEventList 订阅的事件类型列表，以 JSON 形式存储
+type WebhookEventList []string
+
+// Value 实现 driver.Valuer 接口
+func (l WebhookEventList) Value() (driver.Value, error) {
+	return json.Marshal(l)
+}
+
+// Scan 实现 sql.Scanner 接口
+func (l *WebhookEventList) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
+
+// Matches 判断是否订阅了指定事件
+func (l WebhookEventList) Matches(event string) bool {
+	for _, e := range l {
+		if e == event || e == WebhookEventAll {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook 用户注册的 webhook 端点
+// 订阅的事件发生后向 URL 发送以密钥签名的 JSON；连续多次投递失败（重试耗尽）后自动停用
+type Webhook struct {
+	Base
+	WorkspaceID uint             `json:"workspaceId" gorm:"not null;index:idx_webhooks_owner,priority:1"` // 所属工作空间ID
+	UserID      uint             `json:"userId" gorm:"not null;index:idx_webhooks_owner,priority:2"`      // 所属用户ID
+	URL         string           `json:"url" gorm:"size:512;not null"`                                    // 接收事件的地址
+	Secret      string           `json:"-" gorm:"size:64;not null"`                                       // 签名密钥
+	Events      WebhookEventList `json:"events" gorm:"type:json"`                                         // 订阅的事件类型
+	Enabled     bool             `json:"enabled" gorm:"not null;default:true"`                            // 是否启用
+	Failures    int              `json:"failures" gorm:"not null;default:0"`                              // 连续投递失败次数，投递成功后清零
+	DisabledAt  *time.Time       `json:"disabledAt"`                                                      // 因连续失败被自动停用的时间
+}
+
+// WebhookDelivery webhook 投递日志，每次尝试记录一条
+type WebhookDelivery struct {
+	Base
+	WebhookID  uint   `json:"webhookId" gorm:"not null;index"`       // 所属端点ID
+	DeliveryID string `json:"deliveryId" gorm:"size:36;not null"`    // 投递ID，同一事件的多次重试相同
+	Event      string `json:"event" gorm:"size:32;not null"`         // 事件类型
+	Attempt    int    `json:"attempt" gorm:"not null"`               // 第几次尝试，从 1 开始
+	Payload    string `json:"payload" gorm:"type:text"`              // 发送的请求体
+	StatusCode int    `json:"statusCode"`                            // 响应状态码，请求失败时为 0
+	Success    bool   `json:"success" gorm:"not null;default:false"` // 是否成功（响应状态码为 2xx）
+	Error      string `json:"error,omitempty" gorm:"size:255"`       // 失败原因
+	Duration   int64  `json:"duration"`                              // 请求耗时（毫秒）
+}
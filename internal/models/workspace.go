@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// 工作空间成员角色常量
+const (
+	WorkspaceRoleOwner  = "owner"  // 所有者，可管理成员和邀请
+	WorkspaceRoleAdmin  = "admin"  // 管理员，可管理成员和邀请
+	WorkspaceRoleMember = "member" // 普通成员
+)
+
+// Workspace 工作空间（租户）模型
+// 工作空间拥有其下所有的分类、待办事项和提醒，不同工作空间之间的数据完全隔离
+type Workspace struct {
+	Base
+	Name    string `json:"name" gorm:"size:64;not null"`  // 工作空间名称
+	OwnerID uint   `json:"ownerId" gorm:"not null;index"` // 创建者用户ID
+}
+
+// WorkspaceMember 工作空间成员模型
+// 记录用户与工作空间的归属关系及其角色
+type WorkspaceMember struct {
+	Base
+	WorkspaceID uint   `json:"workspaceId" gorm:"not null;uniqueIndex:idx_workspace_members_ws_user"`  // 工作空间ID
+	UserID      uint   `json:"userId" gorm:"not null;uniqueIndex:idx_workspace_members_ws_user;index"` // 用户ID
+	Role        string `json:"role" gorm:"size:16;not null;default:member"`                            // 成员角色
+	User        *User  `json:"user,omitempty" gorm:"foreignKey:UserID"`                                // 关联的用户信息
+}
+
+// CanManage 判断成员是否有管理工作空间成员和邀请的权限
+func (m *WorkspaceMember) CanManage() bool {
+	return m.Role == WorkspaceRoleOwner || m.Role == WorkspaceRoleAdmin
+}
+
+// WorkspaceInvite 工作空间邀请模型
+// 邀请通过随机令牌进行接受，可选地限定被邀请人的邮箱
+type WorkspaceInvite struct {
+	Base
+	WorkspaceID uint       `json:"workspaceId" gorm:"not null;index"`           // 工作空间ID
+	Email       string     `json:"email" gorm:"size:128"`                       // 被邀请人邮箱，为空表示任何持有令牌的用户均可接受
+	Role        string     `json:"role" gorm:"size:16;not null;default:member"` // 接受后获得的角色
+	Token       string     `json:"token" gorm:"size:64;not null;uniqueIndex"`   // 邀请令牌
+	InvitedBy   uint       `json:"invitedBy" gorm:"not null"`                   // 邀请人用户ID
+	ExpiresAt   time.Time  `json:"expiresAt" gorm:"not null"`                   // 过期时间
+	AcceptedAt  *time.Time `json:"acceptedAt"`                                  // 接受时间，为空表示尚未接受
+}
@@ -0,0 +1,351 @@
+// Package query 实现待办事项过滤用的查询语言
+//
+// 查询由空白分隔的若干条件组成，条件之间是“且”的关系：
+//
+//	query    = { term }
+//	term     = [ "-" ] ( field op value | flag | text )
+//	field    = "priority" | "due" | "category" | "tag"
+//	op       = ":" | "<" | "<=" | ">" | ">="
+//	value    = word | quoted
+//	flag     = "completed" | "archived"
+//	text     = word | quoted
+//	quoted   = '"' { 任意字符 | '\"' } '"'
+//
+// 各字段支持的写法：
+//
+//	priority:high            优先级为 low、medium 或 high
+//	due:today                截止时间为 today、this_week、overdue（已逾期且未完成）或 none（没有截止时间）
+//	due:2024-05-01           截止时间在指定日期当天
+//	due<7d  due>=2024-05-01  与相对当前时间的偏移（h 小时、d 天、w 周）或日期比较
+//	category:"Work"          分类名称（不区分大小写）
+//	tag:release              带有指定标签
+//	completed  archived      已完成、已归档
+//	周报  "weekly report"     标题或描述中包含的文本
+//
+// 任何条件前加 "-" 表示取反，例如 -completed、-tag:release。
+// 字段名和关键字不区分大小写；需要按 completed 等关键字本身搜索文本时使用引号。
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"todo/internal/models"
+	"unicode"
+)
+
+// Field 条件类型
+type Field string
+
+const (
+	FieldText      Field = "text"      // 标题或描述中的文本
+	FieldPriority  Field = "priority"  // 优先级
+	FieldDue       Field = "due"       // 截止时间
+	FieldCategory  Field = "category"  // 分类名称
+	FieldTag       Field = "tag"       // 标签
+	FieldCompleted Field = "completed" // 已完成
+	FieldArchived  Field = "archived"  // 已归档
+)
+
+// Op 比较运算符
+type Op string
+
+const (
+	OpEq  Op = ":"
+	OpLt  Op = "<"
+	OpLte Op = "<="
+	OpGt  Op = ">"
+	OpGte Op = ">="
+)
+
+// dateLayout due 字段的日期格式
+const dateLayout = "2006-01-02"
+
+// Term 单个过滤条件
+type Term struct {
+	Pos    int    // 条件在查询中的起始位置，从 1 开始按字符计
+	Negate bool   // 是否取反
+	Field  Field  // 条件类型
+	Op     Op     // 比较运算符，文本和关键字条件为 OpEq
+	Value  string // 规范化后的值：优先级、due 关键字、分类名、标签名或文本
+
+	// 仅 due 字段使用：Value 为空时按 Date 比较，Date 也为零值时按 Offset 比较
+	Offset time.Duration // 相对当前时间的偏移
+	Date   time.Time     // 日期（当天零点，本地时区）
+}
+
+// Query 解析后的查询
+type Query struct {
+	Terms []Term
+}
+
+// Has 判断查询中是否包含指定类型的条件
+func (q *Query) Has(field Field) bool {
+	if q == nil {
+		return false
+	}
+	for _, t := range q.Terms {
+		if t.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// SyntaxError 查询语法错误，包含出错位置和期望的内容
+type SyntaxError struct {
+	Pos      int    // 出错位置，从 1 开始按字符计
+	Expected string // 期望的内容
+	Found    string // 实际遇到的内容
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("查询语法错误：第 %d 个字符处期望 %s，实际为 %s", e.Pos, e.Expected, e.Found)
+}
+
+// Parse 解析查询字符串，空字符串返回没有条件的查询
+func Parse(input string) (*Query, error) {
+	p := &parser{src: []rune(input)}
+	q := &Query{}
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return q, nil
+		}
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		q.Terms = append(q.Terms, term)
+	}
+}
+
+// parser 递归下降解析器，pos 为 src 中的下标（从 0 开始）
+type parser struct {
+	src []rune
+	pos int
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+// errorAt 构造指定位置的语法错误，Found 取该位置的字符
+func (p *parser) errorAt(pos int, expected string) *SyntaxError {
+	found := "查询结尾"
+	if pos < len(p.src) {
+		found = strconv.Quote(string(p.src[pos]))
+		if unicode.IsSpace(p.src[pos]) {
+			found = "空白"
+		}
+	}
+	return &SyntaxError{Pos: pos + 1, Expected: expected, Found: found}
+}
+
+// term 解析一个条件
+func (p *parser) term() (Term, error) {
+	term := Term{Pos: p.pos + 1, Op: OpEq}
+	if p.peek() == '-' {
+		term.Negate = true
+		p.pos++
+		if p.eof() || unicode.IsSpace(p.peek()) {
+			return term, p.errorAt(p.pos, "字段、关键字或文本")
+		}
+	}
+
+	if p.peek() == '"' {
+		text, err := p.quoted()
+		if err != nil {
+			return term, err
+		}
+		term.Field, term.Value = FieldText, text
+		return term, nil
+	}
+
+	start := p.pos
+	word := p.word()
+	if word == "" {
+		return term, p.errorAt(p.pos, "字段、关键字或文本")
+	}
+
+	op, ok := p.op()
+	if !ok {
+		switch Field(strings.ToLower(word)) {
+		case FieldCompleted:
+			term.Field = FieldCompleted
+		case FieldArchived:
+			term.Field = FieldArchived
+		default:
+			term.Field, term.Value = FieldText, word
+		}
+		return term, nil
+	}
+
+	term.Field, term.Op = Field(strings.ToLower(word)), op
+	switch term.Field {
+	case FieldPriority, FieldCategory, FieldTag, FieldDue:
+	default:
+		return term, &SyntaxError{Pos: start + 1, Expected: "字段 priority、due、category 或 tag", Found: strconv.Quote(word)}
+	}
+	if term.Field != FieldDue && op != OpEq {
+		return term, &SyntaxError{Pos: p.pos - len(op) + 1, Expected: `":"`, Found: strconv.Quote(string(op))}
+	}
+
+	valuePos := p.pos
+	value, err := p.value()
+	if err != nil {
+		return term, err
+	}
+	term.Value = value
+
+	switch term.Field {
+	case FieldPriority:
+		term.Value = strings.ToLower(value)
+		if term.Value != "low" && term.Value != "medium" && term.Value != "high" {
+			return term, &SyntaxError{Pos: valuePos + 1, Expected: "low、medium 或 high", Found: strconv.Quote(value)}
+		}
+	case FieldDue:
+		if err := parseDue(&term, value); err != nil {
+			err.Pos = valuePos + 1
+			return term, err
+		}
+	}
+	return term, nil
+}
+
+// word 读取一个不含空白、引号和运算符的词
+func (p *parser) word() string {
+	start := p.pos
+	for !p.eof() {
+		r := p.peek()
+		if unicode.IsSpace(r) || r == '"' || r == ':' || r == '<' || r == '>' {
+			break
+		}
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+// op 读取比较运算符，当前位置不是运算符时返回 false
+func (p *parser) op() (Op, bool) {
+	switch p.peek() {
+	case ':':
+		p.pos++
+		return OpEq, true
+	case '<', '>':
+		op := Op(p.peek())
+		p.pos++
+		if p.peek() == '=' {
+			p.pos++
+			op += "="
+		}
+		return op, true
+	}
+	return "", false
+}
+
+// value 读取字段的值：带引号的字符串或一个词
+func (p *parser) value() (string, error) {
+	if p.peek() == '"' {
+		return p.quoted()
+	}
+	start := p.pos
+	for !p.eof() && !unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorAt(p.pos, "值")
+	}
+	return string(p.src[start:p.pos]), nil
+}
+
+// quoted 读取带引号的字符串，支持 \" 和 \\ 转义
+func (p *parser) quoted() (string, error) {
+	open := p.pos
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		r := p.peek()
+		p.pos++
+		switch {
+		case r == '"':
+			if b.Len() == 0 {
+				return "", &SyntaxError{Pos: open + 1, Expected: "非空字符串", Found: `""`}
+			}
+			return b.String(), nil
+		case r == '\\' && !p.eof() && (p.peek() == '"' || p.peek() == '\\'):
+			b.WriteRune(p.peek())
+			p.pos++
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return "", &SyntaxError{Pos: len(p.src) + 1, Expected: `闭合的 '"'（起始于第 ` + strconv.Itoa(open+1) + ` 个字符）`, Found: "查询结尾"}
+}
+
+// parseDue 解析 due 字段的值：关键字、相对偏移或日期
+func parseDue(term *Term, value string) *SyntaxError {
+	lower := strings.ToLower(value)
+	switch lower {
+	case models.DueToday, models.DueThisWeek, models.DueOverdue, models.DueNone:
+		if term.Op != OpEq {
+			return &SyntaxError{Expected: "相对时间（如 7d）或日期（如 2024-05-01）", Found: strconv.Quote(value)}
+		}
+		term.Value = lower
+		return nil
+	}
+
+	term.Value = ""
+	if date, err := time.ParseInLocation(dateLayout, value, time.Local); err == nil {
+		term.Date = date
+		return nil
+	}
+
+	if term.Op != OpEq && len(lower) > 1 {
+		n, err := strconv.Atoi(lower[:len(lower)-1])
+		if err == nil && n >= 0 {
+			switch lower[len(lower)-1] {
+			case 'h':
+				term.Offset = time.Duration(n) * time.Hour
+				return nil
+			case 'd':
+				term.Offset = time.Duration(n) * 24 * time.Hour
+				return nil
+			case 'w':
+				term.Offset = time.Duration(n) * 7 * 24 * time.Hour
+				return nil
+			}
+		}
+	}
+
+	if term.Op == OpEq {
+		return &SyntaxError{Expected: "today、this_week、overdue、none 或日期（如 2024-05-01）", Found: strconv.Quote(value)}
+	}
+	return &SyntaxError{Expected: "相对时间（如 7d）或日期（如 2024-05-01）", Found: strconv.Quote(value)}
+}
+
+// DueRange 返回 today、this_week 以 now 为基准的时间范围 [from, before)，一周从周一开始
+// 其他取值返回 false
+func DueRange(due string, now time.Time) (from, before time.Time, ok bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch due {
+	case models.DueToday:
+		return today, today.AddDate(0, 0, 1), true
+	case models.DueThisWeek:
+		start := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		return start, start.AddDate(0, 0, 7), true
+	}
+	return time.Time{}, time.Time{}, false
+}
@@ -0,0 +1,106 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todo/internal/models"
+)
+
+// TestParse 测试查询的解析结果
+func TestParse(t *testing.T) {
+	q, err := Parse(`priority:HIGH due<7d -completed tag:release category:"Work Stuff" 周报 "follow-up \"a\""`)
+	if err != nil {
+		t.Fatalf("Parse() 错误 = %v", err)
+	}
+
+	want := []Term{
+		{Pos: 1, Field: FieldPriority, Op: OpEq, Value: "high"},
+		{Pos: 15, Field: FieldDue, Op: OpLt, Offset: 7 * 24 * time.Hour},
+		{Pos: 22, Negate: true, Field: FieldCompleted, Op: OpEq},
+		{Pos: 33, Field: FieldTag, Op: OpEq, Value: "release"},
+		{Pos: 45, Field: FieldCategory, Op: OpEq, Value: "Work Stuff"},
+		{Pos: 67, Field: FieldText, Op: OpEq, Value: "周报"},
+		{Pos: 70, Field: FieldText, Op: OpEq, Value: `follow-up "a"`},
+	}
+	if len(q.Terms) != len(want) {
+		t.Fatalf("条件数量 = %d, 期望 %d: %+v", len(q.Terms), len(want), q.Terms)
+	}
+	for i, term := range q.Terms {
+		if term != want[i] {
+			t.Errorf("条件 %d = %+v, 期望 %+v", i, term, want[i])
+		}
+	}
+}
+
+// TestParseDue 测试 due 字段的各种写法
+func TestParseDue(t *testing.T) {
+	tests := []struct {
+		input  string
+		op     Op
+		value  string
+		offset time.Duration
+		date   time.Time
+	}{
+		{"due:today", OpEq, models.DueToday, 0, time.Time{}},
+		{"due:None", OpEq, models.DueNone, 0, time.Time{}},
+		{"due:2024-05-01", OpEq, "", 0, time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)},
+		{"due>=2w", OpGte, "", 14 * 24 * time.Hour, time.Time{}},
+		{"due<=12h", OpLte, "", 12 * time.Hour, time.Time{}},
+		{"due>2024-05-01", OpGt, "", 0, time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) 错误 = %v", tt.input, err)
+			continue
+		}
+		term := q.Terms[0]
+		if term.Op != tt.op || term.Value != tt.value || term.Offset != tt.offset || !term.Date.Equal(tt.date) {
+			t.Errorf("Parse(%q) = %+v", tt.input, term)
+		}
+	}
+}
+
+// TestParseErrors 测试语法错误的位置和提示
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		pos      int
+		expected string
+	}{
+		{"priority:urgent", 10, "low、medium 或 high"},
+		{"status:open", 1, "字段 priority、due、category 或 tag"},
+		{"a priority<high", 11, `":"`},
+		{"tag:", 5, "值"},
+		{"tag: x", 5, "值"},
+		{"milk -", 7, "字段、关键字或文本"},
+		{":x", 1, "字段、关键字或文本"},
+		{`category:"Work`, 15, `闭合的 '"'（起始于第 10 个字符）`},
+		{`""`, 1, "非空字符串"},
+		{"due:7d", 5, "today、this_week、overdue、none 或日期（如 2024-05-01）"},
+		{"due<today", 5, "相对时间（如 7d）或日期（如 2024-05-01）"},
+		{"due<7x", 5, "相对时间（如 7d）或日期（如 2024-05-01）"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Parse(%q) 错误 = %v, 期望语法错误", tt.input, err)
+			continue
+		}
+		if syntaxErr.Pos != tt.pos || syntaxErr.Expected != tt.expected {
+			t.Errorf("Parse(%q) = 位置 %d 期望 %q, 应为位置 %d 期望 %q", tt.input, syntaxErr.Pos, syntaxErr.Expected, tt.pos, tt.expected)
+		}
+	}
+}
+
+// TestParseEmpty 测试空查询
+func TestParseEmpty(t *testing.T) {
+	q, err := Parse("   ")
+	if err != nil || len(q.Terms) != 0 {
+		t.Errorf("Parse() = %+v, %v, 期望没有条件", q, err)
+	}
+}
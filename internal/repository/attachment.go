@@ -0,0 +1,76 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// AttachmentRepository 定义附件仓储接口
+// 所有方法都限定在上下文中的当前工作空间内
+type AttachmentRepository interface {
+	// Create 创建附件记录
+	// ctx: 上下文信息
+	// attachment: 附件信息
+	// 返回: error 创建过程中的错误信息
+	Create(ctx context.Context, attachment *models.Attachment) error
+
+	// GetByID 根据ID获取附件
+	// ctx: 上下文信息
+	// id: 附件ID
+	// 返回: (*models.Attachment, error) 附件信息和可能的错误
+	GetByID(ctx context.Context, id uint) (*models.Attachment, error)
+
+	// ListByTodoID 获取待办事项的所有附件
+	// ctx: 上下文信息
+	// todoID: 待办事项ID
+	// 返回: ([]*models.Attachment, error) 附件列表和可能的错误
+	ListByTodoID(ctx context.Context, todoID uint) ([]*models.Attachment, error)
+
+	// Delete 删除附件记录
+	// ctx: 上下文信息
+	// id: 要删除的附件ID
+	// 返回: error 删除过程中的错误信息
+	Delete(ctx context.Context, id uint) error
+}
+
+// attachmentRepo 实现 AttachmentRepository 接口
+type attachmentRepo struct {
+	db *gorm.DB
+}
+
+func (r *attachmentRepo) Create(ctx context.Context, attachment *models.Attachment) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	attachment.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(attachment).Error
+}
+
+func (r *attachmentRepo) GetByID(ctx context.Context, id uint) (*models.Attachment, error) {
+	var attachment models.Attachment
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "attachments")).First(&attachment, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepo) ListByTodoID(ctx context.Context, todoID uint) ([]*models.Attachment, error) {
+	var attachments []*models.Attachment
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "attachments")).Where("todo_id = ?", todoID).Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *attachmentRepo) Delete(ctx context.Context, id uint) error {
+	// 附件内容会同时从对象存储中删除，元数据不再保留软删除记录
+	return conn(ctx, r.db).Unscoped().Scopes(workspaceScope(ctx, "attachments")).Delete(&models.Attachment{}, id).Error
+}
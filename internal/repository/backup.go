@@ -0,0 +1,92 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// DataExportRepository 定义数据导出任务仓储接口
+// 所有方法都限定在上下文中的当前工作空间内
+type DataExportRepository interface {
+	// Create 创建数据导出任务
+	// ctx: 上下文信息
+	// export: 数据导出任务
+	// 返回: error 创建过程中的错误信息
+	Create(ctx context.Context, export *models.DataExport) error
+
+	// GetByID 根据ID获取数据导出任务
+	// ctx: 上下文信息
+	// id: 数据导出任务ID
+	// 返回: (*models.DataExport, error) 不存在时返回 ErrDataExportNotFound
+	GetByID(ctx context.Context, id uint) (*models.DataExport, error)
+
+	// GetLatest 获取用户在当前工作空间中最近一次的数据导出任务
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// 返回: (*models.DataExport, error) 不存在时返回 ErrDataExportNotFound
+	GetLatest(ctx context.Context, userID uint) (*models.DataExport, error)
+
+	// Update 更新数据导出任务的状态和结果
+	// ctx: 上下文信息
+	// export: 数据导出任务
+	// 返回: error 更新过程中的错误信息
+	Update(ctx context.Context, export *models.DataExport) error
+
+	// Delete 删除数据导出任务
+	// ctx: 上下文信息
+	// id: 数据导出任务ID
+	// 返回: error 删除过程中的错误信息
+	Delete(ctx context.Context, id uint) error
+}
+
+// dataExportRepo 实现 DataExportRepository 接口
+type dataExportRepo struct {
+	db *gorm.DB
+}
+
+func (r *dataExportRepo) Create(ctx context.Context, export *models.DataExport) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	export.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(export).Error
+}
+
+func (r *dataExportRepo) GetByID(ctx context.Context, id uint) (*models.DataExport, error) {
+	var export models.DataExport
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "data_exports")).First(&export, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrDataExportNotFound
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *dataExportRepo) GetLatest(ctx context.Context, userID uint) (*models.DataExport, error) {
+	var export models.DataExport
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "data_exports")).Where("user_id = ?", userID).
+		Order("id DESC").First(&export).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrDataExportNotFound
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *dataExportRepo) Update(ctx context.Context, export *models.DataExport) error {
+	return conn(ctx, r.db).Model(export).Scopes(workspaceScope(ctx, "data_exports")).
+		Select("status", "storage_key", "size", "error", "finished_at").Updates(export).Error
+}
+
+func (r *dataExportRepo) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Unscoped().Scopes(workspaceScope(ctx, "data_exports")).
+		Delete(&models.DataExport{}, id).Error
+}
@@ -0,0 +1,93 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// CalendarFeedRepository 定义日历订阅仓储接口
+// 除 GetByToken 外，所有方法都限定在上下文中的当前工作空间内
+type CalendarFeedRepository interface {
+	// Create 创建日历订阅
+	// ctx: 上下文信息
+	// feed: 日历订阅
+	// 返回: error 创建过程中的错误信息
+	Create(ctx context.Context, feed *models.CalendarFeed) error
+
+	// GetByUserID 获取用户在当前工作空间中的日历订阅
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// 返回: (*models.CalendarFeed, error) 不存在时返回 ErrCalendarFeedNotFound
+	GetByUserID(ctx context.Context, userID uint) (*models.CalendarFeed, error)
+
+	// GetByToken 根据令牌获取日历订阅
+	// 日历客户端拉取订阅时没有登录状态，因此不限定工作空间，调用方应使用返回的工作空间ID
+	// ctx: 上下文信息
+	// token: 订阅令牌
+	// 返回: (*models.CalendarFeed, error) 不存在时返回 ErrCalendarFeedNotFound
+	GetByToken(ctx context.Context, token string) (*models.CalendarFeed, error)
+
+	// UpdateToken 更新日历订阅的令牌
+	// ctx: 上下文信息
+	// feed: 日历订阅，Token 为新令牌
+	// 返回: error 更新过程中的错误信息
+	UpdateToken(ctx context.Context, feed *models.CalendarFeed) error
+
+	// Delete 删除日历订阅
+	// ctx: 上下文信息
+	// id: 日历订阅ID
+	// 返回: error 删除过程中的错误信息
+	Delete(ctx context.Context, id uint) error
+}
+
+// calendarFeedRepo 实现 CalendarFeedRepository 接口
+type calendarFeedRepo struct {
+	db *gorm.DB
+}
+
+func (r *calendarFeedRepo) Create(ctx context.Context, feed *models.CalendarFeed) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	feed.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(feed).Error
+}
+
+func (r *calendarFeedRepo) GetByUserID(ctx context.Context, userID uint) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "calendar_feeds")).Where("user_id = ?", userID).First(&feed).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrCalendarFeedNotFound
+		}
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *calendarFeedRepo) GetByToken(ctx context.Context, token string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	if err := conn(ctx, r.db).Where("token = ?", token).First(&feed).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrCalendarFeedNotFound
+		}
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *calendarFeedRepo) UpdateToken(ctx context.Context, feed *models.CalendarFeed) error {
+	return conn(ctx, r.db).Model(feed).Scopes(workspaceScope(ctx, "calendar_feeds")).
+		Update("token", feed.Token).Error
+}
+
+func (r *calendarFeedRepo) Delete(ctx context.Context, id uint) error {
+	// 使用硬删除，以便之后可以重新创建订阅（唯一索引不区分软删除）
+	return conn(ctx, r.db).Unscoped().Scopes(workspaceScope(ctx, "calendar_feeds")).
+		Delete(&models.CalendarFeed{}, id).Error
+}
@@ -7,9 +7,11 @@ import (
 	"todo/pkg/errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CategoryRepository 定义分类仓储接口
+// 所有方法都限定在上下文中的当前工作空间内
 type CategoryRepository interface {
 	// Create 创建新的分类
 	// ctx: 上下文信息
@@ -29,6 +31,13 @@ type CategoryRepository interface {
 	// 返回: ([]*models.Category, error) 分类列表和可能的错误
 	ListByUserID(ctx context.Context, userID uint) ([]*models.Category, error)
 
+	// LockByUserID 获取并锁定用户的所有分类，必须在事务中调用
+	// 通过 SELECT ... FOR UPDATE 锁定，同一用户并发的分类移动按顺序执行，直到事务结束
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// 返回: ([]*models.Category, error) 分类列表和可能的错误
+	LockByUserID(ctx context.Context, userID uint) ([]*models.Category, error)
+
 	// Update 更新分类信息
 	// ctx: 上下文信息
 	// category: 需要更新的分类信息
@@ -48,12 +57,17 @@ type categoryRepo struct {
 }
 
 func (r *categoryRepo) Create(ctx context.Context, category *models.Category) error {
-	return r.db.WithContext(ctx).Create(category).Error
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	category.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(category).Error
 }
 
 func (r *categoryRepo) GetByID(ctx context.Context, id uint) (*models.Category, error) {
 	var category models.Category
-	if err := r.db.WithContext(ctx).First(&category, id).Error; err != nil {
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "categories")).First(&category, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.ErrCategoryNotFound
 		}
@@ -64,16 +78,32 @@ func (r *categoryRepo) GetByID(ctx context.Context, id uint) (*models.Category,
 
 func (r *categoryRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.Category, error) {
 	var categories []*models.Category
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&categories).Error; err != nil {
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "categories")).Where("user_id = ?", userID).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepo) LockByUserID(ctx context.Context, userID uint) ([]*models.Category, error) {
+	var categories []*models.Category
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "categories")).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).Find(&categories).Error
+	if err != nil {
 		return nil, err
 	}
 	return categories, nil
 }
 
 func (r *categoryRepo) Update(ctx context.Context, category *models.Category) error {
-	return r.db.WithContext(ctx).Save(category).Error
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	category.WorkspaceID = wsID
+	return conn(ctx, r.db).Model(category).Scopes(workspaceScope(ctx, "categories")).
+		Select("*").Omit(clause.Associations).Updates(category).Error
 }
 
 func (r *categoryRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.Category{}, id).Error
+	return conn(ctx, r.db).Scopes(workspaceScope(ctx, "categories")).Delete(&models.Category{}, id).Error
 }
@@ -0,0 +1,148 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommentRepository 定义评论仓储接口
+// 所有方法都限定在上下文中的当前工作空间内
+type CommentRepository interface {
+	// Create 创建新的评论
+	// ctx: 上下文信息
+	// comment: 评论信息
+	// 返回: error 创建过程中的错误信息
+	Create(ctx context.Context, comment *models.Comment) error
+
+	// GetByID 根据ID获取评论，包含作者信息
+	// ctx: 上下文信息
+	// id: 评论ID
+	// 返回: (*models.Comment, error) 评论信息和可能的错误
+	GetByID(ctx context.Context, id uint) (*models.Comment, error)
+
+	// ListByTodoID 获取待办事项的评论，按创建时间倒序
+	// ctx: 上下文信息
+	// todoID: 待办事项ID
+	// limit: 返回的最大数量，小于等于0表示不限制
+	// 返回: ([]*models.Comment, error) 评论列表和可能的错误
+	ListByTodoID(ctx context.Context, todoID uint, limit int) ([]*models.Comment, error)
+
+	// Update 更新评论
+	// ctx: 上下文信息
+	// comment: 需要更新的评论信息
+	// 返回: error 更新过程中的错误信息
+	Update(ctx context.Context, comment *models.Comment) error
+
+	// Delete 删除评论
+	// ctx: 上下文信息
+	// id: 要删除的评论ID
+	// 返回: error 删除过程中的错误信息
+	Delete(ctx context.Context, id uint) error
+
+	// CreateRevision 保存一条评论编辑历史
+	// ctx: 上下文信息
+	// revision: 编辑历史信息
+	// 返回: error 创建过程中的错误信息
+	CreateRevision(ctx context.Context, revision *models.CommentRevision) error
+
+	// ListRevisions 获取评论的编辑历史，按时间正序
+	// ctx: 上下文信息
+	// commentID: 评论ID
+	// 返回: ([]*models.CommentRevision, error) 编辑历史列表和可能的错误
+	ListRevisions(ctx context.Context, commentID uint) ([]*models.CommentRevision, error)
+
+	// PurgeByTodoID 永久删除待办事项的所有评论及其编辑历史
+	// ctx: 上下文信息
+	// todoID: 待办事项ID
+	// 返回: error 删除过程中的错误信息
+	PurgeByTodoID(ctx context.Context, todoID uint) error
+}
+
+// commentRepo 实现 CommentRepository 接口
+type commentRepo struct {
+	db *gorm.DB
+}
+
+func (r *commentRepo) Create(ctx context.Context, comment *models.Comment) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	comment.WorkspaceID = wsID
+	return conn(ctx, r.db).Omit(clause.Associations).Create(comment).Error
+}
+
+func (r *commentRepo) GetByID(ctx context.Context, id uint) (*models.Comment, error) {
+	var comment models.Comment
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "comments")).Preload("User").First(&comment, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *commentRepo) ListByTodoID(ctx context.Context, todoID uint, limit int) ([]*models.Comment, error) {
+	var comments []*models.Comment
+	db := conn(ctx, r.db).Scopes(workspaceScope(ctx, "comments")).Preload("User").
+		Where("todo_id = ?", todoID).Order("created_at DESC, id DESC")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	if err := db.Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *commentRepo) Update(ctx context.Context, comment *models.Comment) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	comment.WorkspaceID = wsID
+	return conn(ctx, r.db).Model(comment).Scopes(workspaceScope(ctx, "comments")).
+		Select("*").Omit(clause.Associations).Updates(comment).Error
+}
+
+func (r *commentRepo) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Scopes(workspaceScope(ctx, "comments")).Delete(&models.Comment{}, id).Error
+}
+
+func (r *commentRepo) CreateRevision(ctx context.Context, revision *models.CommentRevision) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	revision.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(revision).Error
+}
+
+func (r *commentRepo) ListRevisions(ctx context.Context, commentID uint) ([]*models.CommentRevision, error) {
+	var revisions []*models.CommentRevision
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "comment_revisions")).
+		Where("comment_id = ?", commentID).Order("created_at ASC, id ASC").Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *commentRepo) PurgeByTodoID(ctx context.Context, todoID uint) error {
+	db := conn(ctx, r.db)
+	commentIDs := db.Unscoped().Model(&models.Comment{}).Scopes(workspaceScope(ctx, "comments")).
+		Select("id").Where("todo_id = ?", todoID)
+	err := db.Unscoped().Scopes(workspaceScope(ctx, "comment_revisions")).
+		Where("comment_id IN (?)", commentIDs).Delete(&models.CommentRevision{}).Error
+	if err != nil {
+		return err
+	}
+	return db.Unscoped().Scopes(workspaceScope(ctx, "comments")).
+		Where("todo_id = ?", todoID).Delete(&models.Comment{}).Error
+}
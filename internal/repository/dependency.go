@@ -0,0 +1,130 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// DependencyRepository 定义待办事项依赖关系仓储接口
+// 所有方法都限定在上下文中的当前工作空间内
+type DependencyRepository interface {
+	// Create 创建依赖关系
+	// ctx: 上下文信息
+	// dep: 依赖关系
+	// 返回: error 创建过程中的错误信息
+	Create(ctx context.Context, dep *models.Dependency) error
+
+	// Delete 删除依赖关系
+	// ctx: 上下文信息
+	// todoID: 被阻塞的待办事项ID
+	// blockerID: 阻塞它的待办事项ID
+	// 返回: error 依赖关系不存在时返回 ErrDependencyNotFound
+	Delete(ctx context.Context, todoID, blockerID uint) error
+
+	// ListByUserID 获取用户所有待办事项（包括回收站中的）之间的依赖关系，用于检测环
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// 返回: ([]*models.Dependency, error) 依赖关系列表和可能的错误
+	ListByUserID(ctx context.Context, userID uint) ([]*models.Dependency, error)
+
+	// ListBlockers 获取阻塞待办事项的所有待办事项，不包括回收站中的
+	// ctx: 上下文信息
+	// todoID: 被阻塞的待办事项ID
+	// 返回: ([]*models.Todo, error) 待办事项列表和可能的错误
+	ListBlockers(ctx context.Context, todoID uint) ([]*models.Todo, error)
+
+	// ListBlocking 获取被待办事项阻塞的所有待办事项，不包括回收站中的
+	// ctx: 上下文信息
+	// blockerID: 阻塞其他待办事项的待办事项ID
+	// 返回: ([]*models.Todo, error) 待办事项列表和可能的错误
+	ListBlocking(ctx context.Context, blockerID uint) ([]*models.Todo, error)
+
+	// CountOpenBlockers 统计阻塞待办事项且尚未完成的待办事项数量，不包括回收站中的
+	// ctx: 上下文信息
+	// todoID: 被阻塞的待办事项ID
+	// 返回: (int64, error) 数量和可能的错误
+	CountOpenBlockers(ctx context.Context, todoID uint) (int64, error)
+
+	// DeleteByTodoID 删除待办事项作为任意一方的所有依赖关系，用于永久删除待办事项
+	// ctx: 上下文信息
+	// todoID: 待办事项ID
+	// 返回: error 删除过程中的错误信息
+	DeleteByTodoID(ctx context.Context, todoID uint) error
+}
+
+// dependencyRepo 实现 DependencyRepository 接口
+type dependencyRepo struct {
+	db *gorm.DB
+}
+
+func (r *dependencyRepo) Create(ctx context.Context, dep *models.Dependency) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	dep.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(dep).Error
+}
+
+func (r *dependencyRepo) Delete(ctx context.Context, todoID, blockerID uint) error {
+	// 永久删除，之后可以重新建立同一对依赖关系
+	result := conn(ctx, r.db).Unscoped().Scopes(workspaceScope(ctx, "dependencies")).
+		Where("todo_id = ? AND blocker_id = ?", todoID, blockerID).Delete(&models.Dependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrDependencyNotFound
+	}
+	return nil
+}
+
+func (r *dependencyRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.Dependency, error) {
+	var deps []*models.Dependency
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "dependencies")).
+		Joins("JOIN todos ON todos.id = dependencies.todo_id").
+		Where("todos.user_id = ?", userID).Order("dependencies.id ASC").Find(&deps).Error
+	if err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+func (r *dependencyRepo) ListBlockers(ctx context.Context, todoID uint) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "todos")).
+		Joins("JOIN dependencies ON dependencies.blocker_id = todos.id AND dependencies.deleted_at IS NULL").
+		Where("dependencies.todo_id = ?", todoID).Order("todos.id ASC").Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *dependencyRepo) ListBlocking(ctx context.Context, blockerID uint) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "todos")).
+		Joins("JOIN dependencies ON dependencies.todo_id = todos.id AND dependencies.deleted_at IS NULL").
+		Where("dependencies.blocker_id = ?", blockerID).Order("todos.id ASC").Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *dependencyRepo) CountOpenBlockers(ctx context.Context, todoID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&models.Todo{}).Scopes(workspaceScope(ctx, "todos")).
+		Joins("JOIN dependencies ON dependencies.blocker_id = todos.id AND dependencies.deleted_at IS NULL").
+		Where("dependencies.todo_id = ? AND todos.completed = ?", todoID, false).Count(&count).Error
+	return count, err
+}
+
+func (r *dependencyRepo) DeleteByTodoID(ctx context.Context, todoID uint) error {
+	return conn(ctx, r.db).Unscoped().Scopes(workspaceScope(ctx, "dependencies")).
+		Where("todo_id = ? OR blocker_id = ?", todoID, todoID).Delete(&models.Dependency{}).Error
+}
@@ -0,0 +1,93 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// SavedFilterRepository 定义保存的过滤条件仓储接口
+// 所有方法都限定在上下文中的当前工作空间内
+type SavedFilterRepository interface {
+	// Create 创建过滤条件
+	// ctx: 上下文信息
+	// filter: 过滤条件
+	// 返回: error 创建过程中的错误信息
+	Create(ctx context.Context, filter *models.SavedFilter) error
+
+	// GetByID 根据ID获取过滤条件
+	// ctx: 上下文信息
+	// id: 过滤条件ID
+	// 返回: (*models.SavedFilter, error) 过滤条件和可能的错误
+	GetByID(ctx context.Context, id uint) (*models.SavedFilter, error)
+
+	// ListByUserID 获取用户的所有过滤条件，按创建顺序排列
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// 返回: ([]*models.SavedFilter, error) 过滤条件列表和可能的错误
+	ListByUserID(ctx context.Context, userID uint) ([]*models.SavedFilter, error)
+
+	// Update 更新过滤条件
+	// ctx: 上下文信息
+	// filter: 需要更新的过滤条件
+	// 返回: error 更新过程中的错误信息
+	Update(ctx context.Context, filter *models.SavedFilter) error
+
+	// Delete 删除过滤条件
+	// ctx: 上下文信息
+	// id: 过滤条件ID
+	// 返回: error 删除过程中的错误信息
+	Delete(ctx context.Context, id uint) error
+}
+
+// savedFilterRepo 实现 SavedFilterRepository 接口
+type savedFilterRepo struct {
+	db *gorm.DB
+}
+
+func (r *savedFilterRepo) Create(ctx context.Context, filter *models.SavedFilter) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	filter.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(filter).Error
+}
+
+func (r *savedFilterRepo) GetByID(ctx context.Context, id uint) (*models.SavedFilter, error) {
+	var filter models.SavedFilter
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "saved_filters")).First(&filter, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrFilterNotFound
+		}
+		return nil, err
+	}
+	return &filter, nil
+}
+
+func (r *savedFilterRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.SavedFilter, error) {
+	var filters []*models.SavedFilter
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "saved_filters")).
+		Where("user_id = ?", userID).Order("id ASC").Find(&filters).Error
+	if err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+func (r *savedFilterRepo) Update(ctx context.Context, filter *models.SavedFilter) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	filter.WorkspaceID = wsID
+	return conn(ctx, r.db).Model(filter).Scopes(workspaceScope(ctx, "saved_filters")).
+		Select("name", "definition").Updates(filter).Error
+}
+
+func (r *savedFilterRepo) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Scopes(workspaceScope(ctx, "saved_filters")).Delete(&models.SavedFilter{}, id).Error
+}
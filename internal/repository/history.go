@@ -0,0 +1,69 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// HistoryRepository 定义变更历史仓储接口
+// 变更历史只追加不修改，因此不提供更新和删除方法；所有方法都限定在当前工作空间内
+type HistoryRepository interface {
+	// Create 追加一条变更记录
+	// ctx: 上下文信息
+	// entry: 变更记录
+	// 返回: error 创建过程中的错误信息
+	Create(ctx context.Context, entry *models.ChangeLog) error
+
+	// GetByID 根据ID获取变更记录
+	// ctx: 上下文信息
+	// id: 变更记录ID
+	// 返回: (*models.ChangeLog, error) 变更记录和可能的错误
+	GetByID(ctx context.Context, id uint) (*models.ChangeLog, error)
+
+	// ListByEntity 获取实体的变更历史，按时间倒序
+	// ctx: 上下文信息
+	// entityType: 实体类型
+	// entityID: 实体ID
+	// 返回: ([]*models.ChangeLog, error) 变更记录列表和可能的错误
+	ListByEntity(ctx context.Context, entityType string, entityID uint) ([]*models.ChangeLog, error)
+}
+
+// historyRepo 实现 HistoryRepository 接口
+type historyRepo struct {
+	db *gorm.DB
+}
+
+func (r *historyRepo) Create(ctx context.Context, entry *models.ChangeLog) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	entry.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(entry).Error
+}
+
+func (r *historyRepo) GetByID(ctx context.Context, id uint) (*models.ChangeLog, error) {
+	var entry models.ChangeLog
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "change_logs")).First(&entry, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrChangeNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *historyRepo) ListByEntity(ctx context.Context, entityType string, entityID uint) ([]*models.ChangeLog, error) {
+	var entries []*models.ChangeLog
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "change_logs")).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("id DESC").Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
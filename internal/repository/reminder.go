@@ -3,13 +3,16 @@ package repository
 
 import (
 	"context"
+	"time"
 	"todo/internal/models"
 	"todo/pkg/errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ReminderRepository 定义提醒事项仓储接口
+// 所有方法都限定在上下文中的当前工作空间内
 type ReminderRepository interface {
 	// Create 创建新的提醒事项
 	// ctx: 上下文信息
@@ -29,6 +32,12 @@ type ReminderRepository interface {
 	// 返回: ([]*models.Reminder, error) 提醒事项列表和可能的错误
 	ListByTodoID(ctx context.Context, todoID uint) ([]*models.Reminder, error)
 
+	// ListByTodoIDs 获取多个待办事项的所有提醒，按待办事项ID和提醒时间排序
+	// ctx: 上下文信息
+	// todoIDs: 待办事项ID列表
+	// 返回: ([]*models.Reminder, error) 提醒事项列表和可能的错误
+	ListByTodoIDs(ctx context.Context, todoIDs []uint) ([]*models.Reminder, error)
+
 	// Update 更新提醒事项
 	// ctx: 上下文信息
 	// reminder: 需要更新的提醒事项信息
@@ -40,6 +49,35 @@ type ReminderRepository interface {
 	// id: 要删除的提醒事项ID
 	// 返回: error 删除过程中的错误信息
 	Delete(ctx context.Context, id uint) error
+
+	// DeleteByTodoID 删除待办事项的所有提醒
+	// ctx: 上下文信息
+	// todoID: 待办事项ID
+	// 返回: error 删除过程中的错误信息
+	DeleteByTodoID(ctx context.Context, todoID uint) error
+
+	// RestoreByTodoID 恢复待办事项在指定时间之后被删除的提醒
+	// 与待办事项一起进入回收站的提醒删除时间不早于待办事项本身，据此区分此前单独删除的提醒
+	// ctx: 上下文信息
+	// todoID: 待办事项ID
+	// since: 待办事项的删除时间
+	// 返回: error 恢复过程中的错误信息
+	RestoreByTodoID(ctx context.Context, todoID uint, since time.Time) error
+
+	// PurgeByTodoID 永久删除待办事项的所有提醒（包括已删除的）
+	// ctx: 上下文信息
+	// todoID: 待办事项ID
+	// 返回: error 删除过程中的错误信息
+	PurgeByTodoID(ctx context.Context, todoID uint) error
+
+	// ListDue 获取提醒时间不晚于指定时间且尚未发送的提醒，附带所属待办事项，按提醒时间正序
+	// 已完成或已删除的待办事项的提醒不会返回
+	// 注意：此方法跨工作空间查询，仅供后台提醒任务使用，调用方需按记录的 WorkspaceID 设置上下文后再处理
+	// ctx: 上下文信息
+	// before: 提醒时间上限
+	// limit: 最大返回数量
+	// 返回: ([]*models.Reminder, error) 提醒事项列表和可能的错误
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*models.Reminder, error)
 }
 
 type reminderRepo struct {
@@ -47,12 +85,17 @@ type reminderRepo struct {
 }
 
 func (r *reminderRepo) Create(ctx context.Context, reminder *models.Reminder) error {
-	return r.db.WithContext(ctx).Create(reminder).Error
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	reminder.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(reminder).Error
 }
 
 func (r *reminderRepo) GetByID(ctx context.Context, id uint) (*models.Reminder, error) {
 	var reminder models.Reminder
-	if err := r.db.WithContext(ctx).First(&reminder, id).Error; err != nil {
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "reminders")).First(&reminder, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.ErrReminderNotFound
 		}
@@ -63,16 +106,62 @@ func (r *reminderRepo) GetByID(ctx context.Context, id uint) (*models.Reminder,
 
 func (r *reminderRepo) ListByTodoID(ctx context.Context, todoID uint) ([]*models.Reminder, error) {
 	var reminders []*models.Reminder
-	if err := r.db.WithContext(ctx).Where("todo_id = ?", todoID).Find(&reminders).Error; err != nil {
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "reminders")).Where("todo_id = ?", todoID).Find(&reminders).Error; err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+func (r *reminderRepo) ListByTodoIDs(ctx context.Context, todoIDs []uint) ([]*models.Reminder, error) {
+	var reminders []*models.Reminder
+	if len(todoIDs) == 0 {
+		return reminders, nil
+	}
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "reminders")).
+		Where("todo_id IN ?", todoIDs).Order("todo_id ASC, remind_at ASC").Find(&reminders).Error
+	if err != nil {
 		return nil, err
 	}
 	return reminders, nil
 }
 
 func (r *reminderRepo) Update(ctx context.Context, reminder *models.Reminder) error {
-	return r.db.WithContext(ctx).Save(reminder).Error
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	reminder.WorkspaceID = wsID
+	return conn(ctx, r.db).Model(reminder).Scopes(workspaceScope(ctx, "reminders")).
+		Select("*").Omit(clause.Associations).Updates(reminder).Error
 }
 
 func (r *reminderRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.Reminder{}, id).Error
+	return conn(ctx, r.db).Scopes(workspaceScope(ctx, "reminders")).Delete(&models.Reminder{}, id).Error
+}
+
+func (r *reminderRepo) DeleteByTodoID(ctx context.Context, todoID uint) error {
+	return conn(ctx, r.db).Scopes(workspaceScope(ctx, "reminders")).
+		Where("todo_id = ?", todoID).Delete(&models.Reminder{}).Error
+}
+
+func (r *reminderRepo) RestoreByTodoID(ctx context.Context, todoID uint, since time.Time) error {
+	return conn(ctx, r.db).Unscoped().Model(&models.Reminder{}).Scopes(workspaceScope(ctx, "reminders")).
+		Where("todo_id = ? AND deleted_at >= ?", todoID, since).Update("deleted_at", nil).Error
+}
+
+func (r *reminderRepo) PurgeByTodoID(ctx context.Context, todoID uint) error {
+	return conn(ctx, r.db).Unscoped().Scopes(workspaceScope(ctx, "reminders")).
+		Where("todo_id = ?", todoID).Delete(&models.Reminder{}).Error
+}
+
+func (r *reminderRepo) ListDue(ctx context.Context, before time.Time, limit int) ([]*models.Reminder, error) {
+	var reminders []*models.Reminder
+	err := conn(ctx, r.db).Preload("Todo").
+		Joins("JOIN todos ON todos.id = reminders.todo_id AND todos.deleted_at IS NULL AND todos.completed = ?", false).
+		Where("reminders.status = ? AND reminders.remind_at <= ?", false, before).
+		Order("reminders.remind_at ASC, reminders.id ASC").Limit(limit).Find(&reminders).Error
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
 }
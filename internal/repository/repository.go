@@ -32,3 +32,94 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 func NewReminderRepository(db *gorm.DB) ReminderRepository {
 	return &reminderRepo{db: db}
 }
+
+// NewWorkspaceRepository 创建工作空间仓储实例
+// db: 数据库连接实例
+// 返回: WorkspaceRepository 接口实现
+func NewWorkspaceRepository(db *gorm.DB) WorkspaceRepository {
+	return &workspaceRepo{db: db}
+}
+
+// NewCommentRepository 创建评论仓储实例
+// db: 数据库连接实例
+// 返回: CommentRepository 接口实现
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepo{db: db}
+}
+
+// NewAttachmentRepository 创建附件仓储实例
+// db: 数据库连接实例
+// 返回: AttachmentRepository 接口实现
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepo{db: db}
+}
+
+// NewHistoryRepository 创建变更历史仓储实例
+// db: 数据库连接实例
+// 返回: HistoryRepository 接口实现
+func NewHistoryRepository(db *gorm.DB) HistoryRepository {
+	return &historyRepo{db: db}
+}
+
+// NewSearchIndex 创建基于 MySQL FULLTEXT 索引的全文搜索实例
+// db: 数据库连接实例
+// 返回: SearchIndex 接口实现
+func NewSearchIndex(db *gorm.DB) SearchIndex {
+	return &mysqlSearchIndex{db: db}
+}
+
+// NewSavedFilterRepository 创建保存的过滤条件仓储实例
+// db: 数据库连接实例
+// 返回: SavedFilterRepository 接口实现
+func NewSavedFilterRepository(db *gorm.DB) SavedFilterRepository {
+	return &savedFilterRepo{db: db}
+}
+
+// NewDependencyRepository 创建待办事项依赖关系仓储实例
+// db: 数据库连接实例
+// 返回: DependencyRepository 接口实现
+func NewDependencyRepository(db *gorm.DB) DependencyRepository {
+	return &dependencyRepo{db: db}
+}
+
+// NewStatusRepository 创建工作流状态仓储实例
+// db: 数据库连接实例
+// 返回: StatusRepository 接口实现
+func NewStatusRepository(db *gorm.DB) StatusRepository {
+	return &statusRepo{db: db}
+}
+
+// NewTimeEntryRepository 创建时间记录仓储实例
+// db: 数据库连接实例
+// 返回: TimeEntryRepository 接口实现
+func NewTimeEntryRepository(db *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepo{db: db}
+}
+
+// NewTemplateRepository 创建待办事项模板仓储实例
+// db: 数据库连接实例
+// 返回: TemplateRepository 接口实现
+func NewTemplateRepository(db *gorm.DB) TemplateRepository {
+	return &templateRepo{db: db}
+}
+
+// NewCalendarFeedRepository 创建日历订阅仓储实例
+// db: 数据库连接实例
+// 返回: CalendarFeedRepository 接口实现
+func NewCalendarFeedRepository(db *gorm.DB) CalendarFeedRepository {
+	return &calendarFeedRepo{db: db}
+}
+
+// NewDataExportRepository 创建数据导出任务仓储实例
+// db: 数据库连接实例
+// 返回: DataExportRepository 接口实现
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &dataExportRepo{db: db}
+}
+
+// NewWebhookRepository 创建 webhook 仓储实例
+// db: 数据库连接实例
+// 返回: WebhookRepository 接口实现
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepo{db: db}
+}
@@ -0,0 +1,35 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/tenant"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// workspaceID 从上下文中读取当前工作空间ID
+// 上下文中没有工作空间时返回 ErrWorkspaceRequired，保证任何租户数据的读写都必须显式指定租户
+func workspaceID(ctx context.Context) (uint, error) {
+	id, ok := tenant.WorkspaceIDFromContext(ctx)
+	if !ok {
+		return 0, errors.ErrWorkspaceRequired
+	}
+	return id, nil
+}
+
+// workspaceScope 返回按当前工作空间过滤的查询作用域
+// ctx: 上下文信息
+// table: 需要过滤的表名，用于在联表查询时限定列
+// 返回: GORM 作用域函数，上下文中缺少工作空间时使查询直接失败
+func workspaceScope(ctx context.Context, table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		id, err := workspaceID(ctx)
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+		return db.Where(table+".workspace_id = ?", id)
+	}
+}
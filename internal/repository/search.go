@@ -0,0 +1,114 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SearchQuery 全文搜索条件
+type SearchQuery struct {
+	Text     string     // 搜索文本
+	UserID   uint       // 只搜索该用户的待办事项
+	Filter   TodoFilter // 与列表相同的过滤条件
+	Page     int        // 页码
+	PageSize int        // 每页数量
+}
+
+// SearchHit 一条搜索结果
+type SearchHit struct {
+	Todo    *models.Todo // 命中的待办事项
+	Score   float64      // 相关度，越大越相关
+	Comment string       // 命中的评论正文，没有评论命中时为空
+}
+
+// SearchIndex 定义待办事项全文搜索接口
+// 默认实现基于 MySQL FULLTEXT 索引，也可以替换为其他搜索引擎；所有方法都限定在当前工作空间内
+type SearchIndex interface {
+	// Search 搜索标题、描述和评论，按相关度降序返回
+	// ctx: 上下文信息
+	// query: 搜索条件
+	// 返回: ([]*SearchHit, int64, error) 当前页的结果、命中总数和可能的错误
+	Search(ctx context.Context, query SearchQuery) ([]*SearchHit, int64, error)
+}
+
+// mysqlSearchIndex 基于 MySQL FULLTEXT 索引（ngram 分词）的 SearchIndex 实现
+type mysqlSearchIndex struct {
+	db *gorm.DB
+}
+
+const (
+	todoMatch    = "MATCH(todos.title, todos.description) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	commentMatch = "MATCH(comments.body) AGAINST (? IN NATURAL LANGUAGE MODE)"
+)
+
+// searchRow 搜索结果行，附带相关度
+type searchRow struct {
+	models.Todo
+	Score float64
+}
+
+func (s *mysqlSearchIndex) Search(ctx context.Context, query SearchQuery) ([]*SearchHit, int64, error) {
+	db := conn(ctx, s.db)
+
+	// 每个待办事项下评论的最高相关度
+	commentScores := db.Model(&models.Comment{}).Scopes(workspaceScope(ctx, "comments")).
+		Select("comments.todo_id, MAX("+commentMatch+") AS score", query.Text).
+		Where(commentMatch, query.Text).Group("comments.todo_id")
+
+	base := db.Model(&models.Todo{}).Scopes(workspaceScope(ctx, "todos"), filterScope(query.Filter)).
+		Joins("LEFT JOIN (?) AS comment_scores ON comment_scores.todo_id = todos.id", commentScores).
+		Where("todos.user_id = ?", query.UserID).
+		Where(todoMatch+" OR comment_scores.todo_id IS NOT NULL", query.Text).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []*searchRow
+	err := base.Select("todos.*, "+todoMatch+" + COALESCE(comment_scores.score, 0) AS score", query.Text).
+		Order("score DESC, todos.id DESC").
+		Offset((query.Page - 1) * query.PageSize).Limit(query.PageSize).
+		Find(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]*SearchHit, 0, len(rows))
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		todo := row.Todo
+		hits = append(hits, &SearchHit{Todo: &todo, Score: row.Score})
+		ids = append(ids, todo.ID)
+	}
+	if len(ids) == 0 {
+		return hits, total, nil
+	}
+
+	// 取每个待办事项下最相关的一条评论用于生成摘要
+	var comments []*models.Comment
+	err = db.Scopes(workspaceScope(ctx, "comments")).
+		Select("comments.todo_id, comments.body").
+		Where("comments.todo_id IN ?", ids).Where(commentMatch, query.Text).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: commentMatch + " DESC", Vars: []interface{}{query.Text}}}).
+		Find(&comments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	best := make(map[uint]string, len(comments))
+	for _, c := range comments {
+		if _, ok := best[c.TodoID]; !ok {
+			best[c.TodoID] = c.Body
+		}
+	}
+	for _, hit := range hits {
+		hit.Comment = best[hit.Todo.ID]
+	}
+
+	return hits, total, nil
+}
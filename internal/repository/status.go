@@ -0,0 +1,93 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// StatusRepository 定义看板工作流状态仓储接口
+// 所有方法都限定在上下文中的当前工作空间内
+type StatusRepository interface {
+	// Create 创建工作流状态
+	// ctx: 上下文信息
+	// status: 工作流状态
+	// 返回: error 创建过程中的错误信息
+	Create(ctx context.Context, status *models.Status) error
+
+	// GetByID 根据ID获取工作流状态
+	// ctx: 上下文信息
+	// id: 工作流状态ID
+	// 返回: (*models.Status, error) 工作流状态和可能的错误
+	GetByID(ctx context.Context, id uint) (*models.Status, error)
+
+	// ListByUserID 获取用户的所有工作流状态，按看板顺序排列
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// 返回: ([]*models.Status, error) 工作流状态列表和可能的错误
+	ListByUserID(ctx context.Context, userID uint) ([]*models.Status, error)
+
+	// Update 更新工作流状态
+	// ctx: 上下文信息
+	// status: 需要更新的工作流状态
+	// 返回: error 更新过程中的错误信息
+	Update(ctx context.Context, status *models.Status) error
+
+	// Delete 永久删除工作流状态，之后可以重新创建同名状态
+	// ctx: 上下文信息
+	// id: 工作流状态ID
+	// 返回: error 删除过程中的错误信息
+	Delete(ctx context.Context, id uint) error
+}
+
+// statusRepo 实现 StatusRepository 接口
+type statusRepo struct {
+	db *gorm.DB
+}
+
+func (r *statusRepo) Create(ctx context.Context, status *models.Status) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	status.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(status).Error
+}
+
+func (r *statusRepo) GetByID(ctx context.Context, id uint) (*models.Status, error) {
+	var status models.Status
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "statuses")).First(&status, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrStatusNotFound
+		}
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (r *statusRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.Status, error) {
+	var statuses []*models.Status
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "statuses")).
+		Where("user_id = ?", userID).Order("position ASC, id ASC").Find(&statuses).Error
+	if err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+func (r *statusRepo) Update(ctx context.Context, status *models.Status) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	status.WorkspaceID = wsID
+	return conn(ctx, r.db).Model(status).Scopes(workspaceScope(ctx, "statuses")).
+		Select("name", "position", "terminal", "wip_limit").Updates(status).Error
+}
+
+func (r *statusRepo) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Unscoped().Scopes(workspaceScope(ctx, "statuses")).Delete(&models.Status{}, id).Error
+}
@@ -0,0 +1,93 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// TemplateRepository 定义待办事项模板仓储接口
+// 所有方法都限定在上下文中的当前工作空间内
+type TemplateRepository interface {
+	// Create 创建模板
+	// ctx: 上下文信息
+	// template: 模板
+	// 返回: error 创建过程中的错误信息
+	Create(ctx context.Context, template *models.Template) error
+
+	// GetByID 根据ID获取模板
+	// ctx: 上下文信息
+	// id: 模板ID
+	// 返回: (*models.Template, error) 不存在时返回 ErrTemplateNotFound
+	GetByID(ctx context.Context, id uint) (*models.Template, error)
+
+	// ListByUserID 获取用户的所有模板，按名称排列
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// 返回: ([]*models.Template, error) 模板列表和可能的错误
+	ListByUserID(ctx context.Context, userID uint) ([]*models.Template, error)
+
+	// Update 更新模板
+	// ctx: 上下文信息
+	// template: 需要更新的模板
+	// 返回: error 更新过程中的错误信息
+	Update(ctx context.Context, template *models.Template) error
+
+	// Delete 删除模板
+	// ctx: 上下文信息
+	// id: 模板ID
+	// 返回: error 删除过程中的错误信息
+	Delete(ctx context.Context, id uint) error
+}
+
+// templateRepo 实现 TemplateRepository 接口
+type templateRepo struct {
+	db *gorm.DB
+}
+
+func (r *templateRepo) Create(ctx context.Context, template *models.Template) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	template.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(template).Error
+}
+
+func (r *templateRepo) GetByID(ctx context.Context, id uint) (*models.Template, error) {
+	var template models.Template
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "templates")).First(&template, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *templateRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.Template, error) {
+	var templates []*models.Template
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "templates")).
+		Where("user_id = ?", userID).Order("name ASC, id ASC").Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *templateRepo) Update(ctx context.Context, template *models.Template) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	template.WorkspaceID = wsID
+	return conn(ctx, r.db).Model(template).Scopes(workspaceScope(ctx, "templates")).
+		Select("name", "description", "items").Updates(template).Error
+}
+
+func (r *templateRepo) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Scopes(workspaceScope(ctx, "templates")).Delete(&models.Template{}, id).Error
+}
@@ -0,0 +1,184 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"time"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CategoryTime 某个分类下的时间记录汇总
+type CategoryTime struct {
+	CategoryID *uint // 分类ID，为空表示未分类
+	Seconds    int64 // 总时长（秒）
+	Entries    int64 // 时间记录数量
+}
+
+// TimeEntryRepository 定义时间记录仓储接口
+// 除 GetRunning 和 LockRunning 外，所有方法都限定在上下文中的当前工作空间内
+type TimeEntryRepository interface {
+	// Create 创建时间记录
+	// ctx: 上下文信息
+	// entry: 时间记录
+	// 返回: error 创建过程中的错误信息
+	Create(ctx context.Context, entry *models.TimeEntry) error
+
+	// GetByID 根据ID获取时间记录
+	// ctx: 上下文信息
+	// id: 时间记录ID
+	// 返回: (*models.TimeEntry, error) 不存在时返回 ErrTimeEntryNotFound
+	GetByID(ctx context.Context, id uint) (*models.TimeEntry, error)
+
+	// Update 更新时间记录
+	// ctx: 上下文信息
+	// entry: 时间记录
+	// 返回: error 更新过程中的错误信息
+	Update(ctx context.Context, entry *models.TimeEntry) error
+
+	// Delete 删除时间记录
+	// ctx: 上下文信息
+	// id: 时间记录ID
+	// 返回: error 删除过程中的错误信息
+	Delete(ctx context.Context, id uint) error
+
+	// GetRunning 获取用户正在运行的计时器，不限定工作空间
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// 返回: (*models.TimeEntry, error) 没有正在运行的计时器时返回 nil, nil
+	GetRunning(ctx context.Context, userID uint) (*models.TimeEntry, error)
+
+	// LockRunning 锁定用户的计时器并获取正在运行的计时器，必须在事务中调用
+	// 通过 SELECT ... FOR UPDATE 锁定用户记录，同一用户并发的启动和停止操作按顺序执行，直到事务结束
+	// ctx: 上下文信息，必须携带事务
+	// userID: 用户ID
+	// 返回: (*models.TimeEntry, error) 没有正在运行的计时器时返回 nil, nil
+	LockRunning(ctx context.Context, userID uint) (*models.TimeEntry, error)
+
+	// ListByTodoID 获取待办事项的所有时间记录，按开始时间倒序
+	// ctx: 上下文信息
+	// todoID: 待办事项ID
+	// 返回: ([]*models.TimeEntry, error) 时间记录列表和可能的错误
+	ListByTodoID(ctx context.Context, todoID uint) ([]*models.TimeEntry, error)
+
+	// SumByCategory 按待办事项所属分类汇总用户在 [from, to) 内开始的已结束时间记录
+	// 包括已在回收站中的待办事项，正在运行的计时器不计入
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// from, to: 时间范围
+	// 返回: ([]CategoryTime, error) 各分类的汇总和可能的错误
+	SumByCategory(ctx context.Context, userID uint, from, to time.Time) ([]CategoryTime, error)
+
+	// DeleteByTodoID 删除待办事项的所有时间记录，用于永久删除待办事项
+	// ctx: 上下文信息
+	// todoID: 待办事项ID
+	// 返回: error 删除过程中的错误信息
+	DeleteByTodoID(ctx context.Context, todoID uint) error
+}
+
+// timeEntryRepo 实现 TimeEntryRepository 接口
+type timeEntryRepo struct {
+	db *gorm.DB
+}
+
+func (r *timeEntryRepo) Create(ctx context.Context, entry *models.TimeEntry) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	entry.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(entry).Error
+}
+
+func (r *timeEntryRepo) GetByID(ctx context.Context, id uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "time_entries")).First(&entry, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrTimeEntryNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *timeEntryRepo) Update(ctx context.Context, entry *models.TimeEntry) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	entry.WorkspaceID = wsID
+	// 不使用 Save：Save 在未命中行时会退化为插入，可能覆盖其他工作空间的同ID记录
+	return conn(ctx, r.db).Model(entry).Scopes(workspaceScope(ctx, "time_entries")).
+		Select("*").Updates(entry).Error
+}
+
+func (r *timeEntryRepo) Delete(ctx context.Context, id uint) error {
+	result := conn(ctx, r.db).Scopes(workspaceScope(ctx, "time_entries")).Delete(&models.TimeEntry{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrTimeEntryNotFound
+	}
+	return nil
+}
+
+func (r *timeEntryRepo) GetRunning(ctx context.Context, userID uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	// 每个用户在所有工作空间中最多只有一个正在运行的计时器，因此不限定工作空间
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND ended_at IS NULL", userID).Order("started_at DESC").First(&entry).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *timeEntryRepo) LockRunning(ctx context.Context, userID uint) (*models.TimeEntry, error) {
+	// 没有正在运行的计时器时无记录可锁，因此锁定用户记录作为该用户计时器的互斥锁
+	var user models.User
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, userID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return r.GetRunning(ctx, userID)
+}
+
+func (r *timeEntryRepo) ListByTodoID(ctx context.Context, todoID uint) ([]*models.TimeEntry, error) {
+	var entries []*models.TimeEntry
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "time_entries")).
+		Where("todo_id = ?", todoID).Order("started_at DESC, id DESC").Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *timeEntryRepo) SumByCategory(ctx context.Context, userID uint, from, to time.Time) ([]CategoryTime, error) {
+	var rows []CategoryTime
+	// 回收站中的待办事项仍计入已记录的时间，因此连接时不排除软删除的待办事项
+	err := conn(ctx, r.db).Model(&models.TimeEntry{}).Scopes(workspaceScope(ctx, "time_entries")).
+		Select("todos.category_id AS category_id, SUM(time_entries.seconds) AS seconds, COUNT(*) AS entries").
+		Joins("JOIN todos ON todos.id = time_entries.todo_id").
+		Where("time_entries.user_id = ? AND time_entries.ended_at IS NOT NULL", userID).
+		Where("time_entries.started_at >= ? AND time_entries.started_at < ?", from, to).
+		Group("todos.category_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *timeEntryRepo) DeleteByTodoID(ctx context.Context, todoID uint) error {
+	return conn(ctx, r.db).Unscoped().Scopes(workspaceScope(ctx, "time_entries")).
+		Where("todo_id = ?", todoID).Delete(&models.TimeEntry{}).Error
+}
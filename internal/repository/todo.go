@@ -3,13 +3,52 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
 	"todo/internal/models"
+	"todo/internal/query"
 	"todo/pkg/errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ArchiveFilter 列表查询时对归档状态的过滤方式
+type ArchiveFilter int
+
+const (
+	ArchiveExclude ArchiveFilter = iota // 排除已归档（默认）
+	ArchiveOnly                         // 只看已归档
+	ArchiveInclude                      // 不按归档状态过滤
+)
+
+// TodoSort 待办事项列表的排序方式
+type TodoSort int
+
+const (
+	SortDefault  TodoSort = iota // 数据库默认顺序
+	SortPosition                 // 按分类分组，分类内按手动排序位置
+	SortID                       // 按ID升序，分页遍历全部结果时保证顺序稳定
+)
+
+// TodoFilter 待办事项列表的过滤条件，零值表示只列出未归档的待办事项
+type TodoFilter struct {
+	Archived      ArchiveFilter // 归档状态过滤
+	Completed     *bool         // 完成状态，为空时不过滤
+	Keyword       string        // 标题或描述中包含的关键字
+	CategoryIDs   []uint        // 所属分类ID，为空时不过滤
+	Uncategorized bool          // 只看未分类的待办事项
+	Priority      string        // 优先级，为空时不过滤
+	DueFrom       *time.Time    // 截止时间不早于该时间
+	DueBefore     *time.Time    // 截止时间早于该时间
+	NoDueDate     bool          // 只看没有截止时间的待办事项
+	StatusID      *uint         // 工作流状态ID，为空时不过滤
+	Query         *query.Query  // 查询语言表达的附加条件，为空时不过滤
+	Sort          TodoSort      // 排序方式
+}
+
 // TodoRepository 待办事项仓库接口
+// 所有方法都限定在上下文中的当前工作空间内，无法访问其他工作空间的数据
 type TodoRepository interface {
 	// Create 创建新的待办事项
 	// ctx: 上下文信息
@@ -23,15 +62,71 @@ type TodoRepository interface {
 	// 返回: (*models.Todo, error) 待办事项信息和可能的错误
 	GetByID(ctx context.Context, id uint) (*models.Todo, error)
 
+	// GetByCalendarName 根据 CalDAV 资源名获取用户在指定分类中的待办事项
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// categoryID: 分类ID，为空表示未分类
+	// name: 通过 CalDAV 创建时的资源名
+	// 返回: (*models.Todo, error) 不存在时返回 ErrTodoNotFound
+	GetByCalendarName(ctx context.Context, userID uint, categoryID *uint, name string) (*models.Todo, error)
+
 	// ListByUserID 获取用户的待办事项列表
 	// ctx: 上下文信息
 	// userID: 用户ID
+	// filter: 过滤条件
 	// page: 页码
 	// pageSize: 每页数量
 	// 返回: ([]*models.Todo, int64, error) 待办事项列表、总数和可能的错误
-	ListByUserID(ctx context.Context, userID uint, page, pageSize int) ([]*models.Todo, int64, error)
+	ListByUserID(ctx context.Context, userID uint, filter TodoFilter, page, pageSize int) ([]*models.Todo, int64, error)
+
+	// CountByUserID 统计用户符合过滤条件的待办事项数量
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// filter: 过滤条件
+	// 返回: (int64, error) 数量和可能的错误
+	CountByUserID(ctx context.Context, userID uint, filter TodoFilter) (int64, error)
+
+	// LastPosition 获取用户在分类中最大的排序位置（包括已归档的，不包括回收站中的）
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// categoryID: 分类ID，为空表示未分类
+	// 返回: (string, error) 最大的排序位置，没有待办事项时为空字符串
+	LastPosition(ctx context.Context, userID uint, categoryID *uint) (string, error)
+
+	// ListByPosition 获取用户在分类中的所有待办事项（包括已归档的），按排序位置升序
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// categoryID: 分类ID，为空表示未分类
+	// 返回: ([]*models.Todo, error) 待办事项列表和可能的错误
+	ListByPosition(ctx context.Context, userID uint, categoryID *uint) ([]*models.Todo, error)
+
+	// UpdatePosition 只更新待办事项的排序位置
+	// ctx: 上下文信息
+	// id: 待办事项ID
+	// position: 新的排序位置
+	// 返回: error 更新过程中的错误信息
+	UpdatePosition(ctx context.Context, id uint, position string) error
+
+	// SetTags 将待办事项的标签替换为 names，不存在的标签会自动创建
+	// ctx: 上下文信息
+	// todo: 待办事项，需已保存
+	// names: 标签名列表
+	// 返回: error 更新过程中的错误信息
+	SetTags(ctx context.Context, todo *models.Todo, names []string) error
+
+	// ListByCategoryID 获取分类下的所有待办事项，包括已归档和回收站中的
+	// ctx: 上下文信息
+	// categoryID: 分类ID
+	// 返回: ([]*models.Todo, error) 待办事项列表和可能的错误
+	ListByCategoryID(ctx context.Context, categoryID uint) ([]*models.Todo, error)
 
-	// Update 更新待办事项
+	// ListByStatusID 获取处于工作流状态中的所有待办事项，包括已归档和回收站中的
+	// ctx: 上下文信息
+	// statusID: 工作流状态ID
+	// 返回: ([]*models.Todo, error) 待办事项列表和可能的错误
+	ListByStatusID(ctx context.Context, statusID uint) ([]*models.Todo, error)
+
+	// Update 更新待办事项（回收站中的待办事项同样可以更新）
 	// ctx: 上下文信息
 	// todo: 需要更新的待办事项信息
 	// 返回: error 更新过程中的错误信息
@@ -42,6 +137,48 @@ type TodoRepository interface {
 	// id: 要删除的待办事项ID
 	// 返回: error 删除过程中的错误信息
 	Delete(ctx context.Context, id uint) error
+
+	// ListDeleted 获取用户回收站中的待办事项，按删除时间倒序
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// page: 页码
+	// pageSize: 每页数量
+	// 返回: ([]*models.Todo, int64, error) 待办事项列表、总数和可能的错误
+	ListDeleted(ctx context.Context, userID uint, page, pageSize int) ([]*models.Todo, int64, error)
+
+	// GetDeletedByID 根据ID获取回收站中的待办事项
+	// ctx: 上下文信息
+	// id: 待办事项ID
+	// 返回: (*models.Todo, error) 待办事项信息和可能的错误，不在回收站中时返回 ErrTodoNotFound
+	GetDeletedByID(ctx context.Context, id uint) (*models.Todo, error)
+
+	// Restore 从回收站恢复待办事项
+	// ctx: 上下文信息
+	// id: 待办事项ID
+	// 返回: error 恢复过程中的错误信息
+	Restore(ctx context.Context, id uint) error
+
+	// Purge 永久删除待办事项（无论是否在回收站中）
+	// ctx: 上下文信息
+	// id: 待办事项ID
+	// 返回: error 删除过程中的错误信息
+	Purge(ctx context.Context, id uint) error
+
+	// ListExpiredDeleted 获取删除时间早于指定时间的待办事项，按删除时间正序
+	// 注意：此方法跨工作空间查询，仅供后台清理任务使用，调用方需按记录的 WorkspaceID 设置上下文后再处理
+	// ctx: 上下文信息
+	// before: 删除时间上限
+	// limit: 最大返回数量
+	// 返回: ([]*models.Todo, error) 待办事项列表和可能的错误
+	ListExpiredDeleted(ctx context.Context, before time.Time, limit int) ([]*models.Todo, error)
+
+	// ListArchivable 获取完成时间早于指定时间且尚未归档的待办事项
+	// 注意：此方法跨工作空间查询，仅供后台归档任务使用，调用方需按记录的 WorkspaceID 设置上下文后再处理
+	// ctx: 上下文信息
+	// before: 完成时间上限，没有完成时间的历史数据按更新时间判断
+	// limit: 最大返回数量
+	// 返回: ([]*models.Todo, error) 待办事项列表和可能的错误
+	ListArchivable(ctx context.Context, before time.Time, limit int) ([]*models.Todo, error)
 }
 
 type todoRepo struct {
@@ -49,12 +186,30 @@ type todoRepo struct {
 }
 
 func (r *todoRepo) Create(ctx context.Context, todo *models.Todo) error {
-	return r.db.WithContext(ctx).Create(todo).Error
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	todo.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(todo).Error
 }
 
 func (r *todoRepo) GetByID(ctx context.Context, id uint) (*models.Todo, error) {
 	var todo models.Todo
-	if err := r.db.WithContext(ctx).First(&todo, id).Error; err != nil {
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "todos")).Preload("Tags").First(&todo, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrTodoNotFound
+		}
+		return nil, err
+	}
+	return &todo, nil
+}
+
+func (r *todoRepo) GetByCalendarName(ctx context.Context, userID uint, categoryID *uint, name string) (*models.Todo, error) {
+	var todo models.Todo
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "todos"), categoryScope(categoryID)).Preload("Tags").
+		Where("user_id = ? AND calendar_name = ?", userID, name).First(&todo).Error
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.ErrTodoNotFound
 		}
@@ -63,28 +218,241 @@ func (r *todoRepo) GetByID(ctx context.Context, id uint) (*models.Todo, error) {
 	return &todo, nil
 }
 
-func (r *todoRepo) ListByUserID(ctx context.Context, userID uint, page, pageSize int) ([]*models.Todo, int64, error) {
+func (r *todoRepo) ListByUserID(ctx context.Context, userID uint, filter TodoFilter, page, pageSize int) ([]*models.Todo, int64, error) {
 	var todos []*models.Todo
 	var total int64
 
-	db := r.db.WithContext(ctx).Model(&models.Todo{}).Where("user_id = ?", userID)
+	db := conn(ctx, r.db).Model(&models.Todo{}).Scopes(workspaceScope(ctx, "todos"), filterScope(filter)).
+		Where("user_id = ?", userID)
 
 	if err := db.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	offset := (page - 1) * pageSize
-	if err := db.Offset(offset).Limit(pageSize).Find(&todos).Error; err != nil {
+	switch filter.Sort {
+	case SortPosition:
+		db = db.Order("todos.category_id ASC, todos.position ASC, todos.id ASC")
+	case SortID:
+		db = db.Order("todos.id ASC")
+	}
+	if err := db.Preload("Tags").Offset(offset).Limit(pageSize).Find(&todos).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return todos, total, nil
 }
 
+func (r *todoRepo) CountByUserID(ctx context.Context, userID uint, filter TodoFilter) (int64, error) {
+	var total int64
+	err := conn(ctx, r.db).Model(&models.Todo{}).Scopes(workspaceScope(ctx, "todos"), filterScope(filter)).
+		Where("user_id = ?", userID).Count(&total).Error
+	return total, err
+}
+
+func (r *todoRepo) LastPosition(ctx context.Context, userID uint, categoryID *uint) (string, error) {
+	var position *string
+	err := conn(ctx, r.db).Model(&models.Todo{}).Scopes(workspaceScope(ctx, "todos"), categoryScope(categoryID)).
+		Where("user_id = ?", userID).Select("MAX(position)").Scan(&position).Error
+	if err != nil || position == nil {
+		return "", err
+	}
+	return *position, nil
+}
+
+func (r *todoRepo) ListByPosition(ctx context.Context, userID uint, categoryID *uint) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "todos"), categoryScope(categoryID)).
+		Where("user_id = ?", userID).Order("position ASC, id ASC").Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *todoRepo) UpdatePosition(ctx context.Context, id uint, position string) error {
+	return conn(ctx, r.db).Model(&models.Todo{}).Scopes(workspaceScope(ctx, "todos")).
+		Where("id = ?", id).Update("position", position).Error
+}
+
+// categoryScope 按分类过滤，categoryID 为空时只匹配未分类的待办事项
+func categoryScope(categoryID *uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if categoryID == nil {
+			return db.Where("todos.category_id IS NULL")
+		}
+		return db.Where("todos.category_id = ?", *categoryID)
+	}
+}
+
+func (r *todoRepo) SetTags(ctx context.Context, todo *models.Todo, names []string) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	db := conn(ctx, r.db)
+
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		tag := models.Tag{WorkspaceID: wsID, UserID: todo.UserID, Name: name}
+		if err := db.Where(&tag).FirstOrCreate(&tag).Error; err != nil {
+			return err
+		}
+		tags = append(tags, tag)
+	}
+	return db.Model(todo).Omit("Tags.*").Association("Tags").Replace(tags)
+}
+
+func (r *todoRepo) ListByStatusID(ctx context.Context, statusID uint) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	err := conn(ctx, r.db).Unscoped().Scopes(workspaceScope(ctx, "todos")).
+		Where("status_id = ?", statusID).Order("id ASC").Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *todoRepo) ListByCategoryID(ctx context.Context, categoryID uint) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	err := conn(ctx, r.db).Unscoped().Scopes(workspaceScope(ctx, "todos")).
+		Where("category_id = ?", categoryID).Order("id ASC").Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
 func (r *todoRepo) Update(ctx context.Context, todo *models.Todo) error {
-	return r.db.WithContext(ctx).Save(todo).Error
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	todo.WorkspaceID = wsID
+	// 不使用 Save：Save 在未命中行时会退化为插入，可能覆盖其他工作空间的同ID记录
+	return conn(ctx, r.db).Unscoped().Model(todo).Scopes(workspaceScope(ctx, "todos")).
+		Select("*").Omit(clause.Associations).Updates(todo).Error
 }
 
 func (r *todoRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.Todo{}, id).Error
+	return conn(ctx, r.db).Scopes(workspaceScope(ctx, "todos")).Delete(&models.Todo{}, id).Error
+}
+
+func (r *todoRepo) ListDeleted(ctx context.Context, userID uint, page, pageSize int) ([]*models.Todo, int64, error) {
+	var todos []*models.Todo
+	var total int64
+
+	db := conn(ctx, r.db).Unscoped().Model(&models.Todo{}).Scopes(workspaceScope(ctx, "todos")).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := db.Preload("Tags").Order("deleted_at DESC").Offset(offset).Limit(pageSize).Find(&todos).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return todos, total, nil
+}
+
+func (r *todoRepo) GetDeletedByID(ctx context.Context, id uint) (*models.Todo, error) {
+	var todo models.Todo
+	err := conn(ctx, r.db).Unscoped().Scopes(workspaceScope(ctx, "todos")).
+		Where("deleted_at IS NOT NULL").First(&todo, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrTodoNotFound
+		}
+		return nil, err
+	}
+	return &todo, nil
+}
+
+func (r *todoRepo) Restore(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Unscoped().Model(&models.Todo{}).Scopes(workspaceScope(ctx, "todos")).
+		Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+func (r *todoRepo) Purge(ctx context.Context, id uint) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	db := conn(ctx, r.db)
+	// 先解除标签关联，避免关联表的外键阻止删除
+	err = db.Exec("DELETE FROM todo_tags WHERE todo_id IN (SELECT id FROM todos WHERE id = ? AND workspace_id = ?)", id, wsID).Error
+	if err != nil {
+		return err
+	}
+	return db.Unscoped().Scopes(workspaceScope(ctx, "todos")).Delete(&models.Todo{}, id).Error
+}
+
+func (r *todoRepo) ListExpiredDeleted(ctx context.Context, before time.Time, limit int) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	err := conn(ctx, r.db).Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("deleted_at ASC").Limit(limit).Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *todoRepo) ListArchivable(ctx context.Context, before time.Time, limit int) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	err := conn(ctx, r.db).
+		Where("completed = ? AND archived = ?", true, false).
+		Where("completed_at < ? OR (completed_at IS NULL AND updated_at < ?)", before, before).
+		Order("id ASC").Limit(limit).Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// filterScope 将过滤条件转换为查询作用域
+func filterScope(filter TodoFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch filter.Archived {
+		case ArchiveExclude:
+			db = db.Where("todos.archived = ?", false)
+		case ArchiveOnly:
+			db = db.Where("todos.archived = ?", true)
+		}
+		if filter.Completed != nil {
+			db = db.Where("todos.completed = ?", *filter.Completed)
+		}
+		if len(filter.CategoryIDs) > 0 {
+			db = db.Where("todos.category_id IN ?", filter.CategoryIDs)
+		}
+		if filter.Uncategorized {
+			db = db.Where("todos.category_id IS NULL")
+		}
+		if filter.Priority != "" {
+			db = db.Where("todos.priority = ?", filter.Priority)
+		}
+		if filter.StatusID != nil {
+			db = db.Where("todos.status_id = ?", *filter.StatusID)
+		}
+		if filter.NoDueDate {
+			db = db.Where("todos.due_date IS NULL")
+		}
+		if filter.DueFrom != nil {
+			db = db.Where("todos.due_date >= ?", *filter.DueFrom)
+		}
+		if filter.DueBefore != nil {
+			db = db.Where("todos.due_date < ?", *filter.DueBefore)
+		}
+		if filter.Keyword != "" {
+			like := "%" + escapeLike(filter.Keyword) + "%"
+			db = db.Where("todos.title LIKE ? OR todos.description LIKE ?", like, like)
+		}
+		return db.Scopes(queryScope(filter.Query, time.Now()))
+	}
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
@@ -0,0 +1,84 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"time"
+	"todo/internal/models"
+	"todo/internal/query"
+
+	"gorm.io/gorm"
+)
+
+// queryScope 将解析后的查询编译为 GORM 条件，相对时间以 now 为基准
+// 取反的条件按 NOT COALESCE(...) 生成，使截止时间或分类为空的待办事项也能匹配取反条件
+func queryScope(q *query.Query, now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if q == nil {
+			return db
+		}
+		for _, term := range q.Terms {
+			sql, vars := termCondition(term, now)
+			if term.Negate {
+				sql = "NOT COALESCE((" + sql + "), FALSE)"
+			}
+			db = db.Where(sql, vars...)
+		}
+		return db
+	}
+}
+
+// termCondition 返回单个条件对应的 SQL 片段和参数
+func termCondition(term query.Term, now time.Time) (string, []interface{}) {
+	switch term.Field {
+	case query.FieldPriority:
+		return "todos.priority = ?", []interface{}{term.Value}
+	case query.FieldCompleted:
+		return "todos.completed = ?", []interface{}{true}
+	case query.FieldArchived:
+		return "todos.archived = ?", []interface{}{true}
+	case query.FieldCategory:
+		return "EXISTS (SELECT 1 FROM categories WHERE categories.id = todos.category_id" +
+			" AND categories.deleted_at IS NULL AND categories.name = ?)", []interface{}{term.Value}
+	case query.FieldTag:
+		return "EXISTS (SELECT 1 FROM todo_tags JOIN tags ON tags.id = todo_tags.tag_id" +
+			" WHERE todo_tags.todo_id = todos.id AND tags.name = ?)", []interface{}{term.Value}
+	case query.FieldDue:
+		return dueCondition(term, now)
+	default:
+		like := "%" + escapeLike(term.Value) + "%"
+		return "(todos.title LIKE ? OR todos.description LIKE ?)", []interface{}{like, like}
+	}
+}
+
+// dueCondition 返回截止时间条件：关键字、日期或相对偏移
+func dueCondition(term query.Term, now time.Time) (string, []interface{}) {
+	switch term.Value {
+	case models.DueOverdue:
+		return "todos.due_date < ? AND todos.completed = ?", []interface{}{now, false}
+	case models.DueNone:
+		return "todos.due_date IS NULL", nil
+	case "":
+	default:
+		from, before, _ := query.DueRange(term.Value, now)
+		return "todos.due_date >= ? AND todos.due_date < ?", []interface{}{from, before}
+	}
+
+	if !term.Date.IsZero() {
+		// 日期按整天比较：due:D 表示当天，due<=D 包含当天，due>D 从次日开始
+		next := term.Date.AddDate(0, 0, 1)
+		switch term.Op {
+		case query.OpLt:
+			return "todos.due_date < ?", []interface{}{term.Date}
+		case query.OpLte:
+			return "todos.due_date < ?", []interface{}{next}
+		case query.OpGt:
+			return "todos.due_date >= ?", []interface{}{next}
+		case query.OpGte:
+			return "todos.due_date >= ?", []interface{}{term.Date}
+		default:
+			return "todos.due_date >= ? AND todos.due_date < ?", []interface{}{term.Date, next}
+		}
+	}
+
+	return "todos.due_date " + string(term.Op) + " ?", []interface{}{now.Add(term.Offset)}
+}
@@ -0,0 +1,77 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey 上下文中存放事务连接的键
+type txKey struct{}
+
+// afterCommitKey 上下文中存放事务提交后回调的键
+type afterCommitKey struct{}
+
+// Transactor 定义跨仓储的事务执行接口
+// 在 fn 中通过传入的 ctx 调用任意仓储方法，这些调用都会落在同一个数据库事务中
+type Transactor interface {
+	// WithinTransaction 在事务中执行 fn
+	// ctx: 上下文信息
+	// fn: 事务内执行的函数，返回错误时事务回滚
+	// 返回: error fn 返回的错误或提交失败的错误
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// gormTransactor 基于 GORM 的事务实现
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor 创建事务执行器实例
+// db: 数据库连接实例
+// 返回: Transactor 接口实现
+func NewTransactor(db *gorm.DB) Transactor {
+	return &gormTransactor{db: db}
+}
+
+func (t *gormTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// 已处于事务中时直接复用，避免嵌套开启新事务
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	var hooks []func()
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txCtx := context.WithValue(ctx, txKey{}, tx)
+		return fn(context.WithValue(txCtx, afterCommitKey{}, &hooks))
+	})
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		hook()
+	}
+	return nil
+}
+
+// AfterCommit 注册在当前事务提交后执行的回调，事务回滚时不执行
+// 不在事务中时立即执行；适合发送通知等不应在回滚后发生的副作用
+// ctx: 上下文信息
+// fn: 回调函数
+func AfterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(afterCommitKey{}).(*[]func()); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+	fn()
+}
+
+// conn 返回当前上下文应使用的数据库连接
+// 如果上下文中存在事务则使用事务连接，否则使用仓储自身的连接
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -10,6 +10,8 @@ import (
 )
 
 // UserRepository 定义用户仓储接口
+// 用户是跨工作空间共享的身份信息，本身不属于任何租户；
+// 用户与工作空间的归属关系由 WorkspaceRepository 维护
 type UserRepository interface {
 	// Create 创建新用户
 	// ctx: 上下文信息
@@ -29,6 +31,12 @@ type UserRepository interface {
 	// 返回: (*models.User, error) 用户信息和可能的错误
 	GetByUsername(ctx context.Context, username string) (*models.User, error)
 
+	// GetMemberByUsername 在当前工作空间的成员中按用户名查找用户
+	// ctx: 上下文信息，必须携带工作空间
+	// username: 用户名
+	// 返回: (*models.User, error) 用户信息，不是当前工作空间成员时返回 ErrUserNotFound
+	GetMemberByUsername(ctx context.Context, username string) (*models.User, error)
+
 	// Update 更新用户信息
 	// ctx: 上下文信息
 	// user: 需要更新的用户信息
@@ -48,12 +56,28 @@ type userRepo struct {
 }
 
 func (r *userRepo) Create(ctx context.Context, user *models.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return conn(ctx, r.db).Create(user).Error
 }
 
 func (r *userRepo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	var user models.User
-	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
+	if err := conn(ctx, r.db).Where("username = ?", username).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepo) GetMemberByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	err := conn(ctx, r.db).
+		Joins("JOIN workspace_members ON workspace_members.user_id = users.id AND workspace_members.deleted_at IS NULL").
+		Scopes(workspaceScope(ctx, "workspace_members")).
+		Where("users.username = ?", username).
+		First(&user).Error
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.ErrUserNotFound
 		}
@@ -64,7 +88,7 @@ func (r *userRepo) GetByUsername(ctx context.Context, username string) (*models.
 
 func (r *userRepo) GetByID(ctx context.Context, id uint) (*models.User, error) {
 	var user models.User
-	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&user, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.ErrUserNotFound
 		}
@@ -74,9 +98,9 @@ func (r *userRepo) GetByID(ctx context.Context, id uint) (*models.User, error) {
 }
 
 func (r *userRepo) Update(ctx context.Context, user *models.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return conn(ctx, r.db).Save(user).Error
 }
 
 func (r *userRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.User{}, id).Error
+	return conn(ctx, r.db).Delete(&models.User{}, id).Error
 }
@@ -0,0 +1,119 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// WebhookRepository 定义 webhook 端点及其投递日志的仓储接口
+// 端点的读写都限定在上下文中的当前工作空间内；投递日志通过所属端点间接限定
+type WebhookRepository interface {
+	// Create 创建 webhook 端点
+	// ctx: 上下文信息
+	// hook: webhook 端点
+	// 返回: error 创建过程中的错误信息
+	Create(ctx context.Context, hook *models.Webhook) error
+
+	// GetByID 根据ID获取 webhook 端点
+	// ctx: 上下文信息
+	// id: 端点ID
+	// 返回: (*models.Webhook, error) 不存在时返回 ErrWebhookNotFound
+	GetByID(ctx context.Context, id uint) (*models.Webhook, error)
+
+	// ListByUserID 获取用户在当前工作空间中的所有 webhook 端点，按ID排序
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// 返回: ([]*models.Webhook, error) 端点列表和可能的错误
+	ListByUserID(ctx context.Context, userID uint) ([]*models.Webhook, error)
+
+	// Update 更新 webhook 端点的地址、事件、启用状态和失败计数
+	// ctx: 上下文信息
+	// hook: webhook 端点
+	// 返回: error 更新过程中的错误信息
+	Update(ctx context.Context, hook *models.Webhook) error
+
+	// Delete 永久删除 webhook 端点及其投递日志
+	// ctx: 上下文信息
+	// id: 端点ID
+	// 返回: error 删除过程中的错误信息
+	Delete(ctx context.Context, id uint) error
+
+	// CreateDelivery 记录一次投递尝试
+	// ctx: 上下文信息
+	// delivery: 投递日志
+	// 返回: error 创建过程中的错误信息
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+
+	// ListDeliveries 获取端点最近的投递日志，按ID倒序
+	// ctx: 上下文信息
+	// webhookID: 端点ID
+	// limit: 最大返回数量
+	// 返回: ([]*models.WebhookDelivery, error) 投递日志和可能的错误
+	ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]*models.WebhookDelivery, error)
+}
+
+// webhookRepo 实现 WebhookRepository 接口
+type webhookRepo struct {
+	db *gorm.DB
+}
+
+func (r *webhookRepo) Create(ctx context.Context, hook *models.Webhook) error {
+	wsID, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	hook.WorkspaceID = wsID
+	return conn(ctx, r.db).Create(hook).Error
+}
+
+func (r *webhookRepo) GetByID(ctx context.Context, id uint) (*models.Webhook, error) {
+	var hook models.Webhook
+	if err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "webhooks")).First(&hook, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return &hook, nil
+}
+
+func (r *webhookRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.Webhook, error) {
+	var hooks []*models.Webhook
+	err := conn(ctx, r.db).Scopes(workspaceScope(ctx, "webhooks")).Where("user_id = ?", userID).
+		Order("id ASC").Find(&hooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+func (r *webhookRepo) Update(ctx context.Context, hook *models.Webhook) error {
+	return conn(ctx, r.db).Model(hook).Scopes(workspaceScope(ctx, "webhooks")).
+		Select("url", "events", "enabled", "failures", "disabled_at").Updates(hook).Error
+}
+
+func (r *webhookRepo) Delete(ctx context.Context, id uint) error {
+	db := conn(ctx, r.db)
+	result := db.Unscoped().Scopes(workspaceScope(ctx, "webhooks")).Delete(&models.Webhook{}, id)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	return db.Unscoped().Where("webhook_id = ?", id).Delete(&models.WebhookDelivery{}).Error
+}
+
+func (r *webhookRepo) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return conn(ctx, r.db).Create(delivery).Error
+}
+
+func (r *webhookRepo) ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	err := conn(ctx, r.db).Where("webhook_id = ?", webhookID).Order("id DESC").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
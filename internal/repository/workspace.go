@@ -0,0 +1,183 @@
+// Package repository 实现数据访问层
+package repository
+
+import (
+	"context"
+	"todo/internal/models"
+	"todo/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// WorkspaceRepository 定义工作空间仓储接口
+// 工作空间本身是租户边界，因此该仓储不使用租户作用域，访问控制由成员关系保证
+type WorkspaceRepository interface {
+	// Create 创建新的工作空间
+	// ctx: 上下文信息
+	// workspace: 工作空间信息
+	// 返回: error 创建过程中的错误信息
+	Create(ctx context.Context, workspace *models.Workspace) error
+
+	// GetByID 根据ID获取工作空间
+	// ctx: 上下文信息
+	// id: 工作空间ID
+	// 返回: (*models.Workspace, error) 工作空间信息和可能的错误
+	GetByID(ctx context.Context, id uint) (*models.Workspace, error)
+
+	// ListByUserID 获取用户加入的所有工作空间
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// 返回: ([]*models.Workspace, error) 工作空间列表和可能的错误
+	ListByUserID(ctx context.Context, userID uint) ([]*models.Workspace, error)
+
+	// AddMember 添加工作空间成员
+	// ctx: 上下文信息
+	// member: 成员信息
+	// 返回: error 添加过程中的错误信息
+	AddMember(ctx context.Context, member *models.WorkspaceMember) error
+
+	// GetMember 获取用户在工作空间中的成员信息
+	// ctx: 上下文信息
+	// workspaceID: 工作空间ID
+	// userID: 用户ID
+	// 返回: (*models.WorkspaceMember, error) 成员信息，不是成员时返回 ErrNotWorkspaceMember
+	GetMember(ctx context.Context, workspaceID, userID uint) (*models.WorkspaceMember, error)
+
+	// ListMembers 获取工作空间的所有成员，包含用户信息
+	// ctx: 上下文信息
+	// workspaceID: 工作空间ID
+	// 返回: ([]*models.WorkspaceMember, error) 成员列表和可能的错误
+	ListMembers(ctx context.Context, workspaceID uint) ([]*models.WorkspaceMember, error)
+
+	// RemoveMember 移除工作空间成员
+	// ctx: 上下文信息
+	// workspaceID: 工作空间ID
+	// userID: 用户ID
+	// 返回: error 移除过程中的错误信息
+	RemoveMember(ctx context.Context, workspaceID, userID uint) error
+
+	// CreateInvite 创建邀请
+	// ctx: 上下文信息
+	// invite: 邀请信息
+	// 返回: error 创建过程中的错误信息
+	CreateInvite(ctx context.Context, invite *models.WorkspaceInvite) error
+
+	// GetInviteByToken 根据令牌获取邀请
+	// ctx: 上下文信息
+	// token: 邀请令牌
+	// 返回: (*models.WorkspaceInvite, error) 邀请信息和可能的错误
+	GetInviteByToken(ctx context.Context, token string) (*models.WorkspaceInvite, error)
+
+	// UpdateInvite 更新邀请
+	// ctx: 上下文信息
+	// invite: 需要更新的邀请信息
+	// 返回: error 更新过程中的错误信息
+	UpdateInvite(ctx context.Context, invite *models.WorkspaceInvite) error
+
+	// ClaimLegacyData 将用户在引入工作空间之前创建的数据归入指定工作空间
+	// ctx: 上下文信息
+	// userID: 用户ID
+	// workspaceID: 目标工作空间ID
+	// 返回: error 迁移过程中的错误信息
+	ClaimLegacyData(ctx context.Context, userID, workspaceID uint) error
+}
+
+// workspaceRepo 实现 WorkspaceRepository 接口
+type workspaceRepo struct {
+	db *gorm.DB
+}
+
+func (r *workspaceRepo) Create(ctx context.Context, workspace *models.Workspace) error {
+	return conn(ctx, r.db).Create(workspace).Error
+}
+
+func (r *workspaceRepo) GetByID(ctx context.Context, id uint) (*models.Workspace, error) {
+	var workspace models.Workspace
+	if err := conn(ctx, r.db).First(&workspace, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+func (r *workspaceRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.Workspace, error) {
+	var workspaces []*models.Workspace
+	err := conn(ctx, r.db).
+		Joins("JOIN workspace_members ON workspace_members.workspace_id = workspaces.id AND workspace_members.deleted_at IS NULL").
+		Where("workspace_members.user_id = ?", userID).
+		Find(&workspaces).Error
+	if err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+func (r *workspaceRepo) AddMember(ctx context.Context, member *models.WorkspaceMember) error {
+	return conn(ctx, r.db).Create(member).Error
+}
+
+func (r *workspaceRepo) GetMember(ctx context.Context, workspaceID, userID uint) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+	err := conn(ctx, r.db).Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotWorkspaceMember
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *workspaceRepo) ListMembers(ctx context.Context, workspaceID uint) ([]*models.WorkspaceMember, error) {
+	var members []*models.WorkspaceMember
+	if err := conn(ctx, r.db).Preload("User").Where("workspace_id = ?", workspaceID).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *workspaceRepo) RemoveMember(ctx context.Context, workspaceID, userID uint) error {
+	// 成员关系使用硬删除，以便之后可以再次邀请同一用户（唯一索引不区分软删除）
+	return conn(ctx, r.db).Unscoped().
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Delete(&models.WorkspaceMember{}).Error
+}
+
+func (r *workspaceRepo) CreateInvite(ctx context.Context, invite *models.WorkspaceInvite) error {
+	return conn(ctx, r.db).Create(invite).Error
+}
+
+func (r *workspaceRepo) GetInviteByToken(ctx context.Context, token string) (*models.WorkspaceInvite, error) {
+	var invite models.WorkspaceInvite
+	if err := conn(ctx, r.db).Where("token = ?", token).First(&invite).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrInviteNotFound
+		}
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (r *workspaceRepo) UpdateInvite(ctx context.Context, invite *models.WorkspaceInvite) error {
+	return conn(ctx, r.db).Save(invite).Error
+}
+
+func (r *workspaceRepo) ClaimLegacyData(ctx context.Context, userID, workspaceID uint) error {
+	// 回收站中的数据同样需要归入工作空间，否则恢复后将无法在任何工作空间中访问
+	db := conn(ctx, r.db).Unscoped()
+	if err := db.Model(&models.Category{}).
+		Where("user_id = ? AND workspace_id = 0", userID).
+		Update("workspace_id", workspaceID).Error; err != nil {
+		return err
+	}
+	if err := db.Model(&models.Todo{}).
+		Where("user_id = ? AND workspace_id = 0", userID).
+		Update("workspace_id", workspaceID).Error; err != nil {
+		return err
+	}
+	return db.Model(&models.Reminder{}).
+		Where("workspace_id = 0 AND todo_id IN (?)", db.Model(&models.Todo{}).Select("id").Where("user_id = ?", userID)).
+		Update("workspace_id", workspaceID).Error
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sqlRecorder 记录 DryRun 模式下生成的 SQL 语句
+type sqlRecorder struct {
+	logger.Interface
+	statements []string
+}
+
+func (r *sqlRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, sql)
+}
+
+// newDryRunDB 创建不连接数据库、只生成 SQL 的 gorm 实例
+func newDryRunDB(t *testing.T) (*gorm.DB, *sqlRecorder) {
+	recorder := &sqlRecorder{Interface: logger.Discard}
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "dry:run@tcp(127.0.0.1:0)/todo", SkipInitializeWithVersion: true}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 recorder,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open() 错误 = %v", err)
+	}
+	return db, recorder
+}
+
+// TestWorkspaceRepo_ClaimLegacyDataIncludesDeleted 测试回收站中的旧数据同样被归入工作空间
+func TestWorkspaceRepo_ClaimLegacyDataIncludesDeleted(t *testing.T) {
+	db, recorder := newDryRunDB(t)
+	repo := NewWorkspaceRepository(db)
+
+	if err := repo.ClaimLegacyData(context.Background(), 1, 7); err != nil {
+		t.Fatalf("ClaimLegacyData() 错误 = %v", err)
+	}
+	if len(recorder.statements) != 3 {
+		t.Fatalf("执行了 %d 条语句, 期望 3 条: %v", len(recorder.statements), recorder.statements)
+	}
+	for _, sql := range recorder.statements {
+		if strings.Contains(sql, "deleted_at") {
+			t.Errorf("语句 %q 排除了已软删除的行", sql)
+		}
+	}
+}
@@ -15,7 +15,14 @@ import (
 // InitRouter 初始化路由
 // 该函数负责设置所有的HTTP路由规则，包括API端点、中间件和Swagger文档
 func InitRouter(cfg *config.Config, authService service.AuthService, todoService service.TodoService,
-	categoryService service.CategoryService, reminderService service.ReminderService) *gin.Engine {
+	categoryService service.CategoryService, reminderService service.ReminderService,
+	workspaceService service.WorkspaceService, commentService service.CommentService,
+	attachmentService service.AttachmentService, searchService service.SearchService,
+	filterService service.FilterService, statusService service.StatusService,
+	dependencyService service.DependencyService, timeEntryService service.TimeEntryService,
+	templateService service.TemplateService, calendarService service.CalendarService,
+	calDAVService service.CalDAVService, backupService service.BackupService,
+	webhookService service.WebhookService) *gin.Engine {
 
 	// 创建一个新的Gin引擎实例
 	r := gin.New()
@@ -45,21 +52,128 @@ func InitRouter(cfg *config.Config, authService service.AuthService, todoService
 			auth.POST("/login", handlers.Login(authService))       // 用户登录
 		}
 
+		// 日历订阅地址，由日历客户端定期拉取，令牌即为凭据，不使用JWT认证
+		// 路径形如 /ical/<token>.ics
+		v1.GET("/ical/:file", handlers.CalendarFeed(calendarService))
+
 		// 需要认证的路由组
 		// 以下所有路由都需要有效的JWT令牌才能访问
 		authorized := v1.Group("/")
-		authorized.Use(middleware.AuthMiddleware(&cfg.JWT))
+		authorized.Use(middleware.AuthMiddleware(&cfg.JWT, workspaceService))
 		{
 			// 待办事项管理路由组
 			todos := authorized.Group("/todos")
 			{
 				todos.POST("", handlers.CreateTodo(todoService, categoryService))       // 创建待办事项
-				todos.GET("", handlers.ListTodos(todoService))         // 获取待办事项列表
-				todos.GET("/:id", handlers.GetTodo(todoService))       // 获取单个待办事项
+				todos.GET("", handlers.ListTodos(todoService, filterService))         // 获取待办事项列表
+				todos.GET("/trash", handlers.ListTrash(todoService))   // 获取回收站
+				todos.POST("/bulk", handlers.BulkTodos(todoService))   // 批量操作
+				todos.POST("/quick", handlers.QuickAddTodo(todoService)) // 用自然语言快速添加
+				todos.GET("/export.csv", handlers.ExportTodosCSV(todoService, filterService)) // 导出 CSV
+				todos.GET("/export.txt", handlers.ExportTodosTxt(todoService, filterService))       // 导出 todo.txt
+				todos.GET("/export.md", handlers.ExportTodosMarkdown(todoService, filterService))   // 导出 Markdown 任务列表
+				todos.POST("/import", handlers.ImportTodos(todoService))                      // 导入 CSV、todo.txt 或 Markdown
+				todos.GET("/export.ics", handlers.ExportTodosICal(calendarService, filterService)) // 导出 iCalendar
+				todos.GET("/:id", handlers.GetTodo(todoService, commentService, dependencyService, timeEntryService)) // 获取单个待办事项
 				todos.PUT("/:id", handlers.UpdateTodo(todoService))    // 更新待办事项
-				todos.DELETE("/:id", handlers.DeleteTodo(todoService)) // 删除待办事项
+				todos.DELETE("/:id", handlers.DeleteTodo(todoService)) // 删除待办事项，permanent=true 时永久删除
+				todos.POST("/:id/restore", handlers.RestoreTodo(todoService)) // 从回收站恢复
+				todos.POST("/:id/move", handlers.MoveTodo(todoService))       // 拖动排序
+				todos.POST("/:id/duplicate", handlers.DuplicateTodo(todoService)) // 复制
+				todos.POST("/:id/template", handlers.SaveTodoAsTemplate(templateService)) // 保存为模板
+				todos.POST("/:id/archive", handlers.ArchiveTodo(todoService))     // 归档
+				todos.POST("/:id/unarchive", handlers.UnarchiveTodo(todoService)) // 取消归档
+
+				// 变更历史
+				todos.GET("/:id/history", handlers.GetTodoHistory(todoService))                       // 获取变更历史
+				todos.POST("/:id/history/:change_id/revert", handlers.RevertTodo(todoService))        // 回滚到指定版本
+
+				// 评论
+				todos.POST("/:id/comments", handlers.CreateComment(commentService))                               // 发表评论
+				todos.GET("/:id/comments", handlers.ListComments(commentService))                                 // 获取评论列表
+				todos.PUT("/:id/comments/:comment_id", handlers.UpdateComment(commentService))                    // 编辑评论
+				todos.DELETE("/:id/comments/:comment_id", handlers.DeleteComment(commentService))                 // 删除评论
+				todos.GET("/:id/comments/:comment_id/history", handlers.GetCommentHistory(commentService))        // 获取评论编辑历史
+
+				// 依赖关系
+				todos.POST("/:id/dependencies", handlers.AddDependency(dependencyService))                          // 添加阻塞项
+				todos.GET("/:id/dependencies", handlers.ListDependencies(dependencyService))                        // 获取依赖关系
+				todos.DELETE("/:id/dependencies/:blocker_id", handlers.RemoveDependency(dependencyService))         // 移除阻塞项
+
+				// 时间记录
+				todos.POST("/:id/timer/start", handlers.StartTimer(timeEntryService))                              // 启动计时器
+				todos.POST("/:id/timer/stop", handlers.StopTimer(timeEntryService))                                // 停止计时器
+				todos.POST("/:id/time-entries", handlers.CreateTimeEntry(timeEntryService))                        // 手动录入时间
+				todos.GET("/:id/time-entries", handlers.ListTimeEntries(timeEntryService))                         // 获取时间记录
+				todos.DELETE("/:id/time-entries/:entry_id", handlers.DeleteTimeEntry(timeEntryService))            // 删除时间记录
+
+				// 附件
+				todos.POST("/:id/attachments", handlers.UploadAttachment(attachmentService, cfg.Attachment.MaxSize))                           // 上传附件
+				todos.GET("/:id/attachments", handlers.ListAttachments(attachmentService))                             // 获取附件列表
+				todos.GET("/:id/attachments/:attachment_id", handlers.DownloadAttachment(attachmentService))           // 下载附件
+				todos.DELETE("/:id/attachments/:attachment_id", handlers.DeleteAttachment(attachmentService))          // 删除附件
+			}
+
+			// 时间记录
+			authorized.GET("/timer", handlers.GetRunningTimer(timeEntryService))    // 正在运行的计时器
+			authorized.GET("/reports/time", handlers.GetTimeReport(timeEntryService)) // 按分类汇总的时间报表
+
+			// 日历订阅
+			authorized.GET("/calendar/feed", handlers.GetCalendarFeed(calendarService))       // 获取订阅地址
+			authorized.POST("/calendar/feed", handlers.RotateCalendarFeed(calendarService))   // 创建订阅或重新生成令牌
+			authorized.DELETE("/calendar/feed", handlers.RevokeCalendarFeed(calendarService)) // 撤销订阅
+
+			// 账户数据导出与导入
+			authorized.POST("/backup/export", handlers.StartDataExport(backupService))             // 开始导出
+			authorized.GET("/backup/export", handlers.GetDataExport(backupService))                // 查询导出进度
+			authorized.GET("/backup/export/download", handlers.DownloadDataExport(backupService))  // 下载归档
+			authorized.POST("/backup/import", handlers.ImportDataArchive(backupService))           // 导入归档
+
+			// 全文搜索
+			authorized.GET("/search", handlers.Search(searchService))
+
+			// 保存的过滤条件（智能列表）路由组
+			filters := authorized.Group("/filters")
+			{
+				filters.POST("", handlers.CreateFilter(filterService))       // 保存过滤条件
+				filters.GET("", handlers.ListFilters(filterService))         // 获取过滤条件列表（附带数量）
+				filters.PUT("/:id", handlers.UpdateFilter(filterService))    // 更新过滤条件
+				filters.DELETE("/:id", handlers.DeleteFilter(filterService)) // 删除过滤条件
 			}
 
+			// webhook 路由组
+			webhooks := authorized.Group("/webhooks")
+			{
+				webhooks.POST("", handlers.CreateWebhook(webhookService))                          // 注册端点
+				webhooks.GET("", handlers.ListWebhooks(webhookService))                            // 获取端点列表
+				webhooks.PUT("/:id", handlers.UpdateWebhook(webhookService))                       // 更新端点
+				webhooks.DELETE("/:id", handlers.DeleteWebhook(webhookService))                    // 删除端点
+				webhooks.GET("/:id/deliveries", handlers.ListWebhookDeliveries(webhookService))    // 投递日志
+				webhooks.POST("/:id/ping", handlers.PingWebhook(webhookService))                   // 发送测试事件
+			}
+
+			// 待办事项模板路由组
+			templates := authorized.Group("/templates")
+			{
+				templates.POST("", handlers.CreateTemplate(templateService))                       // 创建模板
+				templates.GET("", handlers.ListTemplates(templateService))                         // 获取模板列表
+				templates.GET("/:id", handlers.GetTemplate(templateService))                       // 获取模板详情
+				templates.PUT("/:id", handlers.UpdateTemplate(templateService))                    // 更新模板
+				templates.DELETE("/:id", handlers.DeleteTemplate(templateService))                 // 删除模板
+				templates.POST("/:id/instantiate", handlers.InstantiateTemplate(templateService))  // 按基准时间创建全部待办事项
+			}
+
+			// 看板工作流状态路由组
+			statuses := authorized.Group("/statuses")
+			{
+				statuses.POST("", handlers.CreateStatus(statusService))          // 创建状态
+				statuses.GET("", handlers.ListStatuses(statusService))           // 获取状态列表
+				statuses.PUT("/order", handlers.ReorderStatuses(statusService))  // 调整列顺序
+				statuses.PUT("/:id", handlers.UpdateStatus(statusService))       // 更新状态
+				statuses.DELETE("/:id", handlers.DeleteStatus(statusService))    // 删除状态，move_to 指定其中待办事项的去向
+			}
+			authorized.GET("/board", handlers.GetBoard(statusService)) // 看板
+
 			// 分类管理路由组
 			categories := authorized.Group("/categories")
 			{
@@ -67,6 +181,7 @@ func InitRouter(cfg *config.Config, authService service.AuthService, todoService
 				categories.GET("", handlers.ListCategories(categoryService))        // 获取分类列表
 				categories.PUT("/:id", handlers.UpdateCategory(categoryService))    // 更新分类
 				categories.DELETE("/:id", handlers.DeleteCategory(categoryService)) // 删除分类
+				categories.POST("/:id/move", handlers.MoveCategory(categoryService)) // 移动分类（含子树）
 			}
 
 			// 提醒管理路由组
@@ -77,8 +192,30 @@ func InitRouter(cfg *config.Config, authService service.AuthService, todoService
 				reminders.PUT("/:id", handlers.UpdateReminder(reminderService))          // 更新提醒
 				reminders.DELETE("/:id", handlers.DeleteReminder(reminderService))       // 删除提醒
 			}
+
+			// 工作空间管理路由组
+			workspaces := authorized.Group("/workspaces")
+			{
+				workspaces.POST("", handlers.CreateWorkspace(workspaceService))                                // 创建工作空间
+				workspaces.GET("", handlers.ListWorkspaces(workspaceService))                                  // 获取工作空间列表
+				workspaces.POST("/:id/switch", handlers.SwitchWorkspace(workspaceService))                     // 切换工作空间
+				workspaces.POST("/:id/invites", handlers.InviteMember(workspaceService))                       // 邀请成员
+				workspaces.GET("/:id/members", handlers.ListWorkspaceMembers(workspaceService))                // 获取成员列表
+				workspaces.DELETE("/:id/members/:user_id", handlers.RemoveWorkspaceMember(workspaceService))   // 移除成员
+				workspaces.POST("/invites/:token/accept", handlers.AcceptInvite(workspaceService))             // 接受邀请
+			}
 		}
 	}
 
+	// CalDAV 双向同步，日历客户端使用 Basic 认证，请求方法包含 PROPFIND、REPORT 等扩展方法
+	// 地址形如 /caldav/<用户名>/<集合>/<资源名>
+	r.Any("/.well-known/caldav", handlers.CalDAVWellKnown)
+	r.Handle("PROPFIND", "/.well-known/caldav", handlers.CalDAVWellKnown)
+	caldav := r.Group("/caldav")
+	caldav.Use(middleware.BasicAuthMiddleware(authService, workspaceService, "todo"))
+	for _, method := range handlers.CalDAVMethods {
+		caldav.Handle(method, "/*path", handlers.CalDAV(calDAVService))
+	}
+
 	return r
 }
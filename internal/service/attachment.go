@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"io"
+	"todo/internal/models"
+)
+
+// AttachmentService 附件服务接口
+type AttachmentService interface {
+	// Upload 上传附件
+	// fileName: 原始文件名
+	// size: 文件大小（字节）
+	// r: 文件内容
+	Upload(ctx context.Context, userID, todoID uint, fileName string, size int64, r io.Reader) (*models.Attachment, error)
+
+	// List 获取待办事项的附件列表
+	List(ctx context.Context, userID, todoID uint) ([]*models.Attachment, error)
+
+	// Open 打开附件内容用于下载，调用方负责关闭返回的 ReadCloser
+	Open(ctx context.Context, userID, todoID, attachmentID uint) (*models.Attachment, io.ReadCloser, error)
+
+	// Delete 删除附件及其存储的文件
+	Delete(ctx context.Context, userID, todoID, attachmentID uint) error
+
+	// CleanupTodo 删除待办事项的所有附件及其存储的文件
+	CleanupTodo(ctx context.Context, todoID uint) error
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"todo/api/v1/dto/auth"
+	"todo/internal/models"
 )
 
 // AuthService 定义认证相关的业务接口
@@ -18,4 +19,9 @@ type AuthService interface {
 	// req: 登录请求，包含用户名和密码
 	// 返回JWT令牌、用户信息和可能的错误
 	Login(ctx context.Context, req *auth.LoginRequest) (string, *auth.UserInfo, error)
+
+	// Authenticate 校验用户名和密码
+	// 用于不使用JWT的客户端（例如 CalDAV 的 Basic 认证），返回的用户一定有默认工作空间
+	// 用户名或密码错误时返回 ErrInvalidCredentials
+	Authenticate(ctx context.Context, username, password string) (*models.User, error)
 }
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"io"
+	"todo/api/v1/dto/backup"
+	"todo/internal/models"
+)
+
+// BackupService 账户数据导出与导入服务接口
+type BackupService interface {
+	// StartExport 创建数据导出任务并交给后台任务队列执行，已有未完成的任务时直接返回该任务
+	StartExport(ctx context.Context, userID uint) (*models.DataExport, error)
+
+	// GetExport 获取用户最近一次的数据导出任务
+	GetExport(ctx context.Context, userID uint) (*models.DataExport, error)
+
+	// OpenExport 打开已生成的归档，调用方负责关闭返回的 ReadCloser
+	OpenExport(ctx context.Context, userID uint) (*models.DataExport, io.ReadCloser, error)
+
+	// Import 从 r 中读取归档，重新分配ID后导入到用户的空账户中
+	Import(ctx context.Context, userID uint, r io.Reader) (*backup.ImportResponse, error)
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"todo/api/v1/dto/caldav"
+)
+
+// CalDAVService CalDAV 同步服务接口
+// 每个分类是一个日历集合，未分类的待办事项组成 uncategorized 集合；已归档的待办事项不参与同步
+type CalDAVService interface {
+	// Collections 获取用户的所有日历集合
+	Collections(ctx context.Context, userID uint) ([]*caldav.Collection, error)
+
+	// Collection 获取日历集合，不存在时返回 ErrCategoryNotFound
+	Collection(ctx context.Context, userID uint, name string) (*caldav.Collection, error)
+
+	// Objects 获取日历集合中的所有对象
+	Objects(ctx context.Context, userID uint, collection string) ([]*caldav.Object, error)
+
+	// Object 获取日历对象，不存在时返回 ErrTodoNotFound
+	Object(ctx context.Context, userID uint, collection, name string) (*caldav.Object, error)
+
+	// Put 创建或更新日历对象，返回写入后的对象以及是否新建
+	Put(ctx context.Context, userID uint, req *caldav.PutRequest) (*caldav.Object, bool, error)
+
+	// Delete 删除日历对象，对应的待办事项移入回收站
+	// ifMatch 非空时只有当前 ETag 匹配才删除，否则返回 ErrPreconditionFailed
+	Delete(ctx context.Context, userID uint, collection, name, ifMatch string) error
+}
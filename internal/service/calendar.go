@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"io"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+)
+
+// CalendarService 日历导出与订阅服务接口
+type CalendarService interface {
+	// Export 将匹配列表查询参数的所有待办事项以 iCalendar 写入 w
+	Export(ctx context.Context, userID uint, req *todo.ListRequest, w io.Writer) error
+
+	// Feed 根据订阅令牌将订阅者未归档的待办事项以 iCalendar 写入 w，无需登录
+	Feed(ctx context.Context, token string, w io.Writer) error
+
+	// GetFeed 获取用户在当前工作空间中的日历订阅
+	GetFeed(ctx context.Context, userID uint) (*models.CalendarFeed, error)
+
+	// RotateFeed 为用户生成新的订阅令牌，尚未订阅时创建订阅
+	RotateFeed(ctx context.Context, userID uint) (*models.CalendarFeed, error)
+
+	// RevokeFeed 撤销用户在当前工作空间中的日历订阅
+	RevokeFeed(ctx context.Context, userID uint) error
+}
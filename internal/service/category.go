@@ -16,12 +16,18 @@ type CategoryService interface {
 	// List 获取用户的分类列表
 	List(ctx context.Context, userID uint) ([]*models.Category, error)
 
+	// Tree 获取用户的分类树，返回顶级分类，下级分类在 Children 中
+	Tree(ctx context.Context, userID uint) ([]*models.Category, error)
+
+	// Move 移动分类到新的上级分类下，parentID 为空时移动到顶级，整棵子树随之移动
+	Move(ctx context.Context, id, userID uint, parentID *uint) error
+
 	// Get 获取分类详情
 	Get(ctx context.Context, id, userID uint) (*models.Category, error)
 
 	// Update 更新分类信息
 	Update(ctx context.Context, id, userID uint, req *category.UpdateRequest) error
 
-	// Delete 删除分类
-	Delete(ctx context.Context, id, userID uint) error
+	// Delete 删除分类，按 req 中的策略处理其下的待办事项，返回受影响的待办事项数量
+	Delete(ctx context.Context, id, userID uint, req *category.DeleteRequest) (int, error)
 }
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"todo/api/v1/dto/comment"
+	"todo/internal/models"
+)
+
+// CommentService 评论服务接口
+type CommentService interface {
+	// Create 在待办事项下发表评论，并通知被@提及的成员
+	Create(ctx context.Context, userID, todoID uint, req *comment.CreateRequest) (*models.Comment, error)
+
+	// List 获取待办事项的评论列表
+	List(ctx context.Context, userID, todoID uint) ([]*models.Comment, error)
+
+	// Latest 获取待办事项最新的若干条评论
+	Latest(ctx context.Context, userID, todoID uint, limit int) ([]*models.Comment, error)
+
+	// Update 编辑评论，旧正文写入编辑历史
+	Update(ctx context.Context, userID, todoID, commentID uint, req *comment.UpdateRequest) (*models.Comment, error)
+
+	// Delete 删除评论
+	Delete(ctx context.Context, userID, todoID, commentID uint) error
+
+	// History 获取评论的编辑历史
+	History(ctx context.Context, userID, todoID, commentID uint) ([]*models.CommentRevision, error)
+
+	// CleanupTodo 永久删除待办事项的所有评论及其编辑历史
+	CleanupTodo(ctx context.Context, todoID uint) error
+}
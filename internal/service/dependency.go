@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"todo/api/v1/dto/dependency"
+	"todo/internal/models"
+)
+
+// DependencyService 待办事项依赖关系服务接口
+type DependencyService interface {
+	// Add 添加阻塞项，blockerID 完成之前 todoID 不能完成；不能形成环
+	Add(ctx context.Context, userID, todoID, blockerID uint) (*models.Dependency, error)
+
+	// Remove 移除阻塞项
+	Remove(ctx context.Context, userID, todoID, blockerID uint) error
+
+	// List 获取待办事项的阻塞项和被它阻塞的待办事项
+	List(ctx context.Context, userID, todoID uint) (*dependency.ListResponse, error)
+
+	// OpenBlockers 获取阻塞待办事项且尚未完成的待办事项，为空表示未被阻塞
+	OpenBlockers(ctx context.Context, userID, todoID uint) ([]*models.Todo, error)
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"todo/api/v1/dto/filter"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+)
+
+// FilterService 保存的过滤条件（智能列表）服务接口
+type FilterService interface {
+	// Create 保存过滤条件
+	Create(ctx context.Context, userID uint, req *filter.CreateRequest) (*models.SavedFilter, error)
+
+	// List 获取用户保存的过滤条件，附带当前匹配的待办事项数量
+	List(ctx context.Context, userID uint) ([]*filter.Item, error)
+
+	// Get 获取过滤条件详情
+	Get(ctx context.Context, id, userID uint) (*models.SavedFilter, error)
+
+	// Update 更新过滤条件
+	Update(ctx context.Context, id, userID uint, req *filter.UpdateRequest) (*models.SavedFilter, error)
+
+	// Delete 删除过滤条件
+	Delete(ctx context.Context, id, userID uint) error
+
+	// Request 返回保存的过滤条件对应的列表查询参数，供导出等需要遍历全部结果的功能使用
+	Request(ctx context.Context, id, userID uint) (*todo.ListRequest, error)
+
+}
@@ -0,0 +1,200 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/config"
+	"todo/pkg/errors"
+	"todo/pkg/logger"
+	"todo/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+// sniffLen 用于识别文件类型的前缀字节数
+const sniffLen = 512
+
+// AttachmentService 附件服务实现
+// 附件元数据保存在数据库中，文件内容保存在可插拔的对象存储中
+type AttachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	todoRepo       repository.TodoRepository
+	blobs          storage.BlobStore
+	cfg            *config.AttachmentConfig
+}
+
+// NewAttachmentService 创建一个新的附件服务实例
+//
+// Parameters:
+//   - attachmentRepo: 附件仓库实现
+//   - todoRepo: 待办事项仓库实现
+//   - blobs: 对象存储
+//   - cfg: 附件大小和类型限制
+//
+// Returns:
+//   - *AttachmentService: 返回附件服务实例
+func NewAttachmentService(attachmentRepo repository.AttachmentRepository, todoRepo repository.TodoRepository,
+	blobs storage.BlobStore, cfg *config.AttachmentConfig) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		todoRepo:       todoRepo,
+		blobs:          blobs,
+		cfg:            cfg,
+	}
+}
+
+// Upload 上传附件
+// 文件类型根据内容识别而不是信任客户端声明的类型
+func (s *AttachmentService) Upload(ctx context.Context, userID, todoID uint, fileName string, size int64, r io.Reader) (*models.Attachment, error) {
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	if s.cfg.MaxSize > 0 && size > s.cfg.MaxSize {
+		return nil, errors.ErrAttachmentTooLarge
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head[:n])
+	if !s.allowed(contentType) {
+		return nil, errors.ErrAttachmentType
+	}
+
+	key := fmt.Sprintf("todos/%d/%s%s", todoID, uuid.New().String(), strings.ToLower(filepath.Ext(fileName)))
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(head[:n]), r), size)
+	if err := s.blobs.Put(ctx, key, body, size, contentType); err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		TodoID:      todoID,
+		UserID:      userID,
+		FileName:    filepath.Base(fileName),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+	}
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		// 元数据写入失败时删除已上传的文件，避免产生孤立对象
+		_ = s.blobs.Delete(ctx, key)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// List 获取待办事项的附件列表
+func (s *AttachmentService) List(ctx context.Context, userID, todoID uint) ([]*models.Attachment, error) {
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	return s.attachmentRepo.ListByTodoID(ctx, todoID)
+}
+
+// Open 打开附件内容
+func (s *AttachmentService) Open(ctx context.Context, userID, todoID, attachmentID uint) (*models.Attachment, io.ReadCloser, error) {
+	attachment, err := s.get(ctx, userID, todoID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := s.blobs.Get(ctx, attachment.StorageKey)
+	if err == storage.ErrBlobNotFound {
+		return nil, nil, errors.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, rc, nil
+}
+
+// Delete 删除附件
+func (s *AttachmentService) Delete(ctx context.Context, userID, todoID, attachmentID uint) error {
+	attachment, err := s.get(ctx, userID, todoID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := s.attachmentRepo.Delete(ctx, attachment.ID); err != nil {
+		return err
+	}
+	return s.blobs.Delete(ctx, attachment.StorageKey)
+}
+
+// CleanupTodo 删除待办事项的所有附件
+// 附件记录随永久删除的事务一起删除，文件在事务提交后删除；单个文件删除失败只记录日志，不影响其余附件的清理
+func (s *AttachmentService) CleanupTodo(ctx context.Context, todoID uint) error {
+	attachments, err := s.attachmentRepo.ListByTodoID(ctx, todoID)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		if err := s.attachmentRepo.Delete(ctx, attachment.ID); err != nil {
+			return err
+		}
+	}
+	// 文件无法回滚，事务提交后再删除
+	repository.AfterCommit(ctx, func() {
+		for _, attachment := range attachments {
+			if err := s.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+				logger.Warn().Err(err).Str("key", attachment.StorageKey).Msg("删除附件文件失败")
+			}
+		}
+	})
+	return nil
+}
+
+// get 获取属于指定待办事项的附件并校验所有权
+func (s *AttachmentService) get(ctx context.Context, userID, todoID, attachmentID uint) (*models.Attachment, error) {
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	attachment, err := s.attachmentRepo.GetByID(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.TodoID != todoID {
+		return nil, errors.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// getTodo 获取待办事项并校验所有权
+func (s *AttachmentService) getTodo(ctx context.Context, userID, todoID uint) (*models.Todo, error) {
+	todo, err := s.todoRepo.GetByID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo.UserID != userID {
+		return nil, errors.ErrForbidden
+	}
+	return todo, nil
+}
+
+// allowed 判断 MIME 类型是否在允许列表中，未配置允许列表时不做限制
+func (s *AttachmentService) allowed(contentType string) bool {
+	if len(s.cfg.AllowedTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range s.cfg.AllowedTypes {
+		if strings.HasSuffix(allowed, "/") && strings.HasPrefix(mediaType, allowed) {
+			return true
+		}
+		if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stderrors "errors"
+	"io"
+	"strings"
+	"testing"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/config"
+	"todo/pkg/errors"
+	"todo/pkg/storage"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// mockAttachmentRepo 模拟附件仓储接口
+type mockAttachmentRepo struct {
+	attachments map[uint]*models.Attachment
+	seq         uint
+	createErr   error
+}
+
+func newMockAttachmentRepo() *mockAttachmentRepo {
+	return &mockAttachmentRepo{attachments: make(map[uint]*models.Attachment), seq: 1}
+}
+
+func (m *mockAttachmentRepo) Create(ctx context.Context, attachment *models.Attachment) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	attachment.ID = m.seq
+	m.seq++
+	m.attachments[attachment.ID] = attachment
+	return nil
+}
+
+func (m *mockAttachmentRepo) GetByID(ctx context.Context, id uint) (*models.Attachment, error) {
+	attachment, exists := m.attachments[id]
+	if !exists {
+		return nil, errors.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+func (m *mockAttachmentRepo) ListByTodoID(ctx context.Context, todoID uint) ([]*models.Attachment, error) {
+	var attachments []*models.Attachment
+	for _, attachment := range m.attachments {
+		if attachment.TodoID == todoID {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments, nil
+}
+
+func (m *mockAttachmentRepo) Delete(ctx context.Context, id uint) error {
+	delete(m.attachments, id)
+	return nil
+}
+
+// memoryBlobs 内存中的对象存储
+type memoryBlobs struct {
+	blobs map[string][]byte
+}
+
+func (m *memoryBlobs) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.blobs[key] = data
+	return nil
+}
+
+func (m *memoryBlobs) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, exists := m.blobs[key]
+	if !exists {
+		return nil, storage.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memoryBlobs) Delete(ctx context.Context, key string) error {
+	delete(m.blobs, key)
+	return nil
+}
+
+// txOnlyConnector 只支持开启、提交和回滚事务的数据库驱动，用于驱动真实的事务执行器
+type txOnlyConnector struct{}
+
+func (txOnlyConnector) Connect(ctx context.Context) (driver.Conn, error) { return txOnlyConn{}, nil }
+func (c txOnlyConnector) Driver() driver.Driver                          { return c }
+func (txOnlyConnector) Open(name string) (driver.Conn, error)            { return txOnlyConn{}, nil }
+
+type txOnlyConn struct{}
+
+func (txOnlyConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, stderrors.New("不支持执行语句")
+}
+func (txOnlyConn) Close() error              { return nil }
+func (txOnlyConn) Begin() (driver.Tx, error) { return txOnlyConn{}, nil }
+func (txOnlyConn) Commit() error             { return nil }
+func (txOnlyConn) Rollback() error           { return nil }
+
+// newTxOnlyTransactor 创建基于 txOnlyConnector 的事务执行器，事务提交后才执行 AfterCommit 回调
+func newTxOnlyTransactor(t *testing.T) repository.Transactor {
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sql.OpenDB(txOnlyConnector{}), SkipInitializeWithVersion: true}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open() 错误 = %v", err)
+	}
+	return repository.NewTransactor(db)
+}
+
+// pngHeader PNG 文件头，足以让内容识别得到 image/png
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// newAttachmentFixture 创建附件服务以及属于用户 1 的待办事项
+func newAttachmentFixture(cfg *config.AttachmentConfig) (*AttachmentService, *mockAttachmentRepo, *memoryBlobs, *models.Todo) {
+	todoRepo := newMockTodoRepo()
+	attachmentRepo := newMockAttachmentRepo()
+	blobs := &memoryBlobs{blobs: make(map[string][]byte)}
+	owned := &models.Todo{Title: "写周报", UserID: 1}
+	_ = todoRepo.Create(context.Background(), owned)
+	return NewAttachmentService(attachmentRepo, todoRepo, blobs, cfg), attachmentRepo, blobs, owned
+}
+
+// TestAttachmentService_UploadLimits 测试上传大小限制和按内容识别的类型限制
+func TestAttachmentService_UploadLimits(t *testing.T) {
+	ctx := context.Background()
+	service, _, blobs, owned := newAttachmentFixture(&config.AttachmentConfig{MaxSize: 64, AllowedTypes: []string{"image/"}})
+
+	large := bytes.Repeat([]byte{0}, 65)
+	if _, err := service.Upload(ctx, 1, owned.ID, "big.png", int64(len(large)), bytes.NewReader(large)); err != errors.ErrAttachmentTooLarge {
+		t.Errorf("超出大小 Upload() 错误 = %v, 期望 %v", err, errors.ErrAttachmentTooLarge)
+	}
+
+	// 扩展名是 .png，但内容是 HTML
+	html := "<html><script>alert(1)</script></html>"
+	if _, err := service.Upload(ctx, 1, owned.ID, "photo.png", int64(len(html)), strings.NewReader(html)); err != errors.ErrAttachmentType {
+		t.Errorf("伪装类型 Upload() 错误 = %v, 期望 %v", err, errors.ErrAttachmentType)
+	}
+	if len(blobs.blobs) != 0 {
+		t.Errorf("被拒绝的上传写入了 %d 个对象", len(blobs.blobs))
+	}
+
+	attachment, err := service.Upload(ctx, 1, owned.ID, "photo.png", int64(len(pngHeader)), bytes.NewReader(pngHeader))
+	if err != nil {
+		t.Fatalf("Upload() 错误 = %v", err)
+	}
+	if attachment.ContentType != "image/png" || !bytes.Equal(blobs.blobs[attachment.StorageKey], pngHeader) {
+		t.Errorf("附件 = %+v, 期望按内容识别为 image/png 并完整保存", attachment)
+	}
+}
+
+// TestAttachmentService_Ownership 测试访问其他待办事项或其他用户的附件
+func TestAttachmentService_Ownership(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, owned := newAttachmentFixture(&config.AttachmentConfig{})
+	other := &models.Todo{Title: "另一个", UserID: 1}
+	_ = service.todoRepo.Create(ctx, other)
+
+	attachment, err := service.Upload(ctx, 1, owned.ID, "a.png", int64(len(pngHeader)), bytes.NewReader(pngHeader))
+	if err != nil {
+		t.Fatalf("Upload() 错误 = %v", err)
+	}
+
+	// 附件不属于路径中的待办事项
+	if _, _, err := service.Open(ctx, 1, other.ID, attachment.ID); err != errors.ErrAttachmentNotFound {
+		t.Errorf("Open() 错误 = %v, 期望 %v", err, errors.ErrAttachmentNotFound)
+	}
+	if err := service.Delete(ctx, 1, other.ID, attachment.ID); err != errors.ErrAttachmentNotFound {
+		t.Errorf("Delete() 错误 = %v, 期望 %v", err, errors.ErrAttachmentNotFound)
+	}
+	// 其他用户
+	if _, _, err := service.Open(ctx, 2, owned.ID, attachment.ID); err != errors.ErrForbidden {
+		t.Errorf("其他用户 Open() 错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+}
+
+// TestAttachmentService_UploadCreateFails 测试元数据写入失败时删除已上传的文件
+func TestAttachmentService_UploadCreateFails(t *testing.T) {
+	ctx := context.Background()
+	service, attachmentRepo, blobs, owned := newAttachmentFixture(&config.AttachmentConfig{})
+	attachmentRepo.createErr = stderrors.New("写入失败")
+
+	if _, err := service.Upload(ctx, 1, owned.ID, "a.png", int64(len(pngHeader)), bytes.NewReader(pngHeader)); err != attachmentRepo.createErr {
+		t.Fatalf("Upload() 错误 = %v, 期望 %v", err, attachmentRepo.createErr)
+	}
+	if len(blobs.blobs) != 0 {
+		t.Errorf("元数据写入失败后仍有 %d 个对象", len(blobs.blobs))
+	}
+}
+
+// TestAttachmentService_CleanupAfterCommit 测试清理附件时文件在事务提交后才删除，回滚时保留
+func TestAttachmentService_CleanupAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	service, attachmentRepo, blobs, owned := newAttachmentFixture(&config.AttachmentConfig{})
+	tx := newTxOnlyTransactor(t)
+	if _, err := service.Upload(ctx, 1, owned.ID, "a.png", int64(len(pngHeader)), bytes.NewReader(pngHeader)); err != nil {
+		t.Fatalf("Upload() 错误 = %v", err)
+	}
+
+	rollback := stderrors.New("回滚")
+	err := tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := service.CleanupTodo(ctx, owned.ID); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if err != rollback || len(blobs.blobs) != 1 {
+		t.Fatalf("回滚后错误 = %v, 剩余对象 %d 个, 期望保留文件", err, len(blobs.blobs))
+	}
+
+	// 模拟回滚恢复的元数据
+	for key := range blobs.blobs {
+		_ = attachmentRepo.Create(ctx, &models.Attachment{TodoID: owned.ID, UserID: 1, StorageKey: key})
+	}
+	err = tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := service.CleanupTodo(ctx, owned.ID); err != nil {
+			return err
+		}
+		if len(blobs.blobs) != 1 {
+			t.Errorf("事务提交前剩余对象 %d 个, 期望 1", len(blobs.blobs))
+		}
+		return nil
+	})
+	if err != nil || len(blobs.blobs) != 0 || len(attachmentRepo.attachments) != 0 {
+		t.Errorf("提交后错误 = %v, 剩余对象 %d 个, 剩余附件 %d 个, 期望全部删除", err, len(blobs.blobs), len(attachmentRepo.attachments))
+	}
+}
@@ -12,15 +12,20 @@ import (
 
 // authService 实现认证服务接口
 type authService struct {
-	userRepo repository.UserRepository // 用户数据访问接口
-	jwtCfg   *config.JWTConfig         // 建议改为 jwtConfig
+	userRepo      repository.UserRepository      // 用户数据访问接口
+	workspaceRepo repository.WorkspaceRepository // 工作空间数据访问接口
+	tx            repository.Transactor          // 事务执行器
+	jwtCfg        *config.JWTConfig              // 建议改为 jwtConfig
 }
 
 // NewAuthService 创建认证服务实例
-func NewAuthService(userRepo repository.UserRepository, jwtCfg *config.JWTConfig) *authService {
+func NewAuthService(userRepo repository.UserRepository, workspaceRepo repository.WorkspaceRepository,
+	tx repository.Transactor, jwtCfg *config.JWTConfig) *authService {
 	return &authService{
-		userRepo: userRepo,
-		jwtCfg:   jwtCfg,
+		userRepo:      userRepo,
+		workspaceRepo: workspaceRepo,
+		tx:            tx,
+		jwtCfg:        jwtCfg,
 	}
 }
 
@@ -45,27 +50,24 @@ func (s *authService) Register(ctx context.Context, req *auth.RegisterRequest) e
 		return err
 	}
 
-	return s.userRepo.Create(ctx, user)
+	// 用户与其个人工作空间在同一事务中创建
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		return s.createPersonalWorkspace(ctx, user)
+	})
 }
 
 // Login 实现用户登录逻辑
 func (s *authService) Login(ctx context.Context, req *auth.LoginRequest) (string, *auth.UserInfo, error) {
-	// 获取用户信息
-	user, err := s.userRepo.GetByUsername(ctx, req.Username)
+	user, err := s.Authenticate(ctx, req.Username, req.Password)
 	if err != nil {
-		if err == errors.ErrUserNotFound {
-			return "", nil, errors.ErrInvalidCredentials
-		}
 		return "", nil, err
 	}
 
-	// 验证密码
-	if !user.CheckPassword(req.Password) {
-		return "", nil, errors.ErrInvalidCredentials
-	}
-
 	// 生成JWT令牌
-	token, err := utils.GenerateToken(user.ID, s.jwtCfg)
+	token, err := utils.GenerateToken(user.ID, user.DefaultWorkspaceID, s.jwtCfg)
 	if err != nil {
 		return "", nil, err
 	}
@@ -75,9 +77,70 @@ func (s *authService) Login(ctx context.Context, req *auth.LoginRequest) (string
 		ID:        user.ID,
 		Username:  user.Username,
 		Email:     user.Email,
+		WorkspaceID: user.DefaultWorkspaceID,
 		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),  // 格式化时间
 		UpdatedAt: user.UpdatedAt.Format("2006-01-02 15:04:05"),  // 格式化时间
 	}
 
 	return token, userInfo, nil
 }
+
+// Authenticate 校验用户名和密码
+func (s *authService) Authenticate(ctx context.Context, username, password string) (*models.User, error) {
+	// 获取用户信息
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if err == errors.ErrUserNotFound {
+			return nil, errors.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	// 验证密码
+	if !user.CheckPassword(password) {
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	// 已被移出默认工作空间时改回个人工作空间
+	if user.DefaultWorkspaceID != 0 {
+		_, err := s.workspaceRepo.GetMember(ctx, user.DefaultWorkspaceID, user.ID)
+		if err != nil && err != errors.ErrNotWorkspaceMember {
+			return nil, err
+		}
+		if err == errors.ErrNotWorkspaceMember {
+			if user.DefaultWorkspaceID, err = personalWorkspaceID(ctx, s.workspaceRepo, user.ID); err != nil {
+				return nil, err
+			}
+			if user.DefaultWorkspaceID != 0 {
+				if err := s.userRepo.Update(ctx, user); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// 引入工作空间之前注册的用户没有默认工作空间，登录时补建并迁移其历史数据；
+	// 个人工作空间不存在的用户同样在此补建
+	if user.DefaultWorkspaceID == 0 {
+		err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.createPersonalWorkspace(ctx, user); err != nil {
+				return err
+			}
+			return s.workspaceRepo.ClaimLegacyData(ctx, user.ID, user.DefaultWorkspaceID)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// createPersonalWorkspace 为用户创建个人工作空间并设为默认工作空间
+func (s *authService) createPersonalWorkspace(ctx context.Context, user *models.User) error {
+	workspace, err := createWorkspace(ctx, s.workspaceRepo, user.ID, user.Username+"的工作空间")
+	if err != nil {
+		return err
+	}
+	user.DefaultWorkspaceID = workspace.ID
+	return s.userRepo.Update(ctx, user)
+}
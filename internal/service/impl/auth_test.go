@@ -40,6 +40,10 @@ func (m *mockUserRepo) GetByUsername(ctx context.Context, username string) (*mod
 	return user, nil
 }
 
+func (m *mockUserRepo) GetMemberByUsername(ctx context.Context, username string) (*models.User, error) {
+	return m.GetByUsername(ctx, username)
+}
+
 func (m *mockUserRepo) GetByID(ctx context.Context, id uint) (*models.User, error) {
 	for _, user := range m.users {
 		if user.ID == id {
@@ -76,7 +80,7 @@ func TestAuthService_Register(t *testing.T) {
 		ExpireHours: 24,            // token过期时间
 		Issuer:      "test",        // 令牌签发者
 	}
-	authService := NewAuthService(userRepo, jwtCfg)
+	authService := NewAuthService(userRepo, newMockWorkspaceRepo(), nopTransactor{}, jwtCfg)
 
 	// 定义测试用例
 	tests := []struct {
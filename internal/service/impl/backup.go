@@ -0,0 +1,496 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+	"todo/api/v1/dto/backup"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/internal/tenant"
+	"todo/pkg/errors"
+	"todo/pkg/logger"
+	"todo/pkg/queue"
+	"todo/pkg/storage"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxArchiveSize     = 64 << 20           // 导入归档的最大字节数
+	exportStaleAfter   = time.Hour          // 超过该时长仍未完成的导出任务视为已中断（如服务重启），允许重新导出
+	archiveContentType = "application/json" // 归档的 MIME 类型
+)
+
+// TaskEnqueuer 后台任务队列，由 queue.TaskQueue 实现
+type TaskEnqueuer interface {
+	// AddTask 加入任务，队列已满时等待
+	AddTask(task queue.Task)
+	// TryAddTask 加入任务，队列已满时不等待并返回 false
+	TryAddTask(task queue.Task) bool
+}
+
+// BackupService 账户数据导出与导入服务实现
+// 导出在后台任务队列中生成用户在当前工作空间中的全部数据的 JSON 归档并保存到对象存储；
+// 导入在一个事务中创建归档中的全部数据，归档内的ID被重新分配
+type BackupService struct {
+	exportRepo   repository.DataExportRepository
+	userRepo     repository.UserRepository
+	todoRepo     repository.TodoRepository
+	reminderRepo repository.ReminderRepository
+	categoryRepo repository.CategoryRepository
+	commentRepo  repository.CommentRepository
+	todos        *TodoService
+	blobs        storage.BlobStore
+	tasks        TaskEnqueuer
+	tx           repository.Transactor
+}
+
+// NewBackupService 创建一个新的账户数据导出与导入服务实例
+//
+// Parameters:
+//   - exportRepo: 数据导出任务仓库实现
+//   - userRepo: 用户仓库实现，用于导出用户资料
+//   - todoRepo: 待办事项仓库实现
+//   - reminderRepo: 提醒仓库实现
+//   - categoryRepo: 分类仓库实现
+//   - commentRepo: 评论仓库实现
+//   - todos: 待办事项服务，导入时为待办事项分配工作流状态和排序位置
+//   - blobs: 对象存储，保存生成的归档
+//   - tasks: 后台任务队列，执行导出任务
+//   - tx: 事务执行器，保证导入的数据全部创建或全部不创建
+//
+// Returns:
+//   - *BackupService: 返回账户数据导出与导入服务实例
+func NewBackupService(exportRepo repository.DataExportRepository, userRepo repository.UserRepository,
+	todoRepo repository.TodoRepository, reminderRepo repository.ReminderRepository,
+	categoryRepo repository.CategoryRepository, commentRepo repository.CommentRepository, todos *TodoService,
+	blobs storage.BlobStore, tasks TaskEnqueuer, tx repository.Transactor) *BackupService {
+	return &BackupService{
+		exportRepo:   exportRepo,
+		userRepo:     userRepo,
+		todoRepo:     todoRepo,
+		reminderRepo: reminderRepo,
+		categoryRepo: categoryRepo,
+		commentRepo:  commentRepo,
+		todos:        todos,
+		blobs:        blobs,
+		tasks:        tasks,
+		tx:           tx,
+	}
+}
+
+// StartExport 创建数据导出任务并交给后台任务队列执行
+// 已有未完成且未中断的任务时直接返回该任务；否则删除上一次的导出及其归档后创建新任务
+func (s *BackupService) StartExport(ctx context.Context, userID uint) (*models.DataExport, error) {
+	wsID, ok := tenant.WorkspaceIDFromContext(ctx)
+	if !ok {
+		return nil, errors.ErrWorkspaceRequired
+	}
+
+	latest, err := s.exportRepo.GetLatest(ctx, userID)
+	switch {
+	case err == nil:
+		inProgress := latest.Status == models.DataExportPending || latest.Status == models.DataExportRunning
+		if inProgress && time.Since(latest.CreatedAt) < exportStaleAfter {
+			return latest, nil
+		}
+		if err := s.removeExport(ctx, latest); err != nil {
+			return nil, err
+		}
+	case err != errors.ErrDataExportNotFound:
+		return nil, err
+	}
+
+	export := &models.DataExport{UserID: userID, Status: models.DataExportPending}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+	s.tasks.AddTask(&exportTask{s: s, workspaceID: wsID, exportID: export.ID})
+	return export, nil
+}
+
+// GetExport 获取用户最近一次的数据导出任务
+func (s *BackupService) GetExport(ctx context.Context, userID uint) (*models.DataExport, error) {
+	return s.exportRepo.GetLatest(ctx, userID)
+}
+
+// OpenExport 打开用户最近一次生成的归档
+// 任务尚未完成或已失败时返回 ErrDataExportNotReady
+func (s *BackupService) OpenExport(ctx context.Context, userID uint) (*models.DataExport, io.ReadCloser, error) {
+	export, err := s.exportRepo.GetLatest(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if export.Status != models.DataExportDone {
+		return nil, nil, errors.ErrDataExportNotReady
+	}
+	rc, err := s.blobs.Get(ctx, export.StorageKey)
+	if err == storage.ErrBlobNotFound {
+		return nil, nil, errors.ErrDataExportNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return export, rc, nil
+}
+
+// removeExport 删除导出任务及其归档
+func (s *BackupService) removeExport(ctx context.Context, export *models.DataExport) error {
+	if export.StorageKey != "" {
+		if err := s.blobs.Delete(ctx, export.StorageKey); err != nil {
+			return err
+		}
+	}
+	return s.exportRepo.Delete(ctx, export.ID)
+}
+
+// exportTask 在后台任务队列中生成归档的任务
+type exportTask struct {
+	s           *BackupService
+	workspaceID uint
+	exportID    uint
+}
+
+// Execute 生成归档并更新导出任务的状态，失败原因记录到日志，任务中只保存概要信息
+func (t *exportTask) Execute(ctx context.Context) error {
+	s := t.s
+	ctx = tenant.WithWorkspaceID(ctx, t.workspaceID)
+	export, err := s.exportRepo.GetByID(ctx, t.exportID)
+	if err != nil {
+		// 任务在执行前被新的导出取代
+		return err
+	}
+	export.Status = models.DataExportRunning
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		return err
+	}
+
+	key, size, runErr := s.writeArchive(ctx, export.UserID)
+	now := time.Now()
+	export.FinishedAt = &now
+	if runErr != nil {
+		logger.Error().Err(runErr).Uint("user_id", export.UserID).Uint("export_id", export.ID).Msg("生成数据归档失败")
+		export.Status = models.DataExportFailed
+		export.Error = "生成归档失败，请重新导出"
+	} else {
+		export.Status = models.DataExportDone
+		export.StorageKey = key
+		export.Size = size
+	}
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		if key != "" {
+			_ = s.blobs.Delete(ctx, key)
+		}
+		return err
+	}
+	return runErr
+}
+
+// writeArchive 生成用户的归档并写入对象存储，返回对象键和大小
+func (s *BackupService) writeArchive(ctx context.Context, userID uint) (string, int64, error) {
+	archive, err := s.buildArchive(ctx, userID)
+	if err != nil {
+		return "", 0, err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(archive); err != nil {
+		return "", 0, err
+	}
+
+	key := fmt.Sprintf("exports/%d/%s.json", userID, uuid.New().String())
+	size := int64(buf.Len())
+	if err := s.blobs.Put(ctx, key, &buf, size, archiveContentType); err != nil {
+		return "", 0, err
+	}
+	return key, size, nil
+}
+
+// buildArchive 读取用户在当前工作空间中的全部数据：分类、待办事项（包括已归档的）及其标签、提醒和评论
+func (s *BackupService) buildArchive(ctx context.Context, userID uint) (*backup.Archive, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	archive := &backup.Archive{
+		Format:     backup.ArchiveFormat,
+		Version:    backup.ArchiveVersion,
+		ExportedAt: time.Now(),
+		User:       backup.User{Username: user.Username, Email: user.Email, CreatedAt: user.CreatedAt},
+		Categories: []backup.Category{},
+		Todos:      []backup.Todo{},
+		Reminders:  []backup.Reminder{},
+		Comments:   []backup.Comment{},
+	}
+
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].ID < categories[j].ID })
+	for _, c := range categories {
+		archive.Categories = append(archive.Categories, backup.Category{
+			ID: c.ID, ParentID: c.ParentID, Name: c.Name, Color: c.Color, CreatedAt: c.CreatedAt,
+		})
+	}
+
+	filter := repository.TodoFilter{Archived: repository.ArchiveInclude, Sort: repository.SortID}
+	err = s.todos.eachPage(ctx, userID, filter, func(todos []*models.Todo) error {
+		ids := make([]uint, 0, len(todos))
+		for _, todoItem := range todos {
+			ids = append(ids, todoItem.ID)
+			tags := make([]string, 0, len(todoItem.Tags))
+			for _, tag := range todoItem.Tags {
+				tags = append(tags, tag.Name)
+			}
+			archive.Todos = append(archive.Todos, backup.Todo{
+				ID:          todoItem.ID,
+				CategoryID:  todoItem.CategoryID,
+				Title:       todoItem.Title,
+				Description: todoItem.Description,
+				Priority:    string(todoItem.Priority),
+				Completed:   todoItem.Completed,
+				CompletedAt: todoItem.CompletedAt,
+				Archived:    todoItem.Archived,
+				ArchivedAt:  todoItem.ArchivedAt,
+				DueDate:     todoItem.DueDate,
+				Estimate:    todoItem.Estimate,
+				Position:    todoItem.Position,
+				Tags:        tags,
+				CreatedAt:   todoItem.CreatedAt,
+			})
+
+			comments, err := s.commentRepo.ListByTodoID(ctx, todoItem.ID, 0)
+			if err != nil {
+				return err
+			}
+			// 评论按时间倒序返回，归档中按发表顺序排列
+			for i := len(comments) - 1; i >= 0; i-- {
+				c := comments[i]
+				author := ""
+				if c.User != nil {
+					author = c.User.Username
+				}
+				archive.Comments = append(archive.Comments, backup.Comment{
+					TodoID: c.TodoID, Author: author, Body: c.Body, CreatedAt: c.CreatedAt, EditedAt: c.EditedAt,
+				})
+			}
+		}
+
+		reminders, err := s.reminderRepo.ListByTodoIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for _, r := range reminders {
+			archive.Reminders = append(archive.Reminders, backup.Reminder{
+				TodoID: r.TodoID, RemindAt: r.RemindAt, RemindType: r.RemindType, NotifyType: r.NotifyType, Status: r.Status,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// Import 从 r 中读取归档并导入到用户在当前工作空间中的账户
+// 只能导入到没有分类和待办事项的空账户，避免重复导入产生重复数据；归档在创建任何数据之前完整校验，
+// 所有数据在一个事务中创建。归档内的ID被重新分配，关联关系保持不变；评论的作者均为导入的用户，
+// 导入的数据不记录变更历史
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID
+//   - r: 归档内容
+//
+// Returns:
+//   - *backup.ImportResponse: 创建的各类数据的数量
+//   - error: 归档无效时返回 ErrInvalidArchive，账户不为空时返回 ErrAccountNotEmpty
+func (s *BackupService) Import(ctx context.Context, userID uint, r io.Reader) (*backup.ImportResponse, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxArchiveSize+1))
+	if err != nil {
+		return nil, err
+	}
+	var archive backup.Archive
+	if len(data) > maxArchiveSize || json.Unmarshal(data, &archive) != nil {
+		return nil, errors.ErrInvalidArchive
+	}
+	categories, err := validateArchive(&archive)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.todoRepo.CountByUserID(ctx, userID, repository.TodoFilter{Archived: repository.ArchiveInclude})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 || count > 0 {
+		return nil, errors.ErrAccountNotEmpty
+	}
+
+	resp := &backup.ImportResponse{}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		categoryIDs := make(map[uint]uint, len(categories))
+		for _, c := range categories {
+			category := &models.Category{Name: c.Name, Color: c.Color, UserID: userID}
+			category.CreatedAt = c.CreatedAt
+			if c.ParentID != nil {
+				parentID := categoryIDs[*c.ParentID]
+				category.ParentID = &parentID
+			}
+			if err := s.categoryRepo.Create(ctx, category); err != nil {
+				return err
+			}
+			categoryIDs[c.ID] = category.ID
+		}
+
+		todoIDs := make(map[uint]uint, len(archive.Todos))
+		for _, t := range archive.Todos {
+			todoItem := &models.Todo{
+				Title:       t.Title,
+				Description: t.Description,
+				Priority:    models.Priority(t.Priority),
+				Completed:   t.Completed,
+				CompletedAt: t.CompletedAt,
+				Archived:    t.Archived,
+				ArchivedAt:  t.ArchivedAt,
+				DueDate:     t.DueDate,
+				Estimate:    t.Estimate,
+				Position:    t.Position,
+				UserID:      userID,
+			}
+			todoItem.CreatedAt = t.CreatedAt
+			if todoItem.Priority == "" {
+				todoItem.Priority = models.PriorityMedium
+			}
+			if t.CategoryID != nil {
+				categoryID := categoryIDs[*t.CategoryID]
+				todoItem.CategoryID = &categoryID
+			}
+			// 已完成的待办事项不放入工作流状态，未完成的使用用户的第一个非终止状态
+			if !todoItem.Completed {
+				if err := s.todos.initialStatus(ctx, todoItem, nil); err != nil {
+					return err
+				}
+			}
+			if todoItem.Position == "" {
+				if err := s.todos.appendPosition(ctx, todoItem); err != nil {
+					return err
+				}
+			}
+			if err := s.todoRepo.Create(ctx, todoItem); err != nil {
+				return err
+			}
+			if tags := normalizeTags(t.Tags); len(tags) > 0 {
+				if err := s.todoRepo.SetTags(ctx, todoItem, tags); err != nil {
+					return err
+				}
+			}
+			todoIDs[t.ID] = todoItem.ID
+		}
+
+		for _, r := range archive.Reminders {
+			reminder := &models.Reminder{
+				TodoID: todoIDs[r.TodoID], RemindAt: r.RemindAt, RemindType: r.RemindType, NotifyType: r.NotifyType, Status: r.Status,
+			}
+			if err := s.reminderRepo.Create(ctx, reminder); err != nil {
+				return err
+			}
+		}
+
+		for _, c := range archive.Comments {
+			comment := &models.Comment{TodoID: todoIDs[c.TodoID], UserID: userID, Body: c.Body, EditedAt: c.EditedAt}
+			comment.CreatedAt = c.CreatedAt
+			if err := s.commentRepo.Create(ctx, comment); err != nil {
+				return err
+			}
+		}
+
+		resp.Categories = len(categories)
+		resp.Todos = len(archive.Todos)
+		resp.Reminders = len(archive.Reminders)
+		resp.Comments = len(archive.Comments)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// validateArchive 校验归档的格式版本、字段限制和归档内的引用
+// 返回按上级分类在前排列的分类，归档无效时返回 ErrInvalidArchive
+func validateArchive(archive *backup.Archive) ([]backup.Category, error) {
+	if archive.Format != backup.ArchiveFormat || archive.Version < 1 || archive.Version > backup.ArchiveVersion {
+		return nil, errors.ErrInvalidArchive
+	}
+
+	categoryIDs := make(map[uint]bool, len(archive.Categories))
+	for _, c := range archive.Categories {
+		if c.ID == 0 || categoryIDs[c.ID] || c.Name == "" ||
+			utf8.RuneCountInString(c.Name) > 32 || utf8.RuneCountInString(c.Color) > 7 {
+			return nil, errors.ErrInvalidArchive
+		}
+		categoryIDs[c.ID] = true
+	}
+	// 反复挑出上级分类已排好的分类，无法继续时说明上级分类不存在或形成了环
+	ordered := make([]backup.Category, 0, len(archive.Categories))
+	placed := make(map[uint]bool, len(archive.Categories))
+	for len(ordered) < len(archive.Categories) {
+		progressed := false
+		for _, c := range archive.Categories {
+			if placed[c.ID] || (c.ParentID != nil && !placed[*c.ParentID]) {
+				continue
+			}
+			ordered = append(ordered, c)
+			placed[c.ID] = true
+			progressed = true
+		}
+		if !progressed {
+			return nil, errors.ErrInvalidArchive
+		}
+	}
+
+	todoIDs := make(map[uint]bool, len(archive.Todos))
+	for _, t := range archive.Todos {
+		item := importedTodo{create: todo.CreateRequest{Title: t.Title, Description: t.Description, Tags: normalizeTags(t.Tags)}}
+		switch {
+		case t.ID == 0 || todoIDs[t.ID] || item.validate() != nil || len(t.Position) > 64:
+			return nil, errors.ErrInvalidArchive
+		case t.CategoryID != nil && !categoryIDs[*t.CategoryID]:
+			return nil, errors.ErrInvalidArchive
+		}
+		switch models.Priority(t.Priority) {
+		case "", models.PriorityLow, models.PriorityMedium, models.PriorityHigh:
+		default:
+			return nil, errors.ErrInvalidArchive
+		}
+		todoIDs[t.ID] = true
+	}
+
+	for _, r := range archive.Reminders {
+		reminder := models.Reminder{RemindType: r.RemindType, NotifyType: r.NotifyType}
+		if !todoIDs[r.TodoID] || r.RemindAt.IsZero() || reminder.Validate() != nil {
+			return nil, errors.ErrInvalidArchive
+		}
+	}
+	for _, c := range archive.Comments {
+		if !todoIDs[c.TodoID] || c.Body == "" || utf8.RuneCountInString(c.Body) > 10000 {
+			return nil, errors.ErrInvalidArchive
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,270 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"todo/api/v1/dto/backup"
+	"todo/internal/models"
+	"todo/internal/tenant"
+	"todo/pkg/errors"
+	"todo/pkg/queue"
+	"todo/pkg/storage"
+)
+
+// mockDataExportRepo 模拟数据导出任务仓储接口
+type mockDataExportRepo struct {
+	exports map[uint]*models.DataExport
+	seq     uint
+}
+
+func newMockDataExportRepo() *mockDataExportRepo {
+	return &mockDataExportRepo{exports: make(map[uint]*models.DataExport), seq: 1}
+}
+
+func (m *mockDataExportRepo) Create(ctx context.Context, export *models.DataExport) error {
+	export.ID = m.seq
+	export.CreatedAt = time.Now()
+	m.seq++
+	stored := *export
+	m.exports[export.ID] = &stored
+	return nil
+}
+
+func (m *mockDataExportRepo) GetByID(ctx context.Context, id uint) (*models.DataExport, error) {
+	export, exists := m.exports[id]
+	if !exists {
+		return nil, errors.ErrDataExportNotFound
+	}
+	copied := *export
+	return &copied, nil
+}
+
+func (m *mockDataExportRepo) GetLatest(ctx context.Context, userID uint) (*models.DataExport, error) {
+	var latest *models.DataExport
+	for _, export := range m.exports {
+		if export.UserID == userID && (latest == nil || export.ID > latest.ID) {
+			latest = export
+		}
+	}
+	if latest == nil {
+		return nil, errors.ErrDataExportNotFound
+	}
+	copied := *latest
+	return &copied, nil
+}
+
+func (m *mockDataExportRepo) Update(ctx context.Context, export *models.DataExport) error {
+	if _, exists := m.exports[export.ID]; exists {
+		stored := *export
+		m.exports[export.ID] = &stored
+	}
+	return nil
+}
+
+func (m *mockDataExportRepo) Delete(ctx context.Context, id uint) error {
+	delete(m.exports, id)
+	return nil
+}
+
+// pendingTasks 记录加入队列的任务，由测试决定何时执行
+type pendingTasks struct {
+	tasks []queue.Task
+	limit int // 队列容量，为 0 时不限制
+}
+
+func (p *pendingTasks) AddTask(task queue.Task) {
+	p.tasks = append(p.tasks, task)
+}
+
+func (p *pendingTasks) TryAddTask(task queue.Task) bool {
+	if p.limit > 0 && len(p.tasks) >= p.limit {
+		return false
+	}
+	p.tasks = append(p.tasks, task)
+	return true
+}
+
+// TestBackupService_ExportImport 测试异步导出的归档可以导入到另一个账户，并保持分类层级、标签、提醒和评论的关联
+func TestBackupService_ExportImport(t *testing.T) {
+	ctx := tenant.WithWorkspaceID(context.Background(), 1)
+	userRepo := newMockUserRepo()
+	userRepo.Create(ctx, &models.User{Base: models.Base{ID: 1}, Username: "alice", Email: "alice@example.com"})
+	todoRepo := newMockTodoRepo()
+	reminderRepo := newMockReminderRepo()
+	categoryRepo := newMockCategoryRepo()
+	commentRepo := newMockCommentRepo()
+	blobs, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore() 错误 = %v", err)
+	}
+	tasks := &pendingTasks{}
+	todos := NewTodoService(todoRepo, reminderRepo, categoryRepo, newMockStatusRepo(), newMockDependencyRepo(),
+		newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+	service := NewBackupService(newMockDataExportRepo(), userRepo, todoRepo, reminderRepo, categoryRepo, commentRepo,
+		todos, blobs, tasks, nopTransactor{})
+
+	work := &models.Category{Name: "Work", UserID: 1}
+	categoryRepo.Create(ctx, work)
+	reports := &models.Category{Name: "Reports", UserID: 1, ParentID: &work.ID}
+	categoryRepo.Create(ctx, reports)
+	due := time.Date(2024, 6, 7, 10, 0, 0, 0, time.UTC)
+	report := &models.Todo{Title: "写周报", UserID: 1, Priority: models.PriorityHigh, DueDate: &due, CategoryID: &reports.ID,
+		Position: "m", Tags: []models.Tag{{Name: "例行"}}}
+	todoRepo.Create(ctx, report)
+	done := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	todoRepo.Create(ctx, &models.Todo{Title: "旧任务", UserID: 1, Priority: models.PriorityLow, Completed: true,
+		CompletedAt: &done, Archived: true, ArchivedAt: &done})
+	todoRepo.Create(ctx, &models.Todo{Title: "别人的", UserID: 3})
+	reminderRepo.Create(ctx, &models.Reminder{TodoID: report.ID, RemindAt: due.Add(-time.Hour),
+		RemindType: models.RemindTypeOnceStr, NotifyType: models.NotifyTypeEmailStr})
+	commentRepo.Create(ctx, &models.Comment{TodoID: report.ID, UserID: 2, Body: "第一条", User: &models.User{Username: "bob"}})
+	commentRepo.Create(ctx, &models.Comment{TodoID: report.ID, UserID: 1, Body: "第二条", User: &models.User{Username: "alice"}})
+
+	export, err := service.StartExport(ctx, 1)
+	if err != nil {
+		t.Fatalf("StartExport() 错误 = %v", err)
+	}
+	if export.Status != models.DataExportPending || len(tasks.tasks) != 1 {
+		t.Fatalf("导出状态 = %s, 队列中 %d 个任务, 期望等待执行的 1 个任务", export.Status, len(tasks.tasks))
+	}
+	if again, err := service.StartExport(ctx, 1); err != nil || again.ID != export.ID || len(tasks.tasks) != 1 {
+		t.Fatalf("重复导出返回 %+v, %v, 期望返回未完成的导出且不重复加入队列", again, err)
+	}
+	if _, _, err := service.OpenExport(ctx, 1); err != errors.ErrDataExportNotReady {
+		t.Fatalf("完成前 OpenExport() 错误 = %v, 期望 %v", err, errors.ErrDataExportNotReady)
+	}
+
+	// 任务在队列的上下文中执行，不依赖请求上下文中的工作空间
+	if err := tasks.tasks[0].Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() 错误 = %v", err)
+	}
+	finished, err := service.GetExport(ctx, 1)
+	if err != nil || finished.Status != models.DataExportDone || finished.Size == 0 || finished.FinishedAt == nil {
+		t.Fatalf("GetExport() = %+v, %v, 期望已完成", finished, err)
+	}
+
+	_, rc, err := service.OpenExport(ctx, 1)
+	if err != nil {
+		t.Fatalf("OpenExport() 错误 = %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	var archive backup.Archive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		t.Fatalf("归档不是有效的 JSON: %v", err)
+	}
+	if archive.Format != backup.ArchiveFormat || archive.Version != backup.ArchiveVersion || archive.User.Username != "alice" ||
+		len(archive.Categories) != 2 || len(archive.Todos) != 2 || len(archive.Reminders) != 1 || len(archive.Comments) != 2 {
+		t.Fatalf("归档内容 = %s", data)
+	}
+	if archive.Comments[0].Author != "bob" || archive.Comments[1].Body != "第二条" {
+		t.Errorf("评论 = %+v, 期望按发表顺序排列并保留作者", archive.Comments)
+	}
+	if strings.Contains(string(data), "password") {
+		t.Errorf("归档中不应包含密码")
+	}
+
+	result, err := service.Import(ctx, 2, strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("Import() 错误 = %v", err)
+	}
+	if *result != (backup.ImportResponse{Categories: 2, Todos: 2, Reminders: 1, Comments: 2}) {
+		t.Fatalf("导入结果 = %+v", result)
+	}
+
+	imported := make(map[string]*models.Todo)
+	for _, item := range todoRepo.todos {
+		if item.UserID == 2 {
+			imported[item.Title] = item
+		}
+	}
+	copied, old := imported["写周报"], imported["旧任务"]
+	if copied == nil || old == nil || copied.ID == report.ID {
+		t.Fatalf("导入的待办事项 = %v", imported)
+	}
+	category := categoryRepo.categories[*copied.CategoryID]
+	parent := categoryRepo.categories[*category.ParentID]
+	if category.Name != "Reports" || category.UserID != 2 || parent.Name != "Work" || parent.UserID != 2 {
+		t.Errorf("导入的分类 = %+v, 上级 = %+v, 期望 Work/Reports 且属于导入的用户", category, parent)
+	}
+	if copied.Priority != models.PriorityHigh || !copied.DueDate.Equal(due) || copied.Position != "m" ||
+		len(copied.Tags) != 1 || copied.Tags[0].Name != "例行" {
+		t.Errorf("导入的待办事项 = %+v", copied)
+	}
+	if !old.Completed || !old.Archived || !old.CompletedAt.Equal(done) || old.CategoryID != nil {
+		t.Errorf("导入的已归档待办事项 = %+v", old)
+	}
+	if reminders, _ := reminderRepo.ListByTodoID(ctx, copied.ID); len(reminders) != 1 || !reminders[0].RemindAt.Equal(due.Add(-time.Hour)) {
+		t.Errorf("导入的提醒 = %+v", reminders)
+	}
+	if comments, _ := commentRepo.ListByTodoID(ctx, copied.ID, 0); len(comments) != 2 || comments[0].UserID != 2 || comments[0].Body != "第二条" {
+		t.Errorf("导入的评论 = %+v, 期望 2 条且作者为导入的用户", comments)
+	}
+
+	if _, err := service.Import(ctx, 2, strings.NewReader(string(data))); err != errors.ErrAccountNotEmpty {
+		t.Errorf("重复导入错误 = %v, 期望 %v", err, errors.ErrAccountNotEmpty)
+	}
+
+	// 再次导出时删除上一次的归档
+	if _, err := service.StartExport(ctx, 1); err != nil {
+		t.Fatalf("再次 StartExport() 错误 = %v", err)
+	}
+	if _, err := blobs.Get(ctx, finished.StorageKey); err != storage.ErrBlobNotFound {
+		t.Errorf("上一次的归档 Get() 错误 = %v, 期望 %v", err, storage.ErrBlobNotFound)
+	}
+}
+
+// TestValidateArchive 测试导入前对归档格式和内部引用的校验
+func TestValidateArchive(t *testing.T) {
+	id := func(v uint) *uint { return &v }
+	valid := func() *backup.Archive {
+		return &backup.Archive{
+			Format:  backup.ArchiveFormat,
+			Version: backup.ArchiveVersion,
+			Categories: []backup.Category{
+				{ID: 7, ParentID: id(5), Name: "子项目"},
+				{ID: 5, Name: "项目"},
+			},
+			Todos:     []backup.Todo{{ID: 1, CategoryID: id(7), Title: "任务", Priority: "high"}},
+			Reminders: []backup.Reminder{{TodoID: 1, RemindAt: time.Now(), RemindType: "once", NotifyType: "push"}},
+			Comments:  []backup.Comment{{TodoID: 1, Body: "评论"}},
+		}
+	}
+
+	ordered, err := validateArchive(valid())
+	if err != nil {
+		t.Fatalf("validateArchive() 错误 = %v", err)
+	}
+	if len(ordered) != 2 || ordered[0].ID != 5 || ordered[1].ID != 7 {
+		t.Errorf("分类顺序 = %+v, 期望上级分类在前", ordered)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(a *backup.Archive)
+	}{
+		{name: "格式标识不对", modify: func(a *backup.Archive) { a.Format = "other" }},
+		{name: "更高的版本", modify: func(a *backup.Archive) { a.Version = backup.ArchiveVersion + 1 }},
+		{name: "分类形成环", modify: func(a *backup.Archive) { a.Categories[1].ParentID = id(7) }},
+		{name: "上级分类不存在", modify: func(a *backup.Archive) { a.Categories[0].ParentID = id(9) }},
+		{name: "重复的待办事项ID", modify: func(a *backup.Archive) { a.Todos = append(a.Todos, a.Todos[0]) }},
+		{name: "待办事项的分类不存在", modify: func(a *backup.Archive) { a.Todos[0].CategoryID = id(9) }},
+		{name: "标题为空", modify: func(a *backup.Archive) { a.Todos[0].Title = "" }},
+		{name: "无效的优先级", modify: func(a *backup.Archive) { a.Todos[0].Priority = "urgent" }},
+		{name: "无效的提醒类型", modify: func(a *backup.Archive) { a.Reminders[0].RemindType = "hourly" }},
+		{name: "评论的待办事项不存在", modify: func(a *backup.Archive) { a.Comments[0].TodoID = 2 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := valid()
+			tt.modify(archive)
+			if _, err := validateArchive(archive); err != errors.ErrInvalidArchive {
+				t.Errorf("validateArchive() 错误 = %v, 期望 %v", err, errors.ErrInvalidArchive)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+)
+
+// maxTodoTags 每个待办事项最多的标签数量，与创建和更新接口的校验一致
+const maxTodoTags = 20
+
+// Bulk 对一组待办事项执行同一操作
+// 所有待办事项在同一事务中处理并逐个校验所有权；任一失败时整体回滚，
+// 返回每个待办事项的处理结果以及 ErrBulkFailed
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID
+//   - req: 选择待办事项的ID列表或过滤条件，以及操作类型和参数
+//
+// Returns:
+//   - *todo.BulkResponse: 每个待办事项的处理结果
+//   - error: 参数无效、选择的待办事项过多或有待办事项处理失败时返回错误
+func (s *TodoService) Bulk(ctx context.Context, userID uint, req *todo.BulkRequest) (*todo.BulkResponse, error) {
+	if err := s.validateBulk(ctx, userID, req); err != nil {
+		return nil, err
+	}
+
+	ids, err := s.bulkTargets(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &todo.BulkResponse{Action: req.Action, Items: make([]*todo.BulkItemResult, 0, len(ids))}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, id := range ids {
+			item := &todo.BulkItemResult{ID: id, Success: true}
+			if err := s.bulkApply(ctx, userID, id, req); err != nil {
+				item.Success, item.Error = false, err.Error()
+				resp.Failed++
+			} else {
+				resp.Succeeded++
+			}
+			resp.Items = append(resp.Items, item)
+		}
+		if resp.Failed > 0 {
+			return errors.ErrBulkFailed
+		}
+		return nil
+	})
+	if err != nil && err != errors.ErrBulkFailed {
+		return nil, err
+	}
+	resp.Applied = err == nil
+	return resp, err
+}
+
+// validateBulk 校验批量操作的选择方式和操作参数
+func (s *TodoService) validateBulk(ctx context.Context, userID uint, req *todo.BulkRequest) error {
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		return errors.ErrInvalidParameter
+	}
+
+	switch req.Action {
+	case todo.BulkSetPriority:
+		if req.Priority == "" {
+			return errors.ErrInvalidParameter
+		}
+	case todo.BulkAddTags, todo.BulkRemoveTags:
+		if len(normalizeTags(req.Tags)) == 0 {
+			return errors.ErrInvalidParameter
+		}
+	case todo.BulkMoveCategory:
+		if req.CategoryID != nil {
+			category, err := s.categoryRepo.GetByID(ctx, *req.CategoryID)
+			if err != nil {
+				return err
+			}
+			if category.UserID != userID {
+				return errors.ErrForbidden
+			}
+		}
+	}
+	return nil
+}
+
+// bulkTargets 返回批量操作的待办事项ID，ID列表去重后保持原有顺序
+func (s *TodoService) bulkTargets(ctx context.Context, userID uint, req *todo.BulkRequest) ([]uint, error) {
+	if req.Filter == nil {
+		seen := make(map[uint]bool, len(req.IDs))
+		ids := make([]uint, 0, len(req.IDs))
+		for _, id := range req.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	filter, err := buildTodoFilter(ctx, s.categoryRepo, userID, definitionRequest(*req.Filter))
+	if err != nil {
+		return nil, err
+	}
+	todos, total, err := s.todoRepo.ListByUserID(ctx, userID, filter, 1, todo.BulkLimit)
+	if err != nil {
+		return nil, err
+	}
+	if total > todo.BulkLimit {
+		return nil, errors.ErrBulkTooMany
+	}
+	ids := make([]uint, 0, len(todos))
+	for _, t := range todos {
+		ids = append(ids, t.ID)
+	}
+	return ids, nil
+}
+
+// bulkApply 对单个待办事项执行批量操作，需在事务中调用
+func (s *TodoService) bulkApply(ctx context.Context, userID, id uint, req *todo.BulkRequest) error {
+	switch req.Action {
+	case todo.BulkComplete, todo.BulkUncomplete:
+		completed := req.Action == todo.BulkComplete
+		return s.Update(ctx, id, userID, &todo.UpdateRequest{Completed: &completed})
+	case todo.BulkSetPriority:
+		return s.Update(ctx, id, userID, &todo.UpdateRequest{Priority: &req.Priority})
+	case todo.BulkDelete:
+		return s.Delete(ctx, id, userID)
+	}
+
+	todoItem, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	switch req.Action {
+	case todo.BulkMoveCategory:
+		before := *todoItem
+		todoItem.CategoryID = req.CategoryID
+		if !sameID(before.CategoryID, todoItem.CategoryID) {
+			if err := s.appendPosition(ctx, todoItem); err != nil {
+				return err
+			}
+		}
+		return s.saveWithHistory(ctx, userID, models.ChangeActionUpdate, &before, todoItem)
+	case todo.BulkAddTags, todo.BulkRemoveTags:
+		tags := bulkTags(todoItem.Tags, req.Action, normalizeTags(req.Tags))
+		if len(tags) > maxTodoTags {
+			return errors.ErrTooManyTags
+		}
+		return s.todoRepo.SetTags(ctx, todoItem, tags)
+	}
+	return errors.ErrInvalidParameter
+}
+
+// bulkTags 计算添加或移除标签后的标签名列表，标签名比较不区分大小写
+func bulkTags(current []models.Tag, action string, tags []string) []string {
+	names := make([]string, 0, len(current)+len(tags))
+	for _, tag := range current {
+		names = append(names, tag.Name)
+	}
+	if action == todo.BulkAddTags {
+		return normalizeTags(append(names, tags...))
+	}
+
+	removed := make(map[string]bool, len(tags))
+	for _, name := range tags {
+		removed[strings.ToLower(name)] = true
+	}
+	kept := names[:0]
+	for _, name := range names {
+		if !removed[strings.ToLower(name)] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,97 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+)
+
+// TestTodoService_Bulk 测试批量操作的选择方式、所有权校验和逐项结果
+func TestTodoService_Bulk(t *testing.T) {
+	ctx := context.Background()
+	service := NewTodoService(newMockTodoRepo(), newMockReminderRepo(), newMockCategoryRepo(), newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+
+	a, _ := service.Create(ctx, 1, &todo.CreateRequest{Title: "A", Tags: []string{"release"}})
+	b, _ := service.Create(ctx, 1, &todo.CreateRequest{Title: "B", Priority: "low"})
+	other, _ := service.Create(ctx, 2, &todo.CreateRequest{Title: "他人的待办"})
+
+	t.Run("按ID完成", func(t *testing.T) {
+		resp, err := service.Bulk(ctx, 1, &todo.BulkRequest{IDs: []uint{a, b, a}, Action: todo.BulkComplete})
+		if err != nil || !resp.Applied || resp.Succeeded != 2 || len(resp.Items) != 2 {
+			t.Fatalf("Bulk() = %+v, %v, 期望两项成功", resp, err)
+		}
+		got, _ := service.Get(ctx, b, 1)
+		if !got.Completed || got.CompletedAt == nil {
+			t.Errorf("待办事项 %d 应已完成", b)
+		}
+	})
+
+	t.Run("包含他人的待办事项", func(t *testing.T) {
+		resp, err := service.Bulk(ctx, 1, &todo.BulkRequest{IDs: []uint{a, other, 99}, Action: todo.BulkSetPriority, Priority: "high"})
+		if err != errors.ErrBulkFailed {
+			t.Fatalf("Bulk() 错误 = %v, 期望 %v", err, errors.ErrBulkFailed)
+		}
+		if resp.Applied || resp.Succeeded != 1 || resp.Failed != 2 {
+			t.Errorf("Bulk() = %+v, 期望一项成功两项失败且未提交", resp)
+		}
+		if resp.Items[1].Error != errors.ErrForbidden.Error() || resp.Items[2].Error != errors.ErrTodoNotFound.Error() {
+			t.Errorf("失败原因 = %q, %q", resp.Items[1].Error, resp.Items[2].Error)
+		}
+	})
+
+	t.Run("按过滤条件处理标签", func(t *testing.T) {
+		resp, err := service.Bulk(ctx, 1, &todo.BulkRequest{
+			Filter: &models.FilterDefinition{Priority: "low"},
+			Action: todo.BulkAddTags,
+			Tags:   []string{"bug", "Release"},
+		})
+		if err != nil || resp.Succeeded != 1 || resp.Items[0].ID != b {
+			t.Fatalf("Bulk() = %+v, %v, 期望只处理待办事项 %d", resp, err, b)
+		}
+
+		if _, err := service.Bulk(ctx, 1, &todo.BulkRequest{IDs: []uint{a, b}, Action: todo.BulkRemoveTags, Tags: []string{"RELEASE"}}); err != nil {
+			t.Fatalf("Bulk() 错误 = %v", err)
+		}
+		gotA, _ := service.Get(ctx, a, 1)
+		gotB, _ := service.Get(ctx, b, 1)
+		if len(gotA.Tags) != 0 || len(gotB.Tags) != 1 || gotB.Tags[0].Name != "bug" {
+			t.Errorf("标签 = %v, %v, 期望 [] 和 [bug]", gotA.Tags, gotB.Tags)
+		}
+	})
+
+	t.Run("标签数量超过上限", func(t *testing.T) {
+		full := make([]string, maxTodoTags)
+		for i := range full {
+			full[i] = fmt.Sprintf("tag%d", i)
+		}
+		c, _ := service.Create(ctx, 1, &todo.CreateRequest{Title: "C", Tags: full[:maxTodoTags-1]})
+		resp, err := service.Bulk(ctx, 1, &todo.BulkRequest{IDs: []uint{a, c}, Action: todo.BulkAddTags, Tags: []string{"extra1", "extra2"}})
+		if err != errors.ErrBulkFailed {
+			t.Fatalf("Bulk() 错误 = %v, 期望 %v", err, errors.ErrBulkFailed)
+		}
+		if !resp.Items[0].Success || resp.Items[1].Success || resp.Items[1].Error != errors.ErrTooManyTags.Error() {
+			t.Errorf("逐项结果 = %+v, %+v, 期望只有标签已满的待办事项失败", resp.Items[0], resp.Items[1])
+		}
+	})
+
+	t.Run("无效请求", func(t *testing.T) {
+		invalid := []*todo.BulkRequest{
+			{Action: todo.BulkDelete},
+			{IDs: []uint{a}, Filter: &models.FilterDefinition{}, Action: todo.BulkDelete},
+			{IDs: []uint{a}, Action: todo.BulkSetPriority},
+			{IDs: []uint{a}, Action: todo.BulkAddTags, Tags: []string{" "}},
+		}
+		for _, req := range invalid {
+			if _, err := service.Bulk(ctx, 1, req); err != errors.ErrInvalidParameter {
+				t.Errorf("Bulk(%+v) 错误 = %v, 期望 %v", req, err, errors.ErrInvalidParameter)
+			}
+		}
+		missing := uint(99)
+		if _, err := service.Bulk(ctx, 1, &todo.BulkRequest{IDs: []uint{a}, Action: todo.BulkMoveCategory, CategoryID: &missing}); err != errors.ErrCategoryNotFound {
+			t.Errorf("Bulk() 错误 = %v, 期望 %v", err, errors.ErrCategoryNotFound)
+		}
+	})
+}
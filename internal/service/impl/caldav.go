@@ -0,0 +1,479 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"todo/api/v1/dto/caldav"
+	"todo/api/v1/dto/reminder"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/errors"
+	"todo/pkg/ical"
+	"unicode/utf8"
+)
+
+const (
+	uncategorizedCollection = "uncategorized" // 未分类待办事项组成的集合名称
+	uncategorizedName       = "未分类"           // 未分类集合的显示名称
+	calendarObjectFmt       = "todo-%d.ics"   // 不是通过 CalDAV 创建的待办事项的资源名
+	maxCalendarObjectName   = 255             // 资源名的最大长度
+)
+
+// CalDAVService CalDAV 同步服务实现
+// 客户端的修改通过 TodoService 和 ReminderService 写入，与 REST 接口一样记录变更历史并校验阻塞关系和在制品上限。
+// 客户端上传的 UID 和资源名保存在待办事项上，之后按原样返回；iCalendar 中没有对应字段的属性（例如 RRULE）不会保存
+type CalDAVService struct {
+	todoRepo     repository.TodoRepository
+	reminderRepo repository.ReminderRepository
+	categoryRepo repository.CategoryRepository
+	todos        *TodoService
+	reminders    *ReminderService
+	tx           repository.Transactor
+}
+
+// NewCalDAVService 创建一个新的 CalDAV 同步服务实例
+//
+// Parameters:
+//   - todoRepo: 待办事项仓库实现
+//   - reminderRepo: 提醒仓库实现
+//   - categoryRepo: 分类仓库实现，每个分类对应一个日历集合
+//   - todos: 待办事项服务，用于创建、更新和删除待办事项
+//   - reminders: 提醒服务，用于同步 VALARM
+//   - tx: 事务执行器
+//
+// Returns:
+//   - *CalDAVService: 返回 CalDAV 同步服务实例
+func NewCalDAVService(todoRepo repository.TodoRepository, reminderRepo repository.ReminderRepository,
+	categoryRepo repository.CategoryRepository, todos *TodoService, reminders *ReminderService,
+	tx repository.Transactor) *CalDAVService {
+	return &CalDAVService{
+		todoRepo:     todoRepo,
+		reminderRepo: reminderRepo,
+		categoryRepo: categoryRepo,
+		todos:        todos,
+		reminders:    reminders,
+		tx:           tx,
+	}
+}
+
+// Collections 获取用户的所有日历集合，未分类集合排在最前
+func (s *CalDAVService) Collections(ctx context.Context, userID uint) ([]*caldav.Collection, error) {
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := make([]*caldav.Collection, 0, len(categories)+1)
+	uncategorized, err := s.describe(ctx, userID, uncategorizedCollection, nil, uncategorizedName)
+	if err != nil {
+		return nil, err
+	}
+	collections = append(collections, uncategorized)
+	for _, c := range categories {
+		collection, err := s.describe(ctx, userID, strconv.FormatUint(uint64(c.ID), 10), &c.ID, c.Name)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+	return collections, nil
+}
+
+// Collection 获取日历集合
+func (s *CalDAVService) Collection(ctx context.Context, userID uint, name string) (*caldav.Collection, error) {
+	categoryID, displayName, err := s.resolve(ctx, userID, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.describe(ctx, userID, name, categoryID, displayName)
+}
+
+// Objects 获取日历集合中的所有对象
+func (s *CalDAVService) Objects(ctx context.Context, userID uint, collection string) ([]*caldav.Object, error) {
+	categoryID, displayName, err := s.resolve(ctx, userID, collection)
+	if err != nil {
+		return nil, err
+	}
+	return s.objects(ctx, userID, categoryID, displayName)
+}
+
+// Object 获取日历对象
+func (s *CalDAVService) Object(ctx context.Context, userID uint, collection, name string) (*caldav.Object, error) {
+	categoryID, displayName, err := s.resolve(ctx, userID, collection)
+	if err != nil {
+		return nil, err
+	}
+	todoItem, err := s.find(ctx, userID, categoryID, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.object(ctx, todoItem, displayName)
+}
+
+// Put 创建或更新日历对象
+// 资源名不存在时在集合对应的分类中创建待办事项，存在时整体更新标题、描述、截止时间、优先级、完成状态和标签，
+// 并使提醒与 VALARM 一致。CATEGORIES 中与集合同名的值不作为标签保存
+//
+// Returns:
+//   - *caldav.Object: 写入后的对象
+//   - bool: 是否新建
+//   - error: 数据无效时返回 ErrInvalidICalendar，前置条件不满足时返回 ErrPreconditionFailed
+func (s *CalDAVService) Put(ctx context.Context, userID uint, req *caldav.PutRequest) (*caldav.Object, bool, error) {
+	categoryID, displayName, err := s.resolve(ctx, userID, req.Collection)
+	if err != nil {
+		return nil, false, err
+	}
+	if !strings.HasSuffix(req.Name, ".ics") || len(req.Name) > maxCalendarObjectName {
+		return nil, false, errors.ErrInvalidParameter
+	}
+	parsed, err := ical.ParseTodo(bytes.NewReader(req.Data))
+	if err == ical.ErrInvalid || err == ical.ErrNoTodo {
+		return nil, false, errors.ErrInvalidICalendar
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	update, err := updateFromICal(parsed, displayName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var created bool
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.find(ctx, userID, categoryID, req.Name)
+		if err != nil && err != errors.ErrTodoNotFound {
+			return err
+		}
+		if err := s.checkPreconditions(ctx, existing, displayName, req.IfMatch, req.IfNoneMatch); err != nil {
+			return err
+		}
+
+		var id uint
+		if existing != nil {
+			id = existing.ID
+			if err := s.todos.Update(ctx, id, userID, update); err != nil {
+				return err
+			}
+		} else {
+			if id, err = s.create(ctx, userID, categoryID, req.Name, parsed.UID, update); err != nil {
+				return err
+			}
+			created = true
+		}
+		return s.syncAlarms(ctx, userID, id, parsed.Alarms)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	todoItem, err := s.find(ctx, userID, categoryID, req.Name)
+	if err != nil {
+		return nil, false, err
+	}
+	object, err := s.object(ctx, todoItem, displayName)
+	return object, created, err
+}
+
+// Delete 删除日历对象
+func (s *CalDAVService) Delete(ctx context.Context, userID uint, collection, name, ifMatch string) error {
+	categoryID, displayName, err := s.resolve(ctx, userID, collection)
+	if err != nil {
+		return err
+	}
+	todoItem, err := s.find(ctx, userID, categoryID, name)
+	if err != nil {
+		return err
+	}
+	if err := s.checkPreconditions(ctx, todoItem, displayName, ifMatch, ""); err != nil {
+		return err
+	}
+	return s.todos.Delete(ctx, todoItem.ID, userID)
+}
+
+// resolve 将集合名称解析为分类ID（未分类为 nil）和显示名称
+func (s *CalDAVService) resolve(ctx context.Context, userID uint, name string) (*uint, string, error) {
+	if name == uncategorizedCollection {
+		return nil, uncategorizedName, nil
+	}
+	id, err := strconv.ParseUint(name, 10, 32)
+	if err != nil {
+		return nil, "", errors.ErrCategoryNotFound
+	}
+	category, err := s.categoryRepo.GetByID(ctx, uint(id))
+	if err != nil {
+		return nil, "", err
+	}
+	if category.UserID != userID {
+		return nil, "", errors.ErrCategoryNotFound
+	}
+	return &category.ID, category.Name, nil
+}
+
+// describe 生成日历集合的描述，集合标签由所有对象的资源名和 ETag 计算
+func (s *CalDAVService) describe(ctx context.Context, userID uint, name string, categoryID *uint, displayName string) (*caldav.Collection, error) {
+	objects, err := s.objects(ctx, userID, categoryID, displayName)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	for _, o := range objects {
+		fmt.Fprintf(h, "%s %s\n", o.Name, o.ETag)
+	}
+	return &caldav.Collection{
+		Name:        name,
+		DisplayName: displayName,
+		CTag:        `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`,
+	}, nil
+}
+
+// objects 按页读取分类中未归档的待办事项及其提醒并生成日历对象
+func (s *CalDAVService) objects(ctx context.Context, userID uint, categoryID *uint, displayName string) ([]*caldav.Object, error) {
+	filter := repository.TodoFilter{Sort: repository.SortID}
+	if categoryID == nil {
+		filter.Uncategorized = true
+	} else {
+		filter.CategoryIDs = []uint{*categoryID}
+	}
+
+	var objects []*caldav.Object
+	for page := 1; ; page++ {
+		todos, _, err := s.todoRepo.ListByUserID(ctx, userID, filter, page, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]uint, len(todos))
+		for i, todoItem := range todos {
+			ids[i] = todoItem.ID
+		}
+		reminders, err := s.reminderRepo.ListByTodoIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		byTodo := make(map[uint][]*models.Reminder, len(todos))
+		for _, r := range reminders {
+			byTodo[r.TodoID] = append(byTodo[r.TodoID], r)
+		}
+		for _, todoItem := range todos {
+			object, err := render(todoItem, byTodo[todoItem.ID], displayName)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, object)
+		}
+		if len(todos) < exportPageSize {
+			return objects, nil
+		}
+	}
+}
+
+// object 读取待办事项的提醒并生成日历对象
+func (s *CalDAVService) object(ctx context.Context, todoItem *models.Todo, displayName string) (*caldav.Object, error) {
+	reminders, err := s.reminderRepo.ListByTodoIDs(ctx, []uint{todoItem.ID})
+	if err != nil {
+		return nil, err
+	}
+	return render(todoItem, reminders, displayName)
+}
+
+// find 根据资源名查找分类中未归档的待办事项
+// 通过 CalDAV 创建的待办事项按保存的资源名查找，其他待办事项按默认资源名 todo-<ID>.ics 查找
+func (s *CalDAVService) find(ctx context.Context, userID uint, categoryID *uint, name string) (*models.Todo, error) {
+	todoItem, err := s.todoRepo.GetByCalendarName(ctx, userID, categoryID, name)
+	if err == errors.ErrTodoNotFound {
+		var id uint
+		if _, scanErr := fmt.Sscanf(name, calendarObjectFmt, &id); scanErr != nil || fmt.Sprintf(calendarObjectFmt, id) != name {
+			return nil, errors.ErrTodoNotFound
+		}
+		if todoItem, err = s.todoRepo.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+		if todoItem.UserID != userID || todoItem.CalendarName != "" || !sameID(todoItem.CategoryID, categoryID) {
+			return nil, errors.ErrTodoNotFound
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if todoItem.Archived {
+		return nil, errors.ErrTodoNotFound
+	}
+	return todoItem, nil
+}
+
+// checkPreconditions 检查 If-Match 和 If-None-Match 条件，existing 为空表示对象不存在
+func (s *CalDAVService) checkPreconditions(ctx context.Context, existing *models.Todo, displayName, ifMatch, ifNoneMatch string) error {
+	if ifNoneMatch == "*" && existing != nil {
+		return errors.ErrPreconditionFailed
+	}
+	if ifMatch == "" {
+		return nil
+	}
+	if existing == nil {
+		return errors.ErrPreconditionFailed
+	}
+	if ifMatch == "*" {
+		return nil
+	}
+	current, err := s.object(ctx, existing, displayName)
+	if err != nil {
+		return err
+	}
+	for _, etag := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(etag) == current.ETag {
+			return nil
+		}
+	}
+	return errors.ErrPreconditionFailed
+}
+
+// create 在分类中创建待办事项并保存客户端指定的 UID 和资源名
+func (s *CalDAVService) create(ctx context.Context, userID uint, categoryID *uint, name, uid string, update *todo.UpdateRequest) (uint, error) {
+	req := &todo.CreateRequest{
+		Title:       *update.Title,
+		Description: *update.Description,
+		Priority:    *update.Priority,
+		CategoryID:  categoryID,
+		DueDate:     update.DueDate,
+		Tags:        *update.Tags,
+	}
+	id, err := s.todos.Create(ctx, userID, req)
+	if err != nil {
+		return 0, err
+	}
+
+	todoItem, err := s.todoRepo.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	todoItem.CalendarUID = uid
+	todoItem.CalendarName = name
+	if err := s.todoRepo.Update(ctx, todoItem); err != nil {
+		return 0, err
+	}
+	if !*update.Completed {
+		return id, nil
+	}
+	return id, s.todos.Update(ctx, id, userID, &todo.UpdateRequest{Completed: update.Completed})
+}
+
+// syncAlarms 使待办事项的提醒与 VALARM 一致
+// 提醒时间与某个 VALARM 相同的提醒保持不变（包括其重复方式和通知方式），其余提醒被删除，
+// 没有对应提醒的 VALARM 创建为一次性的推送提醒
+func (s *CalDAVService) syncAlarms(ctx context.Context, userID, todoID uint, alarms []ical.Alarm) error {
+	wanted := make([]time.Time, 0, len(alarms))
+	for _, a := range alarms {
+		wanted = append(wanted, a.Trigger.Truncate(time.Second))
+	}
+
+	existing, err := s.reminderRepo.ListByTodoID(ctx, todoID)
+	if err != nil {
+		return err
+	}
+	for _, r := range existing {
+		matched := -1
+		for i, at := range wanted {
+			if at.Equal(r.RemindAt.Truncate(time.Second)) {
+				matched = i
+				break
+			}
+		}
+		if matched >= 0 {
+			wanted = append(wanted[:matched], wanted[matched+1:]...)
+			continue
+		}
+		if err := s.reminders.Delete(ctx, r.ID, userID); err != nil {
+			return err
+		}
+	}
+
+	for _, at := range wanted {
+		_, err := s.reminders.Create(ctx, userID, &reminder.CreateRequest{
+			TodoID:     todoID,
+			RemindAt:   at,
+			RemindType: models.RemindTypeOnceStr,
+			NotifyType: models.NotifyTypePushStr,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// render 生成只包含一个待办事项的日历对象，ETag 由内容计算
+func render(todoItem *models.Todo, reminders []*models.Reminder, displayName string) (*caldav.Object, error) {
+	names := map[uint]string{}
+	if todoItem.CategoryID != nil {
+		names[*todoItem.CategoryID] = displayName
+	}
+
+	var buf bytes.Buffer
+	w := ical.NewWriter(&buf, calendarProdID, "")
+	if err := w.WriteTodo(icalTodo(todoItem, reminders, names)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	name := todoItem.CalendarName
+	if name == "" {
+		name = fmt.Sprintf(calendarObjectFmt, todoItem.ID)
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return &caldav.Object{
+		Name: name,
+		ETag: `"` + hex.EncodeToString(sum[:16]) + `"`,
+		Data: buf.Bytes(),
+	}, nil
+}
+
+// updateFromICal 将客户端上传的 VTODO 转换为整体更新请求并校验字段长度
+// 没有截止时间表示清除截止时间；PRIORITY 1-4 为高，6-9 为低，其余为中
+func updateFromICal(t *ical.Todo, collectionName string) (*todo.UpdateRequest, error) {
+	title := strings.TrimSpace(t.Summary)
+	if title == "" || utf8.RuneCountInString(title) > 128 || utf8.RuneCountInString(t.Description) > 1024 {
+		return nil, errors.ErrInvalidICalendar
+	}
+
+	priority := string(models.PriorityMedium)
+	switch {
+	case t.Priority >= 1 && t.Priority <= 4:
+		priority = string(models.PriorityHigh)
+	case t.Priority >= 6 && t.Priority <= 9:
+		priority = string(models.PriorityLow)
+	}
+
+	var names []string
+	for _, c := range t.Categories {
+		if !strings.EqualFold(c, collectionName) {
+			names = append(names, c)
+		}
+	}
+	tags := normalizeTags(names)
+	if len(tags) > 20 {
+		return nil, errors.ErrInvalidICalendar
+	}
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > 32 {
+			return nil, errors.ErrInvalidICalendar
+		}
+	}
+
+	completed := t.Status == ical.StatusCompleted
+	return &todo.UpdateRequest{
+		Title:        &title,
+		Description:  &t.Description,
+		Completed:    &completed,
+		Priority:     &priority,
+		DueDate:      t.Due,
+		ClearDueDate: t.Due == nil,
+		Tags:         &tags,
+	}, nil
+}
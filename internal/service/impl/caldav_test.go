@@ -0,0 +1,249 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"todo/api/v1/dto/caldav"
+	"todo/internal/models"
+	"todo/pkg/errors"
+)
+
+// newTestCalDAVService 创建使用模拟仓储的 CalDAV 同步服务
+func newTestCalDAVService(todoRepo *mockTodoRepo, reminderRepo *mockReminderRepo, categoryRepo *mockCategoryRepo) *CalDAVService {
+	todos := NewTodoService(todoRepo, reminderRepo, categoryRepo, newMockStatusRepo(), newMockDependencyRepo(),
+		newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+	reminders := NewReminderService(reminderRepo, todoRepo, newMockHistoryRepo(), nopTransactor{})
+	return NewCalDAVService(todoRepo, reminderRepo, categoryRepo, todos, reminders, nopTransactor{})
+}
+
+// calendarObject 生成客户端上传的日历对象
+func calendarObject(lines ...string) []byte {
+	all := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//test//EN", "BEGIN:VTODO"}, lines...)
+	all = append(all, "END:VTODO", "END:VCALENDAR")
+	return []byte(strings.Join(all, "\r\n") + "\r\n")
+}
+
+// TestCalDAVService_Put 测试通过 CalDAV 创建和更新待办事项以及提醒同步
+func TestCalDAVService_Put(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	reminderRepo := newMockReminderRepo()
+	categoryRepo := newMockCategoryRepo()
+	service := newTestCalDAVService(todoRepo, reminderRepo, categoryRepo)
+
+	work := &models.Category{Name: "Work", UserID: 1}
+	categoryRepo.Create(ctx, work)
+	collection := "1"
+
+	created, isNew, err := service.Put(ctx, 1, &caldav.PutRequest{
+		Collection:  collection,
+		Name:        "ABC-123.ics",
+		IfNoneMatch: "*",
+		Data: calendarObject(
+			"UID:abc-123@phone",
+			"SUMMARY:写周报",
+			"DUE:20240607T100000Z",
+			"PRIORITY:1",
+			"CATEGORIES:Work,例行",
+			"BEGIN:VALARM",
+			"ACTION:DISPLAY",
+			"TRIGGER:-PT1H",
+			"END:VALARM",
+		),
+	})
+	if err != nil {
+		t.Fatalf("Put() 创建失败: %v", err)
+	}
+	if !isNew || created.Name != "ABC-123.ics" || created.ETag == "" {
+		t.Fatalf("Put() = %+v, %v, 期望新建 ABC-123.ics", created, isNew)
+	}
+
+	item, err := todoRepo.GetByCalendarName(ctx, 1, &work.ID, "ABC-123.ics")
+	if err != nil {
+		t.Fatalf("GetByCalendarName() 失败: %v", err)
+	}
+	if item.Title != "写周报" || item.Priority != models.PriorityHigh || item.CalendarUID != "abc-123@phone" {
+		t.Errorf("创建的待办事项 = %+v", item)
+	}
+	if len(item.Tags) != 1 || item.Tags[0].Name != "例行" {
+		t.Errorf("Tags = %v, 期望只有 例行（与集合同名的分类不作为标签）", item.Tags)
+	}
+	reminders, _ := reminderRepo.ListByTodoID(ctx, item.ID)
+	due := time.Date(2024, 6, 7, 10, 0, 0, 0, time.UTC)
+	if len(reminders) != 1 || !reminders[0].RemindAt.Equal(due.Add(-time.Hour)) {
+		t.Fatalf("提醒 = %v, 期望截止前一小时", reminders)
+	}
+	firstReminder := reminders[0].ID
+	if !strings.Contains(string(created.Data), "UID:abc-123@phone") {
+		t.Errorf("日历对象应返回客户端的 UID:\n%s", created.Data)
+	}
+
+	// 再次以 If-None-Match: * 创建同名对象应失败
+	_, _, err = service.Put(ctx, 1, &caldav.PutRequest{Collection: collection, Name: "ABC-123.ics", IfNoneMatch: "*",
+		Data: calendarObject("UID:abc-123@phone", "SUMMARY:写周报")})
+	if err != errors.ErrPreconditionFailed {
+		t.Errorf("Put() If-None-Match 错误 = %v, 期望 %v", err, errors.ErrPreconditionFailed)
+	}
+
+	// ETag 不匹配时拒绝更新
+	_, _, err = service.Put(ctx, 1, &caldav.PutRequest{Collection: collection, Name: "ABC-123.ics", IfMatch: `"stale"`,
+		Data: calendarObject("UID:abc-123@phone", "SUMMARY:写周报")})
+	if err != errors.ErrPreconditionFailed {
+		t.Errorf("Put() If-Match 错误 = %v, 期望 %v", err, errors.ErrPreconditionFailed)
+	}
+
+	updated, isNew, err := service.Put(ctx, 1, &caldav.PutRequest{
+		Collection: collection,
+		Name:       "ABC-123.ics",
+		IfMatch:    created.ETag,
+		Data: calendarObject(
+			"UID:abc-123@phone",
+			"SUMMARY:写月报",
+			"STATUS:COMPLETED",
+			"COMPLETED:20240607T090000Z",
+			"BEGIN:VALARM",
+			"TRIGGER;VALUE=DATE-TIME:20240607T080000Z",
+			"END:VALARM",
+			"BEGIN:VALARM",
+			"TRIGGER;VALUE=DATE-TIME:20240607T083000Z",
+			"END:VALARM",
+		),
+	})
+	if err != nil {
+		t.Fatalf("Put() 更新失败: %v", err)
+	}
+	if isNew || updated.ETag == created.ETag {
+		t.Errorf("Put() 更新 isNew = %v, ETag 未变化 = %v", isNew, updated.ETag == created.ETag)
+	}
+	item, _ = todoRepo.GetByID(ctx, item.ID)
+	if item.Title != "写月报" || !item.Completed || item.DueDate != nil || len(item.Tags) != 0 {
+		t.Errorf("更新后的待办事项 = %+v, 期望标题已修改、已完成、清除截止时间和标签", item)
+	}
+	reminders, _ = reminderRepo.ListByTodoID(ctx, item.ID)
+	if len(reminders) != 2 {
+		t.Fatalf("提醒数量 = %d, 期望 2", len(reminders))
+	}
+	for _, r := range reminders {
+		if r.ID == firstReminder {
+			t.Errorf("不再对应 VALARM 的提醒应被删除")
+		}
+	}
+
+	// 时间未变化的提醒保持不变
+	kept := reminders[0].ID
+	if _, _, err := service.Put(ctx, 1, &caldav.PutRequest{Collection: collection, Name: "ABC-123.ics",
+		Data: calendarObject("UID:abc-123@phone", "SUMMARY:写月报", "BEGIN:VALARM",
+			"TRIGGER;VALUE=DATE-TIME:"+reminders[0].RemindAt.UTC().Format("20060102T150405Z"), "END:VALARM")}); err != nil {
+		t.Fatalf("Put() 失败: %v", err)
+	}
+	reminders, _ = reminderRepo.ListByTodoID(ctx, item.ID)
+	if len(reminders) != 1 || reminders[0].ID != kept {
+		t.Errorf("提醒 = %v, 期望保留 ID 为 %d 的提醒", reminders, kept)
+	}
+}
+
+// TestCalDAVService_PutInvalid 测试无效的日历对象和集合
+func TestCalDAVService_PutInvalid(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo := newMockCategoryRepo()
+	service := newTestCalDAVService(newMockTodoRepo(), newMockReminderRepo(), categoryRepo)
+	categoryRepo.Create(ctx, &models.Category{Name: "别人的", UserID: 2})
+
+	tests := []struct {
+		name string
+		req  *caldav.PutRequest
+		want error
+	}{
+		{"无效数据", &caldav.PutRequest{Collection: "uncategorized", Name: "a.ics", Data: []byte("hello")}, errors.ErrInvalidICalendar},
+		{"没有待办事项", &caldav.PutRequest{Collection: "uncategorized", Name: "a.ics",
+			Data: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")}, errors.ErrInvalidICalendar},
+		{"缺少标题", &caldav.PutRequest{Collection: "uncategorized", Name: "a.ics", Data: calendarObject("UID:a")}, errors.ErrInvalidICalendar},
+		{"资源名无效", &caldav.PutRequest{Collection: "uncategorized", Name: "a.txt", Data: calendarObject("SUMMARY:a")}, errors.ErrInvalidParameter},
+		{"集合不存在", &caldav.PutRequest{Collection: "99", Name: "a.ics", Data: calendarObject("SUMMARY:a")}, errors.ErrCategoryNotFound},
+		{"其他用户的分类", &caldav.PutRequest{Collection: "1", Name: "a.ics", Data: calendarObject("SUMMARY:a")}, errors.ErrCategoryNotFound},
+		{"不存在时要求 If-Match", &caldav.PutRequest{Collection: "uncategorized", Name: "a.ics", IfMatch: "*",
+			Data: calendarObject("SUMMARY:a")}, errors.ErrPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := service.Put(ctx, 1, tt.req); err != tt.want {
+				t.Errorf("Put() 错误 = %v, 期望 %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestCalDAVService_Collections 测试集合列表、集合标签和已有待办事项的默认资源名
+func TestCalDAVService_Collections(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	categoryRepo := newMockCategoryRepo()
+	service := newTestCalDAVService(todoRepo, newMockReminderRepo(), categoryRepo)
+
+	work := &models.Category{Name: "Work", UserID: 1}
+	categoryRepo.Create(ctx, work)
+	inbox := &models.Todo{Title: "买牛奶", UserID: 1, Priority: models.PriorityMedium}
+	todoRepo.Create(ctx, inbox)
+	todoRepo.Create(ctx, &models.Todo{Title: "写周报", UserID: 1, Priority: models.PriorityHigh, CategoryID: &work.ID})
+	todoRepo.Create(ctx, &models.Todo{Title: "已归档", UserID: 1, Archived: true})
+
+	collections, err := service.Collections(ctx, 1)
+	if err != nil {
+		t.Fatalf("Collections() 失败: %v", err)
+	}
+	if len(collections) != 2 || collections[0].Name != uncategorizedCollection || collections[1].DisplayName != "Work" {
+		t.Fatalf("Collections() = %+v", collections)
+	}
+
+	objects, err := service.Objects(ctx, 1, uncategorizedCollection)
+	if err != nil {
+		t.Fatalf("Objects() 失败: %v", err)
+	}
+	if len(objects) != 1 || objects[0].Name != "todo-1.ics" {
+		t.Fatalf("Objects() = %v, 期望只有未归档的 todo-1.ics", objects)
+	}
+	if _, err := service.Object(ctx, 1, "1", "todo-1.ics"); err != errors.ErrTodoNotFound {
+		t.Errorf("Object() 其他集合中的对象错误 = %v, 期望 %v", err, errors.ErrTodoNotFound)
+	}
+	if _, err := service.Object(ctx, 2, uncategorizedCollection, "todo-1.ics"); err != errors.ErrTodoNotFound {
+		t.Errorf("Object() 其他用户的对象错误 = %v, 期望 %v", err, errors.ErrTodoNotFound)
+	}
+
+	before := collections[0].CTag
+	if _, _, err := service.Put(ctx, 1, &caldav.PutRequest{Collection: uncategorizedCollection, Name: "todo-1.ics",
+		IfMatch: objects[0].ETag, Data: calendarObject("UID:todo-1@todo", "SUMMARY:买豆浆")}); err != nil {
+		t.Fatalf("Put() 更新已有待办事项失败: %v", err)
+	}
+	after, _ := service.Collection(ctx, 1, uncategorizedCollection)
+	if after.CTag == before {
+		t.Error("集合中的对象变化后 CTag 应变化")
+	}
+	if got, _ := todoRepo.GetByID(ctx, inbox.ID); got.Title != "买豆浆" {
+		t.Errorf("Title = %q, 期望 买豆浆", got.Title)
+	}
+}
+
+// TestCalDAVService_Delete 测试删除日历对象
+func TestCalDAVService_Delete(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	service := newTestCalDAVService(todoRepo, newMockReminderRepo(), newMockCategoryRepo())
+
+	item := &models.Todo{Title: "买牛奶", UserID: 1, Priority: models.PriorityMedium}
+	todoRepo.Create(ctx, item)
+
+	if err := service.Delete(ctx, 1, uncategorizedCollection, "todo-1.ics", `"stale"`); err != errors.ErrPreconditionFailed {
+		t.Errorf("Delete() If-Match 错误 = %v, 期望 %v", err, errors.ErrPreconditionFailed)
+	}
+	if err := service.Delete(ctx, 1, uncategorizedCollection, "todo-1.ics", ""); err != nil {
+		t.Fatalf("Delete() 失败: %v", err)
+	}
+	if _, err := service.Object(ctx, 1, uncategorizedCollection, "todo-1.ics"); err != errors.ErrTodoNotFound {
+		t.Errorf("删除后 Object() 错误 = %v, 期望 %v", err, errors.ErrTodoNotFound)
+	}
+	if err := service.Delete(ctx, 1, uncategorizedCollection, "todo-1.ics", ""); err != errors.ErrTodoNotFound {
+		t.Errorf("重复 Delete() 错误 = %v, 期望 %v", err, errors.ErrTodoNotFound)
+	}
+}
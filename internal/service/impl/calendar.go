@@ -0,0 +1,230 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/internal/tenant"
+	"todo/pkg/errors"
+	"todo/pkg/ical"
+)
+
+const (
+	calendarProdID = "-//todo//todo//ZH" // 导出日历的 PRODID
+	calendarName   = "待办事项"              // 日历客户端订阅时的默认名称
+	calendarUIDFmt = "todo-%d@todo"      // 待办事项在日历中的 UID，同一待办事项每次导出相同
+	feedTokenBytes = 32                  // 订阅令牌的随机字节数，十六进制编码后为 64 个字符
+)
+
+// icalPriority 待办事项优先级对应的 iCalendar PRIORITY（1 最高，9 最低）
+var icalPriority = map[models.Priority]int{
+	models.PriorityHigh:   1,
+	models.PriorityMedium: 5,
+	models.PriorityLow:    9,
+}
+
+// CalendarService 日历导出与订阅服务实现
+// 待办事项导出为 VTODO，其提醒导出为 VALARM；订阅地址中的令牌是访问订阅的唯一凭据
+type CalendarService struct {
+	feedRepo      repository.CalendarFeedRepository
+	workspaceRepo repository.WorkspaceRepository
+	todoRepo      repository.TodoRepository
+	reminderRepo  repository.ReminderRepository
+	categoryRepo  repository.CategoryRepository
+}
+
+// NewCalendarService 创建一个新的日历服务实例
+//
+// Parameters:
+//   - feedRepo: 日历订阅仓库实现
+//   - workspaceRepo: 工作空间仓库实现，拉取订阅时确认用户仍是工作空间成员
+//   - todoRepo: 待办事项仓库实现
+//   - reminderRepo: 提醒仓库实现
+//   - categoryRepo: 分类仓库实现，分类名导出为 CATEGORIES
+//
+// Returns:
+//   - *CalendarService: 返回日历服务实例
+func NewCalendarService(feedRepo repository.CalendarFeedRepository, workspaceRepo repository.WorkspaceRepository,
+	todoRepo repository.TodoRepository, reminderRepo repository.ReminderRepository,
+	categoryRepo repository.CategoryRepository) *CalendarService {
+	return &CalendarService{
+		feedRepo:      feedRepo,
+		workspaceRepo: workspaceRepo,
+		todoRepo:      todoRepo,
+		reminderRepo:  reminderRepo,
+		categoryRepo:  categoryRepo,
+	}
+}
+
+// Export 将匹配列表查询参数的所有待办事项以 iCalendar 写入 w
+// 与 CSV 导出一样按页读取并逐页写出；查询参数无效时在写入任何内容之前返回错误
+func (s *CalendarService) Export(ctx context.Context, userID uint, req *todo.ListRequest, w io.Writer) error {
+	filter, err := buildTodoFilter(ctx, s.categoryRepo, userID, req)
+	if err != nil {
+		return err
+	}
+	if filter.Sort == repository.SortDefault {
+		filter.Sort = repository.SortID
+	}
+	return s.write(ctx, userID, filter, w)
+}
+
+// Feed 根据订阅令牌将订阅者在对应工作空间中未归档的待办事项以 iCalendar 写入 w
+// 令牌不存在或用户已不是该工作空间的成员时返回 ErrCalendarFeedNotFound，且不写入任何内容
+func (s *CalendarService) Feed(ctx context.Context, token string, w io.Writer) error {
+	feed, err := s.feedRepo.GetByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if _, err := s.workspaceRepo.GetMember(ctx, feed.WorkspaceID, feed.UserID); err != nil {
+		if err == errors.ErrNotWorkspaceMember {
+			return errors.ErrCalendarFeedNotFound
+		}
+		return err
+	}
+
+	ctx = tenant.WithWorkspaceID(ctx, feed.WorkspaceID)
+	return s.write(ctx, feed.UserID, repository.TodoFilter{Sort: repository.SortID}, w)
+}
+
+// GetFeed 获取用户在当前工作空间中的日历订阅
+func (s *CalendarService) GetFeed(ctx context.Context, userID uint) (*models.CalendarFeed, error) {
+	return s.feedRepo.GetByUserID(ctx, userID)
+}
+
+// RotateFeed 为用户生成新的订阅令牌，尚未订阅时创建订阅
+// 旧令牌立即失效，已经订阅旧地址的日历客户端需要重新订阅
+func (s *CalendarService) RotateFeed(ctx context.Context, userID uint) (*models.CalendarFeed, error) {
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := s.feedRepo.GetByUserID(ctx, userID)
+	if err == errors.ErrCalendarFeedNotFound {
+		feed = &models.CalendarFeed{UserID: userID, Token: token}
+		if err := s.feedRepo.Create(ctx, feed); err != nil {
+			return nil, err
+		}
+		return feed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	feed.Token = token
+	if err := s.feedRepo.UpdateToken(ctx, feed); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// RevokeFeed 撤销用户在当前工作空间中的日历订阅
+func (s *CalendarService) RevokeFeed(ctx context.Context, userID uint) error {
+	feed, err := s.feedRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.feedRepo.Delete(ctx, feed.ID)
+}
+
+// write 按页读取匹配过滤条件的待办事项及其提醒并写出日历
+func (s *CalendarService) write(ctx context.Context, userID uint, filter repository.TodoFilter, w io.Writer) error {
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	names := make(map[uint]string, len(categories))
+	for _, c := range categories {
+		names[c.ID] = c.Name
+	}
+
+	cw := ical.NewWriter(w, calendarProdID, calendarName)
+	for page := 1; ; page++ {
+		todos, _, err := s.todoRepo.ListByUserID(ctx, userID, filter, page, exportPageSize)
+		if err != nil {
+			return err
+		}
+		ids := make([]uint, len(todos))
+		for i, todoItem := range todos {
+			ids[i] = todoItem.ID
+		}
+		reminders, err := s.reminderRepo.ListByTodoIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		byTodo := make(map[uint][]*models.Reminder, len(todos))
+		for _, r := range reminders {
+			byTodo[r.TodoID] = append(byTodo[r.TodoID], r)
+		}
+
+		for _, todoItem := range todos {
+			if err := cw.WriteTodo(icalTodo(todoItem, byTodo[todoItem.ID], names)); err != nil {
+				return err
+			}
+		}
+		if err := cw.Flush(); err != nil {
+			return err
+		}
+		if len(todos) < exportPageSize {
+			return cw.Close()
+		}
+	}
+}
+
+// icalTodo 将待办事项及其提醒转换为 VTODO
+// 提醒的修改不会更新待办事项本身，因此最后修改时间取两者中较晚的一个，使日历客户端能发现提醒的变化
+func icalTodo(t *models.Todo, reminders []*models.Reminder, categoryNames map[uint]string) *ical.Todo {
+	modified := t.UpdatedAt
+	for _, r := range reminders {
+		if r.UpdatedAt.After(modified) {
+			modified = r.UpdatedAt
+		}
+	}
+
+	uid := t.CalendarUID
+	if uid == "" {
+		uid = fmt.Sprintf(calendarUIDFmt, t.ID)
+	}
+	item := &ical.Todo{
+		UID:          uid,
+		Stamp:        modified,
+		Created:      t.CreatedAt,
+		LastModified: modified,
+		Summary:      t.Title,
+		Description:  t.Description,
+		Due:          t.DueDate,
+		Status:       ical.StatusNeedsAction,
+		Priority:     icalPriority[t.Priority],
+	}
+	if t.Completed {
+		item.Status = ical.StatusCompleted
+		item.Completed = t.CompletedAt
+	}
+	if t.CategoryID != nil {
+		if name, ok := categoryNames[*t.CategoryID]; ok {
+			item.Categories = append(item.Categories, name)
+		}
+	}
+	for _, tag := range t.Tags {
+		item.Categories = append(item.Categories, tag.Name)
+	}
+	for _, r := range reminders {
+		item.Alarms = append(item.Alarms, ical.Alarm{Trigger: r.RemindAt, Description: t.Title})
+	}
+	return item
+}
+
+// newFeedToken 生成随机的订阅令牌
+func newFeedToken() (string, error) {
+	b := make([]byte, feedTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
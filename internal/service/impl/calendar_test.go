@@ -0,0 +1,142 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"todo/internal/models"
+	"todo/pkg/errors"
+)
+
+// mockCalendarFeedRepo 模拟日历订阅仓储接口
+type mockCalendarFeedRepo struct {
+	feeds map[uint]*models.CalendarFeed
+	seq   uint
+}
+
+func newMockCalendarFeedRepo() *mockCalendarFeedRepo {
+	return &mockCalendarFeedRepo{feeds: make(map[uint]*models.CalendarFeed), seq: 1}
+}
+
+func (m *mockCalendarFeedRepo) Create(ctx context.Context, feed *models.CalendarFeed) error {
+	feed.ID = m.seq
+	feed.WorkspaceID = 1
+	m.seq++
+	m.feeds[feed.ID] = feed
+	return nil
+}
+
+func (m *mockCalendarFeedRepo) GetByUserID(ctx context.Context, userID uint) (*models.CalendarFeed, error) {
+	for _, feed := range m.feeds {
+		if feed.UserID == userID {
+			return feed, nil
+		}
+	}
+	return nil, errors.ErrCalendarFeedNotFound
+}
+
+func (m *mockCalendarFeedRepo) GetByToken(ctx context.Context, token string) (*models.CalendarFeed, error) {
+	for _, feed := range m.feeds {
+		if feed.Token == token {
+			return feed, nil
+		}
+	}
+	return nil, errors.ErrCalendarFeedNotFound
+}
+
+func (m *mockCalendarFeedRepo) UpdateToken(ctx context.Context, feed *models.CalendarFeed) error {
+	m.feeds[feed.ID].Token = feed.Token
+	return nil
+}
+
+func (m *mockCalendarFeedRepo) Delete(ctx context.Context, id uint) error {
+	delete(m.feeds, id)
+	return nil
+}
+
+// TestCalendarService_Feed 测试订阅令牌的生成、轮换、撤销以及订阅内容
+func TestCalendarService_Feed(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	reminderRepo := newMockReminderRepo()
+	workspaceRepo := newMockWorkspaceRepo()
+	workspaceRepo.AddMember(ctx, &models.WorkspaceMember{WorkspaceID: 1, UserID: 1, Role: models.WorkspaceRoleOwner})
+	categoryRepo := newMockCategoryRepo()
+	service := NewCalendarService(newMockCalendarFeedRepo(), workspaceRepo, todoRepo, reminderRepo, categoryRepo)
+
+	work := &models.Category{Name: "Work", UserID: 1}
+	categoryRepo.Create(ctx, work)
+	due := time.Date(2024, 6, 7, 10, 0, 0, 0, time.UTC)
+	report := &models.Todo{Title: "写周报", UserID: 1, Priority: models.PriorityHigh, DueDate: &due, CategoryID: &work.ID,
+		Tags: []models.Tag{{Name: "例行"}}}
+	todoRepo.Create(ctx, report)
+	todoRepo.Create(ctx, &models.Todo{Title: "已归档", UserID: 1, Archived: true})
+	todoRepo.Create(ctx, &models.Todo{Title: "别人的", UserID: 2})
+	reminderRepo.Create(ctx, &models.Reminder{TodoID: report.ID, RemindAt: due.Add(-time.Hour)})
+
+	if _, err := service.GetFeed(ctx, 1); err != errors.ErrCalendarFeedNotFound {
+		t.Errorf("GetFeed() 错误 = %v, 期望 %v", err, errors.ErrCalendarFeedNotFound)
+	}
+	feed, err := service.RotateFeed(ctx, 1)
+	if err != nil {
+		t.Fatalf("RotateFeed() 错误 = %v", err)
+	}
+	if len(feed.Token) != 2*feedTokenBytes {
+		t.Errorf("令牌长度 = %d, 期望 %d", len(feed.Token), 2*feedTokenBytes)
+	}
+
+	var buf bytes.Buffer
+	if err := service.Feed(ctx, feed.Token, &buf); err != nil {
+		t.Fatalf("Feed() 错误 = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"BEGIN:VTODO\r\nUID:todo-1@todo\r\n",
+		"SUMMARY:写周报\r\n",
+		"DUE:20240607T100000Z\r\n",
+		"STATUS:NEEDS-ACTION\r\n",
+		"PRIORITY:1\r\n",
+		"CATEGORIES:Work,例行\r\n",
+		"TRIGGER;VALUE=DATE-TIME:20240607T090000Z\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("订阅内容中缺少 %q\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "已归档") || strings.Contains(out, "别人的") {
+		t.Errorf("订阅内容包含已归档或其他用户的待办事项:\n%s", out)
+	}
+
+	// 内容没有变化时每次输出相同，ETag 才能稳定
+	var again bytes.Buffer
+	if err := service.Feed(ctx, feed.Token, &again); err != nil || again.String() != out {
+		t.Errorf("第二次 Feed() 输出不同, 错误 = %v", err)
+	}
+
+	oldToken := feed.Token
+	rotated, err := service.RotateFeed(ctx, 1)
+	if err != nil {
+		t.Fatalf("再次 RotateFeed() 错误 = %v", err)
+	}
+	if rotated.ID != feed.ID || rotated.Token == oldToken {
+		t.Errorf("RotateFeed() = %+v, 期望同一订阅的新令牌", rotated)
+	}
+	if err := service.Feed(ctx, oldToken, &bytes.Buffer{}); err != errors.ErrCalendarFeedNotFound {
+		t.Errorf("旧令牌 Feed() 错误 = %v, 期望 %v", err, errors.ErrCalendarFeedNotFound)
+	}
+
+	// 离开工作空间后订阅失效
+	workspaceRepo.members = nil
+	if err := service.Feed(ctx, rotated.Token, &bytes.Buffer{}); err != errors.ErrCalendarFeedNotFound {
+		t.Errorf("非成员 Feed() 错误 = %v, 期望 %v", err, errors.ErrCalendarFeedNotFound)
+	}
+
+	if err := service.RevokeFeed(ctx, 1); err != nil {
+		t.Fatalf("RevokeFeed() 错误 = %v", err)
+	}
+	if err := service.RevokeFeed(ctx, 1); err != errors.ErrCalendarFeedNotFound {
+		t.Errorf("重复 RevokeFeed() 错误 = %v, 期望 %v", err, errors.ErrCalendarFeedNotFound)
+	}
+}
@@ -8,20 +8,38 @@ import (
 	"todo/pkg/errors"
 )
 
+// CategoryTodos 删除分类时处理其下待办事项的操作，由 TodoService 实现
+type CategoryTodos interface {
+	ReassignCategory(ctx context.Context, actorID, from uint, to *uint) (int, error)
+	TrashCategory(ctx context.Context, actorID, categoryID uint) (int, error)
+}
+
 // CategoryService 分类服务实现
 type CategoryService struct {
 	categoryRepo repository.CategoryRepository
+	todos        CategoryTodos
+	history      historyRecorder
+	tx           repository.Transactor
 }
 
 // NewCategoryService 创建一个新的分类服务实例
 //
 // Parameters:
 //   - repo: 分类仓库实现
+//   - todos: 删除分类时处理其下待办事项
+//   - historyRepo: 变更历史仓库实现
+//   - tx: 事务执行器
 //
 // Returns:
 //   - *CategoryService: 返回分类服务实例
-func NewCategoryService(repo repository.CategoryRepository) *CategoryService {
-	return &CategoryService{categoryRepo: repo}
+func NewCategoryService(repo repository.CategoryRepository, todos CategoryTodos,
+	historyRepo repository.HistoryRepository, tx repository.Transactor) *CategoryService {
+	return &CategoryService{
+		categoryRepo: repo,
+		todos:        todos,
+		history:      historyRecorder{repo: historyRepo},
+		tx:           tx,
+	}
 }
 
 // Create 创建新的分类
@@ -36,12 +54,26 @@ func NewCategoryService(repo repository.CategoryRepository) *CategoryService {
 //   - error: 可能的错误信息
 func (s *CategoryService) Create(ctx context.Context, userID uint, req *category.CreateRequest) (uint, error) {
 	category := &models.Category{
-		Name:   req.Name,  // 分类名称
-		Color:  req.Color, // 分类颜色
-		UserID: userID,    // 所属用户ID
+		Name:     req.Name,     // 分类名称
+		Color:    req.Color,    // 分类颜色
+		UserID:   userID,       // 所属用户ID
+		ParentID: req.ParentID, // 上级分类ID
+	}
+
+	// 上级分类必须存在且属于当前用户
+	if req.ParentID != nil {
+		if _, err := s.Get(ctx, *req.ParentID, userID); err != nil {
+			return 0, err
+		}
 	}
 
-	if err := s.categoryRepo.Create(ctx, category); err != nil {
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Create(ctx, category); err != nil {
+			return err
+		}
+		return s.history.record(ctx, models.EntityCategory, category.ID, userID, models.ChangeActionCreate, nil, category)
+	})
+	if err != nil {
 		return 0, err
 	}
 
@@ -85,6 +117,99 @@ func (s *CategoryService) List(ctx context.Context, userID uint) ([]*models.Cate
 	return s.categoryRepo.ListByUserID(ctx, userID)
 }
 
+// Tree 获取用户的分类树，返回顶级分类，下级分类填充在 Children 中
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID
+//
+// Returns:
+//   - []*models.Category: 顶级分类列表
+//   - error: 可能的错误信息
+func (s *CategoryService) Tree(ctx context.Context, userID uint) ([]*models.Category, error) {
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(categories), nil
+}
+
+// Descendants 获取分类及其所有下级分类的ID
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - id: 分类ID
+//   - userID: 用户ID
+//
+// Returns:
+//   - []uint: 分类ID列表，第一个元素为分类本身
+//   - error: 可能的错误信息
+func (s *CategoryService) Descendants(ctx context.Context, id, userID uint) ([]uint, error) {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return descendantIDs(categories, id), nil
+}
+
+// Move 移动分类，整棵子树随之移动
+// 新的上级分类不能是分类本身或其下级分类，否则返回 ErrCategoryCycle
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID
+//   - categoryID: 分类ID
+//   - parentID: 新的上级分类ID，为空时移动到顶级
+//
+// Returns:
+//   - error: 可能的错误信息
+func (s *CategoryService) Move(ctx context.Context, userID, categoryID uint, parentID *uint) error {
+	category, err := s.Get(ctx, categoryID, userID)
+	if err != nil {
+		return err
+	}
+	if parentID != nil {
+		if _, err := s.Get(ctx, *parentID, userID); err != nil {
+			return err
+		}
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// 锁定用户的所有分类后再检查环，避免两个并发的移动各自通过检查后形成环
+		categories, err := s.categoryRepo.LockByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		// 使用锁定后读取的分类，上级分类可能已被并发的移动修改
+		var locked *models.Category
+		for _, c := range categories {
+			if c.ID == category.ID {
+				locked = c
+			}
+		}
+		if locked == nil {
+			return errors.ErrCategoryNotFound
+		}
+		if parentID != nil {
+			for _, id := range descendantIDs(categories, category.ID) {
+				if id == *parentID {
+					return errors.ErrCategoryCycle
+				}
+			}
+		}
+
+		before := *locked
+		locked.ParentID = parentID
+		if err := s.categoryRepo.Update(ctx, locked); err != nil {
+			return err
+		}
+		return s.history.record(ctx, models.EntityCategory, locked.ID, userID, models.ChangeActionUpdate, &before, locked)
+	})
+}
+
 // Update 更新分类信息
 //
 // Parameters:
@@ -100,6 +225,7 @@ func (s *CategoryService) Update(ctx context.Context, userID, categoryID uint, r
 	if err != nil {
 		return err
 	}
+	before := *category
 
 	// 只更新提供的字段
 	if req.Name != nil {
@@ -109,23 +235,137 @@ func (s *CategoryService) Update(ctx context.Context, userID, categoryID uint, r
 		category.Color = *req.Color
 	}
 
-	return s.categoryRepo.Update(ctx, category)
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Update(ctx, category); err != nil {
+			return err
+		}
+		return s.history.record(ctx, models.EntityCategory, category.ID, userID, models.ChangeActionUpdate, &before, category)
+	})
 }
 
-// Delete 删除分类
+// Delete 删除分类，并按指定策略在同一事务中处理其下的待办事项
 //
 // Parameters:
 //   - ctx: 上下文信息
 //   - userID: 用户ID
 //   - categoryID: 分类ID
+//   - req: 删除请求，包含待办事项的处理策略
 //
 // Returns:
+//   - int: 受影响的待办事项数量
 //   - error: 可能的错误信息
-func (s *CategoryService) Delete(ctx context.Context, userID, categoryID uint) error {
-	category, err := s.Get(ctx, categoryID, userID)
+func (s *CategoryService) Delete(ctx context.Context, userID, categoryID uint, req *category.DeleteRequest) (int, error) {
+	strategy, targetID, err := req.Parse()
+	if err != nil {
+		return 0, err
+	}
+
+	item, err := s.Get(ctx, categoryID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if strategy == category.StrategyMoveTo {
+		if targetID == item.ID {
+			return 0, errors.ErrInvalidParameter
+		}
+		if _, err := s.Get(ctx, targetID, userID); err != nil {
+			return 0, err
+		}
+	}
+
+	affected := 0
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		switch strategy {
+		case category.StrategyMoveTo:
+			affected, err = s.todos.ReassignCategory(ctx, userID, item.ID, &targetID)
+		case category.StrategyDeleteTodos:
+			affected, err = s.todos.TrashCategory(ctx, userID, item.ID)
+		default:
+			affected, err = s.todos.ReassignCategory(ctx, userID, item.ID, nil)
+		}
+		if err != nil {
+			return err
+		}
+
+		// 下级分类挂到被删除分类的上级，保持层级不断开
+		if err := s.reparentChildren(ctx, userID, item); err != nil {
+			return err
+		}
+
+		if err := s.categoryRepo.Delete(ctx, item.ID); err != nil {
+			return err
+		}
+		return s.history.record(ctx, models.EntityCategory, item.ID, userID, models.ChangeActionDelete, item, nil)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// reparentChildren 将分类的直接下级分类移动到该分类的上级，需在事务中调用
+func (s *CategoryService) reparentChildren(ctx context.Context, userID uint, parent *models.Category) error {
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
 	if err != nil {
 		return err
 	}
+	for _, child := range categories {
+		if child.ParentID == nil || *child.ParentID != parent.ID {
+			continue
+		}
+		before := *child
+		child.ParentID = parent.ParentID
+		if err := s.categoryRepo.Update(ctx, child); err != nil {
+			return err
+		}
+		if err := s.history.record(ctx, models.EntityCategory, child.ID, userID, models.ChangeActionUpdate, &before, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildCategoryTree 将分类列表组装为树，返回顶级分类
+// 上级分类不在列表中（例如已删除）的分类视为顶级分类
+func buildCategoryTree(categories []*models.Category) []*models.Category {
+	byID := make(map[uint]*models.Category, len(categories))
+	for _, c := range categories {
+		c.Children = nil
+		byID[c.ID] = c
+	}
 
-	return s.categoryRepo.Delete(ctx, category.ID)
+	var roots []*models.Category
+	for _, c := range categories {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok {
+				parent.Children = append(parent.Children, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+	return roots
+}
+
+// descendantIDs 返回分类本身及其所有下级分类的ID
+func descendantIDs(categories []*models.Category, rootID uint) []uint {
+	children := make(map[uint][]uint)
+	for _, c := range categories {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	ids := []uint{rootID}
+	seen := map[uint]bool{rootID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
 }
@@ -0,0 +1,210 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"todo/api/v1/dto/category"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+)
+
+// mockCategoryRepo 模拟分类仓储接口
+type mockCategoryRepo struct {
+	categories map[uint]*models.Category
+	seq        uint
+	locks      int // 在事务中调用 LockByUserID 的次数
+}
+
+func newMockCategoryRepo() *mockCategoryRepo {
+	return &mockCategoryRepo{categories: make(map[uint]*models.Category), seq: 1}
+}
+
+func (m *mockCategoryRepo) Create(ctx context.Context, c *models.Category) error {
+	c.ID = m.seq
+	m.categories[c.ID] = c
+	m.seq++
+	return nil
+}
+
+func (m *mockCategoryRepo) GetByID(ctx context.Context, id uint) (*models.Category, error) {
+	c, exists := m.categories[id]
+	if !exists {
+		return nil, errors.ErrCategoryNotFound
+	}
+	return c, nil
+}
+
+func (m *mockCategoryRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.Category, error) {
+	var categories []*models.Category
+	for _, c := range m.categories {
+		if c.UserID == userID {
+			categories = append(categories, c)
+		}
+	}
+	return categories, nil
+}
+
+func (m *mockCategoryRepo) LockByUserID(ctx context.Context, userID uint) ([]*models.Category, error) {
+	if inTx, _ := ctx.Value(txMarker{}).(bool); inTx {
+		m.locks++
+	}
+	return m.ListByUserID(ctx, userID)
+}
+
+func (m *mockCategoryRepo) Update(ctx context.Context, c *models.Category) error {
+	m.categories[c.ID] = c
+	return nil
+}
+
+func (m *mockCategoryRepo) Delete(ctx context.Context, id uint) error {
+	delete(m.categories, id)
+	return nil
+}
+
+// TestCategoryService_DeleteStrategies 测试删除分类时各策略对待办事项的处理
+func TestCategoryService_DeleteStrategies(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func() (*CategoryService, *TodoService, uint, uint, []uint) {
+		historyRepo := newMockHistoryRepo()
+		categoryRepo := newMockCategoryRepo()
+		todoService := NewTodoService(newMockTodoRepo(), newMockReminderRepo(), categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), historyRepo, nopTransactor{}, &mockNotifier{})
+		categoryService := NewCategoryService(categoryRepo, todoService, historyRepo, nopTransactor{})
+
+		source, _ := categoryService.Create(ctx, 1, &category.CreateRequest{Name: "源分类"})
+		target, _ := categoryService.Create(ctx, 1, &category.CreateRequest{Name: "目标分类"})
+		var ids []uint
+		for i := 0; i < 2; i++ {
+			id, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "待办", CategoryID: &source})
+			ids = append(ids, id)
+		}
+		return categoryService, todoService, source, target, ids
+	}
+
+	t.Run("默认取消分类", func(t *testing.T) {
+		categoryService, todoService, source, _, ids := setup()
+		affected, err := categoryService.Delete(ctx, 1, source, &category.DeleteRequest{})
+		if err != nil || affected != 2 {
+			t.Fatalf("Delete() = %d, %v, 期望 2", affected, err)
+		}
+		got, _ := todoService.Get(ctx, ids[0], 1)
+		if got.CategoryID != nil {
+			t.Errorf("CategoryID = %v, 期望为空", *got.CategoryID)
+		}
+	})
+
+	t.Run("移动到其他分类", func(t *testing.T) {
+		categoryService, todoService, source, target, ids := setup()
+		affected, err := categoryService.Delete(ctx, 1, source, &category.DeleteRequest{Strategy: "move_to=2"})
+		if err != nil || affected != 2 {
+			t.Fatalf("Delete() = %d, %v, 期望 2", affected, err)
+		}
+		got, _ := todoService.Get(ctx, ids[1], 1)
+		if got.CategoryID == nil || *got.CategoryID != target {
+			t.Errorf("CategoryID = %v, 期望 %d", got.CategoryID, target)
+		}
+	})
+
+	t.Run("删除待办事项", func(t *testing.T) {
+		categoryService, todoService, source, _, ids := setup()
+		affected, err := categoryService.Delete(ctx, 1, source, &category.DeleteRequest{Strategy: "delete_todos"})
+		if err != nil || affected != 2 {
+			t.Fatalf("Delete() = %d, %v, 期望 2", affected, err)
+		}
+		if _, err := todoService.Get(ctx, ids[0], 1); err != errors.ErrTodoNotFound {
+			t.Errorf("Get() 错误 = %v, 期望待办事项已移入回收站", err)
+		}
+		trash, _ := todoService.ListTrash(ctx, 1)
+		if len(trash) != 2 || trash[0].CategoryID != nil {
+			t.Errorf("回收站中的待办事项应取消分类: %v", trash)
+		}
+	})
+
+	t.Run("无效策略", func(t *testing.T) {
+		categoryService, _, source, _, _ := setup()
+		for _, strategy := range []string{"unknown", "move_to=abc", "move_to=1"} {
+			if _, err := categoryService.Delete(ctx, 1, source, &category.DeleteRequest{Strategy: strategy}); err != errors.ErrInvalidParameter {
+				t.Errorf("Delete(%q) 错误 = %v, 期望 %v", strategy, err, errors.ErrInvalidParameter)
+			}
+		}
+		if _, err := categoryService.Delete(ctx, 1, source, &category.DeleteRequest{Strategy: "move_to=99"}); err != errors.ErrCategoryNotFound {
+			t.Errorf("Delete() 目标分类不存在错误 = %v, 期望 %v", err, errors.ErrCategoryNotFound)
+		}
+	})
+}
+
+// TestCategoryService_Hierarchy 测试分类树、子树移动、循环检测以及按分类树过滤待办事项
+func TestCategoryService_Hierarchy(t *testing.T) {
+	ctx := context.Background()
+	historyRepo := newMockHistoryRepo()
+	categoryRepo := newMockCategoryRepo()
+	todoService := NewTodoService(newMockTodoRepo(), newMockReminderRepo(), categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), historyRepo, nopTransactor{}, &mockNotifier{})
+	categoryService := NewCategoryService(categoryRepo, todoService, historyRepo, markingTransactor{})
+
+	area, _ := categoryService.Create(ctx, 1, &category.CreateRequest{Name: "领域"})
+	project, _ := categoryService.Create(ctx, 1, &category.CreateRequest{Name: "项目", ParentID: &area})
+	sub, _ := categoryService.Create(ctx, 1, &category.CreateRequest{Name: "子项目", ParentID: &project})
+	other, _ := categoryService.Create(ctx, 1, &category.CreateRequest{Name: "其他"})
+
+	roots, err := categoryService.Tree(ctx, 1)
+	if err != nil {
+		t.Fatalf("Tree() 错误 = %v", err)
+	}
+	if len(roots) != 2 {
+		t.Fatalf("Tree() 返回 %d 个顶级分类, 期望 2", len(roots))
+	}
+	for _, root := range roots {
+		if root.ID == area && (len(root.Children) != 1 || len(root.Children[0].Children) != 1 || root.Children[0].Children[0].ID != sub) {
+			t.Errorf("分类树结构错误: %+v", root)
+		}
+	}
+
+	// 不能移动到自身或下级分类之下
+	for _, parent := range []uint{area, sub} {
+		if err := categoryService.Move(ctx, 1, area, &parent); err != errors.ErrCategoryCycle {
+			t.Errorf("Move(%d) 错误 = %v, 期望 %v", parent, err, errors.ErrCategoryCycle)
+		}
+	}
+
+	subTodo, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "子项目待办", CategoryID: &sub})
+	_, _ = todoService.Create(ctx, 1, &todo.CreateRequest{Title: "其他待办", CategoryID: &other})
+
+	list := func(req *todo.ListRequest) []*models.Todo {
+		todos, err := todoService.List(ctx, 1, req)
+		if err != nil {
+			t.Fatalf("List() 错误 = %v", err)
+		}
+		return todos
+	}
+	if todos := list(&todo.ListRequest{CategoryID: &area}); len(todos) != 0 {
+		t.Errorf("不含下级分类时返回 %d 条, 期望 0", len(todos))
+	}
+	if todos := list(&todo.ListRequest{CategoryID: &area, IncludeDescendants: true}); len(todos) != 1 || todos[0].ID != subTodo {
+		t.Errorf("包含下级分类时返回 %v, 期望只有 %d", todos, subTodo)
+	}
+
+	// 移动子树后，原领域下不再包含子项目的待办事项
+	if err := categoryService.Move(ctx, 1, project, &other); err != nil {
+		t.Fatalf("Move() 错误 = %v", err)
+	}
+	// 每次移动都在事务中锁定分类后再检查环
+	if categoryRepo.locks != 3 {
+		t.Errorf("事务中锁定分类 %d 次, 期望 3 次", categoryRepo.locks)
+	}
+	if todos := list(&todo.ListRequest{CategoryID: &area, IncludeDescendants: true}); len(todos) != 0 {
+		t.Errorf("移动后领域下返回 %d 条, 期望 0", len(todos))
+	}
+	if todos := list(&todo.ListRequest{CategoryID: &other, IncludeDescendants: true}); len(todos) != 2 {
+		t.Errorf("移动后其他分类下返回 %d 条, 期望 2", len(todos))
+	}
+
+	// 删除分类时下级分类挂到其上级
+	if _, err := categoryService.Delete(ctx, 1, project, &category.DeleteRequest{}); err != nil {
+		t.Fatalf("Delete() 错误 = %v", err)
+	}
+	got, _ := categoryService.Get(ctx, sub, 1)
+	if got.ParentID == nil || *got.ParentID != other {
+		t.Errorf("删除后子项目的上级 = %v, 期望 %d", got.ParentID, other)
+	}
+}
@@ -0,0 +1,239 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"todo/api/v1/dto/comment"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/errors"
+	"todo/pkg/logger"
+	"todo/pkg/notify"
+)
+
+// mentionPattern 匹配正文中的 @用户名，要求 @ 前不是单词字符，避免把邮箱地址识别为提及
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.\-]+)`)
+
+// CommentService 评论服务实现
+// 待办事项所在工作空间的成员都可以查看和发表评论，只有作者可以编辑和删除自己的评论
+type CommentService struct {
+	commentRepo   repository.CommentRepository
+	todoRepo      repository.TodoRepository
+	userRepo      repository.UserRepository
+	workspaceRepo repository.WorkspaceRepository
+	tx            repository.Transactor
+	notifier      notify.Notifier
+}
+
+// NewCommentService 创建一个新的评论服务实例
+//
+// Parameters:
+//   - commentRepo: 评论仓库实现
+//   - todoRepo: 待办事项仓库实现，用于校验评论所属的待办事项
+//   - userRepo: 用户仓库实现，用于解析@提及
+//   - workspaceRepo: 工作空间仓库实现，用于校验评论者是待办事项所在工作空间的成员
+//   - tx: 事务执行器
+//   - notifier: 通知器，用于通知被提及的成员
+//
+// Returns:
+//   - *CommentService: 返回评论服务实例
+func NewCommentService(commentRepo repository.CommentRepository, todoRepo repository.TodoRepository,
+	userRepo repository.UserRepository, workspaceRepo repository.WorkspaceRepository, tx repository.Transactor,
+	notifier notify.Notifier) *CommentService {
+	return &CommentService{
+		commentRepo:   commentRepo,
+		todoRepo:      todoRepo,
+		userRepo:      userRepo,
+		workspaceRepo: workspaceRepo,
+		tx:            tx,
+		notifier:      notifier,
+	}
+}
+
+// Create 发表评论
+func (s *CommentService) Create(ctx context.Context, userID, todoID uint, req *comment.CreateRequest) (*models.Comment, error) {
+	todo, err := s.getTodo(ctx, userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &models.Comment{
+		TodoID: todo.ID,
+		UserID: userID,
+		Body:   req.Body,
+	}
+	if err := s.commentRepo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+
+	s.notifyMentions(ctx, userID, todo, req.Body, extractMentions(req.Body))
+	return s.commentRepo.GetByID(ctx, c.ID)
+}
+
+// List 获取待办事项的全部评论
+func (s *CommentService) List(ctx context.Context, userID, todoID uint) ([]*models.Comment, error) {
+	return s.Latest(ctx, userID, todoID, 0)
+}
+
+// Latest 获取待办事项最新的 limit 条评论，limit 小于等于0时返回全部
+func (s *CommentService) Latest(ctx context.Context, userID, todoID uint, limit int) ([]*models.Comment, error) {
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	return s.commentRepo.ListByTodoID(ctx, todoID, limit)
+}
+
+// Update 编辑评论
+// 只有作者可以编辑；旧正文保存到编辑历史，仅对新增的@提及发送通知
+func (s *CommentService) Update(ctx context.Context, userID, todoID, commentID uint, req *comment.UpdateRequest) (*models.Comment, error) {
+	c, err := s.getOwnComment(ctx, userID, todoID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Body == req.Body {
+		return c, nil
+	}
+
+	oldMentions := make(map[string]bool)
+	for _, name := range extractMentions(c.Body) {
+		oldMentions[name] = true
+	}
+
+	revision := &models.CommentRevision{
+		CommentID: c.ID,
+		Body:      c.Body,
+		EditedBy:  userID,
+	}
+	now := time.Now()
+	c.Body = req.Body
+	c.EditedAt = &now
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.commentRepo.CreateRevision(ctx, revision); err != nil {
+			return err
+		}
+		return s.commentRepo.Update(ctx, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var added []string
+	for _, name := range extractMentions(req.Body) {
+		if !oldMentions[name] {
+			added = append(added, name)
+		}
+	}
+	if todo, err := s.todoRepo.GetByID(ctx, todoID); err == nil {
+		s.notifyMentions(ctx, userID, todo, req.Body, added)
+	}
+
+	return s.commentRepo.GetByID(ctx, c.ID)
+}
+
+// Delete 删除评论，只有作者可以删除
+func (s *CommentService) Delete(ctx context.Context, userID, todoID, commentID uint) error {
+	c, err := s.getOwnComment(ctx, userID, todoID, commentID)
+	if err != nil {
+		return err
+	}
+	return s.commentRepo.Delete(ctx, c.ID)
+}
+
+// History 获取评论的编辑历史
+func (s *CommentService) History(ctx context.Context, userID, todoID, commentID uint) ([]*models.CommentRevision, error) {
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	c, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if c.TodoID != todoID {
+		return nil, errors.ErrCommentNotFound
+	}
+	return s.commentRepo.ListRevisions(ctx, c.ID)
+}
+
+// getTodo 获取待办事项并校验当前用户是其所在工作空间的成员
+func (s *CommentService) getTodo(ctx context.Context, userID, todoID uint) (*models.Todo, error) {
+	todo, err := s.todoRepo.GetByID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo.UserID == userID {
+		return todo, nil
+	}
+	if _, err := s.workspaceRepo.GetMember(ctx, todo.WorkspaceID, userID); err != nil {
+		if err == errors.ErrNotWorkspaceMember {
+			return nil, errors.ErrForbidden
+		}
+		return nil, err
+	}
+	return todo, nil
+}
+
+// getOwnComment 获取当前用户在指定待办事项下发表的评论
+func (s *CommentService) getOwnComment(ctx context.Context, userID, todoID, commentID uint) (*models.Comment, error) {
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	c, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if c.TodoID != todoID {
+		return nil, errors.ErrCommentNotFound
+	}
+	if c.UserID != userID {
+		return nil, errors.ErrForbidden
+	}
+	return c, nil
+}
+
+// notifyMentions 通知被提及的工作空间成员
+// 通知失败不影响评论本身，只记录日志；不是当前工作空间成员的用户名会被忽略
+func (s *CommentService) notifyMentions(ctx context.Context, authorID uint, todo *models.Todo, body string, usernames []string) {
+	for _, name := range usernames {
+		user, err := s.userRepo.GetMemberByUsername(ctx, name)
+		if err != nil {
+			continue
+		}
+		if user.ID == authorID {
+			continue
+		}
+		msg := &notify.Message{
+			UserID: user.ID,
+			Type:   notify.TypeCommentMention,
+			Title:  fmt.Sprintf("你在「%s」的评论中被提及", todo.Title),
+			Body:   body,
+			TodoID: todo.ID,
+		}
+		if err := s.notifier.Notify(ctx, msg); err != nil {
+			logger.Warn().Err(err).Uint("user_id", user.ID).Msg("发送提及通知失败")
+		}
+	}
+}
+
+// extractMentions 提取正文中被@提及的用户名，去重并保持出现顺序
+func extractMentions(body string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		name := strings.TrimRight(match[1], ".-")
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// CleanupTodo 永久删除待办事项的所有评论及其编辑历史
+func (s *CommentService) CleanupTodo(ctx context.Context, todoID uint) error {
+	return s.commentRepo.PurgeByTodoID(ctx, todoID)
+}
@@ -0,0 +1,191 @@
+package impl
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"todo/api/v1/dto/comment"
+	"todo/internal/models"
+	"todo/pkg/errors"
+	"todo/pkg/notify"
+)
+
+// mockCommentRepo 模拟评论仓储接口
+type mockCommentRepo struct {
+	comments  map[uint]*models.Comment
+	revisions []*models.CommentRevision
+	seq       uint
+}
+
+func newMockCommentRepo() *mockCommentRepo {
+	return &mockCommentRepo{comments: make(map[uint]*models.Comment), seq: 1}
+}
+
+func (m *mockCommentRepo) Create(ctx context.Context, comment *models.Comment) error {
+	comment.ID = m.seq
+	m.seq++
+	m.comments[comment.ID] = comment
+	return nil
+}
+
+func (m *mockCommentRepo) GetByID(ctx context.Context, id uint) (*models.Comment, error) {
+	comment, exists := m.comments[id]
+	if !exists {
+		return nil, errors.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+func (m *mockCommentRepo) ListByTodoID(ctx context.Context, todoID uint, limit int) ([]*models.Comment, error) {
+	var comments []*models.Comment
+	for _, comment := range m.comments {
+		if comment.TodoID == todoID {
+			comments = append(comments, comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].ID > comments[j].ID })
+	if limit > 0 && len(comments) > limit {
+		comments = comments[:limit]
+	}
+	return comments, nil
+}
+
+func (m *mockCommentRepo) Update(ctx context.Context, comment *models.Comment) error {
+	m.comments[comment.ID] = comment
+	return nil
+}
+
+func (m *mockCommentRepo) Delete(ctx context.Context, id uint) error {
+	delete(m.comments, id)
+	return nil
+}
+
+func (m *mockCommentRepo) CreateRevision(ctx context.Context, revision *models.CommentRevision) error {
+	m.revisions = append(m.revisions, revision)
+	return nil
+}
+
+func (m *mockCommentRepo) ListRevisions(ctx context.Context, commentID uint) ([]*models.CommentRevision, error) {
+	var revisions []*models.CommentRevision
+	for _, revision := range m.revisions {
+		if revision.CommentID == commentID {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions, nil
+}
+
+func (m *mockCommentRepo) PurgeByTodoID(ctx context.Context, todoID uint) error {
+	for id, comment := range m.comments {
+		if comment.TodoID == todoID {
+			delete(m.comments, id)
+		}
+	}
+	return nil
+}
+
+// TestExtractMentions 测试从评论正文中提取@提及
+func TestExtractMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "单个提及", body: "@alice 请看一下", want: []string{"alice"}},
+		{name: "去重并保持顺序", body: "@bob 和 @alice，还有 @bob", want: []string{"bob", "alice"}},
+		{name: "忽略邮箱地址", body: "发送到 dev@example.com", want: nil},
+		{name: "去掉句末标点", body: "交给 @carol.", want: []string{"carol"}},
+		{name: "Markdown 中的提及", body: "- [ ] **@dave_1** 负责", want: []string{"dave_1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractMentions(tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractMentions() = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCommentService 测试工作空间成员发表和查看评论、作者编辑和删除评论以及@提及通知
+func TestCommentService(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	userRepo := newMockUserRepo()
+	workspaceRepo := newMockWorkspaceRepo()
+	commentRepo := newMockCommentRepo()
+	notifier := &mockNotifier{}
+	service := NewCommentService(commentRepo, todoRepo, userRepo, workspaceRepo, nopTransactor{}, notifier)
+
+	owner := &models.User{Base: models.Base{ID: 1}, Username: "alice"}
+	teammate := &models.User{Base: models.Base{ID: 2}, Username: "bob"}
+	outsider := &models.User{Base: models.Base{ID: 3}, Username: "carol"}
+	for _, user := range []*models.User{owner, teammate, outsider} {
+		_ = userRepo.Create(ctx, user)
+	}
+	_ = workspaceRepo.AddMember(ctx, &models.WorkspaceMember{WorkspaceID: 1, UserID: owner.ID, Role: models.WorkspaceRoleOwner})
+	_ = workspaceRepo.AddMember(ctx, &models.WorkspaceMember{WorkspaceID: 1, UserID: teammate.ID, Role: models.WorkspaceRoleMember})
+	report := &models.Todo{WorkspaceID: 1, UserID: owner.ID, Title: "写周报"}
+	_ = todoRepo.Create(ctx, report)
+
+	// 发表评论并通知被提及的成员，作者提及自己不发通知
+	first, err := service.Create(ctx, owner.ID, report.ID, &comment.CreateRequest{Body: "@bob 请补充数据 @alice"})
+	if err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+	if len(notifier.messages) != 1 || notifier.messages[0].UserID != teammate.ID ||
+		notifier.messages[0].Type != notify.TypeCommentMention || notifier.messages[0].TodoID != report.ID {
+		t.Fatalf("提及通知 = %+v, 期望通知 bob", notifier.messages)
+	}
+
+	// 被提及的成员可以查看并回复
+	comments, err := service.List(ctx, teammate.ID, report.ID)
+	if err != nil || len(comments) != 1 {
+		t.Fatalf("List() 成员 = %v, %v, 期望 1 条评论", comments, err)
+	}
+	reply, err := service.Create(ctx, teammate.ID, report.ID, &comment.CreateRequest{Body: "已补充"})
+	if err != nil {
+		t.Fatalf("Create() 成员回复错误 = %v", err)
+	}
+
+	// 非成员不能查看或发表评论
+	if _, err := service.List(ctx, outsider.ID, report.ID); err != errors.ErrForbidden {
+		t.Errorf("List() 非成员错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+	if _, err := service.Create(ctx, outsider.ID, report.ID, &comment.CreateRequest{Body: "路过"}); err != errors.ErrForbidden {
+		t.Errorf("Create() 非成员错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+
+	// 只有作者可以编辑和删除
+	if _, err := service.Update(ctx, owner.ID, report.ID, reply.ID, &comment.UpdateRequest{Body: "改写"}); err != errors.ErrForbidden {
+		t.Errorf("Update() 非作者错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+	if err := service.Delete(ctx, owner.ID, report.ID, reply.ID); err != errors.ErrForbidden {
+		t.Errorf("Delete() 非作者错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+
+	// 编辑保存历史，只通知新增的提及
+	_ = workspaceRepo.AddMember(ctx, &models.WorkspaceMember{WorkspaceID: 1, UserID: outsider.ID, Role: models.WorkspaceRoleMember})
+	updated, err := service.Update(ctx, owner.ID, report.ID, first.ID, &comment.UpdateRequest{Body: "@bob @carol 请补充数据"})
+	if err != nil {
+		t.Fatalf("Update() 错误 = %v", err)
+	}
+	if updated.Body != "@bob @carol 请补充数据" || updated.EditedAt == nil {
+		t.Errorf("Update() = %+v", updated)
+	}
+	if len(notifier.messages) != 2 || notifier.messages[1].UserID != outsider.ID {
+		t.Errorf("编辑后的提及通知 = %+v, 期望只新增通知 carol", notifier.messages)
+	}
+	history, err := service.History(ctx, teammate.ID, report.ID, first.ID)
+	if err != nil || len(history) != 1 || history[0].Body != "@bob 请补充数据 @alice" {
+		t.Errorf("History() = %v, %v", history, err)
+	}
+
+	if err := service.Delete(ctx, teammate.ID, report.ID, reply.ID); err != nil {
+		t.Fatalf("Delete() 错误 = %v", err)
+	}
+	if _, err := service.Update(ctx, teammate.ID, report.ID, reply.ID, &comment.UpdateRequest{Body: "x"}); err != errors.ErrCommentNotFound {
+		t.Errorf("Update() 已删除的评论错误 = %v, 期望 %v", err, errors.ErrCommentNotFound)
+	}
+}
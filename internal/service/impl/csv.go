@@ -0,0 +1,395 @@
+package impl
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/errors"
+	"unicode/utf8"
+)
+
+// CSV 中可以导入的字段
+const (
+	csvTitle       = "title"
+	csvDescription = "description"
+	csvPriority    = "priority"
+	csvCompleted   = "completed"
+	csvDueDate     = "due_date"
+	csvCategory    = "category"
+	csvTags        = "tags"
+	csvEstimate    = "estimate"
+)
+
+const (
+	csvTagSeparator = ";" // tags 列中多个标签的分隔符
+	exportPageSize  = 500 // 导出时每次从数据库读取的数量
+)
+
+// csvExportColumns 导出的列；导出的文件可以直接导入，created_at 和 completed_at 导入时被忽略
+var csvExportColumns = []string{csvTitle, csvDescription, csvPriority, csvCompleted, csvDueDate,
+	csvCategory, csvTags, csvEstimate, "created_at", "completed_at"}
+
+// csvImportFields 导入时可以映射的字段
+var csvImportFields = map[string]bool{
+	csvTitle: true, csvDescription: true, csvPriority: true, csvCompleted: true,
+	csvDueDate: true, csvCategory: true, csvTags: true, csvEstimate: true,
+}
+
+// csvDateLayouts 导入时 due_date 列接受的时间格式，没有时区的按服务器时区解释
+var csvDateLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"}
+
+// ExportCSV 将匹配列表查询参数的所有待办事项以 CSV 写入 w
+// 按页读取并逐页写出，不会把全部结果读入内存；未指定排序时按ID排列。
+// 查询参数无效时在写入任何内容之前返回错误
+func (s *TodoService) ExportCSV(ctx context.Context, userID uint, req *todo.ListRequest, w io.Writer) error {
+	filter, categories, err := s.exportFilter(ctx, userID, req)
+	if err != nil {
+		return err
+	}
+	names := categoryNameMap(categories)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvExportColumns); err != nil {
+		return err
+	}
+	return s.eachPage(ctx, userID, filter, func(todos []*models.Todo) error {
+		for _, todoItem := range todos {
+			if err := cw.Write(csvRecord(todoItem, names)); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+}
+
+// exportFilter 将列表查询参数转换为导出使用的过滤条件，并返回用户的所有分类
+// 未指定排序时按ID排列，使导出结果稳定
+func (s *TodoService) exportFilter(ctx context.Context, userID uint, req *todo.ListRequest) (repository.TodoFilter, []*models.Category, error) {
+	filter, err := buildTodoFilter(ctx, s.categoryRepo, userID, req)
+	if err != nil {
+		return filter, nil, err
+	}
+	if filter.Sort == repository.SortDefault {
+		filter.Sort = repository.SortID
+	}
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return filter, nil, err
+	}
+	return filter, categories, nil
+}
+
+// categoryNameMap 返回分类ID到分类名的映射
+func categoryNameMap(categories []*models.Category) map[uint]string {
+	names := make(map[uint]string, len(categories))
+	for _, c := range categories {
+		names[c.ID] = c.Name
+	}
+	return names
+}
+
+// eachPage 按页读取匹配过滤条件的所有待办事项，每读取一页调用一次 fn
+func (s *TodoService) eachPage(ctx context.Context, userID uint, filter repository.TodoFilter, fn func([]*models.Todo) error) error {
+	for page := 1; ; page++ {
+		todos, _, err := s.todoRepo.ListByUserID(ctx, userID, filter, page, exportPageSize)
+		if err != nil {
+			return err
+		}
+		if err := fn(todos); err != nil {
+			return err
+		}
+		if len(todos) < exportPageSize {
+			return nil
+		}
+	}
+}
+
+// ImportCSV 从 r 中逐行读取 CSV 并创建待办事项
+// 每一行在各自的事务中创建，校验失败的行被跳过并记录在结果中；分类按名称（不区分大小写）匹配，不存在时新建为顶级分类。
+// 表头与导出的表头完全一致时认为是 ExportCSV 导出的文件，导出时加在公式字符前的单引号会被去掉；其他文件原样导入。
+// 试运行时只校验，不创建任何数据
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID
+//   - r: CSV 内容，第一行为表头
+//   - req: 是否试运行以及表头映射
+//
+// Returns:
+//   - *todo.ImportResponse: 导入结果
+//   - error: 缺少表头、没有 title 列或映射无效时返回 ErrInvalidCSV；数据库错误时中止导入，之前的行已经创建
+func (s *TodoService) ImportCSV(ctx context.Context, userID uint, r io.Reader, req *todo.ImportRequest) (*todo.ImportResponse, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err != nil {
+		if _, ok := err.(*csv.ParseError); ok || err == io.EOF {
+			return nil, errors.ErrInvalidCSV
+		}
+		return nil, err
+	}
+	columns, err := csvColumns(header, req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	exported := exportedCSVHeader(header)
+
+	im, err := s.newImporter(ctx, userID, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			im.resp.Rows++
+			im.fail(todo.ImportError{Row: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		im.resp.Rows++
+		line, _ := cr.FieldPos(0)
+
+		row, rowErr := parseCSVRow(record, columns, exported)
+		if rowErr != nil {
+			rowErr.Row = line
+			im.fail(*rowErr)
+			continue
+		}
+		if err := im.add(ctx, line, row); err != nil {
+			return nil, err
+		}
+	}
+	return im.resp, nil
+}
+
+// csvColumns 根据表头和映射确定每一列对应的字段，不导入的列为空字符串
+// 映射中指定的列优先，其余列按列名（不区分大小写）匹配同名字段；同一字段只取第一列
+func csvColumns(header []string, mapping string) ([]string, error) {
+	explicit := make(map[string]string)
+	if strings.TrimSpace(mapping) != "" {
+		for _, pair := range strings.Split(mapping, ",") {
+			name, field, ok := strings.Cut(pair, "=")
+			field = strings.ToLower(strings.TrimSpace(field))
+			if !ok || !csvImportFields[field] {
+				return nil, errors.ErrInvalidCSV
+			}
+			explicit[strings.ToLower(strings.TrimSpace(name))] = field
+		}
+	}
+
+	names := make([]string, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // 电子表格软件导出的 UTF-8 BOM
+		}
+		names[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+
+	columns := make([]string, len(header))
+	used := make(map[string]bool)
+	for i, name := range names {
+		if field, ok := explicit[name]; ok && !used[field] {
+			columns[i] = field
+			used[field] = true
+		}
+	}
+	for i, name := range names {
+		if _, mapped := explicit[name]; !mapped && csvImportFields[name] && !used[name] {
+			columns[i] = name
+			used[name] = true
+		}
+	}
+	if !used[csvTitle] {
+		return nil, errors.ErrInvalidCSV
+	}
+	return columns, nil
+}
+
+// parseCSVRow 按列对应的字段解析并校验一行数据，规则与创建待办事项接口一致
+// exported 为 true 时文件来自 ExportCSV，去掉导出时为防止 CSV 注入添加的单引号
+func parseCSVRow(record []string, columns []string, exported bool) (*importedTodo, *todo.ImportError) {
+	row := &importedTodo{}
+	invalid := func(field, format string, args ...interface{}) (*importedTodo, *todo.ImportError) {
+		return nil, &todo.ImportError{Column: field, Message: fmt.Sprintf(format, args...)}
+	}
+
+	for i, field := range columns {
+		if field == "" || i >= len(record) {
+			continue
+		}
+		value := strings.TrimSpace(record[i])
+		if exported {
+			value = unescapeCSVCell(value)
+		}
+		switch field {
+		case csvTitle:
+			row.create.Title = value
+		case csvDescription:
+			if utf8.RuneCountInString(value) > 1024 {
+				return invalid(field, "描述不能超过 1024 个字符")
+			}
+			row.create.Description = value
+		case csvPriority:
+			value = strings.ToLower(value)
+			if value != "" && value != string(models.PriorityLow) && value != string(models.PriorityMedium) && value != string(models.PriorityHigh) {
+				return invalid(field, "无效的优先级 %q，可选 low、medium、high", value)
+			}
+			row.create.Priority = value
+		case csvCompleted:
+			completed, ok := parseCSVBool(value)
+			if !ok {
+				return invalid(field, "无效的完成状态 %q", value)
+			}
+			row.completed = completed
+		case csvDueDate:
+			if value == "" {
+				continue
+			}
+			due, ok := parseCSVTime(value)
+			if !ok {
+				return invalid(field, "无效的截止时间 %q，使用 RFC3339 或 2006-01-02 格式", value)
+			}
+			row.create.DueDate = &due
+		case csvCategory:
+			if utf8.RuneCountInString(value) > 32 {
+				return invalid(field, "分类名不能超过 32 个字符")
+			}
+			row.category = value
+		case csvTags:
+			tags := normalizeTags(strings.Split(value, csvTagSeparator))
+			if len(tags) > 20 {
+				return invalid(field, "标签不能超过 20 个")
+			}
+			for _, tag := range tags {
+				if utf8.RuneCountInString(tag) > 32 {
+					return invalid(field, "标签 %q 超过 32 个字符", tag)
+				}
+			}
+			row.create.Tags = tags
+		case csvEstimate:
+			if value == "" {
+				continue
+			}
+			estimate, err := strconv.Atoi(value)
+			if err != nil || estimate < 0 {
+				return invalid(field, "无效的预估耗时 %q，应为非负整数（分钟）", value)
+			}
+			row.create.Estimate = &estimate
+		}
+	}
+
+	if row.create.Title == "" {
+		return invalid(csvTitle, "标题不能为空")
+	}
+	if utf8.RuneCountInString(row.create.Title) > 128 {
+		return invalid(csvTitle, "标题不能超过 128 个字符")
+	}
+	return row, nil
+}
+
+// csvRecord 将待办事项转换为导出的一行
+func csvRecord(todoItem *models.Todo, categoryNames map[uint]string) []string {
+	tags := make([]string, 0, len(todoItem.Tags))
+	for _, tag := range todoItem.Tags {
+		tags = append(tags, tag.Name)
+	}
+	var category, estimate string
+	if todoItem.CategoryID != nil {
+		category = categoryNames[*todoItem.CategoryID]
+	}
+	if todoItem.Estimate != nil {
+		estimate = strconv.Itoa(*todoItem.Estimate)
+	}
+	return []string{
+		escapeCSVCell(todoItem.Title),
+		escapeCSVCell(todoItem.Description),
+		string(todoItem.Priority),
+		strconv.FormatBool(todoItem.Completed),
+		formatCSVTime(todoItem.DueDate),
+		escapeCSVCell(category),
+		escapeCSVCell(strings.Join(tags, csvTagSeparator)),
+		estimate,
+		formatCSVTime(&todoItem.CreatedAt),
+		formatCSVTime(todoItem.CompletedAt),
+	}
+}
+
+// csvFormulaPrefixes 电子表格会将以这些字符开头的单元格当作公式执行
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// escapeCSVCell 在以公式字符开头的单元格前加上单引号，防止导出文件在电子表格中打开时执行公式（CSV 注入）
+func escapeCSVCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// exportedCSVHeader 判断表头是否与 ExportCSV 输出的表头完全一致
+// 只有这样的文件才认为经过了 escapeCSVCell 转义；其他来源的文件中以单引号开头的内容原样导入
+func exportedCSVHeader(header []string) bool {
+	if len(header) != len(csvExportColumns) {
+		return false
+	}
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		if name != csvExportColumns[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// unescapeCSVCell 去掉 escapeCSVCell 添加的单引号，其他以单引号开头的内容保持不变
+// 只对 exportedCSVHeader 判断为导出文件的内容调用
+func unescapeCSVCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// formatCSVTime 以 RFC3339 格式输出时间，为空时输出空字符串
+func formatCSVTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parseCSVTime 按 csvDateLayouts 中的格式依次尝试解析时间
+func parseCSVTime(value string) (time.Time, bool) {
+	for _, layout := range csvDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseCSVBool 解析完成状态，接受电子表格中常见的写法，空值表示未完成
+func parseCSVBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "", "false", "0", "no", "n":
+		return false, true
+	case "true", "1", "yes", "y", "x", "done":
+		return true, true
+	}
+	return false, false
+}
@@ -0,0 +1,161 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+)
+
+// TestTodoService_ImportCSV 测试表头映射、逐行校验、试运行和按名称新建分类
+func TestTodoService_ImportCSV(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	categoryRepo := newMockCategoryRepo()
+	service := NewTodoService(todoRepo, newMockReminderRepo(), categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+
+	work := &models.Category{Name: "Work", UserID: 1}
+	categoryRepo.Create(ctx, work)
+
+	input := "\ufeffTask,Notes,Priority,Done,Due,Category,Tags,Ignored\n" +
+		"写周报,本周进展,high,yes,2024-06-07,work,例行;写作,x\n" +
+		",缺少标题,,,,,,\n" +
+		"买咖啡豆,,urgent,,,,,\n" +
+		"整理书架,,low,,,家务,,\n" +
+		"续签合同,,,,next week,,,\n"
+	mapping := "Task=title,Notes=description,Done=completed,Due=due_date"
+
+	t.Run("无效的表头", func(t *testing.T) {
+		if _, err := service.ImportCSV(ctx, 1, strings.NewReader(""), &todo.ImportRequest{}); err != errors.ErrInvalidCSV {
+			t.Errorf("空文件错误 = %v, 期望 %v", err, errors.ErrInvalidCSV)
+		}
+		if _, err := service.ImportCSV(ctx, 1, strings.NewReader(input), &todo.ImportRequest{}); err != errors.ErrInvalidCSV {
+			t.Errorf("没有 title 列错误 = %v, 期望 %v", err, errors.ErrInvalidCSV)
+		}
+		if _, err := service.ImportCSV(ctx, 1, strings.NewReader(input), &todo.ImportRequest{Mapping: "Task=name"}); err != errors.ErrInvalidCSV {
+			t.Errorf("映射到未知字段错误 = %v, 期望 %v", err, errors.ErrInvalidCSV)
+		}
+	})
+
+	dry, err := service.ImportCSV(ctx, 1, strings.NewReader(input), &todo.ImportRequest{DryRun: true, Mapping: mapping})
+	if err != nil {
+		t.Fatalf("试运行 ImportCSV() 错误 = %v", err)
+	}
+	if dry.Rows != 5 || dry.Imported != 2 || dry.Failed != 3 || len(dry.CreatedCategories) != 1 || dry.CreatedCategories[0] != "家务" {
+		t.Errorf("试运行结果 = %+v, 期望 5 行中 2 行通过并将新建分类 家务", dry)
+	}
+	wantErrors := []todo.ImportError{
+		{Row: 3, Column: "title"},
+		{Row: 4, Column: "priority"},
+		{Row: 6, Column: "due_date"},
+	}
+	for i, want := range wantErrors {
+		if i >= len(dry.Errors) || dry.Errors[i].Row != want.Row || dry.Errors[i].Column != want.Column {
+			t.Errorf("第 %d 个错误 = %+v, 期望第 %d 行的 %s 列", i, dry.Errors, want.Row, want.Column)
+		}
+	}
+	if len(todoRepo.todos) != 0 || len(categoryRepo.categories) != 1 {
+		t.Fatalf("试运行不应创建数据")
+	}
+
+	result, err := service.ImportCSV(ctx, 1, strings.NewReader(input), &todo.ImportRequest{Mapping: mapping})
+	if err != nil {
+		t.Fatalf("ImportCSV() 错误 = %v", err)
+	}
+	if result.Imported != 2 || len(todoRepo.todos) != 2 || len(categoryRepo.categories) != 2 {
+		t.Fatalf("导入结果 = %+v, 期望创建 2 个待办事项和 1 个分类", result)
+	}
+	var report *models.Todo
+	for _, item := range todoRepo.todos {
+		if item.Title == "写周报" {
+			report = item
+		}
+	}
+	if report == nil || !report.Completed || report.Priority != models.PriorityHigh || !sameID(report.CategoryID, &work.ID) ||
+		report.DueDate == nil || report.DueDate.Day() != 7 || len(report.Tags) != 2 || report.Description != "本周进展" {
+		t.Errorf("导入的待办事项 = %+v, 期望与 CSV 第 2 行一致", report)
+	}
+
+	t.Run("导出后可以重新导入", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := service.ExportCSV(ctx, 1, &todo.ListRequest{}, &buf); err != nil {
+			t.Fatalf("ExportCSV() 错误 = %v", err)
+		}
+		records, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+		if err != nil || len(records) != 3 || records[1][0] != "写周报" || records[1][5] != "Work" || records[1][6] != "例行;写作" {
+			t.Fatalf("导出内容 = %v, 错误 = %v", records, err)
+		}
+
+		again, err := service.ImportCSV(ctx, 2, bytes.NewReader(buf.Bytes()), &todo.ImportRequest{DryRun: true})
+		if err != nil || again.Imported != 2 || again.Failed != 0 {
+			t.Errorf("重新导入结果 = %+v, 错误 = %v, 期望 2 行全部通过", again, err)
+		}
+	})
+}
+
+// TestTodoService_CSVFormulaEscape 测试导出时转义公式字符开头的单元格，重新导入后内容不变
+func TestTodoService_CSVFormulaEscape(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	categoryRepo := newMockCategoryRepo()
+	service := NewTodoService(todoRepo, newMockReminderRepo(), categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+
+	misc := &models.Category{Name: "-杂项", UserID: 1}
+	categoryRepo.Create(ctx, misc)
+	title := `=HYPERLINK("http://example.com","点击")`
+	if _, err := service.Create(ctx, 1, &todo.CreateRequest{Title: title, Description: "+1 跟进", CategoryID: &misc.ID, Tags: []string{"@home"}}); err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+	if _, err := service.Create(ctx, 1, &todo.CreateRequest{Title: "'普通引号"}); err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := service.ExportCSV(ctx, 1, &todo.ListRequest{}, &buf); err != nil {
+		t.Fatalf("ExportCSV() 错误 = %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	if err != nil || len(records) != 3 {
+		t.Fatalf("导出内容 = %v, 错误 = %v", records, err)
+	}
+	if got := records[1]; got[0] != "'"+title || got[1] != "'+1 跟进" || got[5] != "'-杂项" || got[6] != "'@home" {
+		t.Errorf("导出的单元格 = %q, 期望以单引号转义公式字符", got)
+	}
+	if records[2][0] != "'普通引号" {
+		t.Errorf("导出的单元格 = %q, 期望不转义", records[2][0])
+	}
+
+	if _, err := service.ImportCSV(ctx, 2, bytes.NewReader(buf.Bytes()), &todo.ImportRequest{}); err != nil {
+		t.Fatalf("ImportCSV() 错误 = %v", err)
+	}
+	imported := map[string]*models.Todo{}
+	for _, item := range todoRepo.todos {
+		if item.UserID == 2 {
+			imported[item.Title] = item
+		}
+	}
+	formula := imported[title]
+	if formula == nil || formula.Description != "+1 跟进" || len(formula.Tags) != 1 || formula.Tags[0].Name != "@home" {
+		t.Fatalf("重新导入的待办事项 = %+v, 期望与导出前一致", formula)
+	}
+	if category, _ := categoryRepo.GetByID(ctx, *formula.CategoryID); category == nil || category.Name != "-杂项" {
+		t.Errorf("重新导入的分类 = %+v, 期望 -杂项", category)
+	}
+	if imported["'普通引号"] == nil {
+		t.Errorf("重新导入的标题 = %v, 期望保留开头的单引号", imported)
+	}
+
+	// 其他来源的文件不做反转义，单引号原样保留
+	if _, err := service.ImportCSV(ctx, 3, strings.NewReader("title,notes\n'=1+1,x\n"), &todo.ImportRequest{}); err != nil {
+		t.Fatalf("ImportCSV() 错误 = %v", err)
+	}
+	for _, item := range todoRepo.todos {
+		if item.UserID == 3 && item.Title != "'=1+1" {
+			t.Errorf("手写文件导入的标题 = %q, 期望保留单引号", item.Title)
+		}
+	}
+}
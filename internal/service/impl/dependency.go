@@ -0,0 +1,225 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"todo/api/v1/dto/dependency"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/errors"
+	"todo/pkg/logger"
+	"todo/pkg/notify"
+)
+
+// DependencyService 待办事项依赖关系服务实现
+type DependencyService struct {
+	depRepo  repository.DependencyRepository
+	todoRepo repository.TodoRepository
+	tx       repository.Transactor
+	notifier notify.Notifier
+}
+
+// NewDependencyService 创建一个新的依赖关系服务实例
+//
+// Parameters:
+//   - depRepo: 依赖关系仓库实现
+//   - todoRepo: 待办事项仓库实现，用于校验所有权
+//   - tx: 事务执行器
+//   - notifier: 通知器，移除阻塞项后待办事项不再被阻塞时通知其所有者
+//
+// Returns:
+//   - *DependencyService: 返回依赖关系服务实例
+func NewDependencyService(depRepo repository.DependencyRepository, todoRepo repository.TodoRepository,
+	tx repository.Transactor, notifier notify.Notifier) *DependencyService {
+	return &DependencyService{
+		depRepo:  depRepo,
+		todoRepo: todoRepo,
+		tx:       tx,
+		notifier: notifier,
+	}
+}
+
+// Add 添加阻塞项：blockerID 完成之前 todoID 不能完成
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID，两个待办事项都必须属于该用户
+//   - todoID: 被阻塞的待办事项ID
+//   - blockerID: 阻塞它的待办事项ID
+//
+// Returns:
+//   - *models.Dependency: 新建的依赖关系
+//   - error: 关系已存在返回 ErrDependencyExists，会形成环（包括依赖自身）返回 ErrDependencyCycle
+func (s *DependencyService) Add(ctx context.Context, userID, todoID, blockerID uint) (*models.Dependency, error) {
+	if todoID == blockerID {
+		return nil, errors.ErrDependencyCycle
+	}
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getTodo(ctx, userID, blockerID); err != nil {
+		return nil, err
+	}
+
+	dep := &models.Dependency{TodoID: todoID, BlockerID: blockerID}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		deps, err := s.depRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		blockers := make(map[uint][]uint, len(deps))
+		for _, d := range deps {
+			if d.TodoID == todoID && d.BlockerID == blockerID {
+				return errors.ErrDependencyExists
+			}
+			blockers[d.TodoID] = append(blockers[d.TodoID], d.BlockerID)
+		}
+		// blockerID 直接或间接被 todoID 阻塞时，新关系会形成环
+		if reachable(blockers, blockerID, todoID) {
+			return errors.ErrDependencyCycle
+		}
+		return s.depRepo.Create(ctx, dep)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dep, nil
+}
+
+// Remove 移除阻塞项，待办事项因此不再被阻塞时通知其所有者
+func (s *DependencyService) Remove(ctx context.Context, userID, todoID, blockerID uint) error {
+	todoItem, err := s.getTodo(ctx, userID, todoID)
+	if err != nil {
+		return err
+	}
+	blocker, err := s.todoRepo.GetByID(ctx, blockerID)
+	if err != nil && err != errors.ErrTodoNotFound {
+		return err
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.depRepo.Delete(ctx, todoID, blockerID); err != nil {
+			return err
+		}
+		// 已完成或已删除的阻塞项本就不再阻塞，移除它不会改变阻塞状态
+		if blocker == nil || blocker.Completed || todoItem.Completed {
+			return nil
+		}
+		open, err := s.depRepo.CountOpenBlockers(ctx, todoID)
+		if err != nil || open > 0 {
+			return err
+		}
+		notifyUnblocked(ctx, s.notifier, todoItem, fmt.Sprintf("已移除阻塞项「%s」", blocker.Title))
+		return nil
+	})
+}
+
+// List 获取待办事项的依赖关系
+func (s *DependencyService) List(ctx context.Context, userID, todoID uint) (*dependency.ListResponse, error) {
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	blockedBy, err := s.depRepo.ListBlockers(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	blocking, err := s.depRepo.ListBlocking(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	return &dependency.ListResponse{
+		Blocked:   len(openTodos(blockedBy)) > 0,
+		BlockedBy: blockedBy,
+		Blocking:  blocking,
+	}, nil
+}
+
+// OpenBlockers 获取阻塞待办事项且尚未完成的待办事项，为空表示未被阻塞
+func (s *DependencyService) OpenBlockers(ctx context.Context, userID, todoID uint) ([]*models.Todo, error) {
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	blockers, err := s.depRepo.ListBlockers(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	return openTodos(blockers), nil
+}
+
+// getTodo 获取待办事项并校验所有权
+func (s *DependencyService) getTodo(ctx context.Context, userID, todoID uint) (*models.Todo, error) {
+	todoItem, err := s.todoRepo.GetByID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todoItem.UserID != userID {
+		return nil, errors.ErrForbidden
+	}
+	return todoItem, nil
+}
+
+// notifyDependents 通知被 blocker 阻塞、且已没有其他未完成阻塞项的待办事项，需在 blocker 完成或删除后调用
+func (s *TodoService) notifyDependents(ctx context.Context, blocker *models.Todo, reason string) error {
+	blocked, err := s.depRepo.ListBlocking(ctx, blocker.ID)
+	if err != nil {
+		return err
+	}
+	for _, todoItem := range openTodos(blocked) {
+		open, err := s.depRepo.CountOpenBlockers(ctx, todoItem.ID)
+		if err != nil {
+			return err
+		}
+		if open == 0 {
+			notifyUnblocked(ctx, s.notifier, todoItem, reason)
+		}
+	}
+	return nil
+}
+
+// notifyUnblocked 在事务提交后通知待办事项的所有者它已不再被阻塞
+// 通知失败不影响业务操作，只记录日志
+func notifyUnblocked(ctx context.Context, notifier notify.Notifier, todoItem *models.Todo, reason string) {
+	msg := &notify.Message{
+		UserID: todoItem.UserID,
+		Type:   notify.TypeTodoUnblocked,
+		Title:  fmt.Sprintf("「%s」已不再被阻塞", todoItem.Title),
+		Body:   reason,
+		TodoID: todoItem.ID,
+	}
+	repository.AfterCommit(ctx, func() {
+		if err := notifier.Notify(ctx, msg); err != nil {
+			logger.Warn().Err(err).Uint("todo_id", todoItem.ID).Msg("发送解除阻塞通知失败")
+		}
+	})
+}
+
+// reachable 判断沿 edges 能否从 from 到达 to
+func reachable(edges map[uint][]uint, from, to uint) bool {
+	visited := map[uint]bool{from: true}
+	queue := []uint{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == to {
+			return true
+		}
+		for _, next := range edges[id] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// openTodos 返回其中尚未完成的待办事项
+func openTodos(todos []*models.Todo) []*models.Todo {
+	open := make([]*models.Todo, 0, len(todos))
+	for _, todoItem := range todos {
+		if !todoItem.Completed {
+			open = append(open, todoItem)
+		}
+	}
+	return open
+}
@@ -0,0 +1,173 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+	"todo/pkg/notify"
+)
+
+// mockDependencyRepo 模拟依赖关系仓储接口，通过 todos 查询关联的待办事项
+type mockDependencyRepo struct {
+	deps  []*models.Dependency
+	todos *mockTodoRepo
+}
+
+func newMockDependencyRepo() *mockDependencyRepo {
+	return &mockDependencyRepo{todos: newMockTodoRepo()}
+}
+
+func (m *mockDependencyRepo) Create(ctx context.Context, dep *models.Dependency) error {
+	dep.ID = uint(len(m.deps) + 1)
+	m.deps = append(m.deps, dep)
+	return nil
+}
+
+func (m *mockDependencyRepo) Delete(ctx context.Context, todoID, blockerID uint) error {
+	for i, dep := range m.deps {
+		if dep.TodoID == todoID && dep.BlockerID == blockerID {
+			m.deps = append(m.deps[:i], m.deps[i+1:]...)
+			return nil
+		}
+	}
+	return errors.ErrDependencyNotFound
+}
+
+func (m *mockDependencyRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.Dependency, error) {
+	var deps []*models.Dependency
+	for _, dep := range m.deps {
+		if t, ok := m.todos.todos[dep.TodoID]; ok && t.UserID == userID {
+			deps = append(deps, dep)
+		}
+	}
+	return deps, nil
+}
+
+func (m *mockDependencyRepo) ListBlockers(ctx context.Context, todoID uint) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	for _, dep := range m.deps {
+		if t, ok := m.todos.todos[dep.BlockerID]; ok && dep.TodoID == todoID && !t.DeletedAt.Valid {
+			todos = append(todos, t)
+		}
+	}
+	return todos, nil
+}
+
+func (m *mockDependencyRepo) ListBlocking(ctx context.Context, blockerID uint) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	for _, dep := range m.deps {
+		if t, ok := m.todos.todos[dep.TodoID]; ok && dep.BlockerID == blockerID && !t.DeletedAt.Valid {
+			todos = append(todos, t)
+		}
+	}
+	return todos, nil
+}
+
+func (m *mockDependencyRepo) CountOpenBlockers(ctx context.Context, todoID uint) (int64, error) {
+	blockers, _ := m.ListBlockers(ctx, todoID)
+	return int64(len(openTodos(blockers))), nil
+}
+
+func (m *mockDependencyRepo) DeleteByTodoID(ctx context.Context, todoID uint) error {
+	kept := m.deps[:0]
+	for _, dep := range m.deps {
+		if dep.TodoID != todoID && dep.BlockerID != todoID {
+			kept = append(kept, dep)
+		}
+	}
+	m.deps = kept
+	return nil
+}
+
+// mockNotifier 记录投递的通知
+type mockNotifier struct {
+	messages []*notify.Message
+}
+
+func (m *mockNotifier) Notify(ctx context.Context, msg *notify.Message) error {
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// TestDependencyService 测试环检测、阻止完成被阻塞的待办事项以及解除阻塞通知
+func TestDependencyService(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	depRepo := &mockDependencyRepo{todos: todoRepo}
+	notifier := &mockNotifier{}
+	todoService := NewTodoService(todoRepo, newMockReminderRepo(), newMockCategoryRepo(), newMockStatusRepo(), depRepo, newMockHistoryRepo(), nopTransactor{}, notifier)
+	depService := NewDependencyService(depRepo, todoRepo, nopTransactor{}, notifier)
+
+	design, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "设计"})
+	build, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "开发"})
+	release, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "发布"})
+	other, _ := todoService.Create(ctx, 2, &todo.CreateRequest{Title: "他人的待办"})
+
+	// 发布 被 开发 阻塞，开发 被 设计 阻塞
+	if _, err := depService.Add(ctx, 1, release, build); err != nil {
+		t.Fatalf("Add() 错误 = %v", err)
+	}
+	if _, err := depService.Add(ctx, 1, build, design); err != nil {
+		t.Fatalf("Add() 错误 = %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		todoID, blocker uint
+		want            error
+	}{
+		{"依赖自身", design, design, errors.ErrDependencyCycle},
+		{"间接形成环", design, release, errors.ErrDependencyCycle},
+		{"重复添加", release, build, errors.ErrDependencyExists},
+		{"他人的待办事项", release, other, errors.ErrForbidden},
+		{"待办事项不存在", release, 99, errors.ErrTodoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := depService.Add(ctx, 1, tt.todoID, tt.blocker); err != tt.want {
+				t.Errorf("Add() 错误 = %v, 期望 %v", err, tt.want)
+			}
+		})
+	}
+
+	blockers, _ := depService.OpenBlockers(ctx, 1, release)
+	if len(blockers) != 1 || blockers[0].ID != build {
+		t.Errorf("OpenBlockers() = %v, 期望 [%d]", blockers, build)
+	}
+
+	completed := true
+	if err := todoService.Update(ctx, build, 1, &todo.UpdateRequest{Completed: &completed}); err != errors.ErrTodoBlocked {
+		t.Fatalf("完成被阻塞的待办事项错误 = %v, 期望 %v", err, errors.ErrTodoBlocked)
+	}
+	if got, _ := todoService.Get(ctx, build, 1); got.Completed {
+		t.Errorf("被阻塞的待办事项不应被标记为已完成")
+	}
+
+	if err := todoService.Update(ctx, design, 1, &todo.UpdateRequest{Completed: &completed}); err != nil {
+		t.Fatalf("Update() 错误 = %v", err)
+	}
+	if len(notifier.messages) != 1 || notifier.messages[0].TodoID != build || notifier.messages[0].Type != notify.TypeTodoUnblocked {
+		t.Fatalf("通知 = %+v, 期望通知待办事项 %d 已解除阻塞", notifier.messages, build)
+	}
+	if err := todoService.Update(ctx, build, 1, &todo.UpdateRequest{Completed: &completed}); err != nil {
+		t.Fatalf("阻塞项完成后 Update() 错误 = %v", err)
+	}
+
+	t.Run("移除最后一个阻塞项", func(t *testing.T) {
+		reopen := false
+		todoService.Update(ctx, build, 1, &todo.UpdateRequest{Completed: &reopen})
+		notifier.messages = nil
+
+		if err := depService.Remove(ctx, 1, release, build); err != nil {
+			t.Fatalf("Remove() 错误 = %v", err)
+		}
+		if len(notifier.messages) != 1 || notifier.messages[0].TodoID != release {
+			t.Errorf("通知 = %+v, 期望通知待办事项 %d 已解除阻塞", notifier.messages, release)
+		}
+		if err := depService.Remove(ctx, 1, release, build); err != errors.ErrDependencyNotFound {
+			t.Errorf("Remove() 错误 = %v, 期望 %v", err, errors.ErrDependencyNotFound)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package impl
+
+import (
+	"context"
+	"time"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+)
+
+// Duplicate 复制待办事项，连同分类、标签、预估耗时和提醒一起在一个事务中创建
+// 副本为未完成状态，排在所在分类的末尾；原待办事项已完成时副本使用默认的工作流状态。
+// 已经发送过或平移后仍在过去的一次性提醒不会复制；依赖关系、评论、附件和时间记录不会复制
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - id: 原待办事项ID
+//   - userID: 用户ID
+//   - req: 截止时间和提醒时间的平移量
+//
+// Returns:
+//   - *models.Todo: 新建的副本，附带复制的提醒
+//   - error: 原待办事项不存在、不属于当前用户或工作流状态已达到在制品上限时返回错误
+func (s *TodoService) Duplicate(ctx context.Context, id, userID uint, req *todo.DuplicateRequest) (*models.Todo, error) {
+	original, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	reminders, err := s.reminderRepo.ListByTodoID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	shift := time.Duration(req.ShiftMinutes) * time.Minute
+
+	copied := &models.Todo{
+		Title:       original.Title,
+		Description: original.Description,
+		Priority:    original.Priority,
+		UserID:      userID,
+		CategoryID:  original.CategoryID,
+		Estimate:    original.Estimate,
+	}
+	if original.DueDate != nil {
+		due := original.DueDate.Add(shift)
+		copied.DueDate = &due
+	}
+	statusID := original.StatusID
+	if original.Completed {
+		statusID = nil
+	}
+	tags := make([]string, 0, len(original.Tags))
+	for _, tag := range original.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.initialStatus(ctx, copied, statusID); err != nil {
+			return err
+		}
+		if err := s.appendPosition(ctx, copied); err != nil {
+			return err
+		}
+		if err := s.todoRepo.Create(ctx, copied); err != nil {
+			return err
+		}
+		if len(tags) > 0 {
+			if err := s.todoRepo.SetTags(ctx, copied, tags); err != nil {
+				return err
+			}
+		}
+		// 先记录待办事项的创建，使 todo.created 事件先于复制的提醒的 reminder.created 事件发布
+		if err := s.history.record(ctx, models.EntityTodo, copied.ID, userID, models.ChangeActionCreate, nil, copied); err != nil {
+			return err
+		}
+		copied.Reminders = make([]models.Reminder, 0, len(reminders))
+		now := time.Now()
+		for _, r := range reminders {
+			reminder := &models.Reminder{
+				TodoID:     copied.ID,
+				RemindAt:   r.RemindAt.Add(shift),
+				RemindType: r.RemindType,
+				NotifyType: r.NotifyType,
+			}
+			// 已经发送过或平移后仍在过去的一次性提醒不再复制，否则副本创建后会立即收到过期的提醒
+			if reminder.RemindType == models.RemindTypeOnceStr && (r.Status || reminder.RemindAt.Before(now)) {
+				continue
+			}
+			if err := reminder.Validate(); err != nil {
+				return err
+			}
+			if err := s.reminderRepo.Create(ctx, reminder); err != nil {
+				return err
+			}
+			if err := s.history.record(ctx, models.EntityReminder, reminder.ID, userID, models.ChangeActionCreate, nil, reminder); err != nil {
+				return err
+			}
+			copied.Reminders = append(copied.Reminders, *reminder)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
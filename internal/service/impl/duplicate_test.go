@@ -0,0 +1,100 @@
+package impl
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+)
+
+// TestTodoService_Duplicate 测试复制待办事项及其提醒、标签，并平移时间
+func TestTodoService_Duplicate(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	reminderRepo := newMockReminderRepo()
+	categoryRepo := newMockCategoryRepo()
+	historyRepo := newMockHistoryRepo()
+	service := NewTodoService(todoRepo, reminderRepo, categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), historyRepo, nopTransactor{}, &mockNotifier{})
+	events := &recordingPublisher{}
+	service.SetEventPublisher(events)
+
+	work := &models.Category{Name: "工作", UserID: 1}
+	categoryRepo.Create(ctx, work)
+	due := time.Date(2024, 6, 3, 17, 0, 0, 0, time.UTC)
+	estimate := 30
+	id, _ := service.Create(ctx, 1, &todo.CreateRequest{
+		Title: "周报", Priority: "high", CategoryID: &work.ID, DueDate: &due, Tags: []string{"例行"}, Estimate: &estimate,
+	})
+	tomorrow := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	for _, r := range []*models.Reminder{
+		{RemindAt: due.Add(-time.Hour), RemindType: models.RemindTypeWeeklyStr, NotifyType: models.NotifyTypePushStr, Status: true},
+		{RemindAt: tomorrow, RemindType: models.RemindTypeOnceStr, NotifyType: models.NotifyTypeEmailStr},
+		// 已发送的和平移后仍在过去的一次性提醒不复制
+		{RemindAt: tomorrow, RemindType: models.RemindTypeOnceStr, NotifyType: models.NotifyTypePushStr, Status: true},
+		{RemindAt: due, RemindType: models.RemindTypeOnceStr, NotifyType: models.NotifyTypePushStr},
+	} {
+		r.TodoID = id
+		reminderRepo.Create(ctx, r)
+	}
+	completed := true
+	service.Update(ctx, id, 1, &todo.UpdateRequest{Completed: &completed})
+
+	if _, err := service.Duplicate(ctx, id, 2, &todo.DuplicateRequest{}); err != errors.ErrForbidden {
+		t.Errorf("复制他人的待办事项错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+
+	copied, err := service.Duplicate(ctx, id, 1, &todo.DuplicateRequest{ShiftMinutes: 7 * 24 * 60})
+	if err != nil {
+		t.Fatalf("Duplicate() 错误 = %v", err)
+	}
+	if copied.ID == id || copied.Title != "周报" || copied.Priority != models.PriorityHigh || copied.Completed ||
+		!sameID(copied.CategoryID, &work.ID) || copied.Estimate == nil || *copied.Estimate != 30 {
+		t.Errorf("Duplicate() = %+v, 期望未完成的副本并保留分类、优先级和预估耗时", copied)
+	}
+	if copied.DueDate == nil || !copied.DueDate.Equal(due.AddDate(0, 0, 7)) {
+		t.Errorf("副本截止时间 = %v, 期望平移一周", copied.DueDate)
+	}
+
+	stored, _ := todoRepo.GetByID(ctx, copied.ID)
+	if len(stored.Tags) != 1 || stored.Tags[0].Name != "例行" {
+		t.Errorf("副本标签 = %v, 期望 [例行]", stored.Tags)
+	}
+	reminders, _ := reminderRepo.ListByTodoID(ctx, copied.ID)
+	sort.Slice(reminders, func(i, j int) bool { return reminders[i].RemindAt.Before(reminders[j].RemindAt) })
+	if len(reminders) != 2 || reminders[0].Status || !reminders[0].RemindAt.Equal(due.AddDate(0, 0, 7).Add(-time.Hour)) ||
+		reminders[1].Status || !reminders[1].RemindAt.Equal(tomorrow.AddDate(0, 0, 7)) {
+		t.Errorf("副本提醒 = %+v, 期望每周提醒和未来的一次性提醒各一条，未发送且平移一周", reminders)
+	}
+	// 复制的提醒与其他提醒一样记录变更历史并发布事件
+	if len(reminders) > 0 {
+		if entries, _ := historyRepo.ListByEntity(ctx, models.EntityReminder, reminders[0].ID); len(entries) != 1 ||
+			entries[0].Action != models.ChangeActionCreate {
+			t.Errorf("副本提醒的变更历史 = %+v, 期望一条创建记录", entries)
+		}
+	}
+	if n := len(events.events); n < 3 || events.events[n-3] != models.WebhookEventTodoCreated ||
+		events.events[n-2] != models.WebhookEventReminderCreated || events.events[n-1] != models.WebhookEventReminderCreated {
+		t.Errorf("复制发布的事件 = %v, 期望以 %s 和两个 %s 结尾", events.events, models.WebhookEventTodoCreated, models.WebhookEventReminderCreated)
+	}
+	original, _ := reminderRepo.ListByTodoID(ctx, id)
+	sort.Slice(original, func(i, j int) bool { return original[i].RemindAt.Before(original[j].RemindAt) })
+	if len(original) != 4 || !original[0].RemindAt.Equal(due.Add(-time.Hour)) {
+		t.Errorf("原待办事项的提醒不应改变: %+v", original)
+	}
+}
+
+// TestTodoService_DuplicateInvalidReminder 测试复制时校验提醒
+func TestTodoService_DuplicateInvalidReminder(t *testing.T) {
+	ctx := context.Background()
+	reminderRepo := newMockReminderRepo()
+	service := NewTodoService(newMockTodoRepo(), reminderRepo, newMockCategoryRepo(), newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+	id, _ := service.Create(ctx, 1, &todo.CreateRequest{Title: "周报"})
+	reminderRepo.Create(ctx, &models.Reminder{TodoID: id, RemindAt: time.Now().Add(time.Hour), RemindType: models.RemindTypeDailyStr, NotifyType: "sms"})
+
+	if _, err := service.Duplicate(ctx, id, 1, &todo.DuplicateRequest{}); err == nil {
+		t.Error("Duplicate() 复制无效的提醒未返回错误")
+	}
+}
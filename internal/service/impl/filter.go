@@ -0,0 +1,105 @@
+package impl
+
+import (
+	"context"
+	"time"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/query"
+	"todo/internal/repository"
+	"todo/pkg/errors"
+)
+
+// listPageSize 列表接口一次返回的最大数量
+const listPageSize = 100
+
+// buildTodoFilter 将列表查询参数转换为仓储层的过滤条件
+// 列表和搜索共用同一套过滤参数
+func buildTodoFilter(ctx context.Context, categoryRepo repository.CategoryRepository, userID uint, req *todo.ListRequest) (repository.TodoFilter, error) {
+	filter := repository.TodoFilter{
+		Completed: req.Completed,
+		Keyword:   req.Keyword,
+		Priority:  req.Priority,
+		StatusID:  req.StatusID,
+	}
+	applyDueFilter(&filter, req.Due, time.Now())
+	if req.Sort == todo.SortPosition {
+		filter.Sort = repository.SortPosition
+	}
+	if req.Query != "" {
+		q, err := query.Parse(req.Query)
+		if err != nil {
+			return filter, err
+		}
+		filter.Query = q
+		// 查询中显式指定了归档条件时不再默认排除已归档的待办事项
+		if q.Has(query.FieldArchived) && req.Archived == "" {
+			filter.Archived = repository.ArchiveInclude
+		}
+	}
+	switch req.Archived {
+	case todo.ArchivedOnly:
+		filter.Archived = repository.ArchiveOnly
+	case todo.ArchivedAll:
+		filter.Archived = repository.ArchiveInclude
+	}
+	if req.CategoryID != nil {
+		ids, err := categoryFilter(ctx, categoryRepo, userID, *req.CategoryID, req.IncludeDescendants)
+		if err != nil {
+			return filter, err
+		}
+		filter.CategoryIDs = ids
+	}
+	return filter, nil
+}
+
+// categoryFilter 返回按分类过滤时匹配的分类ID，includeDescendants 为 true 时包含所有下级分类
+func categoryFilter(ctx context.Context, categoryRepo repository.CategoryRepository, userID, categoryID uint, includeDescendants bool) ([]uint, error) {
+	category, err := categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category.UserID != userID {
+		return nil, errors.ErrForbidden
+	}
+	if !includeDescendants {
+		return []uint{categoryID}, nil
+	}
+
+	categories, err := categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return descendantIDs(categories, categoryID), nil
+}
+
+// applyDueFilter 将相对的截止时间取值换算为以 now 为基准的时间范围
+// overdue 只包含未完成的待办事项
+func applyDueFilter(filter *repository.TodoFilter, due string, now time.Time) {
+	switch due {
+	case models.DueOverdue:
+		filter.DueBefore = &now
+		completed := false
+		filter.Completed = &completed
+	case models.DueNone:
+		filter.NoDueDate = true
+	default:
+		if from, before, ok := query.DueRange(due, now); ok {
+			filter.DueFrom, filter.DueBefore = &from, &before
+		}
+	}
+}
+
+// definitionRequest 将保存的过滤条件转换为列表查询参数
+func definitionRequest(def models.FilterDefinition) *todo.ListRequest {
+	return &todo.ListRequest{
+		Archived:           def.Archived,
+		Completed:          def.Completed,
+		Keyword:            def.Keyword,
+		Query:              def.Query,
+		Priority:           def.Priority,
+		Due:                def.Due,
+		CategoryID:         def.CategoryID,
+		IncludeDescendants: def.IncludeDescendants,
+	}
+}
@@ -0,0 +1,44 @@
+package impl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"todo/api/v1/dto/todo"
+	"todo/internal/query"
+	"todo/internal/repository"
+)
+
+// TestBuildTodoFilter_Query 测试查询语言参数的解析与归档条件的处理
+func TestBuildTodoFilter_Query(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo := newMockCategoryRepo()
+
+	filter, err := buildTodoFilter(ctx, categoryRepo, 1, &todo.ListRequest{Query: "priority:high -completed"})
+	if err != nil {
+		t.Fatalf("buildTodoFilter() 错误 = %v", err)
+	}
+	if filter.Query == nil || len(filter.Query.Terms) != 2 || filter.Archived != repository.ArchiveExclude {
+		t.Errorf("filter = %+v, 期望包含两个条件并默认排除已归档", filter)
+	}
+
+	// 查询中包含 archived 时不再默认排除已归档
+	filter, _ = buildTodoFilter(ctx, categoryRepo, 1, &todo.ListRequest{Query: "archived"})
+	if filter.Archived != repository.ArchiveInclude {
+		t.Errorf("Archived = %v, 期望 %v", filter.Archived, repository.ArchiveInclude)
+	}
+
+	_, err = buildTodoFilter(ctx, categoryRepo, 1, &todo.ListRequest{Query: "priority:urgent"})
+	if syntaxErr, ok := err.(*query.SyntaxError); !ok || syntaxErr.Pos != 10 {
+		t.Errorf("buildTodoFilter() 错误 = %v, 期望第 10 个字符处的语法错误", err)
+	}
+}
+
+// TestNormalizeTags 测试标签名的整理
+func TestNormalizeTags(t *testing.T) {
+	got := normalizeTags([]string{" release ", "Bug", "", "bug", "release", "前端"})
+	want := []string{"release", "Bug", "前端"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeTags() = %v, 期望 %v", got, want)
+	}
+}
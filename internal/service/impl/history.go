@@ -0,0 +1,211 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/logger"
+)
+
+// untrackedFields 不参与变更历史的字段：主键、时间戳和归属关系不允许通过回滚修改，
+// 排序位置只在拖动时变化，回滚时保持当前位置
+var untrackedFields = map[string]bool{
+	"id":          true,
+	"createdAt":   true,
+	"updatedAt":   true,
+	"deletedAt":   true,
+	"userId":      true,
+	"workspaceId": true,
+	"position":    true,
+}
+
+// historyRecorder 负责计算实体的字段级变更并追加到变更历史
+// 设置了 events 时，待办事项和提醒的变更在事务提交后作为 webhook 事件发布给其所有者
+type historyRecorder struct {
+	repo   repository.HistoryRepository
+	events EventPublisher
+}
+
+// record 记录一次变更
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - entityType: 实体类型
+//   - entityID: 实体ID
+//   - actorID: 操作人ID
+//   - action: 操作类型
+//   - before: 变更前的实体，创建时为 nil
+//   - after: 变更后的实体，删除时为 nil
+//
+// Returns:
+//   - error: 可能的错误信息；更新前后没有任何字段变化时不记录
+func (h historyRecorder) record(ctx context.Context, entityType string, entityID, actorID uint, action string, before, after interface{}) error {
+	oldSnap, err := snapshotOf(before)
+	if err != nil {
+		return err
+	}
+	newSnap, err := snapshotOf(after)
+	if err != nil {
+		return err
+	}
+
+	changes := diffSnapshots(oldSnap, newSnap)
+	if len(changes) == 0 && action == models.ChangeActionUpdate {
+		return nil
+	}
+
+	// 删除时保存删除前的快照，便于查看和恢复
+	snap := newSnap
+	if after == nil {
+		snap = oldSnap
+	}
+
+	err = h.repo.Create(ctx, &models.ChangeLog{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		ActorID:    actorID,
+		Changes:    changes,
+		Snapshot:   snap,
+	})
+	if err != nil {
+		return err
+	}
+	h.publishChange(ctx, entityType, actorID, action, before, after)
+	return nil
+}
+
+// publishChange 将待办事项和提醒的变更转换为 webhook 事件
+// 待办事项的事件发给其所有者（后台任务的操作人为 0）；提醒的事件发给操作人，提醒只能由待办事项的所有者修改
+func (h historyRecorder) publishChange(ctx context.Context, entityType string, actorID uint, action string, before, after interface{}) {
+	if h.events == nil {
+		return
+	}
+	data := after
+	if isNil(after) {
+		data = before
+	}
+	switch entityType {
+	case models.EntityTodo:
+		todoItem, ok := data.(*models.Todo)
+		if !ok {
+			return
+		}
+		switch action {
+		case models.ChangeActionCreate:
+			h.publish(ctx, todoItem.UserID, models.WebhookEventTodoCreated, todoItem)
+		case models.ChangeActionUpdate, models.ChangeActionRevert:
+			h.publish(ctx, todoItem.UserID, models.WebhookEventTodoUpdated, todoItem)
+			if prev, ok := before.(*models.Todo); ok && prev != nil && !prev.Completed && todoItem.Completed {
+				h.publish(ctx, todoItem.UserID, models.WebhookEventTodoCompleted, todoItem)
+			}
+		case models.ChangeActionDelete:
+			h.publish(ctx, todoItem.UserID, models.WebhookEventTodoDeleted, todoItem)
+		case models.ChangeActionRestore:
+			h.publish(ctx, todoItem.UserID, models.WebhookEventTodoRestored, todoItem)
+		}
+	case models.EntityReminder:
+		events := map[string]string{
+			models.ChangeActionCreate: models.WebhookEventReminderCreated,
+			models.ChangeActionUpdate: models.WebhookEventReminderUpdated,
+			models.ChangeActionDelete: models.WebhookEventReminderDeleted,
+		}
+		if event, ok := events[action]; ok {
+			h.publish(ctx, actorID, event, data)
+		}
+	}
+}
+
+// publish 在事务提交后发布 webhook 事件，事务回滚时不发布
+// 事件数据在调用时序列化，不受之后对实体的修改影响
+func (h historyRecorder) publish(ctx context.Context, userID uint, event string, data interface{}) {
+	if h.events == nil || userID == 0 {
+		return
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		logger.Warn().Err(err).Str("event", event).Msg("序列化 webhook 事件失败")
+		return
+	}
+	repository.AfterCommit(ctx, func() {
+		h.events.Publish(ctx, userID, event, payload)
+	})
+}
+
+// isNil 判断接口值是否为 nil 或 nil 指针
+func isNil(v interface{}) bool {
+	return v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil())
+}
+
+// snapshotOf 将实体转换为可追踪字段的快照
+// 通过 JSON 序列化获取字段，自动忽略关联对象（对象和数组）以及 untrackedFields
+func snapshotOf(v interface{}) (models.Snapshot, error) {
+	if v == nil || reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	snap := make(models.Snapshot, len(raw))
+	for field, value := range raw {
+		if untrackedFields[field] {
+			continue
+		}
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			continue
+		}
+		snap[field] = value
+	}
+	return snap, nil
+}
+
+// diffSnapshots 比较两个快照，返回按字段名排序的变更列表
+func diffSnapshots(before, after models.Snapshot) models.FieldChanges {
+	fields := make(map[string]bool)
+	for field := range before {
+		fields[field] = true
+	}
+	for field := range after {
+		fields[field] = true
+	}
+
+	var changes models.FieldChanges
+	for field := range fields {
+		oldValue, newValue := before[field], after[field]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, models.FieldChange{Field: field, Old: oldValue, New: newValue})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// applySnapshot 将快照中的字段写回实体，快照中不存在的字段保持不变
+// 快照中出现的指针字段先置空，json.Unmarshal 才会分配新值，而不是写入与实体副本共享的指针
+func applySnapshot(dst interface{}, snap models.Snapshot) error {
+	v := reflect.ValueOf(dst).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if _, ok := snap[name]; ok && field.Type.Kind() == reflect.Ptr {
+			v.Field(i).Set(reflect.Zero(field.Type))
+		}
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
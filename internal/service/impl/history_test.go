@@ -0,0 +1,145 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+)
+
+// mockHistoryRepo 模拟变更历史仓储接口
+type mockHistoryRepo struct {
+	entries []*models.ChangeLog
+}
+
+func newMockHistoryRepo() *mockHistoryRepo {
+	return &mockHistoryRepo{}
+}
+
+func (m *mockHistoryRepo) Create(ctx context.Context, entry *models.ChangeLog) error {
+	entry.ID = uint(len(m.entries) + 1)
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *mockHistoryRepo) GetByID(ctx context.Context, id uint) (*models.ChangeLog, error) {
+	if id == 0 || int(id) > len(m.entries) {
+		return nil, errors.ErrChangeNotFound
+	}
+	return m.entries[id-1], nil
+}
+
+func (m *mockHistoryRepo) ListByEntity(ctx context.Context, entityType string, entityID uint) ([]*models.ChangeLog, error) {
+	var entries []*models.ChangeLog
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if m.entries[i].EntityType == entityType && m.entries[i].EntityID == entityID {
+			entries = append(entries, m.entries[i])
+		}
+	}
+	return entries, nil
+}
+
+// TestTodoService_HistoryAndRevert 测试变更历史记录与回滚
+func TestTodoService_HistoryAndRevert(t *testing.T) {
+	ctx := context.Background()
+	historyRepo := newMockHistoryRepo()
+	todoService := NewTodoService(newMockTodoRepo(), newMockReminderRepo(), newMockCategoryRepo(), newMockStatusRepo(), newMockDependencyRepo(), historyRepo, nopTransactor{}, &mockNotifier{})
+
+	id, err := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "原标题", Priority: "low"})
+	if err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+
+	title, priority := "新标题", "high"
+	if err := todoService.Update(ctx, id, 1, &todo.UpdateRequest{Title: &title, Priority: &priority}); err != nil {
+		t.Fatalf("Update() 错误 = %v", err)
+	}
+	// 没有实际变化的更新不应产生记录
+	if err := todoService.Update(ctx, id, 1, &todo.UpdateRequest{Title: &title}); err != nil {
+		t.Fatalf("Update() 错误 = %v", err)
+	}
+
+	entries, err := todoService.History(ctx, id, 1)
+	if err != nil {
+		t.Fatalf("History() 错误 = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("History() 返回 %d 条记录, 期望 2", len(entries))
+	}
+	update := entries[0]
+	if update.Action != models.ChangeActionUpdate || update.ActorID != 1 {
+		t.Errorf("最新记录 = %s/%d, 期望 update/1", update.Action, update.ActorID)
+	}
+	if len(update.Changes) != 2 || update.Changes[0].Field != "priority" || update.Changes[1].Field != "title" {
+		t.Fatalf("字段变更 = %+v, 期望 priority 和 title", update.Changes)
+	}
+	if update.Changes[1].Old != "原标题" || update.Changes[1].New != "新标题" {
+		t.Errorf("title 变更 = %v -> %v", update.Changes[1].Old, update.Changes[1].New)
+	}
+
+	// 回滚到创建时的版本
+	if err := todoService.Revert(ctx, id, 1, entries[1].ID); err != nil {
+		t.Fatalf("Revert() 错误 = %v", err)
+	}
+	got, _ := todoService.Get(ctx, id, 1)
+	if got.Title != "原标题" || got.Priority != models.PriorityLow {
+		t.Errorf("回滚后 = %s/%s, 期望 原标题/low", got.Title, got.Priority)
+	}
+	if got.UserID != 1 || got.ID != id {
+		t.Errorf("回滚不应修改归属和主键: %+v", got)
+	}
+
+	entries, _ = todoService.History(ctx, id, 1)
+	if len(entries) != 3 || entries[0].Action != models.ChangeActionRevert {
+		t.Errorf("回滚后最新记录应为 revert, 共 %d 条", len(entries))
+	}
+
+	// 其他用户无权查看历史
+	if _, err := todoService.History(ctx, id, 2); err != errors.ErrForbidden {
+		t.Errorf("History() 其他用户错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+}
+
+// TestTodoService_RevertChecksReferences 测试回滚时重新校验分类和工作流状态
+func TestTodoService_RevertChecksReferences(t *testing.T) {
+	ctx := context.Background()
+	categoryRepo, statusRepo := newMockCategoryRepo(), newMockStatusRepo()
+	todoService := NewTodoService(newMockTodoRepo(), newMockReminderRepo(), categoryRepo, statusRepo, newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+	work, home := &models.Category{Name: "工作", UserID: 1}, &models.Category{Name: "家庭", UserID: 1}
+	categoryRepo.Create(ctx, work)
+	categoryRepo.Create(ctx, home)
+	backlog := &models.Status{Name: "待办", UserID: 1, Position: 1}
+	doing := &models.Status{Name: "进行中", UserID: 1, Position: 2, WIPLimit: 1}
+	statusRepo.Create(ctx, backlog)
+	statusRepo.Create(ctx, doing)
+
+	// 回滚到已被删除的分类时清除分类
+	id, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "写周报", CategoryID: &work.ID})
+	if err := todoService.Update(ctx, id, 1, &todo.UpdateRequest{CategoryID: &home.ID}); err != nil {
+		t.Fatalf("Update() 错误 = %v", err)
+	}
+	_ = categoryRepo.Delete(ctx, work.ID)
+	entries, _ := todoService.History(ctx, id, 1)
+	if err := todoService.Revert(ctx, id, 1, entries[1].ID); err != nil {
+		t.Fatalf("Revert() 错误 = %v", err)
+	}
+	if got, _ := todoService.Get(ctx, id, 1); got.CategoryID != nil {
+		t.Errorf("回滚后 CategoryID = %v, 期望清除已删除的分类", *got.CategoryID)
+	}
+
+	// 回滚到已达在制品上限的状态时拒绝
+	if err := todoService.Update(ctx, id, 1, &todo.UpdateRequest{StatusID: &doing.ID}); err != nil {
+		t.Fatalf("Update() 错误 = %v", err)
+	}
+	if err := todoService.Update(ctx, id, 1, &todo.UpdateRequest{StatusID: &backlog.ID}); err != nil {
+		t.Fatalf("Update() 错误 = %v", err)
+	}
+	if _, err := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "改代码", StatusID: &doing.ID}); err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+	entries, _ = todoService.History(ctx, id, 1)
+	if err := todoService.Revert(ctx, id, 1, entries[1].ID); err != errors.ErrWIPLimit {
+		t.Errorf("Revert() 错误 = %v, 期望 %v", err, errors.ErrWIPLimit)
+	}
+}
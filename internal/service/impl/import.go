@@ -0,0 +1,149 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+	"unicode/utf8"
+)
+
+// maxImportErrors 导入结果中最多返回的行错误数量
+const maxImportErrors = 100
+
+// importedTodo 从导入文件中解析出的一条待办事项
+type importedTodo struct {
+	create    todo.CreateRequest
+	completed bool
+	category  string
+}
+
+// validate 按创建待办事项接口的规则校验字段长度和数量
+func (item *importedTodo) validate() *todo.ImportError {
+	invalid := func(field, message string) *todo.ImportError {
+		return &todo.ImportError{Column: field, Message: message}
+	}
+	switch {
+	case item.create.Title == "":
+		return invalid(csvTitle, "标题不能为空")
+	case utf8.RuneCountInString(item.create.Title) > 128:
+		return invalid(csvTitle, "标题不能超过 128 个字符")
+	case utf8.RuneCountInString(item.create.Description) > 1024:
+		return invalid(csvDescription, "描述不能超过 1024 个字符")
+	case utf8.RuneCountInString(item.category) > 32:
+		return invalid(csvCategory, "分类名不能超过 32 个字符")
+	case len(item.create.Tags) > 20:
+		return invalid(csvTags, "标签不能超过 20 个")
+	}
+	for _, tag := range item.create.Tags {
+		if utf8.RuneCountInString(tag) > 32 {
+			return invalid(csvTags, "标签 "+tag+" 超过 32 个字符")
+		}
+	}
+	return nil
+}
+
+// importer 逐条导入待办事项并汇总结果，CSV、todo.txt 和 Markdown 导入共用
+// 分类按名称（不区分大小写）匹配，不存在时新建为顶级分类；试运行时只记录将要新建的分类
+type importer struct {
+	s      *TodoService
+	userID uint
+	dryRun bool
+	byName map[string]*uint // 分类名（小写）到分类ID的映射，试运行时将要新建的分类对应 nil
+	resp   *todo.ImportResponse
+}
+
+// newImporter 读取用户现有的分类并创建导入器
+func (s *TodoService) newImporter(ctx context.Context, userID uint, dryRun bool) (*importer, error) {
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*uint, len(categories))
+	for _, c := range categories {
+		key := strings.ToLower(c.Name)
+		if _, exists := byName[key]; !exists {
+			id := c.ID
+			byName[key] = &id
+		}
+	}
+	return &importer{
+		s:      s,
+		userID: userID,
+		dryRun: dryRun,
+		byName: byName,
+		resp:   &todo.ImportResponse{DryRun: dryRun, CreatedCategories: []string{}, Errors: []todo.ImportError{}},
+	}, nil
+}
+
+// fail 记录一条校验失败的数据
+func (im *importer) fail(rowErr todo.ImportError) {
+	im.resp.Failed++
+	if len(im.resp.Errors) < maxImportErrors {
+		im.resp.Errors = append(im.resp.Errors, rowErr)
+	}
+}
+
+// add 导入一条已经校验的数据，line 为其在文件中的行号
+// 超过在制品上限的数据记为失败，数据库错误时返回错误并中止导入
+func (im *importer) add(ctx context.Context, line int, item *importedTodo) error {
+	if im.dryRun {
+		if key := strings.ToLower(item.category); item.category != "" {
+			if _, exists := im.byName[key]; !exists {
+				im.byName[key] = nil
+				im.resp.CreatedCategories = append(im.resp.CreatedCategories, item.category)
+			}
+		}
+		im.resp.Imported++
+		return nil
+	}
+
+	created, err := im.s.importRow(ctx, im.userID, item, im.byName)
+	if err == errors.ErrWIPLimit {
+		im.fail(todo.ImportError{Row: line, Message: err.Error()})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if created != nil {
+		im.byName[strings.ToLower(created.Name)] = &created.ID
+		im.resp.CreatedCategories = append(im.resp.CreatedCategories, created.Name)
+	}
+	im.resp.Imported++
+	return nil
+}
+
+// importRow 在一个事务中创建一条数据对应的待办事项，需要时先按名称新建分类
+// 返回新建的分类，没有新建时为 nil
+func (s *TodoService) importRow(ctx context.Context, userID uint, row *importedTodo, byName map[string]*uint) (*models.Category, error) {
+	var created *models.Category
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		created = nil
+		if row.category != "" {
+			if id, exists := byName[strings.ToLower(row.category)]; exists {
+				row.create.CategoryID = id
+			} else {
+				created = &models.Category{Name: row.category, UserID: userID}
+				if err := s.categoryRepo.Create(ctx, created); err != nil {
+					return err
+				}
+				if err := s.history.record(ctx, models.EntityCategory, created.ID, userID, models.ChangeActionCreate, nil, created); err != nil {
+					return err
+				}
+				row.create.CategoryID = &created.ID
+			}
+		}
+
+		id, err := s.Create(ctx, userID, &row.create)
+		if err != nil || !row.completed {
+			return err
+		}
+		return s.Update(ctx, id, userID, &todo.UpdateRequest{Completed: &row.completed})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
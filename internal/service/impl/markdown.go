@@ -0,0 +1,208 @@
+package impl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/errors"
+	"todo/pkg/tasklist"
+	"unicode"
+)
+
+// Markdown 任务中的元数据标记，与 Obsidian Tasks 插件的写法一致
+const (
+	markdownHigh      = "⏫" // 高优先级
+	markdownHighest   = "🔺" // 最高优先级，导入为高优先级
+	markdownMedium    = "🔼" // 中优先级，导出时省略
+	markdownLow       = "🔽" // 低优先级
+	markdownLowest    = "⏬" // 最低优先级，导入为低优先级
+	markdownDue       = "📅" // 截止时间
+	markdownDone      = "✅" // 完成日期，导入时忽略
+	markdownCreated   = "➕" // 创建日期，导入时忽略
+	markdownScheduled = "⏳" // 计划日期，导入时忽略
+	markdownStart     = "🛫" // 开始日期，导入时忽略
+)
+
+// markdownIgnoredDates 导入时忽略的日期标记
+var markdownIgnoredDates = map[string]bool{
+	markdownDone: true, markdownCreated: true, markdownScheduled: true, markdownStart: true,
+}
+
+// ExportMarkdown 将匹配列表查询参数的所有待办事项以 GitHub 风格的 Markdown 任务列表写入 w
+// 未分类的待办事项在最前，其余按分类名排列，每个分类一个二级标题；描述写在任务下方并缩进。
+// 标签写作 #标签（空白替换为下划线），优先级、截止时间和完成日期使用 ⏫、🔽、📅、✅ 标记
+func (s *TodoService) ExportMarkdown(ctx context.Context, userID uint, req *todo.ListRequest, w io.Writer) error {
+	filter, categories, err := s.exportFilter(ctx, userID, req)
+	if err != nil {
+		return err
+	}
+
+	tw := tasklist.NewWriter(w)
+	writeSection := func(filter repository.TodoFilter, section string) error {
+		return s.eachPage(ctx, userID, filter, func(todos []*models.Todo) error {
+			for _, todoItem := range todos {
+				err := tw.Write(&tasklist.Item{
+					Section: section,
+					Done:    todoItem.Completed,
+					Text:    markdownText(todoItem),
+					Note:    todoItem.Description,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return tw.Flush()
+		})
+	}
+
+	// 按分类过滤时只导出匹配的分类，否则先导出未分类的待办事项；分类按名称排列
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Name != categories[j].Name {
+			return categories[i].Name < categories[j].Name
+		}
+		return categories[i].ID < categories[j].ID
+	})
+	selected := make(map[uint]bool, len(filter.CategoryIDs))
+	for _, id := range filter.CategoryIDs {
+		selected[id] = true
+	}
+	if len(selected) == 0 {
+		uncategorized := filter
+		uncategorized.Uncategorized = true
+		if err := writeSection(uncategorized, ""); err != nil {
+			return err
+		}
+	}
+	for _, c := range categories {
+		if len(selected) > 0 && !selected[c.ID] {
+			continue
+		}
+		section := filter
+		section.CategoryIDs = []uint{c.ID}
+		if err := writeSection(section, c.Name); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// ImportMarkdown 从 r 中读取 Markdown 任务列表并创建待办事项，非任务内容被忽略
+// 二级及以下标题作为其后任务的分类（按名称匹配，不存在时新建），任务下方缩进的文字作为描述，
+// #标签、优先级标记和 📅 截止时间从标题中去掉并保存到对应字段。
+// 每个任务在各自的事务中创建，校验失败的任务被跳过并记录在结果中，试运行时只校验
+func (s *TodoService) ImportMarkdown(ctx context.Context, userID uint, r io.Reader, req *todo.ImportRequest) (*todo.ImportResponse, error) {
+	im, err := s.newImporter(ctx, userID, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	tr := tasklist.NewReader(r)
+	for {
+		task, err := tr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err == bufio.ErrTooLong {
+			return nil, errors.ErrInvalidParameter
+		}
+		if err != nil {
+			return nil, err
+		}
+		im.resp.Rows++
+
+		item, rowErr := parseMarkdownTask(task)
+		if rowErr != nil {
+			rowErr.Row = task.Line
+			im.fail(*rowErr)
+			continue
+		}
+		if err := im.add(ctx, task.Line, item); err != nil {
+			return nil, err
+		}
+	}
+	return im.resp, nil
+}
+
+// markdownText 生成任务的文本：标题、标签、优先级、截止时间和完成日期
+func markdownText(todoItem *models.Todo) string {
+	parts := []string{todoItem.Title}
+	for _, tag := range todoItem.Tags {
+		parts = append(parts, "#"+plainName(tag.Name))
+	}
+	switch todoItem.Priority {
+	case models.PriorityHigh:
+		parts = append(parts, markdownHigh)
+	case models.PriorityLow:
+		parts = append(parts, markdownLow)
+	}
+	if todoItem.DueDate != nil {
+		parts = append(parts, markdownDue, formatPlainTime(*todoItem.DueDate))
+	}
+	if todoItem.Completed && todoItem.CompletedAt != nil {
+		parts = append(parts, markdownDone, todoItem.CompletedAt.Format(plainDateLayout))
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseMarkdownTask 从任务文本中解析标签、优先级和截止时间并校验
+func parseMarkdownTask(task *tasklist.Item) (*importedTodo, *todo.ImportError) {
+	item := &importedTodo{completed: task.Done, category: task.Section}
+	item.create.Description = task.Note
+
+	words := strings.Fields(task.Text)
+	var title, tags []string
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		switch {
+		case word == markdownHigh || word == markdownHighest:
+			item.create.Priority = string(models.PriorityHigh)
+		case word == markdownMedium:
+			item.create.Priority = string(models.PriorityMedium)
+		case word == markdownLow || word == markdownLowest:
+			item.create.Priority = string(models.PriorityLow)
+		case word == markdownDue && i+1 < len(words):
+			i++
+			due, ok := parsePlainTime(words[i])
+			if !ok {
+				return nil, &todo.ImportError{Column: csvDueDate, Message: fmt.Sprintf("无效的截止时间 %q，使用 2006-01-02 或 2006-01-02T15:04 格式", words[i])}
+			}
+			item.create.DueDate = &due
+		case markdownIgnoredDates[word] && i+1 < len(words):
+			if _, ok := parsePlainTime(words[i+1]); ok {
+				i++
+				continue
+			}
+			title = append(title, word)
+		case isMarkdownTag(word):
+			tags = append(tags, word[1:])
+		default:
+			title = append(title, word)
+		}
+	}
+	item.create.Title = strings.Join(title, " ")
+	item.create.Tags = normalizeTags(tags)
+
+	if rowErr := item.validate(); rowErr != nil {
+		return nil, rowErr
+	}
+	return item, nil
+}
+
+// isMarkdownTag 判断单词是否为 #标签；只由数字组成的 #123 通常是议题编号，不作为标签
+func isMarkdownTag(word string) bool {
+	if len(word) < 2 || word[0] != '#' || word[1] == '#' {
+		return false
+	}
+	for _, r := range word[1:] {
+		if !unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+)
+
+// TestTodoService_MarkdownRoundTrip 测试导出的 Markdown 任务列表按分类名分组，并可以导入为相同的待办事项
+func TestTodoService_MarkdownRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	categoryRepo := newMockCategoryRepo()
+	service := NewTodoService(todoRepo, newMockReminderRepo(), categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+	seedPlainTextTodos(t, service, categoryRepo)
+
+	var buf bytes.Buffer
+	if err := service.ExportMarkdown(ctx, 1, &todo.ListRequest{}, &buf); err != nil {
+		t.Fatalf("ExportMarkdown() 错误 = %v", err)
+	}
+	today := time.Now().Format("2006-01-02")
+	want := "- [x] 买菜 10:30 前 🔽 📅 2024-06-08T18:30 ✅ " + today + "\n" +
+		"\n## Home Office\n\n" +
+		"- [ ] 整理书架\n" +
+		"\n## Work\n\n" +
+		"- [ ] 写周报 #例行 #写作 ⏫ 📅 2024-06-07\n" +
+		"  本周进展\n" +
+		"\n" +
+		"  下周计划\n"
+	if buf.String() != want {
+		t.Fatalf("导出内容 = %q, 期望 %q", buf.String(), want)
+	}
+
+	t.Run("按分类过滤时只导出该分类", func(t *testing.T) {
+		var filtered bytes.Buffer
+		categoryID := uint(2)
+		if err := service.ExportMarkdown(ctx, 1, &todo.ListRequest{CategoryID: &categoryID}, &filtered); err != nil {
+			t.Fatalf("ExportMarkdown() 错误 = %v", err)
+		}
+		if filtered.String() != "## Home Office\n\n- [ ] 整理书架\n" {
+			t.Errorf("导出内容 = %q", filtered.String())
+		}
+	})
+
+	result, err := service.ImportMarkdown(ctx, 2, bytes.NewReader(buf.Bytes()), &todo.ImportRequest{})
+	if err != nil {
+		t.Fatalf("ImportMarkdown() 错误 = %v", err)
+	}
+	if result.Rows != 3 || result.Imported != 3 || !reflect.DeepEqual(result.CreatedCategories, []string{"Home Office", "Work"}) {
+		t.Fatalf("导入结果 = %+v, 期望导入 3 个待办事项并新建 2 个分类", result)
+	}
+	if got, want := todoSummaries(todoRepo, categoryRepo, 2, true), todoSummaries(todoRepo, categoryRepo, 1, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("导入后 = %v, 期望 %v", got, want)
+	}
+}
+
+// TestTodoService_ImportMarkdown 测试手写的 Markdown 中的标题、议题编号和逐项校验
+func TestTodoService_ImportMarkdown(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	categoryRepo := newMockCategoryRepo()
+	service := NewTodoService(todoRepo, newMockReminderRepo(), categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+	categoryRepo.Create(ctx, &models.Category{Name: "Work", UserID: 1})
+
+	input := "# 迭代计划\n" +
+		"\n" +
+		"- [ ] 修复 #123 的崩溃 #bug 🔺\n" +
+		"## work\n" +
+		"- [X] 发布 ➕ 2024-06-01\n" +
+		"- [ ] 续签合同 📅 下周\n" +
+		"- [ ] #只有标签\n"
+	dry, err := service.ImportMarkdown(ctx, 1, strings.NewReader(input), &todo.ImportRequest{DryRun: true})
+	if err != nil {
+		t.Fatalf("试运行 ImportMarkdown() 错误 = %v", err)
+	}
+	if dry.Rows != 4 || dry.Imported != 2 || dry.Failed != 2 || len(dry.CreatedCategories) != 0 || len(todoRepo.todos) != 0 {
+		t.Fatalf("试运行结果 = %+v, 期望 4 项中 2 项通过且不新建分类", dry)
+	}
+	wantErrors := []todo.ImportError{{Row: 6, Column: "due_date"}, {Row: 7, Column: "title"}}
+	for i, want := range wantErrors {
+		if i >= len(dry.Errors) || dry.Errors[i].Row != want.Row || dry.Errors[i].Column != want.Column {
+			t.Errorf("第 %d 个错误 = %+v, 期望第 %d 行的 %s 列", i, dry.Errors, want.Row, want.Column)
+		}
+	}
+
+	if _, err := service.ImportMarkdown(ctx, 1, strings.NewReader(input), &todo.ImportRequest{}); err != nil {
+		t.Fatalf("ImportMarkdown() 错误 = %v", err)
+	}
+	// 没有优先级标记时使用默认的中优先级，标题 work 匹配到已有的分类 Work
+	want := map[string]string{
+		"修复 #123 的崩溃": "high|false|||[bug]",
+		"发布":          "medium|true||Work|[]",
+	}
+	if got := todoSummaries(todoRepo, categoryRepo, 1, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("导入后 = %v, 期望 %v", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"todo/api/v1/dto/category"
+	"todo/api/v1/dto/todo"
+	"todo/pkg/errors"
+	"todo/pkg/rank"
+)
+
+// TestTodoService_Move 测试新建排在末尾、拖动到锚点前后以及按排序位置列出
+func TestTodoService_Move(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	categoryRepo := newMockCategoryRepo()
+	historyRepo := newMockHistoryRepo()
+	service := NewTodoService(todoRepo, newMockReminderRepo(), categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), historyRepo, nopTransactor{}, &mockNotifier{})
+	categoryService := NewCategoryService(categoryRepo, service, historyRepo, nopTransactor{})
+
+	work, _ := categoryService.Create(ctx, 1, &category.CreateRequest{Name: "工作"})
+	a, _ := service.Create(ctx, 1, &todo.CreateRequest{Title: "A", CategoryID: &work})
+	b, _ := service.Create(ctx, 1, &todo.CreateRequest{Title: "B", CategoryID: &work})
+	c, _ := service.Create(ctx, 1, &todo.CreateRequest{Title: "C", CategoryID: &work})
+	loose, _ := service.Create(ctx, 1, &todo.CreateRequest{Title: "未分类"})
+	other, _ := service.Create(ctx, 2, &todo.CreateRequest{Title: "他人的待办"})
+
+	order := func() []uint {
+		todos, err := service.List(ctx, 1, &todo.ListRequest{CategoryID: &work, Sort: todo.SortPosition})
+		if err != nil {
+			t.Fatalf("List() 错误 = %v", err)
+		}
+		ids := make([]uint, 0, len(todos))
+		for _, todoItem := range todos {
+			ids = append(ids, todoItem.ID)
+		}
+		return ids
+	}
+	assertOrder := func(want ...uint) {
+		t.Helper()
+		got := order()
+		if len(got) != len(want) {
+			t.Fatalf("顺序 = %v, 期望 %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("顺序 = %v, 期望 %v", got, want)
+			}
+		}
+	}
+
+	assertOrder(a, b, c)
+
+	if err := service.Move(ctx, c, 1, &todo.MoveRequest{Before: &a}); err != nil {
+		t.Fatalf("Move() 错误 = %v", err)
+	}
+	assertOrder(c, a, b)
+
+	if err := service.Move(ctx, c, 1, &todo.MoveRequest{After: &a, Before: &b}); err != nil {
+		t.Fatalf("Move() 错误 = %v", err)
+	}
+	assertOrder(a, c, b)
+
+	if err := service.Move(ctx, a, 1, &todo.MoveRequest{After: &b}); err != nil {
+		t.Fatalf("Move() 错误 = %v", err)
+	}
+	assertOrder(c, b, a)
+
+	missing := uint(99)
+	tests := []struct {
+		name string
+		id   uint
+		req  *todo.MoveRequest
+		want error
+	}{
+		{"没有锚点", a, &todo.MoveRequest{}, errors.ErrInvalidParameter},
+		{"锚点是自身", a, &todo.MoveRequest{Before: &a}, errors.ErrInvalidParameter},
+		{"锚点不相邻", a, &todo.MoveRequest{After: &c, Before: &a}, errors.ErrInvalidParameter},
+		{"锚点在其他分类", a, &todo.MoveRequest{Before: &loose}, errors.ErrInvalidParameter},
+		{"锚点属于他人", a, &todo.MoveRequest{Before: &other}, errors.ErrForbidden},
+		{"锚点不存在", a, &todo.MoveRequest{After: &missing}, errors.ErrTodoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := service.Move(ctx, tt.id, 1, tt.req); err != tt.want {
+				t.Errorf("Move() 错误 = %v, 期望 %v", err, tt.want)
+			}
+		})
+	}
+	assertOrder(c, b, a)
+
+	t.Run("反复插入到同一位置后重新分配", func(t *testing.T) {
+		// 交替把 a、b 插到 c 之后，每次都落在 c 和上一次插入的待办事项之间
+		for i := 0; i < 200; i++ {
+			id := []uint{a, b}[i%2]
+			if err := service.Move(ctx, id, 1, &todo.MoveRequest{After: &c}); err != nil {
+				t.Fatalf("Move() 错误 = %v", err)
+			}
+		}
+		assertOrder(c, b, a)
+		for _, id := range []uint{a, b, c} {
+			if p := todoRepo.todos[id].Position; len(p) > rank.MaxLength {
+				t.Errorf("待办事项 %d 的排序位置 %q 超过 %d 个字符", id, p, rank.MaxLength)
+			}
+		}
+	})
+
+	t.Run("移动分类后排到末尾", func(t *testing.T) {
+		if err := service.Update(ctx, loose, 1, &todo.UpdateRequest{CategoryID: &work}); err != nil {
+			t.Fatalf("Update() 错误 = %v", err)
+		}
+		got := order()
+		if got[len(got)-1] != loose {
+			t.Errorf("顺序 = %v, 期望 %d 在末尾", got, loose)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package impl
+
+import (
+	"context"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+	"todo/pkg/rank"
+)
+
+// Move 拖动待办事项到分类内的新位置
+// before 表示放到该待办事项之前，after 表示放到该待办事项之后，同时提供时两者必须相邻；
+// 锚点必须与待办事项属于同一分类。通常只改写被移动的这一行，排名过长时重新分配整个分类
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - id: 待办事项ID
+//   - userID: 用户ID
+//   - req: 位置锚点
+//
+// Returns:
+//   - error: 可能的错误信息
+func (s *TodoService) Move(ctx context.Context, id, userID uint, req *todo.MoveRequest) error {
+	if req.Before == nil && req.After == nil {
+		return errors.ErrInvalidParameter
+	}
+	todoItem, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		siblings, err := s.todoRepo.ListByPosition(ctx, userID, todoItem.CategoryID)
+		if err != nil {
+			return err
+		}
+		others := make([]*models.Todo, 0, len(siblings))
+		for _, sibling := range siblings {
+			if sibling.ID != todoItem.ID {
+				others = append(others, sibling)
+			}
+		}
+
+		index, err := s.insertIndex(ctx, userID, others, req)
+		if err != nil {
+			return err
+		}
+
+		lower, upper := "", ""
+		if index > 0 {
+			lower = others[index-1].Position
+		}
+		if index < len(others) {
+			upper = others[index].Position
+		}
+		// 相邻的待办事项还没有排序位置（早于排序功能创建）时同样需要重新分配
+		if (index == 0 || lower != "") && (index == len(others) || upper != "") {
+			position, err := rank.Between(lower, upper)
+			if err == nil && len(position) <= rank.MaxLength {
+				return s.todoRepo.UpdatePosition(ctx, todoItem.ID, position)
+			}
+		}
+
+		ordered := make([]*models.Todo, 0, len(others)+1)
+		ordered = append(ordered, others[:index]...)
+		ordered = append(ordered, todoItem)
+		ordered = append(ordered, others[index:]...)
+		_, err = s.respread(ctx, ordered)
+		return err
+	})
+}
+
+// insertIndex 根据锚点计算待办事项在 others 中的插入下标
+func (s *TodoService) insertIndex(ctx context.Context, userID uint, others []*models.Todo, req *todo.MoveRequest) (int, error) {
+	find := func(anchorID uint) (int, error) {
+		for i, other := range others {
+			if other.ID == anchorID {
+				return i, nil
+			}
+		}
+		// 不在同一分类中：区分不存在、无权访问和属于其他分类（或就是待办事项本身）
+		if _, err := s.Get(ctx, anchorID, userID); err != nil {
+			return 0, err
+		}
+		return 0, errors.ErrInvalidParameter
+	}
+
+	index := -1
+	if req.After != nil {
+		i, err := find(*req.After)
+		if err != nil {
+			return 0, err
+		}
+		index = i + 1
+	}
+	if req.Before != nil {
+		i, err := find(*req.Before)
+		if err != nil {
+			return 0, err
+		}
+		if index >= 0 && index != i {
+			return 0, errors.ErrInvalidParameter
+		}
+		index = i
+	}
+	return index, nil
+}
+
+// appendPosition 将待办事项的排序位置设置为所在分类的末尾，需在事务中、保存待办事项之前调用
+func (s *TodoService) appendPosition(ctx context.Context, todoItem *models.Todo) error {
+	last, err := s.todoRepo.LastPosition(ctx, todoItem.UserID, todoItem.CategoryID)
+	if err != nil {
+		return err
+	}
+	position, err := rank.Between(last, "")
+	if err != nil || len(position) > rank.MaxLength {
+		// 末尾的排名过长或格式无效时重新分配整个分类
+		siblings, err := s.todoRepo.ListByPosition(ctx, todoItem.UserID, todoItem.CategoryID)
+		if err != nil {
+			return err
+		}
+		if last, err = s.respread(ctx, siblings); err != nil {
+			return err
+		}
+		if position, err = rank.Between(last, ""); err != nil {
+			return err
+		}
+	}
+	todoItem.Position = position
+	return nil
+}
+
+// respread 按 todos 的顺序重新均匀分配排序位置，返回最后一个位置，需在事务中调用
+func (s *TodoService) respread(ctx context.Context, todos []*models.Todo) (string, error) {
+	ranks := rank.Spread(len(todos))
+	for i, todoItem := range todos {
+		if err := s.todoRepo.UpdatePosition(ctx, todoItem.ID, ranks[i]); err != nil {
+			return "", err
+		}
+		todoItem.Position = ranks[i]
+	}
+	if len(ranks) == 0 {
+		return "", nil
+	}
+	return ranks[len(ranks)-1], nil
+}
+
+// sameID 判断两个可能为空的ID（如分类ID、工作流状态ID）是否相同
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,137 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"time"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+	"todo/pkg/quickadd"
+	"unicode/utf8"
+)
+
+// QuickAdd 解析一行自然语言文本并创建待办事项，试运行时只返回解析结果供用户确认
+// 第一个与现有分类同名（不区分大小写，空白可写作下划线）的 #标签作为分类，其余 #标签作为标签；
+// 提前提醒按截止时间计算为一次性推送提醒，没有截止时间或提醒时间已过时忽略并在结果中给出警告。
+// 待办事项和提醒在同一事务中创建
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID
+//   - req: 文本、时区和是否试运行
+//
+// Returns:
+//   - *todo.QuickAddResponse: 解析结果，创建时附带新待办事项的ID
+//   - error: 时区无效、标题为空或超长、标签超过限制时返回 ErrInvalidParameter
+func (s *TodoService) QuickAdd(ctx context.Context, userID uint, req *todo.QuickAddRequest) (*todo.QuickAddResponse, error) {
+	loc := time.Local
+	if req.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, errors.ErrInvalidParameter
+		}
+	}
+	now := time.Now().In(loc)
+	parsed := quickadd.Parse(req.Text, now)
+	if parsed.Title == "" || utf8.RuneCountInString(parsed.Title) > 128 {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	resp := &todo.QuickAddResponse{
+		DryRun:   req.DryRun,
+		Title:    parsed.Title,
+		DueDate:  parsed.Due,
+		Priority: parsed.Priority,
+		Tags:     []string{},
+		Tokens:   make([]todo.QuickAddToken, 0, len(parsed.Tokens)),
+		Warnings: []string{},
+	}
+	if resp.Priority == "" {
+		resp.Priority = string(models.PriorityMedium)
+	}
+	for _, token := range parsed.Tokens {
+		resp.Tokens = append(resp.Tokens, todo.QuickAddToken{Kind: string(token.Kind), Text: token.Text})
+	}
+
+	if len(parsed.Hashtags) > 0 {
+		categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		byName := make(map[string]*models.Category, len(categories)*2)
+		for _, c := range categories {
+			for _, key := range []string{strings.ToLower(c.Name), strings.ToLower(plainName(c.Name))} {
+				if _, exists := byName[key]; !exists {
+					byName[key] = c
+				}
+			}
+		}
+		var tags []string
+		for _, tag := range parsed.Hashtags {
+			if c, ok := byName[strings.ToLower(tag)]; ok && resp.CategoryID == nil {
+				id := c.ID
+				resp.CategoryID = &id
+				resp.Category = c.Name
+				continue
+			}
+			tags = append(tags, tag)
+		}
+		resp.Tags = normalizeTags(tags)
+		if len(resp.Tags) > 20 {
+			return nil, errors.ErrInvalidParameter
+		}
+		for _, tag := range resp.Tags {
+			if utf8.RuneCountInString(tag) > 32 {
+				return nil, errors.ErrInvalidParameter
+			}
+		}
+	}
+
+	if parsed.RemindBefore != nil {
+		switch {
+		case parsed.Due == nil:
+			resp.Warnings = append(resp.Warnings, "没有截止时间，已忽略提醒")
+		case parsed.Due.Add(-*parsed.RemindBefore).Before(now):
+			resp.Warnings = append(resp.Warnings, "提醒时间已过，已忽略提醒")
+		default:
+			remindAt := parsed.Due.Add(-*parsed.RemindBefore)
+			resp.RemindAt = &remindAt
+		}
+	}
+	if req.DryRun {
+		return resp, nil
+	}
+
+	create := &todo.CreateRequest{
+		Title:      resp.Title,
+		Priority:   resp.Priority,
+		CategoryID: resp.CategoryID,
+		DueDate:    resp.DueDate,
+		Tags:       resp.Tags,
+	}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		id, err := s.Create(ctx, userID, create)
+		if err != nil {
+			return err
+		}
+		resp.ID = id
+		if resp.RemindAt == nil {
+			return nil
+		}
+		reminder := &models.Reminder{
+			TodoID:     id,
+			RemindAt:   *resp.RemindAt,
+			RemindType: models.RemindTypeOnceStr,
+			NotifyType: models.NotifyTypePushStr,
+		}
+		if err := s.reminderRepo.Create(ctx, reminder); err != nil {
+			return err
+		}
+		return s.history.record(ctx, models.EntityReminder, reminder.ID, userID, models.ChangeActionCreate, nil, reminder)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,88 @@
+package impl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+)
+
+// TestTodoService_QuickAdd 测试快速添加时匹配分类、拆分标签、创建提醒以及试运行
+func TestTodoService_QuickAdd(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	reminderRepo := newMockReminderRepo()
+	categoryRepo := newMockCategoryRepo()
+	service := NewTodoService(todoRepo, reminderRepo, categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+
+	work := &models.Category{Name: "Team Work", UserID: 1}
+	categoryRepo.Create(ctx, work)
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	due := time.Date(2099, 3, 1, 15, 0, 0, 0, shanghai)
+	req := &todo.QuickAddRequest{
+		Text:     "季度复盘 2099-03-01 15:00 !high #q1 #team_work #Q1 remind 1h before",
+		Timezone: "Asia/Shanghai",
+		DryRun:   true,
+	}
+
+	preview, err := service.QuickAdd(ctx, 1, req)
+	if err != nil {
+		t.Fatalf("QuickAdd() 试运行错误 = %v", err)
+	}
+	if preview.ID != 0 || len(todoRepo.todos) != 0 {
+		t.Errorf("试运行不应创建待办事项: %+v", preview)
+	}
+	if preview.Title != "季度复盘" || preview.Priority != "high" || !sameID(preview.CategoryID, &work.ID) ||
+		preview.Category != "Team Work" || !reflect.DeepEqual(preview.Tags, []string{"q1"}) {
+		t.Errorf("QuickAdd() = %+v, 期望匹配分类 Team Work 和标签 q1", preview)
+	}
+	if preview.DueDate == nil || !preview.DueDate.Equal(due) || preview.RemindAt == nil || !preview.RemindAt.Equal(due.Add(-time.Hour)) {
+		t.Errorf("截止时间 = %v, 提醒时间 = %v, 期望 %v 和提前一小时", preview.DueDate, preview.RemindAt, due)
+	}
+	if len(preview.Tokens) != 7 {
+		t.Errorf("识别出的片段 = %+v, 期望 7 个", preview.Tokens)
+	}
+
+	req.DryRun = false
+	created, err := service.QuickAdd(ctx, 1, req)
+	if err != nil {
+		t.Fatalf("QuickAdd() 错误 = %v", err)
+	}
+	stored, err := todoRepo.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("待办事项未创建: %v", err)
+	}
+	if stored.Title != "季度复盘" || stored.Priority != models.PriorityHigh || !sameID(stored.CategoryID, &work.ID) ||
+		len(stored.Tags) != 1 || stored.Tags[0].Name != "q1" {
+		t.Errorf("创建的待办事项 = %+v", stored)
+	}
+	reminders, _ := reminderRepo.ListByTodoID(ctx, created.ID)
+	if len(reminders) != 1 || !reminders[0].RemindAt.Equal(due.Add(-time.Hour)) ||
+		reminders[0].RemindType != models.RemindTypeOnceStr || reminders[0].NotifyType != models.NotifyTypePushStr {
+		t.Errorf("创建的提醒 = %+v, 期望一条提前一小时的一次性推送提醒", reminders)
+	}
+
+	// 没有截止时间时忽略提醒
+	noDue, err := service.QuickAdd(ctx, 1, &todo.QuickAddRequest{Text: "读书 提前10分钟提醒"})
+	if err != nil {
+		t.Fatalf("QuickAdd() 错误 = %v", err)
+	}
+	if noDue.Priority != "medium" || noDue.RemindAt != nil || len(noDue.Warnings) != 1 {
+		t.Errorf("QuickAdd() = %+v, 期望默认中优先级并警告忽略提醒", noDue)
+	}
+	if reminders, _ := reminderRepo.ListByTodoID(ctx, noDue.ID); len(reminders) != 0 {
+		t.Errorf("没有截止时间时不应创建提醒: %+v", reminders)
+	}
+
+	for _, invalid := range []*todo.QuickAddRequest{
+		{Text: "明天 !high #work"},
+		{Text: "买菜", Timezone: "Mars/Olympus"},
+	} {
+		if _, err := service.QuickAdd(ctx, 1, invalid); err != errors.ErrInvalidParameter {
+			t.Errorf("QuickAdd(%+v) 错误 = %v, 期望 %v", invalid, err, errors.ErrInvalidParameter)
+		}
+	}
+}
@@ -2,22 +2,30 @@ package impl
 
 import (
 	"context"
+	"time"
 	"todo/api/v1/dto/reminder"
 	"todo/internal/models"
 	"todo/internal/repository"
+	"todo/internal/tenant"
 	"todo/pkg/errors"
+	"todo/pkg/logger"
 )
 
 // ReminderService 提醒服务实现
 type ReminderService struct {
 	reminderRepo repository.ReminderRepository
 	todoRepo     repository.TodoRepository
+	history      historyRecorder
+	tx           repository.Transactor
 }
 
-func NewReminderService(reminderRepo repository.ReminderRepository, todoRepo repository.TodoRepository) *ReminderService {
+func NewReminderService(reminderRepo repository.ReminderRepository, todoRepo repository.TodoRepository,
+	historyRepo repository.HistoryRepository, tx repository.Transactor) *ReminderService {
 	return &ReminderService{
 		reminderRepo: reminderRepo,
 		todoRepo:     todoRepo,
+		history:      historyRecorder{repo: historyRepo},
+		tx:           tx,
 	}
 }
 
@@ -45,7 +53,13 @@ func (s *ReminderService) Create(ctx context.Context, userID uint, req *reminder
 		return 0, err
 	}
 
-	if err := s.reminderRepo.Create(ctx, reminder); err != nil {
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.reminderRepo.Create(ctx, reminder); err != nil {
+			return err
+		}
+		return s.history.record(ctx, models.EntityReminder, reminder.ID, userID, models.ChangeActionCreate, nil, reminder)
+	})
+	if err != nil {
 		return 0, err
 	}
 
@@ -72,6 +86,75 @@ func (s *ReminderService) ListByTodoID(ctx context.Context, todoID uint) ([]*mod
 	return s.reminderRepo.ListByTodoID(ctx, todoID)
 }
 
+// SetEventPublisher 设置提醒变更和到期后发布 webhook 事件的发布器，未设置时不发布事件
+//
+// Parameters:
+//   - events: 事件发布器
+func (s *ReminderService) SetEventPublisher(events EventPublisher) {
+	s.history.events = events
+}
+
+// FireDue 发送所有提醒时间不晚于 now 的提醒，返回发送数量
+// 一次性提醒标记为已发送；每天、每周重复的提醒顺延到 now 之后的下一次。每个提醒到期时发布 reminder.fired 事件
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - now: 当前时间
+//
+// Returns:
+//   - int: 发送的提醒数量
+//   - error: 查询到期提醒失败时返回错误，单个提醒处理失败只记录日志
+func (s *ReminderService) FireDue(ctx context.Context, now time.Time) (int, error) {
+	fired := 0
+	for {
+		reminders, err := s.reminderRepo.ListDue(ctx, now, batchSize)
+		if err != nil {
+			return fired, err
+		}
+
+		failed := 0
+		for _, r := range reminders {
+			if err := s.fire(tenant.WithWorkspaceID(ctx, r.WorkspaceID), r, now); err != nil {
+				logger.Warn().Err(err).Uint("reminder_id", r.ID).Msg("发送提醒失败")
+				failed++
+				continue
+			}
+			fired++
+		}
+
+		// 整批都失败时停止，避免反复处理同一批记录
+		if len(reminders) < batchSize || failed == len(reminders) {
+			return fired, nil
+		}
+	}
+}
+
+// fire 发送一个到期的提醒：更新提醒状态后发布附带待办事项的 reminder.fired 事件
+func (s *ReminderService) fire(ctx context.Context, r *models.Reminder, now time.Time) error {
+	due := *r
+	var step time.Duration
+	switch r.RemindType {
+	case models.RemindTypeDailyStr:
+		step = 24 * time.Hour
+	case models.RemindTypeWeeklyStr:
+		step = 7 * 24 * time.Hour
+	}
+	if step > 0 {
+		for !r.RemindAt.After(now) {
+			r.RemindAt = r.RemindAt.Add(step)
+		}
+	} else {
+		r.Status = true
+	}
+	if err := s.reminderRepo.Update(ctx, r); err != nil {
+		return err
+	}
+	if due.Todo != nil {
+		s.history.publish(ctx, due.Todo.UserID, models.WebhookEventReminderFired, &due)
+	}
+	return nil
+}
+
 func (s *ReminderService) GetReminderRepo() repository.ReminderRepository {
 	return s.reminderRepo
 }
@@ -93,6 +176,11 @@ func (s *ReminderService) Update(ctx context.Context, id, userID uint, req *remi
 	}
 
 	// 更新提醒信息
+	before := *r
+	if !r.RemindAt.Equal(req.RemindAt) {
+		// 修改提醒时间后重新等待发送
+		r.Status = false
+	}
 	r.RemindAt = req.RemindAt
 	r.RemindType = req.RemindType
 	r.NotifyType = req.NotifyType
@@ -102,7 +190,12 @@ func (s *ReminderService) Update(ctx context.Context, id, userID uint, req *remi
 		return err
 	}
 
-	return s.reminderRepo.Update(ctx, r)
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.reminderRepo.Update(ctx, r); err != nil {
+			return err
+		}
+		return s.history.record(ctx, models.EntityReminder, r.ID, userID, models.ChangeActionUpdate, &before, r)
+	})
 }
 
 func (s *ReminderService) Delete(ctx context.Context, id, userID uint) error {
@@ -110,5 +203,10 @@ func (s *ReminderService) Delete(ctx context.Context, id, userID uint) error {
 	if err != nil {
 		return err
 	}
-	return s.reminderRepo.Delete(ctx, reminder.ID)
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.reminderRepo.Delete(ctx, reminder.ID); err != nil {
+			return err
+		}
+		return s.history.record(ctx, models.EntityReminder, reminder.ID, userID, models.ChangeActionDelete, reminder, nil)
+	})
 }
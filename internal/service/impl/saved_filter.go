@@ -0,0 +1,144 @@
+package impl
+
+import (
+	"context"
+	"todo/api/v1/dto/filter"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/errors"
+)
+
+// FilterService 保存的过滤条件服务实现
+type FilterService struct {
+	filterRepo   repository.SavedFilterRepository
+	todoRepo     repository.TodoRepository
+	categoryRepo repository.CategoryRepository
+}
+
+// NewFilterService 创建一个新的过滤条件服务实例
+//
+// Parameters:
+//   - filterRepo: 过滤条件仓库实现
+//   - todoRepo: 待办事项仓库实现，用于求值和统计数量
+//   - categoryRepo: 分类仓库实现，用于校验和展开分类条件
+//
+// Returns:
+//   - *FilterService: 返回过滤条件服务实例
+func NewFilterService(filterRepo repository.SavedFilterRepository, todoRepo repository.TodoRepository,
+	categoryRepo repository.CategoryRepository) *FilterService {
+	return &FilterService{
+		filterRepo:   filterRepo,
+		todoRepo:     todoRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// Create 保存过滤条件
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID
+//   - req: 名称和过滤条件
+//
+// Returns:
+//   - *models.SavedFilter: 新保存的过滤条件
+//   - error: 过滤条件引用的分类不存在或不属于当前用户时返回错误
+func (s *FilterService) Create(ctx context.Context, userID uint, req *filter.CreateRequest) (*models.SavedFilter, error) {
+	if _, err := s.todoFilter(ctx, userID, req.Definition); err != nil {
+		return nil, err
+	}
+
+	saved := &models.SavedFilter{
+		UserID:     userID,
+		Name:       req.Name,
+		Definition: req.Definition,
+	}
+	if err := s.filterRepo.Create(ctx, saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// List 获取用户保存的过滤条件，附带每个条件当前匹配的待办事项数量
+// 引用的分类已被删除的过滤条件数量为 0
+func (s *FilterService) List(ctx context.Context, userID uint) ([]*filter.Item, error) {
+	saved, err := s.filterRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*filter.Item, 0, len(saved))
+	for _, f := range saved {
+		item := &filter.Item{SavedFilter: f}
+		todoFilter, err := s.todoFilter(ctx, userID, f.Definition)
+		switch err {
+		case nil:
+			if item.Count, err = s.todoRepo.CountByUserID(ctx, userID, todoFilter); err != nil {
+				return nil, err
+			}
+		case errors.ErrCategoryNotFound:
+		default:
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Get 获取过滤条件详情
+func (s *FilterService) Get(ctx context.Context, id, userID uint) (*models.SavedFilter, error) {
+	saved, err := s.filterRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if saved.UserID != userID {
+		return nil, errors.ErrForbidden
+	}
+	return saved, nil
+}
+
+// Update 更新过滤条件的名称或条件
+func (s *FilterService) Update(ctx context.Context, id, userID uint, req *filter.UpdateRequest) (*models.SavedFilter, error) {
+	saved, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		saved.Name = *req.Name
+	}
+	if req.Definition != nil {
+		if _, err := s.todoFilter(ctx, userID, *req.Definition); err != nil {
+			return nil, err
+		}
+		saved.Definition = *req.Definition
+	}
+
+	if err := s.filterRepo.Update(ctx, saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// Delete 删除过滤条件
+func (s *FilterService) Delete(ctx context.Context, id, userID uint) error {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.filterRepo.Delete(ctx, id)
+}
+
+// Request 返回保存的过滤条件对应的列表查询参数
+func (s *FilterService) Request(ctx context.Context, id, userID uint) (*todo.ListRequest, error) {
+	saved, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return definitionRequest(saved.Definition), nil
+}
+
+// todoFilter 将保存的过滤条件转换为仓储层的过滤条件
+func (s *FilterService) todoFilter(ctx context.Context, userID uint, def models.FilterDefinition) (repository.TodoFilter, error) {
+	return buildTodoFilter(ctx, s.categoryRepo, userID, definitionRequest(def))
+}
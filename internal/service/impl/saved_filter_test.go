@@ -0,0 +1,172 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo/api/v1/dto/category"
+	"todo/api/v1/dto/filter"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/errors"
+)
+
+// mockSavedFilterRepo 模拟过滤条件仓储接口
+type mockSavedFilterRepo struct {
+	filters map[uint]*models.SavedFilter
+	seq     uint
+}
+
+func newMockSavedFilterRepo() *mockSavedFilterRepo {
+	return &mockSavedFilterRepo{filters: make(map[uint]*models.SavedFilter), seq: 1}
+}
+
+func (m *mockSavedFilterRepo) Create(ctx context.Context, f *models.SavedFilter) error {
+	f.ID = m.seq
+	m.filters[f.ID] = f
+	m.seq++
+	return nil
+}
+
+func (m *mockSavedFilterRepo) GetByID(ctx context.Context, id uint) (*models.SavedFilter, error) {
+	f, exists := m.filters[id]
+	if !exists {
+		return nil, errors.ErrFilterNotFound
+	}
+	return f, nil
+}
+
+func (m *mockSavedFilterRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.SavedFilter, error) {
+	var filters []*models.SavedFilter
+	for id := uint(1); id < m.seq; id++ {
+		if f, ok := m.filters[id]; ok && f.UserID == userID {
+			filters = append(filters, f)
+		}
+	}
+	return filters, nil
+}
+
+func (m *mockSavedFilterRepo) Update(ctx context.Context, f *models.SavedFilter) error {
+	m.filters[f.ID] = f
+	return nil
+}
+
+func (m *mockSavedFilterRepo) Delete(ctx context.Context, id uint) error {
+	delete(m.filters, id)
+	return nil
+}
+
+// TestApplyDueFilter 测试相对截止时间的换算
+func TestApplyDueFilter(t *testing.T) {
+	// 2024-05-16 是周四
+	now := time.Date(2024, 5, 16, 15, 30, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		due        string
+		from, to   *time.Time
+		noDueDate  bool
+		incomplete bool
+	}{
+		{due: models.DueToday, from: ptr(day(16)), to: ptr(day(17))},
+		{due: models.DueThisWeek, from: ptr(day(13)), to: ptr(day(20))},
+		{due: models.DueOverdue, to: &now, incomplete: true},
+		{due: models.DueNone, noDueDate: true},
+		{due: ""},
+	}
+
+	for _, tt := range tests {
+		var f repository.TodoFilter
+		applyDueFilter(&f, tt.due, now)
+		if !sameTime(f.DueFrom, tt.from) || !sameTime(f.DueBefore, tt.to) {
+			t.Errorf("%q: 范围 = [%v, %v), 期望 [%v, %v)", tt.due, f.DueFrom, f.DueBefore, tt.from, tt.to)
+		}
+		if f.NoDueDate != tt.noDueDate {
+			t.Errorf("%q: NoDueDate = %v, 期望 %v", tt.due, f.NoDueDate, tt.noDueDate)
+		}
+		if incomplete := f.Completed != nil && !*f.Completed; incomplete != tt.incomplete {
+			t.Errorf("%q: 只看未完成 = %v, 期望 %v", tt.due, incomplete, tt.incomplete)
+		}
+	}
+
+	// 周日属于本周，而不是下一周
+	var f repository.TodoFilter
+	applyDueFilter(&f, models.DueThisWeek, time.Date(2024, 5, 19, 23, 0, 0, 0, time.UTC))
+	if !f.DueFrom.Equal(day(13)) {
+		t.Errorf("周日的本周开始 = %v, 期望 %v", f.DueFrom, day(13))
+	}
+}
+
+// TestFilterService 测试保存、统计和求值过滤条件
+func TestFilterService(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	categoryRepo := newMockCategoryRepo()
+	historyRepo := newMockHistoryRepo()
+	todoService := NewTodoService(todoRepo, newMockReminderRepo(), categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), historyRepo, nopTransactor{}, &mockNotifier{})
+	categoryService := NewCategoryService(categoryRepo, todoService, historyRepo, nopTransactor{})
+	filterService := NewFilterService(newMockSavedFilterRepo(), todoRepo, categoryRepo)
+
+	work, _ := categoryService.Create(ctx, 1, &category.CreateRequest{Name: "工作"})
+	today := time.Now()
+	nextYear := today.AddDate(1, 0, 0)
+	match, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "周报", Priority: "high", CategoryID: &work, DueDate: &today})
+	todoService.Create(ctx, 1, &todo.CreateRequest{Title: "低优先级", Priority: "low", CategoryID: &work, DueDate: &today})
+	todoService.Create(ctx, 1, &todo.CreateRequest{Title: "明年", Priority: "high", CategoryID: &work, DueDate: &nextYear})
+	todoService.Create(ctx, 1, &todo.CreateRequest{Title: "无分类", Priority: "high", DueDate: &today})
+
+	saved, err := filterService.Create(ctx, 1, &filter.CreateRequest{
+		Name:       "今天到期的高优先级工作",
+		Definition: models.FilterDefinition{Priority: "high", Due: models.DueToday, CategoryID: &work},
+	})
+	if err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+
+	req, err := filterService.Request(ctx, saved.ID, 1)
+	if err != nil {
+		t.Fatalf("Request() 错误 = %v", err)
+	}
+	todos, err := todoService.List(ctx, 1, req)
+	if err != nil || len(todos) != 1 || todos[0].ID != match {
+		t.Fatalf("List() = %v, %v, 期望只包含待办事项 %d", todos, err, match)
+	}
+
+	items, err := filterService.List(ctx, 1)
+	if err != nil || len(items) != 1 || items[0].Count != 1 {
+		t.Fatalf("List() = %v, %v, 期望一个数量为 1 的过滤条件", items, err)
+	}
+
+	// 条件变化后数量实时更新
+	todoService.Update(ctx, match, 1, &todo.UpdateRequest{ClearDueDate: true})
+	items, _ = filterService.List(ctx, 1)
+	if items[0].Count != 0 {
+		t.Errorf("清除截止时间后数量 = %d, 期望 0", items[0].Count)
+	}
+
+	// 其他用户无法使用
+	if _, err := filterService.Request(ctx, saved.ID, 2); err != errors.ErrForbidden {
+		t.Errorf("其他用户 Request() 错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+
+	// 引用不存在的分类时拒绝保存
+	missing := uint(99)
+	_, err = filterService.Create(ctx, 1, &filter.CreateRequest{
+		Name:       "无效",
+		Definition: models.FilterDefinition{CategoryID: &missing},
+	})
+	if err != errors.ErrCategoryNotFound {
+		t.Errorf("Create() 错误 = %v, 期望 %v", err, errors.ErrCategoryNotFound)
+	}
+}
+
+func ptr(t time.Time) *time.Time { return &t }
+
+// sameTime 比较两个可能为空的时间
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
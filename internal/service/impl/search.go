@@ -0,0 +1,181 @@
+package impl
+
+import (
+	"context"
+	"html"
+	"strings"
+	"todo/api/v1/dto/search"
+	"todo/api/v1/dto/todo"
+	"todo/internal/repository"
+	"todo/pkg/errors"
+	"unicode"
+)
+
+const (
+	defaultSearchPageSize = 20  // 默认每页数量
+	maxSearchPageSize     = 100 // 每页数量上限
+	snippetLength         = 120 // 摘要的最大字符数
+)
+
+// SearchService 全文搜索服务实现
+type SearchService struct {
+	index        repository.SearchIndex
+	categoryRepo repository.CategoryRepository
+}
+
+// NewSearchService 创建一个新的搜索服务实例
+//
+// Parameters:
+//   - index: 全文搜索实现
+//   - categoryRepo: 分类仓库实现，用于按分类树过滤
+//
+// Returns:
+//   - *SearchService: 返回搜索服务实例
+func NewSearchService(index repository.SearchIndex, categoryRepo repository.CategoryRepository) *SearchService {
+	return &SearchService{
+		index:        index,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// Search 搜索待办事项
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID
+//   - req: 搜索文本、过滤条件和分页参数
+//
+// Returns:
+//   - *search.Response: 按相关度降序的搜索结果
+//   - error: 搜索文本为空白时返回 ErrInvalidParameter
+func (s *SearchService) Search(ctx context.Context, userID uint, req *search.Request) (*search.Response, error) {
+	terms := strings.Fields(req.Q)
+	if len(terms) == 0 {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	filter, err := buildTodoFilter(ctx, s.categoryRepo, userID, &todo.ListRequest{
+		Archived:           req.Archived,
+		Completed:          req.Completed,
+		Query:              req.Query,
+		Priority:           req.Priority,
+		Due:                req.Due,
+		CategoryID:         req.CategoryID,
+		IncludeDescendants: req.IncludeDescendants,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultSearchPageSize
+	} else if pageSize > maxSearchPageSize {
+		pageSize = maxSearchPageSize
+	}
+
+	hits, total, err := s.index.Search(ctx, repository.SearchQuery{
+		Text:     req.Q,
+		UserID:   userID,
+		Filter:   filter,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*search.Item, 0, len(hits))
+	for _, hit := range hits {
+		highlights := make(map[string]string)
+		for field, text := range map[string]string{
+			"title":       hit.Todo.Title,
+			"description": hit.Todo.Description,
+			"comment":     hit.Comment,
+		} {
+			if snippet := highlight(text, terms, snippetLength); snippet != "" {
+				highlights[field] = snippet
+			}
+		}
+		items = append(items, &search.Item{
+			Todo:       hit.Todo,
+			Score:      hit.Score,
+			Highlights: highlights,
+		})
+	}
+
+	return &search.Response{Total: total, Items: items}, nil
+}
+
+// highlight 生成文本中第一个命中词附近的摘要，命中的词用 <mark> 包裹
+// 匹配不区分大小写；摘要中的其他内容做 HTML 转义；没有命中时返回空字符串
+func highlight(text string, terms []string, length int) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// 标记所有命中的字符
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		needle := []rune(strings.ToLower(term))
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if string(lower[i:i+len(needle)]) != string(needle) {
+				continue
+			}
+			for j := i; j < i+len(needle); j++ {
+				marked[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	// 以第一个命中词为中心截取摘要
+	start, end := 0, len(runes)
+	if len(runes) > length {
+		start = first - length/4
+		if start < 0 {
+			start = 0
+		}
+		end = start + length
+		if end > len(runes) {
+			end = len(runes)
+			start = end - length
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+		segment := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			b.WriteString("<mark>" + segment + "</mark>")
+		} else {
+			b.WriteString(segment)
+		}
+		i = j
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,176 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"todo/api/v1/dto/search"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/internal/tenant"
+	"todo/pkg/errors"
+)
+
+// TestHighlight 测试搜索摘要的截取、高亮与转义
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		terms  []string
+		length int
+		want   string
+	}{
+		{"未命中", "买牛奶", []string{"面包"}, 20, ""},
+		{"不区分大小写", "Write the Report", []string{"report"}, 40, "Write the <mark>Report</mark>"},
+		{"多个词", "周报 和 月报", []string{"周报", "月报"}, 40, "<mark>周报</mark> 和 <mark>月报</mark>"},
+		{"转义", "<b>urgent</b> fix", []string{"fix"}, 40, "&lt;b&gt;urgent&lt;/b&gt; <mark>fix</mark>"},
+		{"截取", "0123456789abcdefghijklmn", []string{"f"}, 8, "…de<mark>f</mark>ghijk…"},
+		{"靠近末尾", "0123456789abcdefghij", []string{"i"}, 8, "…cdefgh<mark>i</mark>j"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlight(tt.text, tt.terms, tt.length); got != tt.want {
+				t.Errorf("highlight() = %q, 期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeSearchIndex 模拟全文搜索实现
+// 按标题子串匹配，与真实实现一样只返回当前工作空间和指定用户的待办事项，并记录收到的查询
+type fakeSearchIndex struct {
+	todos    []*models.Todo
+	comments map[uint]string // 待办事项ID到评论正文
+	queries  []repository.SearchQuery
+}
+
+func (f *fakeSearchIndex) Search(ctx context.Context, query repository.SearchQuery) ([]*repository.SearchHit, int64, error) {
+	f.queries = append(f.queries, query)
+	wsID, _ := tenant.WorkspaceIDFromContext(ctx)
+	var hits []*repository.SearchHit
+	for _, todo := range f.todos {
+		if todo.WorkspaceID != wsID || todo.UserID != query.UserID {
+			continue
+		}
+		comment := f.comments[todo.ID]
+		if strings.Contains(todo.Title, query.Text) || strings.Contains(comment, query.Text) {
+			hits = append(hits, &repository.SearchHit{Todo: todo, Score: 1, Comment: comment})
+		}
+	}
+	return hits, int64(len(hits)), nil
+}
+
+// TestSearchService_Filters 测试搜索与列表过滤条件、查询语言组合使用
+func TestSearchService_Filters(t *testing.T) {
+	ctx := tenant.WithWorkspaceID(context.Background(), 1)
+	categoryRepo := newMockCategoryRepo()
+	index := &fakeSearchIndex{}
+	service := NewSearchService(index, categoryRepo)
+	work := &models.Category{Name: "工作", UserID: 1}
+	categoryRepo.Create(ctx, work)
+	reports := &models.Category{Name: "报告", UserID: 1, ParentID: &work.ID}
+	categoryRepo.Create(ctx, reports)
+
+	_, err := service.Search(ctx, 1, &search.Request{
+		Q:                  "周报",
+		Priority:           "high",
+		Query:              "tag:weekly",
+		CategoryID:         &work.ID,
+		IncludeDescendants: true,
+	})
+	if err != nil {
+		t.Fatalf("Search() 错误 = %v", err)
+	}
+	q := index.queries[0]
+	if q.Text != "周报" || q.UserID != 1 || q.Filter.Priority != "high" || q.Filter.Query == nil {
+		t.Errorf("查询 = %+v, 期望带上优先级和查询语言条件", q)
+	}
+	if len(q.Filter.CategoryIDs) != 2 {
+		t.Errorf("CategoryIDs = %v, 期望包含分类及其下级分类", q.Filter.CategoryIDs)
+	}
+
+	// 查询语言语法错误
+	if _, err := service.Search(ctx, 1, &search.Request{Q: "周报", Query: "priority:"}); err == nil {
+		t.Error("Search() 无效查询语言未返回错误")
+	}
+	// 其他用户的分类
+	other := &models.Category{Name: "别人的", UserID: 2}
+	categoryRepo.Create(ctx, other)
+	if _, err := service.Search(ctx, 1, &search.Request{Q: "周报", CategoryID: &other.ID}); err != errors.ErrForbidden {
+		t.Errorf("Search() 其他用户的分类错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+}
+
+// TestSearchService_Paging 测试分页参数的默认值和上限，以及空白搜索文本
+func TestSearchService_Paging(t *testing.T) {
+	ctx := tenant.WithWorkspaceID(context.Background(), 1)
+	index := &fakeSearchIndex{}
+	service := NewSearchService(index, newMockCategoryRepo())
+
+	tests := []struct {
+		page, pageSize         int
+		wantPage, wantPageSize int
+	}{
+		{0, 0, 1, defaultSearchPageSize},
+		{3, 50, 3, 50},
+		{2, 1000, 2, maxSearchPageSize},
+	}
+	for _, tt := range tests {
+		if _, err := service.Search(ctx, 1, &search.Request{Q: "周报", Page: tt.page, PageSize: tt.pageSize}); err != nil {
+			t.Fatalf("Search() 错误 = %v", err)
+		}
+		q := index.queries[len(index.queries)-1]
+		if q.Page != tt.wantPage || q.PageSize != tt.wantPageSize {
+			t.Errorf("page=%d page_size=%d 时查询分页 = %d/%d, 期望 %d/%d",
+				tt.page, tt.pageSize, q.Page, q.PageSize, tt.wantPage, tt.wantPageSize)
+		}
+	}
+
+	for _, q := range []string{"", "  \t"} {
+		if _, err := service.Search(ctx, 1, &search.Request{Q: q}); err != errors.ErrInvalidParameter {
+			t.Errorf("Search(%q) 错误 = %v, 期望 %v", q, err, errors.ErrInvalidParameter)
+		}
+	}
+	if len(index.queries) != len(tests) {
+		t.Errorf("空白搜索文本执行了查询, 共 %d 次", len(index.queries))
+	}
+}
+
+// TestSearchService_Results 测试评论命中的摘要，以及结果只包含当前工作空间
+func TestSearchService_Results(t *testing.T) {
+	index := &fakeSearchIndex{
+		todos: []*models.Todo{
+			{Base: models.Base{ID: 1}, Title: "整理周报", WorkspaceID: 1, UserID: 1},
+			{Base: models.Base{ID: 2}, Title: "会议纪要", WorkspaceID: 1, UserID: 1},
+			{Base: models.Base{ID: 3}, Title: "团队周报", WorkspaceID: 2, UserID: 1},
+		},
+		comments: map[uint]string{2: "记得附上<周报>链接"},
+	}
+	service := NewSearchService(index, newMockCategoryRepo())
+
+	result, err := service.Search(tenant.WithWorkspaceID(context.Background(), 1), 1, &search.Request{Q: "周报"})
+	if err != nil {
+		t.Fatalf("Search() 错误 = %v", err)
+	}
+	if result.Total != 2 || len(result.Items) != 2 {
+		t.Fatalf("Search() 返回 %d/%d 条, 期望只有当前工作空间的 2 条", len(result.Items), result.Total)
+	}
+	for _, item := range result.Items {
+		if item.Todo.WorkspaceID != 1 {
+			t.Errorf("结果包含其他工作空间的待办事项 %d", item.Todo.ID)
+		}
+	}
+
+	byTitle := result.Items[0]
+	if byTitle.Highlights["title"] != "整理<mark>周报</mark>" || byTitle.Highlights["comment"] != "" {
+		t.Errorf("标题命中的摘要 = %v", byTitle.Highlights)
+	}
+	byComment := result.Items[1]
+	if _, ok := byComment.Highlights["title"]; ok {
+		t.Errorf("评论命中时不应有标题摘要: %v", byComment.Highlights)
+	}
+	if got := byComment.Highlights["comment"]; got != "记得附上&lt;<mark>周报</mark>&gt;链接" {
+		t.Errorf("评论摘要 = %q, 期望转义并标记命中词", got)
+	}
+}
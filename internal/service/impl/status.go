@@ -0,0 +1,304 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"todo/api/v1/dto/status"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/errors"
+)
+
+// boardPageSize 看板一次显示的最大待办事项数量
+const boardPageSize = 500
+
+// StatusTodos 修改或删除工作流状态时处理其中待办事项的操作，由 TodoService 实现
+type StatusTodos interface {
+	ReassignStatus(ctx context.Context, actorID, from uint, to *models.Status) (int, error)
+}
+
+// StatusService 看板工作流状态服务实现
+type StatusService struct {
+	statusRepo   repository.StatusRepository
+	todoRepo     repository.TodoRepository
+	categoryRepo repository.CategoryRepository
+	todos        StatusTodos
+	tx           repository.Transactor
+}
+
+// NewStatusService 创建一个新的工作流状态服务实例
+//
+// Parameters:
+//   - statusRepo: 工作流状态仓库实现
+//   - todoRepo: 待办事项仓库实现，用于生成看板
+//   - categoryRepo: 分类仓库实现，用于按分类过滤看板
+//   - todos: 修改或删除状态时处理其中的待办事项
+//   - tx: 事务执行器
+//
+// Returns:
+//   - *StatusService: 返回工作流状态服务实例
+func NewStatusService(statusRepo repository.StatusRepository, todoRepo repository.TodoRepository,
+	categoryRepo repository.CategoryRepository, todos StatusTodos, tx repository.Transactor) *StatusService {
+	return &StatusService{
+		statusRepo:   statusRepo,
+		todoRepo:     todoRepo,
+		categoryRepo: categoryRepo,
+		todos:        todos,
+		tx:           tx,
+	}
+}
+
+// Create 创建工作流状态，新状态排在看板最右侧
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID
+//   - req: 状态名、是否为终止状态和在制品上限
+//
+// Returns:
+//   - *models.Status: 新创建的工作流状态
+//   - error: 同名状态已存在时返回 ErrStatusExists
+func (s *StatusService) Create(ctx context.Context, userID uint, req *status.CreateRequest) (*models.Status, error) {
+	statuses, err := s.statusRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.ErrInvalidParameter
+	}
+	if nameTaken(statuses, name, 0) {
+		return nil, errors.ErrStatusExists
+	}
+
+	item := &models.Status{
+		UserID:   userID,
+		Name:     name,
+		Terminal: req.Terminal,
+		WIPLimit: req.WIPLimit,
+	}
+	if len(statuses) > 0 {
+		item.Position = statuses[len(statuses)-1].Position + 1
+	}
+	if err := s.statusRepo.Create(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// List 获取用户的工作流状态，按看板顺序排列
+func (s *StatusService) List(ctx context.Context, userID uint) ([]*models.Status, error) {
+	return s.statusRepo.ListByUserID(ctx, userID)
+}
+
+// Get 获取工作流状态详情
+func (s *StatusService) Get(ctx context.Context, id, userID uint) (*models.Status, error) {
+	item, err := s.statusRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.UserID != userID {
+		return nil, errors.ErrForbidden
+	}
+	return item, nil
+}
+
+// Update 更新工作流状态
+// 修改是否为终止状态时，其中所有待办事项的完成状态随之同步
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - id: 工作流状态ID
+//   - userID: 用户ID
+//   - req: 需要更新的字段
+//
+// Returns:
+//   - *models.Status: 更新后的工作流状态
+//   - error: 可能的错误信息
+func (s *StatusService) Update(ctx context.Context, id, userID uint, req *status.UpdateRequest) (*models.Status, error) {
+	item, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	terminalChanged := req.Terminal != nil && *req.Terminal != item.Terminal
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.ErrInvalidParameter
+		}
+		statuses, err := s.statusRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if nameTaken(statuses, name, item.ID) {
+			return nil, errors.ErrStatusExists
+		}
+		item.Name = name
+	}
+	if req.Terminal != nil {
+		item.Terminal = *req.Terminal
+	}
+	if req.WIPLimit != nil {
+		item.WIPLimit = *req.WIPLimit
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.statusRepo.Update(ctx, item); err != nil {
+			return err
+		}
+		if !terminalChanged {
+			return nil
+		}
+		_, err := s.todos.ReassignStatus(ctx, userID, item.ID, item)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Reorder 调整看板列顺序，ids 必须恰好包含用户的所有工作流状态
+func (s *StatusService) Reorder(ctx context.Context, userID uint, req *status.ReorderRequest) ([]*models.Status, error) {
+	statuses, err := s.statusRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Status, len(statuses))
+	for _, item := range statuses {
+		byID[item.ID] = item
+	}
+	if len(req.IDs) != len(statuses) {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	ordered := make([]*models.Status, 0, len(req.IDs))
+	for i, id := range req.IDs {
+		item, ok := byID[id]
+		if !ok {
+			return nil, errors.ErrInvalidParameter
+		}
+		delete(byID, id)
+		item.Position = i
+		ordered = append(ordered, item)
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, item := range ordered {
+			if err := s.statusRepo.Update(ctx, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ordered, nil
+}
+
+// Delete 删除工作流状态
+// 其中的待办事项移动到 req.MoveTo 指定的状态（完成状态随之同步），未指定时清除待办事项的状态
+//
+// Returns:
+//   - int: 受影响的待办事项数量
+//   - error: 目标状态无效或达到在制品上限时返回错误
+func (s *StatusService) Delete(ctx context.Context, id, userID uint, req *status.DeleteRequest) (int, error) {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return 0, err
+	}
+	var target *models.Status
+	if req.MoveTo != nil {
+		if *req.MoveTo == id {
+			return 0, errors.ErrInvalidParameter
+		}
+		var err error
+		if target, err = s.Get(ctx, *req.MoveTo, userID); err != nil {
+			return 0, err
+		}
+	}
+
+	var affected int
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if affected, err = s.todos.ReassignStatus(ctx, userID, id, target); err != nil {
+			return err
+		}
+		return s.statusRepo.Delete(ctx, id)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// Board 获取看板：按工作流状态分列的未归档待办事项，列内按手动排序位置排列
+// 存在未设置状态的待办事项时，它们排在最左侧状态为空的一列
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID
+//   - req: 分类过滤条件
+//
+// Returns:
+//   - *status.BoardResponse: 看板的各列
+//   - error: 可能的错误信息
+func (s *StatusService) Board(ctx context.Context, userID uint, req *status.BoardRequest) (*status.BoardResponse, error) {
+	statuses, err := s.statusRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := buildTodoFilter(ctx, s.categoryRepo, userID, &todo.ListRequest{
+		CategoryID:         req.CategoryID,
+		IncludeDescendants: req.IncludeDescendants,
+		Sort:               todo.SortPosition,
+	})
+	if err != nil {
+		return nil, err
+	}
+	todos, _, err := s.todoRepo.ListByUserID(ctx, userID, filter, 1, boardPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	unassigned := &status.Column{Todos: []*models.Todo{}}
+	columns := make(map[uint]*status.Column, len(statuses))
+	resp := &status.BoardResponse{Columns: make([]*status.Column, 0, len(statuses)+1)}
+	for _, item := range statuses {
+		column := &status.Column{Status: item, WIPLimit: item.WIPLimit, Todos: []*models.Todo{}}
+		if item.WIPLimit > 0 {
+			id := item.ID
+			if column.WIPCount, err = s.todoRepo.CountByUserID(ctx, userID, repository.TodoFilter{StatusID: &id}); err != nil {
+				return nil, err
+			}
+			column.OverLimit = column.WIPCount > int64(item.WIPLimit)
+		}
+		columns[item.ID] = column
+		resp.Columns = append(resp.Columns, column)
+	}
+
+	for _, todoItem := range todos {
+		column := unassigned
+		if todoItem.StatusID != nil && columns[*todoItem.StatusID] != nil {
+			column = columns[*todoItem.StatusID]
+		}
+		column.Todos = append(column.Todos, todoItem)
+		column.Count++
+	}
+	if unassigned.Count > 0 {
+		resp.Columns = append([]*status.Column{unassigned}, resp.Columns...)
+	}
+	return resp, nil
+}
+
+// nameTaken 判断状态名是否已被 exceptID 以外的状态使用，不区分大小写
+func nameTaken(statuses []*models.Status, name string, exceptID uint) bool {
+	for _, item := range statuses {
+		if item.ID != exceptID && strings.EqualFold(item.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package impl
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"todo/api/v1/dto/status"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+)
+
+// mockStatusRepo 模拟工作流状态仓储接口
+type mockStatusRepo struct {
+	statuses map[uint]*models.Status
+	seq      uint
+}
+
+func newMockStatusRepo() *mockStatusRepo {
+	return &mockStatusRepo{statuses: make(map[uint]*models.Status), seq: 1}
+}
+
+func (m *mockStatusRepo) Create(ctx context.Context, s *models.Status) error {
+	s.ID = m.seq
+	m.statuses[s.ID] = s
+	m.seq++
+	return nil
+}
+
+func (m *mockStatusRepo) GetByID(ctx context.Context, id uint) (*models.Status, error) {
+	s, exists := m.statuses[id]
+	if !exists {
+		return nil, errors.ErrStatusNotFound
+	}
+	return s, nil
+}
+
+func (m *mockStatusRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.Status, error) {
+	var statuses []*models.Status
+	for _, s := range m.statuses {
+		if s.UserID == userID {
+			statuses = append(statuses, s)
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Position != statuses[j].Position {
+			return statuses[i].Position < statuses[j].Position
+		}
+		return statuses[i].ID < statuses[j].ID
+	})
+	return statuses, nil
+}
+
+func (m *mockStatusRepo) Update(ctx context.Context, s *models.Status) error {
+	m.statuses[s.ID] = s
+	return nil
+}
+
+func (m *mockStatusRepo) Delete(ctx context.Context, id uint) error {
+	delete(m.statuses, id)
+	return nil
+}
+
+// TestStatusService 测试工作流状态与完成状态的同步、在制品上限和看板分列
+func TestStatusService(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	categoryRepo := newMockCategoryRepo()
+	statusRepo := newMockStatusRepo()
+	todoService := NewTodoService(todoRepo, newMockReminderRepo(), categoryRepo, statusRepo, newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+	statusService := NewStatusService(statusRepo, todoRepo, categoryRepo, todoService, nopTransactor{})
+
+	// 没有定义状态时新建的待办事项没有状态
+	legacy, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "看板之前"})
+
+	backlog, _ := statusService.Create(ctx, 1, &status.CreateRequest{Name: "Backlog"})
+	doing, _ := statusService.Create(ctx, 1, &status.CreateRequest{Name: "In Progress", WIPLimit: 1})
+	review, _ := statusService.Create(ctx, 1, &status.CreateRequest{Name: "Review"})
+	done, _ := statusService.Create(ctx, 1, &status.CreateRequest{Name: "Done", Terminal: true})
+	if _, err := statusService.Create(ctx, 1, &status.CreateRequest{Name: "done"}); err != errors.ErrStatusExists {
+		t.Errorf("Create() 重名错误 = %v, 期望 %v", err, errors.ErrStatusExists)
+	}
+
+	a, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "A"})
+	b, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "B"})
+	if got, _ := todoService.Get(ctx, a, 1); got.StatusID == nil || *got.StatusID != backlog.ID {
+		t.Fatalf("新建待办事项的状态 = %v, 期望 Backlog", got.StatusID)
+	}
+
+	t.Run("移入状态同步完成状态", func(t *testing.T) {
+		if err := todoService.Update(ctx, a, 1, &todo.UpdateRequest{StatusID: &done.ID}); err != nil {
+			t.Fatalf("Update() 错误 = %v", err)
+		}
+		got, _ := todoService.Get(ctx, a, 1)
+		if !got.Completed || got.CompletedAt == nil {
+			t.Errorf("移入终止状态后应已完成")
+		}
+
+		reopen := false
+		if err := todoService.Update(ctx, a, 1, &todo.UpdateRequest{Completed: &reopen}); err != nil {
+			t.Fatalf("Update() 错误 = %v", err)
+		}
+		got, _ = todoService.Get(ctx, a, 1)
+		if got.Completed || got.StatusID == nil || *got.StatusID != backlog.ID {
+			t.Errorf("取消完成后状态 = %v, 期望回到 Backlog", got.StatusID)
+		}
+
+		completed := true
+		err := todoService.Update(ctx, a, 1, &todo.UpdateRequest{StatusID: &review.ID, Completed: &completed})
+		if err != errors.ErrInvalidParameter {
+			t.Errorf("Update() 矛盾请求错误 = %v, 期望 %v", err, errors.ErrInvalidParameter)
+		}
+	})
+
+	t.Run("在制品上限", func(t *testing.T) {
+		if err := todoService.Update(ctx, a, 1, &todo.UpdateRequest{StatusID: &doing.ID}); err != nil {
+			t.Fatalf("Update() 错误 = %v", err)
+		}
+		if err := todoService.Update(ctx, b, 1, &todo.UpdateRequest{StatusID: &doing.ID}); err != errors.ErrWIPLimit {
+			t.Errorf("Update() 错误 = %v, 期望 %v", err, errors.ErrWIPLimit)
+		}
+		// 已在该状态中的待办事项再次设置同一状态不受上限影响
+		if err := todoService.Update(ctx, a, 1, &todo.UpdateRequest{StatusID: &doing.ID}); err != nil {
+			t.Errorf("Update() 错误 = %v", err)
+		}
+	})
+
+	t.Run("修改终止状态同步待办事项", func(t *testing.T) {
+		terminal := true
+		if _, err := statusService.Update(ctx, doing.ID, 1, &status.UpdateRequest{Terminal: &terminal}); err != nil {
+			t.Fatalf("Update() 错误 = %v", err)
+		}
+		if got, _ := todoService.Get(ctx, a, 1); !got.Completed {
+			t.Errorf("状态改为终止状态后待办事项应已完成")
+		}
+		terminal = false
+		statusService.Update(ctx, doing.ID, 1, &status.UpdateRequest{Terminal: &terminal})
+	})
+
+	t.Run("看板", func(t *testing.T) {
+		if _, err := statusService.Reorder(ctx, 1, &status.ReorderRequest{IDs: []uint{doing.ID, backlog.ID}}); err != errors.ErrInvalidParameter {
+			t.Errorf("Reorder() 缺少状态时错误 = %v, 期望 %v", err, errors.ErrInvalidParameter)
+		}
+		if _, err := statusService.Reorder(ctx, 1, &status.ReorderRequest{IDs: []uint{backlog.ID, review.ID, doing.ID, done.ID}}); err != nil {
+			t.Fatalf("Reorder() 错误 = %v", err)
+		}
+
+		board, err := statusService.Board(ctx, 1, &status.BoardRequest{})
+		if err != nil {
+			t.Fatalf("Board() 错误 = %v", err)
+		}
+		want := []struct {
+			status *models.Status
+			todos  []uint
+		}{
+			{nil, []uint{legacy}},
+			{backlog, []uint{b}},
+			{review, nil},
+			{doing, []uint{a}},
+			{done, nil},
+		}
+		if len(board.Columns) != len(want) {
+			t.Fatalf("看板列数 = %d, 期望 %d", len(board.Columns), len(want))
+		}
+		for i, w := range want {
+			column := board.Columns[i]
+			if (column.Status == nil) != (w.status == nil) || column.Status != nil && column.Status.ID != w.status.ID {
+				t.Errorf("第 %d 列状态 = %v, 期望 %v", i, column.Status, w.status)
+			}
+			if column.Count != len(w.todos) || len(w.todos) > 0 && column.Todos[0].ID != w.todos[0] {
+				t.Errorf("第 %d 列待办事项数量 = %d, 期望 %v", i, column.Count, w.todos)
+			}
+		}
+		if doingColumn := board.Columns[3]; doingColumn.WIPCount != 1 || doingColumn.OverLimit {
+			t.Errorf("在制品数量 = %d, 超限 = %v, 期望 1 且未超限", doingColumn.WIPCount, doingColumn.OverLimit)
+		}
+	})
+
+	t.Run("删除状态", func(t *testing.T) {
+		if _, err := statusService.Delete(ctx, doing.ID, 1, &status.DeleteRequest{MoveTo: &doing.ID}); err != errors.ErrInvalidParameter {
+			t.Errorf("Delete() 移动到自身错误 = %v, 期望 %v", err, errors.ErrInvalidParameter)
+		}
+		affected, err := statusService.Delete(ctx, doing.ID, 1, &status.DeleteRequest{MoveTo: &done.ID})
+		if err != nil || affected != 1 {
+			t.Fatalf("Delete() = %d, %v, 期望移动 1 个待办事项", affected, err)
+		}
+		got, _ := todoService.Get(ctx, a, 1)
+		if got.StatusID == nil || *got.StatusID != done.ID || !got.Completed {
+			t.Errorf("删除状态后待办事项状态 = %v, 完成 = %v, 期望移入 Done 并完成", got.StatusID, got.Completed)
+		}
+		if _, err := statusService.Delete(ctx, done.ID, 2, &status.DeleteRequest{}); err != errors.ErrForbidden {
+			t.Errorf("Delete() 他人状态错误 = %v, 期望 %v", err, errors.ErrForbidden)
+		}
+	})
+}
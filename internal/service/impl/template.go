@@ -0,0 +1,320 @@
+package impl
+
+import (
+	"context"
+	"time"
+	"todo/api/v1/dto/reminder"
+	"todo/api/v1/dto/template"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/errors"
+)
+
+// maxTemplateItems 模板中待办事项（包括各级子任务）的数量上限
+const maxTemplateItems = 100
+
+// TemplateService 待办事项模板服务实现
+// 模板中的子任务实例化为阻塞其父任务的待办事项：全部子任务完成之前父任务不能完成
+type TemplateService struct {
+	templateRepo repository.TemplateRepository
+	categoryRepo repository.CategoryRepository
+	depRepo      repository.DependencyRepository
+	todos        *TodoService     // 创建待办事项，保证工作流状态、排序位置和变更历史与普通创建一致
+	reminders    *ReminderService // 创建提醒并记录变更历史
+	tx           repository.Transactor
+}
+
+// NewTemplateService 创建一个新的模板服务实例
+//
+// Parameters:
+//   - templateRepo: 模板仓库实现
+//   - categoryRepo: 分类仓库实现，用于校验模板引用的分类
+//   - depRepo: 依赖关系仓库实现，用于读取和建立子任务关系
+//   - todos: 待办事项服务
+//   - reminders: 提醒服务
+//   - tx: 事务执行器，实例化模板时所有待办事项在同一个事务中创建
+//
+// Returns:
+//   - *TemplateService: 返回模板服务实例
+func NewTemplateService(templateRepo repository.TemplateRepository, categoryRepo repository.CategoryRepository,
+	depRepo repository.DependencyRepository, todos *TodoService, reminders *ReminderService,
+	tx repository.Transactor) *TemplateService {
+	return &TemplateService{
+		templateRepo: templateRepo,
+		categoryRepo: categoryRepo,
+		depRepo:      depRepo,
+		todos:        todos,
+		reminders:    reminders,
+		tx:           tx,
+	}
+}
+
+// Create 创建模板
+//
+// Returns:
+//   - *models.Template: 新建的模板
+//   - error: 待办事项超过上限返回 ErrTemplateTooLarge，引用的分类不属于当前用户返回错误
+func (s *TemplateService) Create(ctx context.Context, userID uint, req *template.CreateRequest) (*models.Template, error) {
+	if err := s.validate(ctx, userID, req.Items); err != nil {
+		return nil, err
+	}
+
+	tmpl := &models.Template{
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Items:       req.Items,
+	}
+	if err := s.templateRepo.Create(ctx, tmpl); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// List 获取用户的模板，按名称排列
+func (s *TemplateService) List(ctx context.Context, userID uint) ([]*models.Template, error) {
+	return s.templateRepo.ListByUserID(ctx, userID)
+}
+
+// Get 获取模板详情
+func (s *TemplateService) Get(ctx context.Context, id, userID uint) (*models.Template, error) {
+	tmpl, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl.UserID != userID {
+		return nil, errors.ErrForbidden
+	}
+	return tmpl, nil
+}
+
+// Update 更新模板的名称、说明或待办事项
+func (s *TemplateService) Update(ctx context.Context, id, userID uint, req *template.UpdateRequest) (*models.Template, error) {
+	tmpl, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		tmpl.Name = *req.Name
+	}
+	if req.Description != nil {
+		tmpl.Description = *req.Description
+	}
+	if req.Items != nil {
+		if err := s.validate(ctx, userID, *req.Items); err != nil {
+			return nil, err
+		}
+		tmpl.Items = *req.Items
+	}
+
+	if err := s.templateRepo.Update(ctx, tmpl); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// Delete 删除模板，已经创建的待办事项不受影响
+func (s *TemplateService) Delete(ctx context.Context, id, userID uint) error {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.templateRepo.Delete(ctx, id)
+}
+
+// FromTodo 将待办事项保存为模板
+// 待办事项的阻塞项递归保存为子任务；截止时间换算为相对基准时间的偏移，提醒换算为相对截止时间（没有截止时间时相对基准时间）的偏移
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID
+//   - todoID: 待办事项ID
+//   - req: 模板名称、说明和基准时间，基准时间为空时使用待办事项的创建时间
+//
+// Returns:
+//   - *models.Template: 新建的模板
+//   - error: 待办事项不存在、不属于当前用户或包括阻塞项在内超过上限时返回错误
+func (s *TemplateService) FromTodo(ctx context.Context, userID, todoID uint, req *template.FromTodoRequest) (*models.Template, error) {
+	root, err := s.todos.Get(ctx, todoID, userID)
+	if err != nil {
+		return nil, err
+	}
+	base := root.CreatedAt
+	if req.BaseDate != nil {
+		base = *req.BaseDate
+	}
+
+	visited := map[uint]bool{}
+	item, err := s.capture(ctx, root, base, visited)
+	if err != nil {
+		return nil, err
+	}
+	return s.Create(ctx, userID, &template.CreateRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Items:       models.TemplateItems{*item},
+	})
+}
+
+// Instantiate 按基准时间在一个事务中创建模板中的所有待办事项，任何一个创建失败时全部回滚
+//
+// Returns:
+//   - []uint: 新建的待办事项ID，父任务排在其子任务之前
+//   - error: 模板不存在、引用的分类已被删除等错误
+func (s *TemplateService) Instantiate(ctx context.Context, id, userID uint, req *template.InstantiateRequest) ([]uint, error) {
+	tmpl, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(ctx, userID, tmpl.Items); err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ids = ids[:0]
+		for i := range tmpl.Items {
+			if _, err := s.instantiate(ctx, userID, &tmpl.Items[i], req.BaseDate, &ids); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// instantiate 创建模板中的一个待办事项及其子任务，新建的ID依次追加到 ids
+func (s *TemplateService) instantiate(ctx context.Context, userID uint, item *models.TemplateItem, base time.Time, ids *[]uint) (uint, error) {
+	anchor := base
+	var due *time.Time
+	if item.DueOffset != nil {
+		t := base.Add(time.Duration(*item.DueOffset) * time.Minute)
+		due, anchor = &t, t
+	}
+
+	todoID, err := s.todos.Create(ctx, userID, &todo.CreateRequest{
+		Title:       item.Title,
+		Description: item.Description,
+		Priority:    item.Priority,
+		CategoryID:  item.CategoryID,
+		DueDate:     due,
+		Tags:        item.Tags,
+		Estimate:    item.Estimate,
+	})
+	if err != nil {
+		return 0, err
+	}
+	*ids = append(*ids, todoID)
+
+	for _, r := range item.Reminders {
+		_, err := s.reminders.Create(ctx, userID, &reminder.CreateRequest{
+			TodoID:     todoID,
+			RemindAt:   anchor.Add(time.Duration(r.Offset) * time.Minute),
+			RemindType: r.RemindType,
+			NotifyType: r.NotifyType,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for i := range item.Subtasks {
+		subtaskID, err := s.instantiate(ctx, userID, &item.Subtasks[i], base, ids)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.depRepo.Create(ctx, &models.Dependency{TodoID: todoID, BlockerID: subtaskID}); err != nil {
+			return 0, err
+		}
+	}
+	return todoID, nil
+}
+
+// capture 将待办事项及其阻塞项转换为模板中的待办事项，visited 用于避免重复收录同一个阻塞项
+func (s *TemplateService) capture(ctx context.Context, todoItem *models.Todo, base time.Time, visited map[uint]bool) (*models.TemplateItem, error) {
+	visited[todoItem.ID] = true
+	if len(visited) > maxTemplateItems {
+		return nil, errors.ErrTemplateTooLarge
+	}
+
+	item := &models.TemplateItem{
+		Title:       todoItem.Title,
+		Description: todoItem.Description,
+		Priority:    string(todoItem.Priority),
+		CategoryID:  todoItem.CategoryID,
+		Estimate:    todoItem.Estimate,
+	}
+	for _, tag := range todoItem.Tags {
+		item.Tags = append(item.Tags, tag.Name)
+	}
+	anchor := base
+	if todoItem.DueDate != nil {
+		offset := minutesBetween(base, *todoItem.DueDate)
+		item.DueOffset, anchor = &offset, *todoItem.DueDate
+	}
+
+	reminders, err := s.reminders.ListByTodoID(ctx, todoItem.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reminders {
+		item.Reminders = append(item.Reminders, models.TemplateReminder{
+			Offset:     minutesBetween(anchor, r.RemindAt),
+			RemindType: r.RemindType,
+			NotifyType: r.NotifyType,
+		})
+	}
+
+	blockers, err := s.depRepo.ListBlockers(ctx, todoItem.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, blocker := range blockers {
+		if visited[blocker.ID] {
+			continue
+		}
+		subtask, err := s.capture(ctx, blocker, base, visited)
+		if err != nil {
+			return nil, err
+		}
+		item.Subtasks = append(item.Subtasks, *subtask)
+	}
+	return item, nil
+}
+
+// validate 校验模板中待办事项的数量和引用的分类
+func (s *TemplateService) validate(ctx context.Context, userID uint, items models.TemplateItems) error {
+	if items.Count() > maxTemplateItems {
+		return errors.ErrTemplateTooLarge
+	}
+	checked := map[uint]bool{}
+	var walk func(items []models.TemplateItem) error
+	walk = func(items []models.TemplateItem) error {
+		for _, item := range items {
+			if item.CategoryID != nil && !checked[*item.CategoryID] {
+				category, err := s.categoryRepo.GetByID(ctx, *item.CategoryID)
+				if err != nil {
+					return err
+				}
+				if category.UserID != userID {
+					return errors.ErrForbidden
+				}
+				checked[*item.CategoryID] = true
+			}
+			if err := walk(item.Subtasks); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(items)
+}
+
+// minutesBetween 返回从 from 到 to 经过的整分钟数，to 早于 from 时为负数
+func minutesBetween(from, to time.Time) int {
+	return int(to.Sub(from) / time.Minute)
+}
@@ -0,0 +1,151 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo/api/v1/dto/template"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+)
+
+// mockTemplateRepo 模拟模板仓储接口
+type mockTemplateRepo struct {
+	templates map[uint]*models.Template
+}
+
+func newMockTemplateRepo() *mockTemplateRepo {
+	return &mockTemplateRepo{templates: make(map[uint]*models.Template)}
+}
+
+func (m *mockTemplateRepo) Create(ctx context.Context, tmpl *models.Template) error {
+	tmpl.ID = uint(len(m.templates) + 1)
+	m.templates[tmpl.ID] = tmpl
+	return nil
+}
+
+func (m *mockTemplateRepo) GetByID(ctx context.Context, id uint) (*models.Template, error) {
+	tmpl, ok := m.templates[id]
+	if !ok {
+		return nil, errors.ErrTemplateNotFound
+	}
+	return tmpl, nil
+}
+
+func (m *mockTemplateRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.Template, error) {
+	var templates []*models.Template
+	for _, tmpl := range m.templates {
+		if tmpl.UserID == userID {
+			templates = append(templates, tmpl)
+		}
+	}
+	return templates, nil
+}
+
+func (m *mockTemplateRepo) Update(ctx context.Context, tmpl *models.Template) error {
+	m.templates[tmpl.ID] = tmpl
+	return nil
+}
+
+func (m *mockTemplateRepo) Delete(ctx context.Context, id uint) error {
+	delete(m.templates, id)
+	return nil
+}
+
+// TestTemplateService 测试模板的实例化、子任务阻塞关系以及将待办事项保存为模板
+func TestTemplateService(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	reminderRepo := newMockReminderRepo()
+	categoryRepo := newMockCategoryRepo()
+	historyRepo := newMockHistoryRepo()
+	depRepo := &mockDependencyRepo{todos: todoRepo}
+	todoService := NewTodoService(todoRepo, reminderRepo, categoryRepo, newMockStatusRepo(), depRepo, historyRepo, nopTransactor{}, &mockNotifier{})
+	reminderService := NewReminderService(reminderRepo, todoRepo, historyRepo, nopTransactor{})
+	templateService := NewTemplateService(newMockTemplateRepo(), categoryRepo, depRepo, todoService, reminderService, nopTransactor{})
+
+	release := &models.Category{Name: "发布", UserID: 1}
+	categoryRepo.Create(ctx, release)
+	others := &models.Category{Name: "他人的分类", UserID: 2}
+	categoryRepo.Create(ctx, others)
+
+	dayBefore, dayAfter := -24*60, 24*60
+	items := models.TemplateItems{{
+		Title:      "发布 v2",
+		Priority:   "high",
+		CategoryID: &release.ID,
+		Tags:       []string{"release"},
+		DueOffset:  &dayAfter,
+		Reminders:  []models.TemplateReminder{{Offset: -60, RemindType: models.RemindTypeOnceStr, NotifyType: models.NotifyTypeEmailStr}},
+		Subtasks: []models.TemplateItem{
+			{Title: "冻结代码", DueOffset: &dayBefore},
+			{Title: "更新变更日志"},
+		},
+	}}
+
+	t.Run("校验", func(t *testing.T) {
+		invalid := models.TemplateItems{{Title: "他人的分类", CategoryID: &others.ID}}
+		if _, err := templateService.Create(ctx, 1, &template.CreateRequest{Name: "无效", Items: invalid}); err != errors.ErrForbidden {
+			t.Errorf("引用他人的分类错误 = %v, 期望 %v", err, errors.ErrForbidden)
+		}
+		tooMany := make(models.TemplateItems, maxTemplateItems+1)
+		if _, err := templateService.Create(ctx, 1, &template.CreateRequest{Name: "过大", Items: tooMany}); err != errors.ErrTemplateTooLarge {
+			t.Errorf("超过上限错误 = %v, 期望 %v", err, errors.ErrTemplateTooLarge)
+		}
+	})
+
+	tmpl, err := templateService.Create(ctx, 1, &template.CreateRequest{Name: "发布检查清单", Items: items})
+	if err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+	if _, err := templateService.Instantiate(ctx, tmpl.ID, 2, &template.InstantiateRequest{}); err != errors.ErrForbidden {
+		t.Errorf("实例化他人的模板错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+
+	base := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+	ids, err := templateService.Instantiate(ctx, tmpl.ID, 1, &template.InstantiateRequest{BaseDate: base})
+	if err != nil {
+		t.Fatalf("Instantiate() 错误 = %v", err)
+	}
+	if len(ids) != 3 {
+		t.Fatalf("Instantiate() 创建 %d 个待办事项, 期望 3", len(ids))
+	}
+
+	parent, _ := todoService.Get(ctx, ids[0], 1)
+	if parent.Title != "发布 v2" || parent.Priority != models.PriorityHigh || !sameID(parent.CategoryID, &release.ID) ||
+		len(parent.Tags) != 1 || parent.DueDate == nil || !parent.DueDate.Equal(base.AddDate(0, 0, 1)) {
+		t.Errorf("父任务 = %+v, 期望按模板创建且截止时间为基准时间后一天", parent)
+	}
+	freeze, _ := todoService.Get(ctx, ids[1], 1)
+	if freeze.DueDate == nil || !freeze.DueDate.Equal(base.AddDate(0, 0, -1)) {
+		t.Errorf("子任务截止时间 = %v, 期望基准时间前一天", freeze.DueDate)
+	}
+	reminders, _ := reminderRepo.ListByTodoID(ctx, ids[0])
+	if len(reminders) != 1 || !reminders[0].RemindAt.Equal(parent.DueDate.Add(-time.Hour)) {
+		t.Errorf("提醒 = %+v, 期望截止前一小时", reminders)
+	}
+
+	// 子任务未全部完成之前父任务不能完成
+	completed := true
+	if err := todoService.Update(ctx, ids[0], 1, &todo.UpdateRequest{Completed: &completed}); err != errors.ErrTodoBlocked {
+		t.Errorf("完成父任务错误 = %v, 期望 %v", err, errors.ErrTodoBlocked)
+	}
+
+	t.Run("保存为模板", func(t *testing.T) {
+		saved, err := templateService.FromTodo(ctx, 1, ids[0], &template.FromTodoRequest{Name: "副本", BaseDate: &base})
+		if err != nil {
+			t.Fatalf("FromTodo() 错误 = %v", err)
+		}
+		if saved.Items.Count() != 3 {
+			t.Fatalf("FromTodo() 包含 %d 个待办事项, 期望 3", saved.Items.Count())
+		}
+		root := saved.Items[0]
+		if root.DueOffset == nil || *root.DueOffset != dayAfter || len(root.Reminders) != 1 || root.Reminders[0].Offset != -60 {
+			t.Errorf("FromTodo() 根任务 = %+v, 期望与原模板相同的偏移", root)
+		}
+		if len(root.Subtasks) != 2 || root.Subtasks[0].DueOffset == nil || *root.Subtasks[0].DueOffset != dayBefore {
+			t.Errorf("FromTodo() 子任务 = %+v, 期望与原模板相同", root.Subtasks)
+		}
+	})
+}
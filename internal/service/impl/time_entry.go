@@ -0,0 +1,246 @@
+package impl
+
+import (
+	"context"
+	"sort"
+	"time"
+	"todo/api/v1/dto/timeentry"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/internal/tenant"
+	"todo/pkg/errors"
+)
+
+// maxReportDays 单次时间报表允许的最大天数
+const maxReportDays = 366
+
+// TimeEntryService 时间记录服务实现
+// 每个用户同一时间最多只有一个正在运行的计时器，启动新的计时器会先停止正在运行的计时器
+type TimeEntryService struct {
+	entryRepo    repository.TimeEntryRepository
+	todoRepo     repository.TodoRepository
+	categoryRepo repository.CategoryRepository
+	tx           repository.Transactor
+	now          func() time.Time // 便于测试时固定当前时间
+}
+
+// NewTimeEntryService 创建一个新的时间记录服务实例
+//
+// Parameters:
+//   - entryRepo: 时间记录仓库实现
+//   - todoRepo: 待办事项仓库实现，用于校验所有权
+//   - categoryRepo: 分类仓库实现，用于在报表中显示分类名
+//   - tx: 事务执行器
+//
+// Returns:
+//   - *TimeEntryService: 返回时间记录服务实例
+func NewTimeEntryService(entryRepo repository.TimeEntryRepository, todoRepo repository.TodoRepository,
+	categoryRepo repository.CategoryRepository, tx repository.Transactor) *TimeEntryService {
+	return &TimeEntryService{
+		entryRepo:    entryRepo,
+		todoRepo:     todoRepo,
+		categoryRepo: categoryRepo,
+		tx:           tx,
+		now:          time.Now,
+	}
+}
+
+// Start 为待办事项启动计时器，用户正在运行的其他计时器（包括其他工作空间中的）会先被停止
+// 该待办事项的计时器已在运行时直接返回它；检查和创建在锁定用户计时器的事务中进行，并发启动不会产生多个计时器
+func (s *TimeEntryService) Start(ctx context.Context, userID, todoID uint, req *timeentry.StartRequest) (*models.TimeEntry, error) {
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	var entry *models.TimeEntry
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := s.now()
+		running, err := s.entryRepo.LockRunning(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if running != nil {
+			if running.TodoID == todoID {
+				entry = running
+				return nil
+			}
+			if err := s.stop(ctx, running, now); err != nil {
+				return err
+			}
+		}
+		entry = &models.TimeEntry{UserID: userID, TodoID: todoID, StartedAt: now, Note: req.Note}
+		return s.entryRepo.Create(ctx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Stop 停止待办事项正在运行的计时器
+//
+// Returns:
+//   - *models.TimeEntry: 已停止的时间记录
+//   - error: 该待办事项没有正在运行的计时器时返回 ErrTimerNotRunning
+func (s *TimeEntryService) Stop(ctx context.Context, userID, todoID uint) (*models.TimeEntry, error) {
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	var entry *models.TimeEntry
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		running, err := s.entryRepo.LockRunning(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if running == nil || running.TodoID != todoID {
+			return errors.ErrTimerNotRunning
+		}
+		entry = running
+		return s.stop(ctx, running, s.now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Running 获取用户正在运行的计时器，计时器可能属于其他工作空间；没有时返回 nil
+func (s *TimeEntryService) Running(ctx context.Context, userID uint) (*models.TimeEntry, error) {
+	return s.entryRepo.GetRunning(ctx, userID)
+}
+
+// Add 手动录入一段时间
+func (s *TimeEntryService) Add(ctx context.Context, userID, todoID uint, req *timeentry.CreateRequest) (*models.TimeEntry, error) {
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	duration := time.Duration(req.Minutes) * time.Minute
+	endedAt := req.StartedAt.Add(duration)
+	if endedAt.After(s.now()) {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	entry := &models.TimeEntry{
+		UserID:    userID,
+		TodoID:    todoID,
+		StartedAt: req.StartedAt,
+		EndedAt:   &endedAt,
+		Seconds:   int64(duration / time.Second),
+		Note:      req.Note,
+	}
+	if err := s.entryRepo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// List 获取待办事项的时间记录及总时长
+func (s *TimeEntryService) List(ctx context.Context, userID, todoID uint) (*timeentry.ListResponse, error) {
+	todoItem, err := s.getTodo(ctx, userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.entryRepo.ListByTodoID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &timeentry.ListResponse{Items: entries, Estimate: todoItem.Estimate}
+	now := s.now()
+	for _, entry := range entries {
+		resp.TotalSeconds += entry.Elapsed(now)
+		if entry.Running() {
+			resp.Running = true
+		}
+	}
+	return resp, nil
+}
+
+// Delete 删除待办事项的一条时间记录，删除正在运行的计时器相当于放弃本次计时
+func (s *TimeEntryService) Delete(ctx context.Context, userID, todoID, entryID uint) error {
+	if _, err := s.getTodo(ctx, userID, todoID); err != nil {
+		return err
+	}
+	entry, err := s.entryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	if entry.TodoID != todoID {
+		return errors.ErrTimeEntryNotFound
+	}
+	if entry.UserID != userID {
+		return errors.ErrForbidden
+	}
+	return s.entryRepo.Delete(ctx, entryID)
+}
+
+// Report 按分类汇总用户在日期范围内记录的时间
+// 日期按服务器时区解释，From 和 To 都包含在内
+func (s *TimeEntryService) Report(ctx context.Context, userID uint, req *timeentry.ReportRequest) (*timeentry.ReportResponse, error) {
+	from := startOfDay(req.From)
+	to := startOfDay(req.To).AddDate(0, 0, 1)
+	if !to.After(from) || to.Sub(from) > maxReportDays*24*time.Hour {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	rows, err := s.entryRepo.SumByCategory(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(categories))
+	for _, c := range categories {
+		names[c.ID] = c.Name
+	}
+
+	resp := &timeentry.ReportResponse{From: from, To: to, Categories: make([]timeentry.CategoryTotal, 0, len(rows))}
+	for _, row := range rows {
+		total := timeentry.CategoryTotal{CategoryID: row.CategoryID, Seconds: row.Seconds, Entries: row.Entries}
+		if row.CategoryID != nil {
+			total.CategoryName = names[*row.CategoryID]
+		}
+		resp.Categories = append(resp.Categories, total)
+		resp.TotalSeconds += row.Seconds
+	}
+	sort.SliceStable(resp.Categories, func(i, j int) bool {
+		return resp.Categories[i].Seconds > resp.Categories[j].Seconds
+	})
+	return resp, nil
+}
+
+// CleanupTodo 删除待办事项的所有时间记录，在永久删除待办事项前调用
+func (s *TimeEntryService) CleanupTodo(ctx context.Context, todoID uint) error {
+	return s.entryRepo.DeleteByTodoID(ctx, todoID)
+}
+
+// stop 以 now 为结束时间停止正在运行的计时器
+// 计时器可能在其他工作空间中运行，按计时器所在的工作空间更新
+func (s *TimeEntryService) stop(ctx context.Context, entry *models.TimeEntry, now time.Time) error {
+	ctx = tenant.WithWorkspaceID(ctx, entry.WorkspaceID)
+	entry.Seconds = entry.Elapsed(now)
+	endedAt := entry.StartedAt.Add(time.Duration(entry.Seconds) * time.Second)
+	entry.EndedAt = &endedAt
+	return s.entryRepo.Update(ctx, entry)
+}
+
+// getTodo 获取待办事项并校验所有权
+func (s *TimeEntryService) getTodo(ctx context.Context, userID, todoID uint) (*models.Todo, error) {
+	todoItem, err := s.todoRepo.GetByID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todoItem.UserID != userID {
+		return nil, errors.ErrForbidden
+	}
+	return todoItem, nil
+}
+
+// startOfDay 返回 t 所在日期的零点
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
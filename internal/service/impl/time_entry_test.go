@@ -0,0 +1,269 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo/api/v1/dto/timeentry"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/internal/tenant"
+	"todo/pkg/errors"
+)
+
+// mockTimeEntryRepo 模拟时间记录仓储接口，通过 todos 查询待办事项所属分类
+type mockTimeEntryRepo struct {
+	entries []*models.TimeEntry
+	todos   *mockTodoRepo
+	locks   []bool // 每次 LockRunning 调用是否在事务中
+	updated []uint // 每次 Update 调用时上下文中的工作空间
+}
+
+func (m *mockTimeEntryRepo) Create(ctx context.Context, entry *models.TimeEntry) error {
+	entry.ID = uint(len(m.entries) + 1)
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *mockTimeEntryRepo) GetByID(ctx context.Context, id uint) (*models.TimeEntry, error) {
+	for _, entry := range m.entries {
+		if entry.ID == id && !entry.DeletedAt.Valid {
+			return entry, nil
+		}
+	}
+	return nil, errors.ErrTimeEntryNotFound
+}
+
+func (m *mockTimeEntryRepo) Update(ctx context.Context, entry *models.TimeEntry) error {
+	wsID, _ := tenant.WorkspaceIDFromContext(ctx)
+	m.updated = append(m.updated, wsID)
+	return nil
+}
+
+func (m *mockTimeEntryRepo) Delete(ctx context.Context, id uint) error {
+	entry, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	entry.DeletedAt.Valid = true
+	return nil
+}
+
+func (m *mockTimeEntryRepo) GetRunning(ctx context.Context, userID uint) (*models.TimeEntry, error) {
+	for _, entry := range m.entries {
+		if entry.UserID == userID && entry.Running() && !entry.DeletedAt.Valid {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockTimeEntryRepo) LockRunning(ctx context.Context, userID uint) (*models.TimeEntry, error) {
+	inTx, _ := ctx.Value(txMarker{}).(bool)
+	m.locks = append(m.locks, inTx)
+	return m.GetRunning(ctx, userID)
+}
+
+func (m *mockTimeEntryRepo) ListByTodoID(ctx context.Context, todoID uint) ([]*models.TimeEntry, error) {
+	var entries []*models.TimeEntry
+	for _, entry := range m.entries {
+		if entry.TodoID == todoID && !entry.DeletedAt.Valid {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *mockTimeEntryRepo) SumByCategory(ctx context.Context, userID uint, from, to time.Time) ([]repository.CategoryTime, error) {
+	var rows []repository.CategoryTime
+	for _, entry := range m.entries {
+		if entry.UserID != userID || entry.Running() || entry.DeletedAt.Valid ||
+			entry.StartedAt.Before(from) || !entry.StartedAt.Before(to) {
+			continue
+		}
+		categoryID := m.todos.todos[entry.TodoID].CategoryID
+		found := false
+		for i := range rows {
+			if sameID(rows[i].CategoryID, categoryID) {
+				rows[i].Seconds += entry.Seconds
+				rows[i].Entries++
+				found = true
+			}
+		}
+		if !found {
+			rows = append(rows, repository.CategoryTime{CategoryID: categoryID, Seconds: entry.Seconds, Entries: 1})
+		}
+	}
+	return rows, nil
+}
+
+func (m *mockTimeEntryRepo) DeleteByTodoID(ctx context.Context, todoID uint) error {
+	kept := m.entries[:0]
+	for _, entry := range m.entries {
+		if entry.TodoID != todoID {
+			kept = append(kept, entry)
+		}
+	}
+	m.entries = kept
+	return nil
+}
+
+// TestTimeEntryService 测试计时器切换、手动录入、总时长和按分类汇总的报表
+func TestTimeEntryService(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	categoryRepo := newMockCategoryRepo()
+	entryRepo := &mockTimeEntryRepo{todos: todoRepo}
+	todoService := NewTodoService(todoRepo, newMockReminderRepo(), categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+	timeService := NewTimeEntryService(entryRepo, todoRepo, categoryRepo, nopTransactor{})
+
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.Local)
+	timeService.now = func() time.Time { return now }
+
+	work := &models.Category{Name: "工作", UserID: 1}
+	categoryRepo.Create(ctx, work)
+	estimate := 90
+	report, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "写周报", CategoryID: &work.ID, Estimate: &estimate})
+	errand, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "取快递"})
+	other, _ := todoService.Create(ctx, 2, &todo.CreateRequest{Title: "他人的待办"})
+
+	if _, err := timeService.Start(ctx, 1, other, &timeentry.StartRequest{}); err != errors.ErrForbidden {
+		t.Errorf("为他人的待办事项计时错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+	if _, err := timeService.Stop(ctx, 1, report); err != errors.ErrTimerNotRunning {
+		t.Errorf("停止未运行的计时器错误 = %v, 期望 %v", err, errors.ErrTimerNotRunning)
+	}
+
+	first, err := timeService.Start(ctx, 1, report, &timeentry.StartRequest{})
+	if err != nil {
+		t.Fatalf("Start() 错误 = %v", err)
+	}
+	now = now.Add(30 * time.Minute)
+	if again, _ := timeService.Start(ctx, 1, report, &timeentry.StartRequest{}); again != first {
+		t.Errorf("重复启动同一待办事项的计时器应返回正在运行的计时器")
+	}
+
+	// 启动另一个计时器会先停止正在运行的计时器
+	if _, err := timeService.Start(ctx, 1, errand, &timeentry.StartRequest{}); err != nil {
+		t.Fatalf("Start() 错误 = %v", err)
+	}
+	if first.Running() || first.Seconds != 1800 {
+		t.Errorf("切换计时器后原计时器 running = %v, seconds = %d, 期望已停止且为 1800", first.Running(), first.Seconds)
+	}
+	now = now.Add(10 * time.Minute)
+	running, _ := timeService.Running(ctx, 1)
+	if running == nil || running.TodoID != errand {
+		t.Fatalf("Running() = %+v, 期望待办事项 %d 的计时器", running, errand)
+	}
+
+	t.Run("总时长包括正在运行的计时器", func(t *testing.T) {
+		got, err := timeService.List(ctx, 1, errand)
+		if err != nil {
+			t.Fatalf("List() 错误 = %v", err)
+		}
+		if !got.Running || got.TotalSeconds != 600 {
+			t.Errorf("List() running = %v, total = %d, 期望 true, 600", got.Running, got.TotalSeconds)
+		}
+	})
+
+	if _, err := timeService.Stop(ctx, 1, errand); err != nil {
+		t.Fatalf("Stop() 错误 = %v", err)
+	}
+
+	t.Run("手动录入", func(t *testing.T) {
+		if _, err := timeService.Add(ctx, 1, report, &timeentry.CreateRequest{StartedAt: now, Minutes: 15}); err != errors.ErrInvalidParameter {
+			t.Errorf("录入未来的时间错误 = %v, 期望 %v", err, errors.ErrInvalidParameter)
+		}
+		if _, err := timeService.Add(ctx, 1, report, &timeentry.CreateRequest{StartedAt: now.Add(-time.Hour), Minutes: 15}); err != nil {
+			t.Fatalf("Add() 错误 = %v", err)
+		}
+		// 上个月的记录不计入本月的报表
+		if _, err := timeService.Add(ctx, 1, report, &timeentry.CreateRequest{StartedAt: now.AddDate(0, -1, 0), Minutes: 60}); err != nil {
+			t.Fatalf("Add() 错误 = %v", err)
+		}
+		got, _ := timeService.List(ctx, 1, report)
+		if got.TotalSeconds != 1800+900+3600 || got.Estimate == nil || *got.Estimate != 90 {
+			t.Errorf("List() total = %d, estimate = %v, 期望 6300, 90", got.TotalSeconds, got.Estimate)
+		}
+	})
+
+	t.Run("按分类汇总", func(t *testing.T) {
+		got, err := timeService.Report(ctx, 1, &timeentry.ReportRequest{
+			From: time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local),
+			To:   time.Date(2024, 5, 10, 0, 0, 0, 0, time.Local),
+		})
+		if err != nil {
+			t.Fatalf("Report() 错误 = %v", err)
+		}
+		if got.TotalSeconds != 1800+900+600 || len(got.Categories) != 2 {
+			t.Fatalf("Report() = %+v, 期望两个分类共 3300 秒", got)
+		}
+		if c := got.Categories[0]; c.CategoryName != "工作" || c.Seconds != 2700 || c.Entries != 2 {
+			t.Errorf("Report() 第一个分类 = %+v, 期望 工作 2700 秒 2 条", c)
+		}
+		if c := got.Categories[1]; c.CategoryID != nil || c.Seconds != 600 {
+			t.Errorf("Report() 第二个分类 = %+v, 期望未分类 600 秒", c)
+		}
+
+		if _, err := timeService.Report(ctx, 1, &timeentry.ReportRequest{From: now, To: now.AddDate(0, 0, -1)}); err != errors.ErrInvalidParameter {
+			t.Errorf("结束日期早于起始日期错误 = %v, 期望 %v", err, errors.ErrInvalidParameter)
+		}
+	})
+
+	t.Run("删除记录", func(t *testing.T) {
+		if err := timeService.Delete(ctx, 1, errand, first.ID); err != errors.ErrTimeEntryNotFound {
+			t.Errorf("删除其他待办事项的记录错误 = %v, 期望 %v", err, errors.ErrTimeEntryNotFound)
+		}
+		if err := timeService.Delete(ctx, 1, report, first.ID); err != nil {
+			t.Fatalf("Delete() 错误 = %v", err)
+		}
+		got, _ := timeService.List(ctx, 1, report)
+		if got.TotalSeconds != 900+3600 {
+			t.Errorf("删除后总时长 = %d, 期望 4500", got.TotalSeconds)
+		}
+	})
+}
+
+// TestTimeEntryService_StartLocks 测试启动和停止计时器时在事务中锁定用户的计时器
+func TestTimeEntryService_StartLocks(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	entryRepo := &mockTimeEntryRepo{todos: todoRepo}
+	service := NewTimeEntryService(entryRepo, todoRepo, newMockCategoryRepo(), markingTransactor{})
+	report := &models.Todo{Title: "写周报", UserID: 1}
+	_ = todoRepo.Create(ctx, report)
+
+	if _, err := service.Start(ctx, 1, report.ID, &timeentry.StartRequest{}); err != nil {
+		t.Fatalf("Start() 错误 = %v", err)
+	}
+	if _, err := service.Stop(ctx, 1, report.ID); err != nil {
+		t.Fatalf("Stop() 错误 = %v", err)
+	}
+	if len(entryRepo.locks) != 2 || !entryRepo.locks[0] || !entryRepo.locks[1] {
+		t.Errorf("LockRunning() 调用是否在事务中 = %v, 期望 [true true]", entryRepo.locks)
+	}
+}
+
+// TestTimeEntryService_StartStopsOtherWorkspace 测试启动计时器时停止用户在其他工作空间中运行的计时器
+func TestTimeEntryService_StartStopsOtherWorkspace(t *testing.T) {
+	ctx := tenant.WithWorkspaceID(context.Background(), 1)
+	todoRepo := newMockTodoRepo()
+	entryRepo := &mockTimeEntryRepo{todos: todoRepo}
+	service := NewTimeEntryService(entryRepo, todoRepo, newMockCategoryRepo(), nopTransactor{})
+	report := &models.Todo{Title: "写周报", UserID: 1}
+	_ = todoRepo.Create(ctx, report)
+	elsewhere := &models.TimeEntry{WorkspaceID: 2, UserID: 1, TodoID: 99, StartedAt: time.Now().Add(-time.Hour)}
+	_ = entryRepo.Create(ctx, elsewhere)
+
+	entry, err := service.Start(ctx, 1, report.ID, &timeentry.StartRequest{})
+	if err != nil {
+		t.Fatalf("Start() 错误 = %v", err)
+	}
+	if elsewhere.Running() || len(entryRepo.updated) != 1 || entryRepo.updated[0] != 2 {
+		t.Errorf("其他工作空间的计时器 Running = %v, 更新时的工作空间 = %v, 期望在工作空间 2 中停止", elsewhere.Running(), entryRepo.updated)
+	}
+	if running, _ := service.Running(ctx, 1); running == nil || running.ID != entry.ID {
+		t.Errorf("Running() = %+v, 期望只有新启动的计时器", running)
+	}
+}
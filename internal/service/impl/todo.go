@@ -2,28 +2,70 @@ package impl
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 	"todo/api/v1/dto/todo"
 	"todo/internal/models"
 	"todo/internal/repository"
+	"todo/internal/tenant"
 	"todo/pkg/errors"
+	"todo/pkg/logger"
+	"todo/pkg/notify"
 )
 
+// batchSize 后台任务每批处理的待办事项数量
+const batchSize = 100
+
+// TodoCleaner 在待办事项被永久删除前清理其关联资源（如评论、附件文件）
+// CleanupTodo 在永久删除的事务中调用，数据库以外的资源应在事务提交后再删除
+type TodoCleaner interface {
+	CleanupTodo(ctx context.Context, todoID uint) error
+}
+
 // TodoService 待办事项服务结构体
 // 负责处理所有与待办事项相关的业务逻辑
 type TodoService struct {
-	todoRepo repository.TodoRepository // 待办事项数据仓库接口
+	todoRepo     repository.TodoRepository     // 待办事项数据仓库接口
+	reminderRepo repository.ReminderRepository // 提醒数据仓库接口，提醒随待办事项一起删除和恢复
+	categoryRepo repository.CategoryRepository // 分类数据仓库接口，用于按分类树过滤
+	statusRepo   repository.StatusRepository   // 工作流状态数据仓库接口，用于同步状态与完成状态
+	depRepo      repository.DependencyRepository // 依赖关系数据仓库接口，用于阻止完成被阻塞的待办事项
+	history      historyRecorder               // 变更历史记录
+	tx           repository.Transactor         // 事务执行器
+	notifier     notify.Notifier               // 通知器，用于通知不再被阻塞的待办事项
+	cleaners     []TodoCleaner                 // 永久删除待办事项前执行的资源清理
 }
 
 // NewTodoService 创建一个新的待办事项服务实例
 //
 // Parameters:
 //   - todoRepo: 待办事项仓库实现
+//   - reminderRepo: 提醒仓库实现
+//   - categoryRepo: 分类仓库实现
+//   - statusRepo: 工作流状态仓库实现
+//   - depRepo: 依赖关系仓库实现
+//   - historyRepo: 变更历史仓库实现
+//   - tx: 事务执行器，保证数据变更与变更历史同时写入
+//   - notifier: 通知器，待办事项不再被阻塞时通知其所有者
+//   - cleaners: 永久删除待办事项前需要执行的资源清理
 //
 // Returns:
 //   - *TodoService: 返回待办事项服务实例
-func NewTodoService(todoRepo repository.TodoRepository) *TodoService {
+func NewTodoService(todoRepo repository.TodoRepository, reminderRepo repository.ReminderRepository,
+	categoryRepo repository.CategoryRepository, statusRepo repository.StatusRepository,
+	depRepo repository.DependencyRepository, historyRepo repository.HistoryRepository,
+	tx repository.Transactor, notifier notify.Notifier, cleaners ...TodoCleaner) *TodoService {
 	return &TodoService{
-		todoRepo: todoRepo,
+		todoRepo:     todoRepo,
+		reminderRepo: reminderRepo,
+		categoryRepo: categoryRepo,
+		statusRepo:   statusRepo,
+		depRepo:      depRepo,
+		history:      historyRecorder{repo: historyRepo},
+		tx:           tx,
+		notifier:     notifier,
+		cleaners:     cleaners,
 	}
 }
 
@@ -43,6 +85,8 @@ func (s *TodoService) Create(ctx context.Context, userID uint, req *todo.CreateR
 		Description: req.Description,
 		UserID:      userID,
 		CategoryID:  req.CategoryID,
+		DueDate:     req.DueDate,
+		Estimate:    req.Estimate,
 	}
 
 	if req.Priority != "" {
@@ -51,19 +95,38 @@ func (s *TodoService) Create(ctx context.Context, userID uint, req *todo.CreateR
 		todoItem.Priority = models.PriorityMedium // 默认中优先级
 	}
 
-	if err := s.todoRepo.Create(ctx, todoItem); err != nil {
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.initialStatus(ctx, todoItem, req.StatusID); err != nil {
+			return err
+		}
+		if err := s.appendPosition(ctx, todoItem); err != nil {
+			return err
+		}
+		if err := s.todoRepo.Create(ctx, todoItem); err != nil {
+			return err
+		}
+		if tags := normalizeTags(req.Tags); len(tags) > 0 {
+			if err := s.todoRepo.SetTags(ctx, todoItem, tags); err != nil {
+				return err
+			}
+		}
+		return s.history.record(ctx, models.EntityTodo, todoItem.ID, userID, models.ChangeActionCreate, nil, todoItem)
+	})
+	if err != nil {
 		return 0, err
 	}
 
 	return todoItem.ID, nil
 }
 
-// List 获取用户的所有待办事项
-func (s *TodoService) List(ctx context.Context, userID uint) ([]*models.Todo, error) {
-	// 默认分页参数
-	page := 1
-	pageSize := 100
-	todos, _, err := s.todoRepo.ListByUserID(ctx, userID, page, pageSize)
+// List 获取用户的待办事项，默认不包含已归档的待办事项
+func (s *TodoService) List(ctx context.Context, userID uint, req *todo.ListRequest) ([]*models.Todo, error) {
+	filter, err := buildTodoFilter(ctx, s.categoryRepo, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	todos, _, err := s.todoRepo.ListByUserID(ctx, userID, filter, 1, listPageSize)
 	return todos, err
 }
 
@@ -88,6 +151,7 @@ func (s *TodoService) Update(ctx context.Context, id, userID uint, req *todo.Upd
 	if err != nil {
 		return err
 	}
+	before := *todoItem
 
 	if req.Title != nil {
 		todoItem.Title = *req.Title
@@ -96,7 +160,7 @@ func (s *TodoService) Update(ctx context.Context, id, userID uint, req *todo.Upd
 		todoItem.Description = *req.Description
 	}
 	if req.Completed != nil {
-		todoItem.Completed = *req.Completed
+		markCompleted(todoItem, *req.Completed)
 	}
 	if req.Priority != nil {
 		todoItem.Priority = models.Priority(*req.Priority)
@@ -104,18 +168,454 @@ func (s *TodoService) Update(ctx context.Context, id, userID uint, req *todo.Upd
 	if req.CategoryID != nil {
 		todoItem.CategoryID = req.CategoryID
 	}
+	if req.ClearDueDate {
+		todoItem.DueDate = nil
+	} else if req.DueDate != nil {
+		todoItem.DueDate = req.DueDate
+	}
+	if req.ClearEstimate {
+		todoItem.Estimate = nil
+	} else if req.Estimate != nil {
+		todoItem.Estimate = req.Estimate
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.updateStatus(ctx, todoItem, &before, req); err != nil {
+			return err
+		}
+		// 移动到其他分类时排到新分类的末尾
+		if !sameID(before.CategoryID, todoItem.CategoryID) {
+			if err := s.appendPosition(ctx, todoItem); err != nil {
+				return err
+			}
+		}
+		if err := s.saveWithHistory(ctx, userID, models.ChangeActionUpdate, &before, todoItem); err != nil {
+			return err
+		}
+		if req.Tags == nil {
+			return nil
+		}
+		return s.todoRepo.SetTags(ctx, todoItem, normalizeTags(*req.Tags))
+	})
+}
+
+// normalizeTags 去除标签名首尾空白，忽略空标签和重复标签（不区分大小写），保持原有顺序
+func normalizeTags(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, name)
+	}
+	return tags
+}
+
+// Archive 归档待办事项，归档后默认不出现在列表中
+func (s *TodoService) Archive(ctx context.Context, id, userID uint) error {
+	return s.setArchived(ctx, id, userID, true)
+}
+
+// Unarchive 取消归档
+func (s *TodoService) Unarchive(ctx context.Context, id, userID uint) error {
+	return s.setArchived(ctx, id, userID, false)
+}
+
+// ArchiveCompleted 归档所有工作空间中完成时间早于 before 的待办事项
+// 供后台任务调用；单个待办事项归档失败只记录日志，留待下次重试
+//
+// Returns:
+//   - int: 成功归档的数量
+//   - error: 查询待归档数据失败时返回
+func (s *TodoService) ArchiveCompleted(ctx context.Context, before time.Time) (int, error) {
+	archived := 0
+	for {
+		todos, err := s.todoRepo.ListArchivable(ctx, before, batchSize)
+		if err != nil {
+			return archived, err
+		}
+
+		failed := 0
+		for _, todoItem := range todos {
+			wsCtx := tenant.WithWorkspaceID(ctx, todoItem.WorkspaceID)
+			prev := *todoItem
+			markArchived(todoItem, true)
+			if err := s.saveWithHistory(wsCtx, 0, models.ChangeActionUpdate, &prev, todoItem); err != nil {
+				logger.Warn().Err(err).Uint("todo_id", todoItem.ID).Msg("自动归档待办事项失败")
+				failed++
+				continue
+			}
+			archived++
+		}
+
+		// 整批都失败时停止，避免反复处理同一批记录
+		if len(todos) < batchSize || failed == len(todos) {
+			return archived, nil
+		}
+	}
+}
+
+// setArchived 设置待办事项的归档状态
+func (s *TodoService) setArchived(ctx context.Context, id, userID uint, archived bool) error {
+	todoItem, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if todoItem.Archived == archived {
+		return nil
+	}
+
+	before := *todoItem
+	markArchived(todoItem, archived)
+	return s.saveWithHistory(ctx, userID, models.ChangeActionUpdate, &before, todoItem)
+}
+
+// markArchived 修改归档状态并同步归档时间
+func markArchived(todoItem *models.Todo, archived bool) {
+	todoItem.Archived = archived
+	todoItem.ArchivedAt = nil
+	if archived {
+		now := time.Now()
+		todoItem.ArchivedAt = &now
+	}
+}
 
-	return s.todoRepo.Update(ctx, todoItem)
+// markCompleted 修改完成状态并同步完成时间，状态未变化时保留原有的完成时间
+func markCompleted(todoItem *models.Todo, completed bool) {
+	if todoItem.Completed == completed {
+		return
+	}
+	todoItem.Completed = completed
+	todoItem.CompletedAt = nil
+	if completed {
+		now := time.Now()
+		todoItem.CompletedAt = &now
+	}
 }
 
 // Delete 删除待办事项
+// 待办事项及其提醒移入回收站（软删除），可通过 Restore 恢复
 func (s *TodoService) Delete(ctx context.Context, id, userID uint) error {
 	todo, err := s.Get(ctx, id, userID)
 	if err != nil {
 		return err
 	}
 
-	return s.todoRepo.Delete(ctx, todo.ID)
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.trash(ctx, todo, userID)
+	})
+}
+
+// ReassignCategory 将分类下的所有待办事项（包括回收站中的）移动到另一个分类，to 为空时取消分类
+// 每个待办事项的变更都会记录到变更历史
+//
+// Returns:
+//   - int: 受影响的待办事项数量
+//   - error: 可能的错误信息
+func (s *TodoService) ReassignCategory(ctx context.Context, actorID, from uint, to *uint) (int, error) {
+	todos, err := s.todoRepo.ListByCategoryID(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, todoItem := range todos {
+			prev := *todoItem
+			todoItem.CategoryID = to
+			if err := s.appendPosition(ctx, todoItem); err != nil {
+				return err
+			}
+			if err := s.saveWithHistory(ctx, actorID, models.ChangeActionUpdate, &prev, todoItem); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(todos), nil
+}
+
+// TrashCategory 将分类下的待办事项移入回收站
+// 待办事项同时取消分类，避免恢复后指向已删除的分类；已在回收站中的只取消分类
+//
+// Returns:
+//   - int: 新移入回收站的待办事项数量
+//   - error: 可能的错误信息
+func (s *TodoService) TrashCategory(ctx context.Context, actorID, categoryID uint) (int, error) {
+	todos, err := s.todoRepo.ListByCategoryID(ctx, categoryID)
+	if err != nil {
+		return 0, err
+	}
+
+	trashed := 0
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, todoItem := range todos {
+			prev := *todoItem
+			todoItem.CategoryID = nil
+			if err := s.saveWithHistory(ctx, actorID, models.ChangeActionUpdate, &prev, todoItem); err != nil {
+				return err
+			}
+			if todoItem.DeletedAt.Valid {
+				continue
+			}
+			if err := s.trash(ctx, todoItem, actorID); err != nil {
+				return err
+			}
+			trashed++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return trashed, nil
+}
+
+// trash 将待办事项及其提醒移入回收站，需在事务中调用
+func (s *TodoService) trash(ctx context.Context, todo *models.Todo, actorID uint) error {
+	if err := s.todoRepo.Delete(ctx, todo.ID); err != nil {
+		return err
+	}
+	if err := s.reminderRepo.DeleteByTodoID(ctx, todo.ID); err != nil {
+		return err
+	}
+	if err := s.history.record(ctx, models.EntityTodo, todo.ID, actorID, models.ChangeActionDelete, todo, nil); err != nil {
+		return err
+	}
+	// 回收站中的待办事项不再阻塞其他待办事项
+	if todo.Completed {
+		return nil
+	}
+	return s.notifyDependents(ctx, todo, fmt.Sprintf("阻塞它的「%s」已被删除", todo.Title))
+}
+
+// ListTrash 获取用户回收站中的待办事项，按删除时间倒序
+func (s *TodoService) ListTrash(ctx context.Context, userID uint) ([]*models.Todo, error) {
+	page := 1
+	pageSize := 100
+	todos, _, err := s.todoRepo.ListDeleted(ctx, userID, page, pageSize)
+	return todos, err
+}
+
+// Restore 从回收站恢复待办事项，同时恢复随它一起删除的提醒
+func (s *TodoService) Restore(ctx context.Context, id, userID uint) error {
+	todo, err := s.todoRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if todo.UserID != userID {
+		return errors.ErrForbidden
+	}
+	deletedAt := todo.DeletedAt.Time
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.todoRepo.Restore(ctx, todo.ID); err != nil {
+			return err
+		}
+		if err := s.reminderRepo.RestoreByTodoID(ctx, todo.ID, deletedAt); err != nil {
+			return err
+		}
+		return s.history.record(ctx, models.EntityTodo, todo.ID, userID, models.ChangeActionRestore, nil, todo)
+	})
+}
+
+// Purge 永久删除待办事项，无论它是否已在回收站中
+// 评论、附件、提醒等关联数据一并删除，无法恢复
+func (s *TodoService) Purge(ctx context.Context, id, userID uint) error {
+	todo, err := s.todoRepo.GetByID(ctx, id)
+	if err == errors.ErrTodoNotFound {
+		todo, err = s.todoRepo.GetDeletedByID(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+	if todo.UserID != userID {
+		return errors.ErrForbidden
+	}
+
+	return s.purge(ctx, todo, userID)
+}
+
+// PurgeExpired 永久删除所有工作空间中删除时间早于 before 的待办事项
+// 供后台任务调用；单个待办事项清理失败只记录日志，留待下次重试
+//
+// Returns:
+//   - int: 成功清理的数量
+//   - error: 查询回收站失败时返回
+func (s *TodoService) PurgeExpired(ctx context.Context, before time.Time) (int, error) {
+	purged := 0
+	for {
+		todos, err := s.todoRepo.ListExpiredDeleted(ctx, before, batchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		failed := 0
+		for _, todo := range todos {
+			wsCtx := tenant.WithWorkspaceID(ctx, todo.WorkspaceID)
+			if err := s.purge(wsCtx, todo, 0); err != nil {
+				logger.Warn().Err(err).Uint("todo_id", todo.ID).Msg("清理回收站待办事项失败")
+				failed++
+				continue
+			}
+			purged++
+		}
+
+		// 整批都失败时停止，避免反复处理同一批记录
+		if len(todos) < batchSize || failed == len(todos) {
+			return purged, nil
+		}
+	}
+}
+
+// purge 永久删除待办事项及其关联数据
+// actorID 为 0 表示由系统任务执行
+func (s *TodoService) purge(ctx context.Context, todo *models.Todo, actorID uint) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// 先清理关联资源，外键约束要求评论和附件先于待办事项删除；与待办事项在同一事务中删除，失败时一起回滚
+		for _, cleaner := range s.cleaners {
+			if err := cleaner.CleanupTodo(ctx, todo.ID); err != nil {
+				return err
+			}
+		}
+		if err := s.reminderRepo.PurgeByTodoID(ctx, todo.ID); err != nil {
+			return err
+		}
+		if err := s.depRepo.DeleteByTodoID(ctx, todo.ID); err != nil {
+			return err
+		}
+		if err := s.todoRepo.Purge(ctx, todo.ID); err != nil {
+			return err
+		}
+		return s.history.record(ctx, models.EntityTodo, todo.ID, actorID, models.ChangeActionPurge, todo, nil)
+	})
+}
+
+// History 获取待办事项的变更历史，按时间倒序
+func (s *TodoService) History(ctx context.Context, id, userID uint) ([]*models.ChangeLog, error) {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return s.history.repo.ListByEntity(ctx, models.EntityTodo, id)
+}
+
+// Revert 将待办事项回滚到指定版本
+// 版本即变更历史记录的ID，回滚后的状态为该次变更完成后的快照，回滚本身也会记录为一次变更
+func (s *TodoService) Revert(ctx context.Context, id, userID, changeID uint) error {
+	todoItem, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	entry, err := s.history.repo.GetByID(ctx, changeID)
+	if err != nil {
+		return err
+	}
+	if entry.EntityType != models.EntityTodo || entry.EntityID != id {
+		return errors.ErrChangeNotFound
+	}
+
+	before := *todoItem
+	if err := applySnapshot(todoItem, entry.Snapshot); err != nil {
+		return err
+	}
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.revertCategory(ctx, todoItem, &before); err != nil {
+			return err
+		}
+		if err := s.revertStatus(ctx, todoItem, &before); err != nil {
+			return err
+		}
+		return s.saveWithHistory(ctx, userID, models.ChangeActionRevert, &before, todoItem)
+	})
+}
+
+// revertCategory 校验回滚后的分类，需在事务中调用
+// 分类已被删除时清除分类；分类改变时排到该分类的末尾
+func (s *TodoService) revertCategory(ctx context.Context, todoItem, before *models.Todo) error {
+	if sameID(before.CategoryID, todoItem.CategoryID) {
+		return nil
+	}
+	if todoItem.CategoryID != nil {
+		category, err := s.categoryRepo.GetByID(ctx, *todoItem.CategoryID)
+		switch {
+		case err == errors.ErrCategoryNotFound:
+			todoItem.CategoryID = nil
+		case err != nil:
+			return err
+		case category.UserID != todoItem.UserID:
+			return errors.ErrForbidden
+		}
+		if sameID(before.CategoryID, todoItem.CategoryID) {
+			return nil
+		}
+	}
+	return s.appendPosition(ctx, todoItem)
+}
+
+// revertStatus 校验回滚后的工作流状态，需在事务中调用
+// 状态已被删除时按完成状态重新选择；移入其他状态时同样受在制品上限约束
+func (s *TodoService) revertStatus(ctx context.Context, todoItem, before *models.Todo) error {
+	if sameID(before.StatusID, todoItem.StatusID) || todoItem.StatusID == nil {
+		if todoItem.Completed != before.Completed {
+			return s.syncStatus(ctx, todoItem)
+		}
+		return nil
+	}
+
+	status, err := s.ownedStatus(ctx, todoItem.UserID, *todoItem.StatusID)
+	switch {
+	case err == errors.ErrStatusNotFound:
+		todoItem.StatusID = before.StatusID
+		return s.syncStatus(ctx, todoItem)
+	case err != nil:
+		return err
+	}
+	// setStatus 只在状态改变时检查在制品上限，先恢复为回滚前的状态
+	todoItem.StatusID = before.StatusID
+	return s.setStatus(ctx, todoItem, status)
+}
+
+// saveWithHistory 在同一事务中保存待办事项并记录变更历史
+// 待办事项由未完成变为已完成时，仍有未完成的阻塞项则返回 ErrTodoBlocked，
+// 否则通知因此不再被阻塞的待办事项
+func (s *TodoService) saveWithHistory(ctx context.Context, userID uint, action string, before, after *models.Todo) error {
+	completing := !before.Completed && after.Completed
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if completing {
+			open, err := s.depRepo.CountOpenBlockers(ctx, after.ID)
+			if err != nil {
+				return err
+			}
+			if open > 0 {
+				return errors.ErrTodoBlocked
+			}
+		}
+		if err := s.todoRepo.Update(ctx, after); err != nil {
+			return err
+		}
+		if err := s.history.record(ctx, models.EntityTodo, after.ID, userID, action, before, after); err != nil {
+			return err
+		}
+		if !completing {
+			return nil
+		}
+		return s.notifyDependents(ctx, after, fmt.Sprintf("阻塞它的「%s」已完成", after.Title))
+	})
+}
+
+// SetEventPublisher 设置待办事项变更后发布 webhook 事件的发布器，未设置时不发布事件
+//
+// Parameters:
+//   - events: 事件发布器
+func (s *TodoService) SetEventPublisher(events EventPublisher) {
+	s.history.events = events
 }
 
 // GetTodoRepo 获取待办事项仓库实例
@@ -2,10 +2,15 @@ package impl
 
 import (
 	"context"
+	"sort"
 	"testing"
+	"time"
 	"todo/api/v1/dto/todo"
 	"todo/internal/models"
+	"todo/internal/repository"
 	"todo/pkg/errors"
+
+	"gorm.io/gorm"
 )
 
 // mockTodoRepo 模拟待办事项仓储接口
@@ -32,35 +37,99 @@ func (m *mockTodoRepo) Create(ctx context.Context, todo *models.Todo) error {
 }
 
 // GetByID 根据ID获取待办事项
+// 与真实仓储一样返回副本，未保存的修改不会影响存储的数据
 func (m *mockTodoRepo) GetByID(ctx context.Context, id uint) (*models.Todo, error) {
 	todo, exists := m.todos[id]
-	if !exists {
+	if !exists || todo.DeletedAt.Valid {
 		return nil, errors.ErrTodoNotFound
 	}
-	return todo, nil
+	copied := *todo
+	return &copied, nil
 }
 
-// Delete 删除待办事项
+// Delete 删除待办事项（软删除）
 func (m *mockTodoRepo) Delete(ctx context.Context, id uint) error {
-	if _, exists := m.todos[id]; !exists {
+	todo, exists := m.todos[id]
+	if !exists || todo.DeletedAt.Valid {
 		return errors.ErrTodoNotFound
 	}
+	todo.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	return nil
+}
+
+// ListDeleted 获取回收站中的待办事项
+func (m *mockTodoRepo) ListDeleted(ctx context.Context, userID uint, page, pageSize int) ([]*models.Todo, int64, error) {
+	var todos []*models.Todo
+	for _, todo := range m.todos {
+		if todo.UserID == userID && todo.DeletedAt.Valid {
+			todos = append(todos, todo)
+		}
+	}
+	return todos, int64(len(todos)), nil
+}
+
+// GetDeletedByID 获取回收站中的待办事项
+func (m *mockTodoRepo) GetDeletedByID(ctx context.Context, id uint) (*models.Todo, error) {
+	todo, exists := m.todos[id]
+	if !exists || !todo.DeletedAt.Valid {
+		return nil, errors.ErrTodoNotFound
+	}
+	return todo, nil
+}
+
+// Restore 恢复待办事项
+func (m *mockTodoRepo) Restore(ctx context.Context, id uint) error {
+	if todo, exists := m.todos[id]; exists {
+		todo.DeletedAt = gorm.DeletedAt{}
+	}
+	return nil
+}
+
+// Purge 永久删除待办事项
+func (m *mockTodoRepo) Purge(ctx context.Context, id uint) error {
 	delete(m.todos, id)
 	return nil
 }
 
-// ListByUserID 获取用户的待办事项列表
-func (m *mockTodoRepo) ListByUserID(ctx context.Context, userID uint, page, pageSize int) ([]*models.Todo, int64, error) {
+// ListExpiredDeleted 获取删除时间早于 before 的待办事项
+func (m *mockTodoRepo) ListExpiredDeleted(ctx context.Context, before time.Time, limit int) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	for _, todo := range m.todos {
+		if todo.DeletedAt.Valid && todo.DeletedAt.Time.Before(before) && len(todos) < limit {
+			todos = append(todos, todo)
+		}
+	}
+	return todos, nil
+}
+
+// GetByCalendarName 根据 CalDAV 资源名获取用户在指定分类中的待办事项
+func (m *mockTodoRepo) GetByCalendarName(ctx context.Context, userID uint, categoryID *uint, name string) (*models.Todo, error) {
+	for _, todo := range m.todos {
+		if todo.UserID == userID && !todo.DeletedAt.Valid && sameID(todo.CategoryID, categoryID) && todo.CalendarName == name {
+			copied := *todo
+			return &copied, nil
+		}
+	}
+	return nil, errors.ErrTodoNotFound
+}
+
+func (m *mockTodoRepo) ListByUserID(ctx context.Context, userID uint, filter repository.TodoFilter, page, pageSize int) ([]*models.Todo, int64, error) {
 	var todos []*models.Todo
 	var total int64
 
 	// 筛选出属于指定用户的待办事项
 	for _, todo := range m.todos {
-		if todo.UserID == userID {
+		if matchesFilter(todo, userID, filter) {
 			todos = append(todos, todo)
 		}
 	}
 	total = int64(len(todos))
+	switch filter.Sort {
+	case repository.SortPosition:
+		sortByPosition(todos)
+	case repository.SortID:
+		sort.Slice(todos, func(i, j int) bool { return todos[i].ID < todos[j].ID })
+	}
 
 	// 实现分页逻辑
 	start := (page - 1) * pageSize
@@ -76,6 +145,150 @@ func (m *mockTodoRepo) ListByUserID(ctx context.Context, userID uint, page, page
 	return todos, total, nil
 }
 
+func (m *mockTodoRepo) CountByUserID(ctx context.Context, userID uint, filter repository.TodoFilter) (int64, error) {
+	var total int64
+	for _, todo := range m.todos {
+		if matchesFilter(todo, userID, filter) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+func (m *mockTodoRepo) SetTags(ctx context.Context, todo *models.Todo, names []string) error {
+	todo.Tags = make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		todo.Tags = append(todo.Tags, models.Tag{UserID: todo.UserID, Name: name})
+	}
+	if stored, exists := m.todos[todo.ID]; exists {
+		stored.Tags = todo.Tags
+	}
+	return nil
+}
+
+func (m *mockTodoRepo) LastPosition(ctx context.Context, userID uint, categoryID *uint) (string, error) {
+	last := ""
+	for _, todo := range m.todos {
+		if todo.UserID == userID && !todo.DeletedAt.Valid && sameID(todo.CategoryID, categoryID) && todo.Position > last {
+			last = todo.Position
+		}
+	}
+	return last, nil
+}
+
+func (m *mockTodoRepo) ListByPosition(ctx context.Context, userID uint, categoryID *uint) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	for _, todo := range m.todos {
+		if todo.UserID == userID && !todo.DeletedAt.Valid && sameID(todo.CategoryID, categoryID) {
+			todos = append(todos, todo)
+		}
+	}
+	sortByPosition(todos)
+	return todos, nil
+}
+
+func (m *mockTodoRepo) UpdatePosition(ctx context.Context, id uint, position string) error {
+	todo, exists := m.todos[id]
+	if !exists {
+		return errors.ErrTodoNotFound
+	}
+	todo.Position = position
+	return nil
+}
+
+// sortByPosition 按排序位置和ID排序，与仓储的 ORDER BY position, id 一致
+func sortByPosition(todos []*models.Todo) {
+	sort.Slice(todos, func(i, j int) bool {
+		if todos[i].Position != todos[j].Position {
+			return todos[i].Position < todos[j].Position
+		}
+		return todos[i].ID < todos[j].ID
+	})
+}
+
+// matchesFilter 判断待办事项是否属于指定用户且符合过滤条件
+func matchesFilter(todo *models.Todo, userID uint, filter repository.TodoFilter) bool {
+	if todo.UserID != userID || todo.DeletedAt.Valid {
+		return false
+	}
+	if filter.Archived == repository.ArchiveExclude && todo.Archived ||
+		filter.Archived == repository.ArchiveOnly && !todo.Archived {
+		return false
+	}
+	if filter.Completed != nil && todo.Completed != *filter.Completed {
+		return false
+	}
+	if len(filter.CategoryIDs) > 0 && !containsCategory(filter.CategoryIDs, todo.CategoryID) {
+		return false
+	}
+	if filter.Uncategorized && todo.CategoryID != nil {
+		return false
+	}
+	if filter.Priority != "" && string(todo.Priority) != filter.Priority {
+		return false
+	}
+	if filter.StatusID != nil && !sameID(todo.StatusID, filter.StatusID) {
+		return false
+	}
+	if filter.NoDueDate && todo.DueDate != nil {
+		return false
+	}
+	if filter.DueFrom != nil && (todo.DueDate == nil || todo.DueDate.Before(*filter.DueFrom)) {
+		return false
+	}
+	if filter.DueBefore != nil && (todo.DueDate == nil || !todo.DueDate.Before(*filter.DueBefore)) {
+		return false
+	}
+	return true
+}
+
+// containsCategory 判断待办事项的分类是否在列表中
+func containsCategory(ids []uint, categoryID *uint) bool {
+	if categoryID == nil {
+		return false
+	}
+	for _, id := range ids {
+		if id == *categoryID {
+			return true
+		}
+	}
+	return false
+}
+
+// ListByStatusID 获取工作流状态中的所有待办事项（包括回收站中的）
+func (m *mockTodoRepo) ListByStatusID(ctx context.Context, statusID uint) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	for id := uint(1); id < m.seq; id++ {
+		if todo, exists := m.todos[id]; exists && todo.StatusID != nil && *todo.StatusID == statusID {
+			todos = append(todos, todo)
+		}
+	}
+	return todos, nil
+}
+
+// ListByCategoryID 获取分类下的所有待办事项（包括回收站中的）
+func (m *mockTodoRepo) ListByCategoryID(ctx context.Context, categoryID uint) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	for id := uint(1); id < m.seq; id++ {
+		if todo, exists := m.todos[id]; exists && todo.CategoryID != nil && *todo.CategoryID == categoryID {
+			todos = append(todos, todo)
+		}
+	}
+	return todos, nil
+}
+
+// ListArchivable 获取完成时间早于 before 且未归档的待办事项
+func (m *mockTodoRepo) ListArchivable(ctx context.Context, before time.Time, limit int) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	for _, todo := range m.todos {
+		if todo.Completed && !todo.Archived && !todo.DeletedAt.Valid &&
+			todo.CompletedAt != nil && todo.CompletedAt.Before(before) && len(todos) < limit {
+			todos = append(todos, todo)
+		}
+	}
+	return todos, nil
+}
+
 // Update 更新待办事项
 func (m *mockTodoRepo) Update(ctx context.Context, todo *models.Todo) error {
 	if _, exists := m.todos[todo.ID]; !exists {
@@ -85,11 +298,118 @@ func (m *mockTodoRepo) Update(ctx context.Context, todo *models.Todo) error {
 	return nil
 }
 
+// mockReminderRepo 模拟提醒仓储接口
+type mockReminderRepo struct {
+	reminders map[uint]*models.Reminder
+	seq       uint
+	todos     *mockTodoRepo // 设置后 ListDue 按待办事项过滤并附带待办事项
+}
+
+func newMockReminderRepo() *mockReminderRepo {
+	return &mockReminderRepo{reminders: make(map[uint]*models.Reminder), seq: 1}
+}
+
+func (m *mockReminderRepo) Create(ctx context.Context, reminder *models.Reminder) error {
+	reminder.ID = m.seq
+	m.reminders[reminder.ID] = reminder
+	m.seq++
+	return nil
+}
+
+func (m *mockReminderRepo) GetByID(ctx context.Context, id uint) (*models.Reminder, error) {
+	reminder, exists := m.reminders[id]
+	if !exists || reminder.DeletedAt.Valid {
+		return nil, errors.ErrReminderNotFound
+	}
+	return reminder, nil
+}
+
+func (m *mockReminderRepo) ListByTodoID(ctx context.Context, todoID uint) ([]*models.Reminder, error) {
+	var reminders []*models.Reminder
+	for _, reminder := range m.reminders {
+		if reminder.TodoID == todoID && !reminder.DeletedAt.Valid {
+			reminders = append(reminders, reminder)
+		}
+	}
+	return reminders, nil
+}
+
+func (m *mockReminderRepo) ListByTodoIDs(ctx context.Context, todoIDs []uint) ([]*models.Reminder, error) {
+	var reminders []*models.Reminder
+	for _, todoID := range todoIDs {
+		list, _ := m.ListByTodoID(ctx, todoID)
+		sort.Slice(list, func(i, j int) bool { return list[i].RemindAt.Before(list[j].RemindAt) })
+		reminders = append(reminders, list...)
+	}
+	return reminders, nil
+}
+
+func (m *mockReminderRepo) Update(ctx context.Context, reminder *models.Reminder) error {
+	m.reminders[reminder.ID] = reminder
+	return nil
+}
+
+func (m *mockReminderRepo) Delete(ctx context.Context, id uint) error {
+	if reminder, exists := m.reminders[id]; exists {
+		reminder.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	}
+	return nil
+}
+
+func (m *mockReminderRepo) DeleteByTodoID(ctx context.Context, todoID uint) error {
+	for _, reminder := range m.reminders {
+		if reminder.TodoID == todoID && !reminder.DeletedAt.Valid {
+			reminder.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		}
+	}
+	return nil
+}
+
+func (m *mockReminderRepo) RestoreByTodoID(ctx context.Context, todoID uint, since time.Time) error {
+	for _, reminder := range m.reminders {
+		if reminder.TodoID == todoID && reminder.DeletedAt.Valid && !reminder.DeletedAt.Time.Before(since) {
+			reminder.DeletedAt = gorm.DeletedAt{}
+		}
+	}
+	return nil
+}
+
+func (m *mockReminderRepo) PurgeByTodoID(ctx context.Context, todoID uint) error {
+	for id, reminder := range m.reminders {
+		if reminder.TodoID == todoID {
+			delete(m.reminders, id)
+		}
+	}
+	return nil
+}
+
+func (m *mockReminderRepo) ListDue(ctx context.Context, before time.Time, limit int) ([]*models.Reminder, error) {
+	var reminders []*models.Reminder
+	for _, reminder := range m.reminders {
+		if reminder.Status || reminder.DeletedAt.Valid || reminder.RemindAt.After(before) {
+			continue
+		}
+		if m.todos != nil {
+			todoItem, exists := m.todos.todos[reminder.TodoID]
+			if !exists || todoItem.Completed || todoItem.DeletedAt.Valid {
+				continue
+			}
+			reminder.Todo = todoItem
+		}
+		reminders = append(reminders, reminder)
+	}
+	sort.Slice(reminders, func(i, j int) bool { return reminders[i].RemindAt.Before(reminders[j].RemindAt) })
+	if len(reminders) > limit {
+		reminders = reminders[:limit]
+	}
+	return reminders, nil
+}
+
 // TestTodoService_Create 测试创建待办事项功能
 func TestTodoService_Create(t *testing.T) {
 	// 初始化测试环境
 	todoRepo := newMockTodoRepo()
-	todoService := NewTodoService(todoRepo)
+	todoService := NewTodoService(todoRepo, newMockReminderRepo(), newMockCategoryRepo(), newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
 
 	// 定义测试用例
 	tests := []struct {
@@ -104,7 +424,7 @@ func TestTodoService_Create(t *testing.T) {
 			req: &todo.CreateRequest{
 				Title:       "测试待办事项",
 				Description: "测试描述",
-				Priority:    1,
+				Priority:    "high",
 			},
 			wantErr: nil,
 		},
@@ -123,3 +443,160 @@ func TestTodoService_Create(t *testing.T) {
 		})
 	}
 }
+
+// TestTodoService_Trash 测试回收站的删除、恢复与永久删除
+func TestTodoService_Trash(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	reminderRepo := newMockReminderRepo()
+	todoService := NewTodoService(todoRepo, reminderRepo, newMockCategoryRepo(), newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+
+	id, err := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "待删除"})
+	if err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+	// 一个提醒事先被单独删除，恢复待办事项时不应随之恢复
+	_ = reminderRepo.Create(ctx, &models.Reminder{TodoID: id})
+	_ = reminderRepo.Create(ctx, &models.Reminder{TodoID: id})
+	_ = reminderRepo.Delete(ctx, 1)
+	time.Sleep(time.Millisecond)
+
+	if err := todoService.Delete(ctx, id, 1); err != nil {
+		t.Fatalf("Delete() 错误 = %v", err)
+	}
+	if _, err := todoService.Get(ctx, id, 1); err != errors.ErrTodoNotFound {
+		t.Errorf("Get() 已删除的待办事项错误 = %v, 期望 %v", err, errors.ErrTodoNotFound)
+	}
+	trash, _ := todoService.ListTrash(ctx, 1)
+	if len(trash) != 1 || trash[0].ID != id {
+		t.Fatalf("ListTrash() = %v, 期望包含待办事项 %d", trash, id)
+	}
+
+	if err := todoService.Restore(ctx, id, 2); err != errors.ErrForbidden {
+		t.Errorf("Restore() 其他用户错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+	if err := todoService.Restore(ctx, id, 1); err != nil {
+		t.Fatalf("Restore() 错误 = %v", err)
+	}
+	if _, err := todoService.Get(ctx, id, 1); err != nil {
+		t.Errorf("Get() 恢复后错误 = %v", err)
+	}
+	reminders, _ := reminderRepo.ListByTodoID(ctx, id)
+	if len(reminders) != 1 || reminders[0].ID != 2 {
+		t.Errorf("恢复后的提醒 = %v, 期望只恢复提醒 2", reminders)
+	}
+
+	// 永久删除超过保留期的回收站记录
+	if err := todoService.Delete(ctx, id, 1); err != nil {
+		t.Fatalf("Delete() 错误 = %v", err)
+	}
+	if n, _ := todoService.PurgeExpired(ctx, time.Now().Add(-time.Hour)); n != 0 {
+		t.Errorf("PurgeExpired() 清理了未过期的记录: %d", n)
+	}
+	n, err := todoService.PurgeExpired(ctx, time.Now().Add(time.Second))
+	if err != nil || n != 1 {
+		t.Fatalf("PurgeExpired() = %d, %v, 期望 1", n, err)
+	}
+	if err := todoService.Restore(ctx, id, 1); err != errors.ErrTodoNotFound {
+		t.Errorf("Restore() 已永久删除的待办事项错误 = %v, 期望 %v", err, errors.ErrTodoNotFound)
+	}
+	if len(reminderRepo.reminders) != 0 {
+		t.Errorf("永久删除后仍有 %d 个提醒", len(reminderRepo.reminders))
+	}
+}
+
+// txMarker 标记上下文处于 markingTransactor 开启的事务中
+type txMarker struct{}
+
+// markingTransactor 模拟事务执行器，在上下文中标记事务
+type markingTransactor struct{}
+
+func (markingTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, txMarker{}, true))
+}
+
+// recordingCleaner 记录清理调用是否发生在事务中
+type recordingCleaner struct {
+	inTx []bool
+}
+
+func (c *recordingCleaner) CleanupTodo(ctx context.Context, todoID uint) error {
+	inTx, _ := ctx.Value(txMarker{}).(bool)
+	c.inTx = append(c.inTx, inTx)
+	return nil
+}
+
+// TestTodoService_PurgeCleanupInTx 测试永久删除时关联资源在同一事务中清理
+func TestTodoService_PurgeCleanupInTx(t *testing.T) {
+	ctx := context.Background()
+	cleaner := &recordingCleaner{}
+	todoService := NewTodoService(newMockTodoRepo(), newMockReminderRepo(), newMockCategoryRepo(), newMockStatusRepo(),
+		newMockDependencyRepo(), newMockHistoryRepo(), markingTransactor{}, &mockNotifier{}, cleaner)
+
+	id, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "待清理"})
+	if err := todoService.Delete(ctx, id, 1); err != nil {
+		t.Fatalf("Delete() 错误 = %v", err)
+	}
+	if n, err := todoService.PurgeExpired(ctx, time.Now().Add(time.Second)); err != nil || n != 1 {
+		t.Fatalf("PurgeExpired() = %d, %v, 期望 1", n, err)
+	}
+	if len(cleaner.inTx) != 1 || !cleaner.inTx[0] {
+		t.Errorf("清理调用是否在事务中 = %v, 期望 [true]", cleaner.inTx)
+	}
+}
+
+// TestTodoService_Archive 测试手动归档、自动归档与列表过滤
+func TestTodoService_Archive(t *testing.T) {
+	ctx := context.Background()
+	todoService := NewTodoService(newMockTodoRepo(), newMockReminderRepo(), newMockCategoryRepo(), newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+
+	manualID, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "手动归档"})
+	doneID, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "已完成"})
+	openID, _ := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "未完成"})
+
+	completed := true
+	if err := todoService.Update(ctx, doneID, 1, &todo.UpdateRequest{Completed: &completed}); err != nil {
+		t.Fatalf("Update() 错误 = %v", err)
+	}
+	if err := todoService.Archive(ctx, manualID, 1); err != nil {
+		t.Fatalf("Archive() 错误 = %v", err)
+	}
+
+	// 完成时间晚于 before 的不归档
+	if n, _ := todoService.ArchiveCompleted(ctx, time.Now().Add(-time.Hour)); n != 0 {
+		t.Errorf("ArchiveCompleted() 归档了 %d 条, 期望 0", n)
+	}
+	n, err := todoService.ArchiveCompleted(ctx, time.Now().Add(time.Second))
+	if err != nil || n != 1 {
+		t.Fatalf("ArchiveCompleted() = %d, %v, 期望 1", n, err)
+	}
+
+	listIDs := func(archived string) []uint {
+		todos, err := todoService.List(ctx, 1, &todo.ListRequest{Archived: archived})
+		if err != nil {
+			t.Fatalf("List() 错误 = %v", err)
+		}
+		var ids []uint
+		for _, item := range todos {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+	if ids := listIDs(""); len(ids) != 1 || ids[0] != openID {
+		t.Errorf("默认列表 = %v, 期望只有 %d", ids, openID)
+	}
+	if ids := listIDs(todo.ArchivedOnly); len(ids) != 2 {
+		t.Errorf("已归档列表 = %v, 期望 2 条", ids)
+	}
+	if ids := listIDs(todo.ArchivedAll); len(ids) != 3 {
+		t.Errorf("全部列表 = %v, 期望 3 条", ids)
+	}
+
+	if err := todoService.Unarchive(ctx, manualID, 1); err != nil {
+		t.Fatalf("Unarchive() 错误 = %v", err)
+	}
+	got, _ := todoService.Get(ctx, manualID, 1)
+	if got.Archived || got.ArchivedAt != nil {
+		t.Errorf("取消归档后 Archived = %v, ArchivedAt = %v", got.Archived, got.ArchivedAt)
+	}
+}
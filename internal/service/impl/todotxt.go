@@ -0,0 +1,199 @@
+package impl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/pkg/errors"
+	"todo/pkg/todotxt"
+)
+
+// todoTxtDueKey todo.txt 中截止时间使用的键
+const todoTxtDueKey = "due"
+
+// 纯文本格式中截止时间的格式：零点的截止时间只输出日期
+const (
+	plainDateLayout     = "2006-01-02"
+	plainDateTimeLayout = "2006-01-02T15:04"
+)
+
+// todoTxtPriorities 优先级与 todo.txt 优先级字母的对应关系
+var todoTxtPriorities = map[models.Priority]byte{
+	models.PriorityHigh:   'A',
+	models.PriorityMedium: 'B',
+	models.PriorityLow:    'C',
+}
+
+// ExportTodoTxt 将匹配列表查询参数的所有待办事项以 todo.txt 格式写入 w，每行一个待办事项
+// 分类写作 +project，标签写作 @context，名称中的空白被替换为下划线；截止时间写作 due:，
+// 优先级 high、medium、low 分别写作 (A)、(B)、(C)；标题中会被解析为其他字段的单词以反斜杠转义。
+// todo.txt 没有描述字段，描述不会导出
+func (s *TodoService) ExportTodoTxt(ctx context.Context, userID uint, req *todo.ListRequest, w io.Writer) error {
+	filter, categories, err := s.exportFilter(ctx, userID, req)
+	if err != nil {
+		return err
+	}
+	names := categoryNameMap(categories)
+
+	bw := bufio.NewWriter(w)
+	return s.eachPage(ctx, userID, filter, func(todos []*models.Todo) error {
+		for _, todoItem := range todos {
+			if _, err := bw.WriteString(todoTxtTask(todoItem, names).String() + "\n"); err != nil {
+				return err
+			}
+		}
+		return bw.Flush()
+	})
+}
+
+// ImportTodoTxt 从 r 中逐行读取 todo.txt 并创建待办事项，空行被忽略
+// 第一个 +project 作为分类（与导出时一样按空白替换为下划线后的名称匹配现有分类），其余 +project 和 @context 作为标签；
+// (A) 为高优先级、(B) 为中优先级、其余字母为低优先级；不认识的 key:value 保留在标题中，以反斜杠开头的单词去掉反斜杠后作为标题。
+// 每一行在各自的事务中创建，校验失败的行被跳过并记录在结果中，试运行时只校验
+func (s *TodoService) ImportTodoTxt(ctx context.Context, userID uint, r io.Reader, req *todo.ImportRequest) (*todo.ImportResponse, error) {
+	im, err := s.newImporter(ctx, userID, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	// 导出时分类名中的空白被替换为下划线，按替换后的名称也能匹配到原来的分类
+	aliases := make(map[string]string, len(im.byName))
+	for key := range im.byName {
+		aliases[plainName(key)] = key
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		im.resp.Rows++
+
+		item, rowErr := parseTodoTxtLine(text)
+		if rowErr != nil {
+			rowErr.Row = line
+			im.fail(*rowErr)
+			continue
+		}
+		if key, ok := aliases[strings.ToLower(item.category)]; ok {
+			item.category = key
+		}
+		if err := im.add(ctx, line, item); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			return nil, errors.ErrInvalidParameter
+		}
+		return nil, err
+	}
+	return im.resp, nil
+}
+
+// todoTxtTask 将待办事项转换为 todo.txt 任务
+func todoTxtTask(todoItem *models.Todo, categoryNames map[uint]string) todotxt.Task {
+	task := todotxt.Task{
+		Done:      todoItem.Completed,
+		Priority:  todoTxtPriorities[todoItem.Priority],
+		Completed: todoItem.CompletedAt,
+		Text:      todoItem.Title,
+	}
+	if !todoItem.CreatedAt.IsZero() {
+		task.Created = &todoItem.CreatedAt
+	}
+	if todoItem.CategoryID != nil {
+		if name := plainName(categoryNames[*todoItem.CategoryID]); name != "" {
+			task.Projects = []string{name}
+		}
+	}
+	for _, tag := range todoItem.Tags {
+		task.Contexts = append(task.Contexts, plainName(tag.Name))
+	}
+	if todoItem.DueDate != nil {
+		task.Values = map[string]string{todoTxtDueKey: formatPlainTime(*todoItem.DueDate)}
+	}
+	return task
+}
+
+// parseTodoTxtLine 解析并校验一行 todo.txt
+func parseTodoTxtLine(line string) (*importedTodo, *todo.ImportError) {
+	task := todotxt.Parse(line)
+	item := &importedTodo{completed: task.Done}
+
+	switch task.Priority {
+	case 0:
+	case todoTxtPriorities[models.PriorityHigh]:
+		item.create.Priority = string(models.PriorityHigh)
+	case todoTxtPriorities[models.PriorityMedium]:
+		item.create.Priority = string(models.PriorityMedium)
+	default:
+		item.create.Priority = string(models.PriorityLow)
+	}
+
+	tags := task.Contexts
+	if len(task.Projects) > 0 {
+		item.category = task.Projects[0]
+		tags = append(tags, task.Projects[1:]...)
+	}
+	item.create.Tags = normalizeTags(tags)
+
+	title := task.Text
+	keys := make([]string, 0, len(task.Values))
+	for key := range task.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := task.Values[key]
+		if key != todoTxtDueKey {
+			title = strings.TrimSpace(title + " " + key + ":" + value)
+			continue
+		}
+		due, ok := parsePlainTime(value)
+		if !ok {
+			return nil, &todo.ImportError{Column: csvDueDate, Message: fmt.Sprintf("无效的截止时间 %q，使用 2006-01-02 或 2006-01-02T15:04 格式", value)}
+		}
+		item.create.DueDate = &due
+	}
+	item.create.Title = title
+
+	if rowErr := item.validate(); rowErr != nil {
+		return nil, rowErr
+	}
+	return item, nil
+}
+
+// plainName 将分类名或标签名中的空白替换为下划线，使其可以作为 todo.txt 和 Markdown 中的单个单词
+func plainName(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
+
+// formatPlainTime 格式化纯文本格式中的截止时间，零点时只输出日期
+func formatPlainTime(t time.Time) string {
+	t = t.In(time.Local)
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return t.Format(plainDateLayout)
+	}
+	return t.Format(plainDateTimeLayout)
+}
+
+// parsePlainTime 解析纯文本格式中的截止时间，按服务器时区解释
+func parsePlainTime(value string) (time.Time, bool) {
+	for _, layout := range []string{plainDateLayout, plainDateTimeLayout} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
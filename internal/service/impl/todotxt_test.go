@@ -0,0 +1,158 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+)
+
+// seedPlainTextTodos 创建用于纯文本格式导出的待办事项：带分类、标签、截止时间和描述，其中一个已完成
+func seedPlainTextTodos(t *testing.T, service *TodoService, categoryRepo *mockCategoryRepo) {
+	t.Helper()
+	ctx := context.Background()
+	work := &models.Category{Name: "Work", UserID: 1}
+	home := &models.Category{Name: "Home Office", UserID: 1}
+	categoryRepo.Create(ctx, work)
+	categoryRepo.Create(ctx, home)
+
+	due := time.Date(2024, 6, 7, 0, 0, 0, 0, time.Local)
+	evening := time.Date(2024, 6, 8, 18, 30, 0, 0, time.Local)
+	requests := []*todo.CreateRequest{
+		{Title: "写周报", Description: "本周进展\n\n下周计划", Priority: "high", CategoryID: &work.ID, DueDate: &due, Tags: []string{"例行", "写作"}},
+		{Title: "买菜 10:30 前", Priority: "low", DueDate: &evening},
+		{Title: "整理书架", Priority: "medium", CategoryID: &home.ID},
+	}
+	for _, req := range requests {
+		id, err := service.Create(ctx, 1, req)
+		if err != nil {
+			t.Fatalf("Create() 错误 = %v", err)
+		}
+		if req.Title == "买菜 10:30 前" {
+			completed := true
+			if err := service.Update(ctx, id, 1, &todo.UpdateRequest{Completed: &completed}); err != nil {
+				t.Fatalf("Update() 错误 = %v", err)
+			}
+		}
+	}
+}
+
+// todoSummaries 以标题为键汇总用户的待办事项，用于比较导出再导入前后的数据
+func todoSummaries(todoRepo *mockTodoRepo, categoryRepo *mockCategoryRepo, userID uint, withDescription bool) map[string]string {
+	summaries := make(map[string]string)
+	for _, item := range todoRepo.todos {
+		if item.UserID != userID {
+			continue
+		}
+		category := ""
+		if item.CategoryID != nil {
+			category = categoryRepo.categories[*item.CategoryID].Name
+		}
+		tags := make([]string, 0, len(item.Tags))
+		for _, tag := range item.Tags {
+			tags = append(tags, tag.Name)
+		}
+		sort.Strings(tags)
+		due := ""
+		if item.DueDate != nil {
+			due = item.DueDate.Format(time.RFC3339)
+		}
+		summary := fmt.Sprintf("%s|%v|%s|%s|%v", item.Priority, item.Completed, due, category, tags)
+		if withDescription {
+			summary += "|" + item.Description
+		}
+		summaries[item.Title] = summary
+	}
+	return summaries
+}
+
+// TestTodoService_TodoTxtRoundTrip 测试导出的 todo.txt 可以导入为相同的待办事项
+func TestTodoService_TodoTxtRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	categoryRepo := newMockCategoryRepo()
+	service := NewTodoService(todoRepo, newMockReminderRepo(), categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+	seedPlainTextTodos(t, service, categoryRepo)
+	// 标题中会被解析为完成标记、项目、上下文和键值对的单词需要转义
+	for _, title := range []string{`x 提醒 +1 @张三 note:明天 \server`, "(A) 2024-06-01 开头"} {
+		if _, err := service.Create(ctx, 1, &todo.CreateRequest{Title: title, Priority: "low"}); err != nil {
+			t.Fatalf("Create() 错误 = %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := service.ExportTodoTxt(ctx, 1, &todo.ListRequest{}, &buf); err != nil {
+		t.Fatalf("ExportTodoTxt() 错误 = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	today := time.Now().Format("2006-01-02")
+	want := []string{
+		"(A) 写周报 +Work @例行 @写作 due:2024-06-07",
+		"x " + today + " 买菜 10:30 前 due:2024-06-08T18:30 pri:C",
+		"(B) 整理书架 +Home_Office",
+		`(C) \x 提醒 \+1 \@张三 \note:明天 \\server`,
+		`(C) \(A) 2024-06-01 开头`,
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Fatalf("导出内容 = %q, 期望 %q", lines, want)
+	}
+
+	// 另一个用户已有同名分类 Home Office，+Home_Office 应匹配到它而不是新建分类
+	categoryRepo.Create(ctx, &models.Category{Name: "Home Office", UserID: 2})
+	dry, err := service.ImportTodoTxt(ctx, 2, bytes.NewReader(buf.Bytes()), &todo.ImportRequest{DryRun: true})
+	if err != nil {
+		t.Fatalf("试运行 ImportTodoTxt() 错误 = %v", err)
+	}
+	if dry.Imported != 5 || !reflect.DeepEqual(dry.CreatedCategories, []string{"Work"}) || len(todoSummaries(todoRepo, categoryRepo, 2, false)) != 0 {
+		t.Fatalf("试运行结果 = %+v, 期望 5 行通过、只新建分类 Work 且不创建数据", dry)
+	}
+
+	result, err := service.ImportTodoTxt(ctx, 2, bytes.NewReader(buf.Bytes()), &todo.ImportRequest{})
+	if err != nil {
+		t.Fatalf("ImportTodoTxt() 错误 = %v", err)
+	}
+	if result.Rows != 5 || result.Imported != 5 || result.Failed != 0 {
+		t.Fatalf("导入结果 = %+v, 期望 5 行全部导入", result)
+	}
+	// todo.txt 没有描述字段，比较描述以外的所有字段
+	if got, want := todoSummaries(todoRepo, categoryRepo, 2, false), todoSummaries(todoRepo, categoryRepo, 1, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("导入后 = %v, 期望 %v", got, want)
+	}
+}
+
+// TestTodoService_ImportTodoTxt 测试手写的 todo.txt 中的各种写法和逐行校验
+func TestTodoService_ImportTodoTxt(t *testing.T) {
+	ctx := context.Background()
+	todoRepo := newMockTodoRepo()
+	categoryRepo := newMockCategoryRepo()
+	service := NewTodoService(todoRepo, newMockReminderRepo(), categoryRepo, newMockStatusRepo(), newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+
+	input := "\ufeff(D) 2024-06-01 回复邮件 +Inbox +Later @电脑 see:https://example.com\n" +
+		"\n" +
+		"x 2024-06-08 +Inbox\n" +
+		"(A) 续签合同 due:next-week\n"
+	result, err := service.ImportTodoTxt(ctx, 1, strings.NewReader(input), &todo.ImportRequest{})
+	if err != nil {
+		t.Fatalf("ImportTodoTxt() 错误 = %v", err)
+	}
+	if result.Rows != 3 || result.Imported != 1 || result.Failed != 2 {
+		t.Fatalf("导入结果 = %+v, 期望 3 行中 1 行导入", result)
+	}
+	wantErrors := []todo.ImportError{{Row: 3, Column: "title"}, {Row: 4, Column: "due_date"}}
+	for i, want := range wantErrors {
+		if i >= len(result.Errors) || result.Errors[i].Row != want.Row || result.Errors[i].Column != want.Column {
+			t.Errorf("第 %d 个错误 = %+v, 期望第 %d 行的 %s 列", i, result.Errors, want.Row, want.Column)
+		}
+	}
+
+	want := map[string]string{"回复邮件 see:https://example.com": "low|false||Inbox|[Later 电脑]"}
+	if got := todoSummaries(todoRepo, categoryRepo, 1, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("导入后 = %v, 期望 %v", got, want)
+	}
+}
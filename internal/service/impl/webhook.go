@@ -0,0 +1,458 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"todo/api/v1/dto/webhook"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/internal/tenant"
+	"todo/pkg/config"
+	"todo/pkg/errors"
+	"todo/pkg/logger"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// webhookSecretBytes 签名密钥的随机字节数
+	webhookSecretBytes = 24
+	// webhookDeliveryLimit 查询投递日志时最多返回的条数
+	webhookDeliveryLimit = 50
+	// webhookResponseLimit 读取响应体的最大字节数，只为复用连接，不保存响应内容
+	webhookResponseLimit = 4 << 10
+)
+
+// errWebhookAddress 投递地址解析到了不允许访问的地址
+var errWebhookAddress = stderrors.New("webhook 地址指向回环、内网或保留地址")
+
+// EventPublisher 发布待办事项和提醒事件，由 WebhookService 实现
+type EventPublisher interface {
+	// Publish 发布事件，data 为已序列化的事件数据；投递失败只记录日志，不影响业务操作
+	Publish(ctx context.Context, userID uint, event string, data json.RawMessage)
+}
+
+// WebhookService webhook 端点管理与事件投递服务实现
+// 事件在后台任务队列中投递：先查出订阅了该事件的端点，再逐个发送签名的 JSON；
+// 失败后按指数退避重新加入队列，重试耗尽记为端点的一次失败，连续失败达到上限后自动停用端点。
+// 投递是尽力而为的：队列已满时事件被丢弃，等待中的重试只保存在内存中，服务重启后不会继续投递，
+// 接收方需要时可以根据投递日志（webhook_deliveries）或重新查询数据来补齐
+type WebhookService struct {
+	repo         repository.WebhookRepository
+	tasks        TaskEnqueuer
+	client       *http.Client
+	maxAttempts  int
+	backoff      time.Duration
+	disableAfter int
+	allowPrivate bool                            // 是否允许投递到非公网地址
+	after        func(d time.Duration, f func()) // 延迟执行重试，只在内存中计时；测试时替换为立即执行
+}
+
+// NewWebhookService 创建一个新的 webhook 服务实例
+//
+// Parameters:
+//   - repo: webhook 仓库实现
+//   - tasks: 执行投递的后台任务队列
+//   - cfg: 超时、重试和自动停用配置
+//
+// Returns:
+//   - *WebhookService: 返回 webhook 服务实例
+func NewWebhookService(repo repository.WebhookRepository, tasks TaskEnqueuer, cfg *config.WebhookConfig) *WebhookService {
+	// 在建立连接时检查实际连接的地址，域名解析结果变化（DNS 重绑定）或重定向也无法访问内网
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	if !cfg.AllowPrivate {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: publicAddressOnly}
+		transport.DialContext = dialer.DialContext
+	}
+	return &WebhookService{
+		repo:         repo,
+		tasks:        tasks,
+		client:       &http.Client{Timeout: cfg.Timeout, Transport: transport},
+		maxAttempts:  max(cfg.MaxAttempts, 1),
+		backoff:      cfg.RetryBackoff,
+		disableAfter: max(cfg.DisableAfter, 1),
+		allowPrivate: cfg.AllowPrivate,
+		after: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}
+}
+
+// Create 注册 webhook 端点并生成签名密钥
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - userID: 用户ID
+//   - req: 地址和订阅的事件
+//
+// Returns:
+//   - *webhook.CreateResponse: 新建的端点及签名密钥，密钥之后不能再查询
+//   - error: 地址不是 http 或 https、事件类型不受支持时返回 ErrInvalidWebhook
+func (s *WebhookService) Create(ctx context.Context, userID uint, req *webhook.CreateRequest) (*webhook.CreateResponse, error) {
+	events, err := validateWebhook(req.URL, req.Events, s.allowPrivate)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	hook := &models.Webhook{
+		UserID:  userID,
+		URL:     req.URL,
+		Secret:  hex.EncodeToString(b),
+		Events:  events,
+		Enabled: true,
+	}
+	if err := s.repo.Create(ctx, hook); err != nil {
+		return nil, err
+	}
+	return &webhook.CreateResponse{Webhook: hook, Secret: hook.Secret}, nil
+}
+
+// List 获取用户在当前工作空间中的所有 webhook 端点
+func (s *WebhookService) List(ctx context.Context, userID uint) ([]*models.Webhook, error) {
+	return s.repo.ListByUserID(ctx, userID)
+}
+
+// Update 修改 webhook 端点，只修改请求中提供的字段
+// 重新启用端点时清零连续失败次数
+//
+// Parameters:
+//   - ctx: 上下文信息
+//   - id: 端点ID
+//   - userID: 用户ID
+//   - req: 要修改的字段
+//
+// Returns:
+//   - *models.Webhook: 修改后的端点
+//   - error: 端点不存在或不属于当前用户、地址或事件类型无效时返回错误
+func (s *WebhookService) Update(ctx context.Context, id, userID uint, req *webhook.UpdateRequest) (*models.Webhook, error) {
+	hook, err := s.get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	targetURL, events := hook.URL, []string(hook.Events)
+	if req.URL != nil {
+		targetURL = *req.URL
+	}
+	if req.Events != nil {
+		events = req.Events
+	}
+	if hook.Events, err = validateWebhook(targetURL, events, s.allowPrivate); err != nil {
+		return nil, err
+	}
+	hook.URL = targetURL
+	if req.Enabled != nil && *req.Enabled != hook.Enabled {
+		hook.Enabled = *req.Enabled
+		if hook.Enabled {
+			hook.Failures = 0
+			hook.DisabledAt = nil
+		}
+	}
+	if err := s.repo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// Delete 删除 webhook 端点及其投递日志，尚在队列中的投递会被跳过
+func (s *WebhookService) Delete(ctx context.Context, id, userID uint) error {
+	if _, err := s.get(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Deliveries 获取 webhook 端点最近的投递日志，按时间倒序
+func (s *WebhookService) Deliveries(ctx context.Context, id, userID uint) ([]*models.WebhookDelivery, error) {
+	if _, err := s.get(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, id, webhookDeliveryLimit)
+}
+
+// Ping 向 webhook 端点发送一次 webhook.ping 测试事件
+// 测试事件不受订阅的事件和启用状态限制，结果与普通投递一样记录在投递日志中并计入失败次数
+func (s *WebhookService) Ping(ctx context.Context, id, userID uint) error {
+	hook, err := s.get(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(hook)
+	if err != nil {
+		return err
+	}
+	s.tasks.AddTask(s.newDelivery(hook, models.WebhookEventPing, data))
+	return nil
+}
+
+// Publish 将事件交给后台任务队列，由队列查出订阅了该事件的端点并投递
+// 在事务提交后的回调中调用，不能等待队列；上下文中没有工作空间或队列已满时丢弃事件并记录日志
+func (s *WebhookService) Publish(ctx context.Context, userID uint, event string, data json.RawMessage) {
+	wsID, ok := tenant.WorkspaceIDFromContext(ctx)
+	if !ok {
+		logger.Warn().Str("event", event).Uint("user_id", userID).Msg("webhook 事件缺少工作空间，已忽略")
+		return
+	}
+	added := s.tasks.TryAddTask(&webhookEventTask{
+		s:           s,
+		workspaceID: wsID,
+		userID:      userID,
+		event:       event,
+		data:        data,
+		createdAt:   time.Now(),
+	})
+	if !added {
+		logger.Warn().Str("event", event).Uint("user_id", userID).Uint("workspace_id", wsID).Msg("任务队列已满，webhook 事件已丢弃")
+	}
+}
+
+// get 获取 webhook 端点并校验所有者
+func (s *WebhookService) get(ctx context.Context, id, userID uint) (*models.Webhook, error) {
+	hook, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if hook.UserID != userID {
+		return nil, errors.ErrForbidden
+	}
+	return hook, nil
+}
+
+// newDelivery 创建对一个端点的第一次投递
+func (s *WebhookService) newDelivery(hook *models.Webhook, event string, data json.RawMessage) *webhookDelivery {
+	return &webhookDelivery{
+		s:           s,
+		webhookID:   hook.ID,
+		workspaceID: hook.WorkspaceID,
+		event:       webhook.Event{ID: uuid.New().String(), Event: event, CreatedAt: time.Now(), WorkspaceID: hook.WorkspaceID, Data: data},
+		attempt:     1,
+	}
+}
+
+// validateWebhook 校验地址和事件类型，返回去重后的事件列表
+// allowPrivate 为 false 时拒绝 localhost 和非公网的 IP 地址；域名解析到的地址在投递时检查
+func validateWebhook(rawURL string, events []string, allowPrivate bool) (models.WebhookEventList, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, errors.ErrInvalidWebhook
+	}
+	if !allowPrivate {
+		host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+		if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return nil, errors.ErrInvalidWebhook
+		}
+		if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+			return nil, errors.ErrInvalidWebhook
+		}
+	}
+	known := map[string]bool{models.WebhookEventAll: true}
+	for _, e := range models.WebhookEvents {
+		known[e] = true
+	}
+	seen := make(map[string]bool, len(events))
+	list := make(models.WebhookEventList, 0, len(events))
+	for _, e := range events {
+		if !known[e] {
+			return nil, errors.ErrInvalidWebhook
+		}
+		if !seen[e] {
+			seen[e] = true
+			list = append(list, e)
+		}
+	}
+	if len(list) == 0 {
+		return nil, errors.ErrInvalidWebhook
+	}
+	return list, nil
+}
+
+// sharedAddressSpace 运营商级 NAT 使用的共享地址段 100.64.0.0/10
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPublicIP 判断是否为可以投递的公网地址，拒绝回环、链路本地（含云服务元数据地址）、内网、未指定和组播地址
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || ip.IsPrivate() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip))
+}
+
+// publicAddressOnly 作为 net.Dialer.Control 在建立连接前检查目标地址
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return errWebhookAddress
+	}
+	return nil
+}
+
+// signWebhook 计算投递请求的签名：HMAC-SHA256(密钥, 时间戳 + "." + 请求体)
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookEventTask 查出订阅了事件的端点并逐个进行第一次投递
+// 第一次投递在本任务中直接执行，避免队列的工作协程向已满的队列添加任务而阻塞
+type webhookEventTask struct {
+	s           *WebhookService
+	workspaceID uint
+	userID      uint
+	event       string
+	data        json.RawMessage
+	createdAt   time.Time
+}
+
+// Execute 投递事件给所有订阅了该事件的已启用端点
+func (t *webhookEventTask) Execute(ctx context.Context) error {
+	ctx = tenant.WithWorkspaceID(ctx, t.workspaceID)
+	hooks, err := t.s.repo.ListByUserID(ctx, t.userID)
+	if err != nil {
+		logger.Error().Err(err).Str("event", t.event).Uint("user_id", t.userID).Msg("查询 webhook 端点失败")
+		return err
+	}
+	for _, hook := range hooks {
+		if !hook.Enabled || !hook.Events.Matches(t.event) {
+			continue
+		}
+		d := t.s.newDelivery(hook, t.event, t.data)
+		d.event.CreatedAt = t.createdAt
+		_ = d.Execute(ctx)
+	}
+	return nil
+}
+
+// webhookDelivery 对一个端点的一次投递尝试，失败时以递增的 attempt 重新加入队列
+type webhookDelivery struct {
+	s           *WebhookService
+	webhookID   uint
+	workspaceID uint
+	event       webhook.Event
+	attempt     int
+}
+
+// Execute 发送请求并记录投递日志，失败时安排重试或记为端点的一次失败
+func (d *webhookDelivery) Execute(ctx context.Context) error {
+	s := d.s
+	ctx = tenant.WithWorkspaceID(ctx, d.workspaceID)
+	hook, err := s.repo.GetByID(ctx, d.webhookID)
+	if err == errors.ErrWebhookNotFound {
+		// 端点在投递前被删除
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !hook.Enabled && d.event.Event != models.WebhookEventPing {
+		return nil
+	}
+
+	body, err := json.Marshal(d.event)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	status, sendErr := s.send(ctx, hook, d.event, body)
+	entry := &models.WebhookDelivery{
+		WebhookID:  hook.ID,
+		DeliveryID: d.event.ID,
+		Event:      d.event.Event,
+		Attempt:    d.attempt,
+		Payload:    string(body),
+		StatusCode: status,
+		Success:    sendErr == nil,
+		Duration:   time.Since(start).Milliseconds(),
+	}
+	if sendErr != nil {
+		entry.Error = truncate(sendErr.Error(), 255)
+	}
+	if err := s.repo.CreateDelivery(ctx, entry); err != nil {
+		logger.Warn().Err(err).Uint("webhook_id", hook.ID).Msg("记录 webhook 投递日志失败")
+	}
+
+	if sendErr == nil {
+		if hook.Failures > 0 {
+			hook.Failures = 0
+			return s.repo.Update(ctx, hook)
+		}
+		return nil
+	}
+	if d.attempt < s.maxAttempts {
+		next := *d
+		next.attempt++
+		s.after(s.backoff<<(d.attempt-1), func() { s.tasks.AddTask(&next) })
+		return sendErr
+	}
+
+	// 重试耗尽
+	hook.Failures++
+	if hook.Enabled && hook.Failures >= s.disableAfter {
+		now := time.Now()
+		hook.Enabled = false
+		hook.DisabledAt = &now
+		logger.Warn().Uint("webhook_id", hook.ID).Int("failures", hook.Failures).Msg("webhook 连续投递失败，已自动停用")
+	}
+	if err := s.repo.Update(ctx, hook); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// send 发送签名的投递请求，返回响应状态码；响应状态码不是 2xx 时返回错误
+func (s *WebhookService) send(ctx context.Context, hook *models.Webhook, event webhook.Event, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-webhook/1.0")
+	req.Header.Set(webhook.HeaderEvent, event.Event)
+	req.Header.Set(webhook.HeaderDelivery, event.ID)
+	req.Header.Set(webhook.HeaderTimestamp, timestamp)
+	req.Header.Set(webhook.HeaderSignature, signWebhook(hook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhookResponseLimit))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("响应状态码 %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// truncate 将字符串截断到不超过 n 个字节，不截断多字节字符
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,441 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"todo/api/v1/dto/todo"
+	"todo/api/v1/dto/webhook"
+	"todo/internal/models"
+	"todo/internal/tenant"
+	"todo/pkg/config"
+	"todo/pkg/errors"
+)
+
+// mockWebhookRepo 模拟 webhook 仓储接口
+type mockWebhookRepo struct {
+	hooks      map[uint]*models.Webhook
+	deliveries []*models.WebhookDelivery
+	seq        uint
+}
+
+func newMockWebhookRepo() *mockWebhookRepo {
+	return &mockWebhookRepo{hooks: make(map[uint]*models.Webhook), seq: 1}
+}
+
+func (m *mockWebhookRepo) Create(ctx context.Context, hook *models.Webhook) error {
+	hook.ID = m.seq
+	hook.WorkspaceID = 1
+	m.seq++
+	m.hooks[hook.ID] = hook
+	return nil
+}
+
+func (m *mockWebhookRepo) GetByID(ctx context.Context, id uint) (*models.Webhook, error) {
+	hook, exists := m.hooks[id]
+	if !exists {
+		return nil, errors.ErrWebhookNotFound
+	}
+	copied := *hook
+	return &copied, nil
+}
+
+func (m *mockWebhookRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.Webhook, error) {
+	var hooks []*models.Webhook
+	for id := uint(1); id < m.seq; id++ {
+		if hook, exists := m.hooks[id]; exists && hook.UserID == userID {
+			copied := *hook
+			hooks = append(hooks, &copied)
+		}
+	}
+	return hooks, nil
+}
+
+func (m *mockWebhookRepo) Update(ctx context.Context, hook *models.Webhook) error {
+	copied := *hook
+	m.hooks[hook.ID] = &copied
+	return nil
+}
+
+func (m *mockWebhookRepo) Delete(ctx context.Context, id uint) error {
+	delete(m.hooks, id)
+	return nil
+}
+
+func (m *mockWebhookRepo) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	delivery.ID = uint(len(m.deliveries) + 1)
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func (m *mockWebhookRepo) ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	for i := len(m.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if m.deliveries[i].WebhookID == webhookID {
+			deliveries = append(deliveries, m.deliveries[i])
+		}
+	}
+	return deliveries, nil
+}
+
+// recordingPublisher 记录发布的事件
+type recordingPublisher struct {
+	events []string
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, userID uint, event string, data json.RawMessage) {
+	p.events = append(p.events, event)
+}
+
+// webhookReceiver 记录收到的投递请求，按 statuses 依次返回状态码，用完后返回 200
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	rcv.requests = append(rcv.requests, r)
+	rcv.bodies = append(rcv.bodies, body)
+	status := http.StatusOK
+	if len(rcv.statuses) > 0 {
+		status, rcv.statuses = rcv.statuses[0], rcv.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+// newTestWebhookService 创建立即执行重试的 webhook 服务，返回记录的重试间隔
+func newTestWebhookService(repo *mockWebhookRepo, tasks *pendingTasks) (*WebhookService, *[]time.Duration) {
+	service := NewWebhookService(repo, tasks, &config.WebhookConfig{
+		Timeout: time.Second, MaxAttempts: 3, RetryBackoff: time.Second, DisableAfter: 2, AllowPrivate: true,
+	})
+	var delays []time.Duration
+	service.after = func(d time.Duration, f func()) {
+		delays = append(delays, d)
+		f()
+	}
+	return service, &delays
+}
+
+// drain 依次执行队列中的任务，包括执行过程中新加入的任务
+func (p *pendingTasks) drain(ctx context.Context) {
+	for len(p.tasks) > 0 {
+		task := p.tasks[0]
+		p.tasks = p.tasks[1:]
+		_ = task.Execute(ctx)
+	}
+}
+
+// TestWebhookService_Validate 测试地址和事件类型的校验
+func TestWebhookService_Validate(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestWebhookService(newMockWebhookRepo(), &pendingTasks{})
+
+	tests := []struct {
+		name   string
+		url    string
+		events []string
+	}{
+		{name: "非 http 地址", url: "ftp://example.com/hook", events: []string{models.WebhookEventTodoCreated}},
+		{name: "缺少主机", url: "https:///hook", events: []string{models.WebhookEventTodoCreated}},
+		{name: "未知事件", url: "https://example.com/hook", events: []string{"todo.exploded"}},
+		{name: "没有事件", url: "https://example.com/hook"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Create(ctx, 1, &webhook.CreateRequest{URL: tt.url, Events: tt.events}); err != errors.ErrInvalidWebhook {
+				t.Errorf("Create() 错误 = %v, 期望 %v", err, errors.ErrInvalidWebhook)
+			}
+		})
+	}
+
+	resp, err := service.Create(ctx, 1, &webhook.CreateRequest{URL: "https://example.com/hook",
+		Events: []string{models.WebhookEventTodoCreated, models.WebhookEventTodoCreated, models.WebhookEventAll}})
+	if err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+	if len(resp.Secret) != 2*webhookSecretBytes || len(resp.Events) != 2 {
+		t.Errorf("Create() 密钥长度 = %d, 事件 = %v", len(resp.Secret), resp.Events)
+	}
+	if _, err := service.Update(ctx, resp.ID, 2, &webhook.UpdateRequest{}); err != errors.ErrForbidden {
+		t.Errorf("Update() 其他用户的端点错误 = %v, 期望 %v", err, errors.ErrForbidden)
+	}
+}
+
+// TestValidateWebhook_PrivateAddress 测试拒绝回环、链路本地、内网和未指定地址
+func TestValidateWebhook_PrivateAddress(t *testing.T) {
+	events := []string{models.WebhookEventAll}
+	for _, rawURL := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://api.localhost./hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.8/hook",
+		"http://172.16.3.4/hook",
+		"http://192.168.1.1/hook",
+		"http://100.64.0.1/hook",
+		"http://0.0.0.0/hook",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+		"http://[fd00::1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+	} {
+		if _, err := validateWebhook(rawURL, events, false); err != errors.ErrInvalidWebhook {
+			t.Errorf("validateWebhook(%q) 错误 = %v, 期望 %v", rawURL, err, errors.ErrInvalidWebhook)
+		}
+	}
+	for _, rawURL := range []string{"https://example.com/hook", "http://93.184.216.34:8080/hook"} {
+		if _, err := validateWebhook(rawURL, events, false); err != nil {
+			t.Errorf("validateWebhook(%q) 错误 = %v", rawURL, err)
+		}
+	}
+	if _, err := validateWebhook("http://127.0.0.1:8080/hook", events, true); err != nil {
+		t.Errorf("允许内网时 validateWebhook() 错误 = %v", err)
+	}
+}
+
+// TestWebhookService_DialPrivate 测试投递时拒绝连接到非公网地址，即使地址绕过了注册时的校验
+func TestWebhookService_DialPrivate(t *testing.T) {
+	ctx := tenant.WithWorkspaceID(context.Background(), 1)
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	repo := newMockWebhookRepo()
+	tasks := &pendingTasks{}
+	service := NewWebhookService(repo, tasks, &config.WebhookConfig{Timeout: time.Second, MaxAttempts: 1, DisableAfter: 5})
+	// 模拟域名在注册后被解析到回环地址
+	hook := &models.Webhook{UserID: 1, URL: server.URL, Secret: "secret", Events: models.WebhookEventList{models.WebhookEventAll}, Enabled: true}
+	_ = repo.Create(ctx, hook)
+
+	service.Publish(ctx, 1, models.WebhookEventTodoCreated, json.RawMessage(`{}`))
+	tasks.drain(ctx)
+	if len(receiver.requests) != 0 {
+		t.Fatalf("回环地址收到了 %d 个请求", len(receiver.requests))
+	}
+	if len(repo.deliveries) != 1 || repo.deliveries[0].Success || repo.deliveries[0].StatusCode != 0 {
+		t.Errorf("投递日志 = %+v, 期望一次失败的投递", repo.deliveries)
+	}
+}
+
+// TestWebhookService_Deliver 测试事件按订阅过滤并以签名的 JSON 投递
+func TestWebhookService_Deliver(t *testing.T) {
+	ctx := tenant.WithWorkspaceID(context.Background(), 1)
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	repo := newMockWebhookRepo()
+	tasks := &pendingTasks{}
+	service, _ := newTestWebhookService(repo, tasks)
+	created, err := service.Create(ctx, 1, &webhook.CreateRequest{URL: server.URL, Events: []string{models.WebhookEventTodoCompleted}})
+	if err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+	if _, err := service.Create(ctx, 2, &webhook.CreateRequest{URL: server.URL, Events: []string{models.WebhookEventAll}}); err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+
+	service.Publish(ctx, 1, models.WebhookEventTodoCreated, json.RawMessage(`{"id":1}`))
+	service.Publish(ctx, 1, models.WebhookEventTodoCompleted, json.RawMessage(`{"id":1}`))
+	service.Publish(context.Background(), 1, models.WebhookEventTodoCompleted, json.RawMessage(`{"id":2}`))
+	tasks.drain(ctx)
+
+	if len(receiver.requests) != 1 {
+		t.Fatalf("收到 %d 个请求, 期望 1 个", len(receiver.requests))
+	}
+	req, body := receiver.requests[0], receiver.bodies[0]
+	if req.Header.Get(webhook.HeaderEvent) != models.WebhookEventTodoCompleted {
+		t.Errorf("事件头 = %q", req.Header.Get(webhook.HeaderEvent))
+	}
+	signature := signWebhook(created.Secret, req.Header.Get(webhook.HeaderTimestamp), body)
+	if req.Header.Get(webhook.HeaderSignature) != signature {
+		t.Errorf("签名 = %q, 期望 %q", req.Header.Get(webhook.HeaderSignature), signature)
+	}
+	var event webhook.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("解析请求体错误 = %v", err)
+	}
+	if event.ID != req.Header.Get(webhook.HeaderDelivery) || event.WorkspaceID != 1 || string(event.Data) != `{"id":1}` {
+		t.Errorf("请求体 = %s", body)
+	}
+
+	deliveries, _ := service.Deliveries(ctx, created.ID, 1)
+	if len(deliveries) != 1 || !deliveries[0].Success || deliveries[0].StatusCode != http.StatusOK {
+		t.Errorf("Deliveries() = %+v", deliveries)
+	}
+}
+
+// TestWebhookService_RetryAndDisable 测试失败重试的指数退避，以及连续失败后自动停用和重新启用
+func TestWebhookService_RetryAndDisable(t *testing.T) {
+	ctx := tenant.WithWorkspaceID(context.Background(), 1)
+	receiver := &webhookReceiver{statuses: []int{500, 502, 200, 500, 500, 500, 500, 500, 500}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	repo := newMockWebhookRepo()
+	tasks := &pendingTasks{}
+	service, delays := newTestWebhookService(repo, tasks)
+	created, err := service.Create(ctx, 1, &webhook.CreateRequest{URL: server.URL, Events: []string{models.WebhookEventAll}})
+	if err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+
+	// 第三次尝试成功，不计入失败次数
+	service.Publish(ctx, 1, models.WebhookEventTodoCreated, json.RawMessage(`{}`))
+	tasks.drain(ctx)
+	if len(*delays) != 2 || (*delays)[0] != time.Second || (*delays)[1] != 2*time.Second {
+		t.Errorf("重试间隔 = %v, 期望 [1s 2s]", *delays)
+	}
+	deliveries, _ := service.Deliveries(ctx, created.ID, 1)
+	if len(deliveries) != 3 || deliveries[0].Attempt != 3 || !deliveries[0].Success || deliveries[2].StatusCode != 500 {
+		t.Errorf("Deliveries() = %+v", deliveries)
+	}
+	if deliveries[0].DeliveryID != deliveries[2].DeliveryID {
+		t.Error("同一事件的重试应使用相同的投递ID")
+	}
+	if repo.hooks[created.ID].Failures != 0 {
+		t.Errorf("Failures = %d, 期望 0", repo.hooks[created.ID].Failures)
+	}
+
+	// 两个事件都重试耗尽后自动停用
+	service.Publish(ctx, 1, models.WebhookEventTodoUpdated, json.RawMessage(`{}`))
+	tasks.drain(ctx)
+	if hook := repo.hooks[created.ID]; !hook.Enabled || hook.Failures != 1 {
+		t.Errorf("一次失败后 Enabled = %v, Failures = %d", hook.Enabled, hook.Failures)
+	}
+	service.Publish(ctx, 1, models.WebhookEventTodoUpdated, json.RawMessage(`{}`))
+	tasks.drain(ctx)
+	if hook := repo.hooks[created.ID]; hook.Enabled || hook.DisabledAt == nil || hook.Failures != 2 {
+		t.Errorf("连续失败后 Enabled = %v, DisabledAt = %v, Failures = %d", hook.Enabled, hook.DisabledAt, hook.Failures)
+	}
+
+	// 停用的端点不再接收事件，但仍可以测试
+	sent := len(receiver.requests)
+	service.Publish(ctx, 1, models.WebhookEventTodoUpdated, json.RawMessage(`{}`))
+	tasks.drain(ctx)
+	if len(receiver.requests) != sent {
+		t.Errorf("停用的端点收到了 %d 个请求", len(receiver.requests)-sent)
+	}
+	if err := service.Ping(ctx, created.ID, 1); err != nil {
+		t.Fatalf("Ping() 错误 = %v", err)
+	}
+	tasks.drain(ctx)
+	if len(receiver.requests) != sent+1 || receiver.requests[sent].Header.Get(webhook.HeaderEvent) != models.WebhookEventPing {
+		t.Errorf("Ping() 未投递到停用的端点")
+	}
+
+	enabled := true
+	hook, err := service.Update(ctx, created.ID, 1, &webhook.UpdateRequest{Enabled: &enabled})
+	if err != nil {
+		t.Fatalf("Update() 错误 = %v", err)
+	}
+	if !hook.Enabled || hook.Failures != 0 || hook.DisabledAt != nil {
+		t.Errorf("重新启用后 Enabled = %v, Failures = %d, DisabledAt = %v", hook.Enabled, hook.Failures, hook.DisabledAt)
+	}
+}
+
+// TestWebhookEvents_Todo 测试待办事项的创建、修改和完成发布对应的事件
+func TestWebhookEvents_Todo(t *testing.T) {
+	ctx := tenant.WithWorkspaceID(context.Background(), 1)
+	events := &recordingPublisher{}
+	todoService := NewTodoService(newMockTodoRepo(), newMockReminderRepo(), newMockCategoryRepo(), newMockStatusRepo(),
+		newMockDependencyRepo(), newMockHistoryRepo(), nopTransactor{}, &mockNotifier{})
+	todoService.SetEventPublisher(events)
+
+	id, err := todoService.Create(ctx, 1, &todo.CreateRequest{Title: "写周报"})
+	if err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+	title := "写月报"
+	if err := todoService.Update(ctx, id, 1, &todo.UpdateRequest{Title: &title}); err != nil {
+		t.Fatalf("Update() 错误 = %v", err)
+	}
+	completed := true
+	if err := todoService.Update(ctx, id, 1, &todo.UpdateRequest{Completed: &completed}); err != nil {
+		t.Fatalf("Update() 错误 = %v", err)
+	}
+	if err := todoService.Delete(ctx, id, 1); err != nil {
+		t.Fatalf("Delete() 错误 = %v", err)
+	}
+
+	want := []string{models.WebhookEventTodoCreated, models.WebhookEventTodoUpdated, models.WebhookEventTodoUpdated,
+		models.WebhookEventTodoCompleted, models.WebhookEventTodoDeleted}
+	if len(events.events) != len(want) {
+		t.Fatalf("事件 = %v, 期望 %v", events.events, want)
+	}
+	for i := range want {
+		if events.events[i] != want[i] {
+			t.Errorf("事件 = %v, 期望 %v", events.events, want)
+			break
+		}
+	}
+}
+
+// TestReminderService_FireDue 测试到期提醒的发送：一次性提醒标记为已发送，每日提醒顺延到下一次
+func TestReminderService_FireDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 7, 9, 30, 0, 0, time.UTC)
+	todoRepo := newMockTodoRepo()
+	reminderRepo := newMockReminderRepo()
+	reminderRepo.todos = todoRepo
+	events := &recordingPublisher{}
+	service := NewReminderService(reminderRepo, todoRepo, newMockHistoryRepo(), nopTransactor{})
+	service.SetEventPublisher(events)
+
+	open := &models.Todo{Title: "写周报", UserID: 1}
+	done := &models.Todo{Title: "已完成", UserID: 1, Completed: true}
+	todoRepo.Create(ctx, open)
+	todoRepo.Create(ctx, done)
+	once := &models.Reminder{WorkspaceID: 1, TodoID: open.ID, RemindAt: now.Add(-time.Minute), RemindType: models.RemindTypeOnceStr}
+	daily := &models.Reminder{WorkspaceID: 1, TodoID: open.ID, RemindAt: now.Add(-49 * time.Hour), RemindType: models.RemindTypeDailyStr}
+	later := &models.Reminder{WorkspaceID: 1, TodoID: open.ID, RemindAt: now.Add(time.Hour), RemindType: models.RemindTypeOnceStr}
+	skipped := &models.Reminder{WorkspaceID: 1, TodoID: done.ID, RemindAt: now.Add(-time.Hour), RemindType: models.RemindTypeOnceStr}
+	for _, r := range []*models.Reminder{once, daily, later, skipped} {
+		reminderRepo.Create(ctx, r)
+	}
+
+	fired, err := service.FireDue(ctx, now)
+	if err != nil {
+		t.Fatalf("FireDue() 错误 = %v", err)
+	}
+	if fired != 2 || len(events.events) != 2 || events.events[0] != models.WebhookEventReminderFired {
+		t.Errorf("FireDue() = %d, 事件 = %v", fired, events.events)
+	}
+	if !once.Status || later.Status || skipped.Status {
+		t.Errorf("Status once = %v, later = %v, skipped = %v", once.Status, later.Status, skipped.Status)
+	}
+	if daily.Status || !daily.RemindAt.Equal(now.Add(23*time.Hour)) {
+		t.Errorf("每日提醒 Status = %v, RemindAt = %v, 期望 %v", daily.Status, daily.RemindAt, now.Add(23*time.Hour))
+	}
+
+	// 再次执行时没有到期的提醒
+	if fired, _ := service.FireDue(ctx, now); fired != 0 {
+		t.Errorf("再次 FireDue() = %d, 期望 0", fired)
+	}
+}
+
+// TestWebhookService_PublishQueueFull 测试队列已满时丢弃事件而不是等待
+func TestWebhookService_PublishQueueFull(t *testing.T) {
+	ctx := tenant.WithWorkspaceID(context.Background(), 1)
+	tasks := &pendingTasks{limit: 1}
+	service, _ := newTestWebhookService(newMockWebhookRepo(), tasks)
+
+	service.Publish(ctx, 1, models.WebhookEventTodoCreated, json.RawMessage(`{"id":1}`))
+	service.Publish(ctx, 1, models.WebhookEventTodoCreated, json.RawMessage(`{"id":2}`))
+	if len(tasks.tasks) != 1 {
+		t.Fatalf("队列中有 %d 个任务, 期望 1 个", len(tasks.tasks))
+	}
+	if task, ok := tasks.tasks[0].(*webhookEventTask); !ok || string(task.data) != `{"id":1}` {
+		t.Errorf("队列中的任务 = %+v, 期望保留第一个事件", tasks.tasks[0])
+	}
+}
@@ -0,0 +1,143 @@
+package impl
+
+import (
+	"context"
+	"todo/api/v1/dto/todo"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/errors"
+)
+
+// ReassignStatus 将工作流状态中的所有待办事项（包括回收站中的）移动到另一个状态，to 为空时清除状态
+// to 与 from 相同时只按状态是否为终止状态同步完成状态，用于修改状态的 Terminal 之后
+// 每个待办事项的变更都会记录到变更历史
+//
+// Returns:
+//   - int: 受影响的待办事项数量
+//   - error: 目标状态达到在制品上限时返回 ErrWIPLimit
+func (s *TodoService) ReassignStatus(ctx context.Context, actorID, from uint, to *models.Status) (int, error) {
+	todos, err := s.todoRepo.ListByStatusID(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, todoItem := range todos {
+			prev := *todoItem
+			if to == nil {
+				todoItem.StatusID = nil
+			} else if err := s.setStatus(ctx, todoItem, to); err != nil {
+				return err
+			}
+			if err := s.saveWithHistory(ctx, actorID, models.ChangeActionUpdate, &prev, todoItem); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(todos), nil
+}
+
+// initialStatus 设置新建待办事项的工作流状态，需在事务中调用
+// 未指定状态时使用用户的第一个非终止状态；用户没有定义状态时保持为空
+func (s *TodoService) initialStatus(ctx context.Context, todoItem *models.Todo, statusID *uint) error {
+	if statusID != nil {
+		status, err := s.ownedStatus(ctx, todoItem.UserID, *statusID)
+		if err != nil {
+			return err
+		}
+		return s.setStatus(ctx, todoItem, status)
+	}
+
+	statuses, err := s.statusRepo.ListByUserID(ctx, todoItem.UserID)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if !status.Terminal {
+			return s.setStatus(ctx, todoItem, status)
+		}
+	}
+	return nil
+}
+
+// updateStatus 根据更新请求调整待办事项的工作流状态，需在事务中、Completed 已按请求修改后调用
+// 指定状态时完成状态随之改变，与请求中的 Completed 矛盾时返回 ErrInvalidParameter；
+// 只修改完成状态时把待办事项移到第一个与之一致的状态
+func (s *TodoService) updateStatus(ctx context.Context, todoItem, before *models.Todo, req *todo.UpdateRequest) error {
+	switch {
+	case req.ClearStatus:
+		todoItem.StatusID = nil
+	case req.StatusID != nil:
+		status, err := s.ownedStatus(ctx, todoItem.UserID, *req.StatusID)
+		if err != nil {
+			return err
+		}
+		if req.Completed != nil && *req.Completed != status.Terminal {
+			return errors.ErrInvalidParameter
+		}
+		return s.setStatus(ctx, todoItem, status)
+	case todoItem.Completed != before.Completed:
+		return s.syncStatus(ctx, todoItem)
+	}
+	return nil
+}
+
+// syncStatus 完成状态改变后，把不一致的工作流状态换成第一个与完成状态一致的状态
+// 没有可用的状态时清除状态
+func (s *TodoService) syncStatus(ctx context.Context, todoItem *models.Todo) error {
+	if todoItem.StatusID == nil {
+		return nil
+	}
+	current, err := s.statusRepo.GetByID(ctx, *todoItem.StatusID)
+	switch {
+	case err == nil && current.Terminal == todoItem.Completed:
+		return nil
+	case err != nil && err != errors.ErrStatusNotFound:
+		return err
+	}
+
+	statuses, err := s.statusRepo.ListByUserID(ctx, todoItem.UserID)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.Terminal == todoItem.Completed {
+			return s.setStatus(ctx, todoItem, status)
+		}
+	}
+	todoItem.StatusID = nil
+	return nil
+}
+
+// setStatus 将待办事项移入工作流状态并同步完成状态
+// 移入设置了在制品上限的状态时，其中未归档的待办事项数量不能已达到上限
+func (s *TodoService) setStatus(ctx context.Context, todoItem *models.Todo, status *models.Status) error {
+	if status.WIPLimit > 0 && !sameID(todoItem.StatusID, &status.ID) {
+		count, err := s.todoRepo.CountByUserID(ctx, todoItem.UserID, repository.TodoFilter{StatusID: &status.ID})
+		if err != nil {
+			return err
+		}
+		if count >= int64(status.WIPLimit) {
+			return errors.ErrWIPLimit
+		}
+	}
+	todoItem.StatusID = &status.ID
+	markCompleted(todoItem, status.Terminal)
+	return nil
+}
+
+// ownedStatus 获取属于用户的工作流状态
+func (s *TodoService) ownedStatus(ctx context.Context, userID, id uint) (*models.Status, error) {
+	status, err := s.statusRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status.UserID != userID {
+		return nil, errors.ErrForbidden
+	}
+	return status, nil
+}
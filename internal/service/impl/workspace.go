@@ -0,0 +1,260 @@
+package impl
+
+import (
+	"context"
+	"time"
+	"todo/api/v1/dto/workspace"
+	"todo/internal/models"
+	"todo/internal/repository"
+	"todo/pkg/config"
+	"todo/pkg/errors"
+	"todo/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// inviteTTL 邀请的有效期
+const inviteTTL = 7 * 24 * time.Hour
+
+// WorkspaceService 工作空间服务实现
+// 负责工作空间的创建、切换以及成员和邀请管理
+type WorkspaceService struct {
+	workspaceRepo repository.WorkspaceRepository
+	userRepo      repository.UserRepository
+	tx            repository.Transactor
+	jwtCfg        *config.JWTConfig
+}
+
+// NewWorkspaceService 创建一个新的工作空间服务实例
+//
+// Parameters:
+//   - workspaceRepo: 工作空间仓库实现
+//   - userRepo: 用户仓库实现
+//   - tx: 事务执行器
+//   - jwtCfg: JWT配置，用于切换工作空间时签发新令牌
+//
+// Returns:
+//   - *WorkspaceService: 返回工作空间服务实例
+func NewWorkspaceService(workspaceRepo repository.WorkspaceRepository, userRepo repository.UserRepository,
+	tx repository.Transactor, jwtCfg *config.JWTConfig) *WorkspaceService {
+	return &WorkspaceService{
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+		tx:            tx,
+		jwtCfg:        jwtCfg,
+	}
+}
+
+// Create 创建工作空间，创建者自动成为所有者
+func (s *WorkspaceService) Create(ctx context.Context, userID uint, req *workspace.CreateRequest) (*models.Workspace, error) {
+	var ws *models.Workspace
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		ws, err = createWorkspace(ctx, s.workspaceRepo, userID, req.Name)
+		return err
+	})
+	return ws, err
+}
+
+// List 获取用户加入的所有工作空间
+func (s *WorkspaceService) List(ctx context.Context, userID uint) ([]*models.Workspace, error) {
+	return s.workspaceRepo.ListByUserID(ctx, userID)
+}
+
+// Switch 切换当前工作空间
+// 只有工作空间成员才能切换；切换后的工作空间同时成为用户下次登录的默认工作空间
+//
+// Returns:
+//   - string: 携带新工作空间ID的JWT令牌
+//   - error: 可能的错误信息
+func (s *WorkspaceService) Switch(ctx context.Context, userID, workspaceID uint) (string, error) {
+	if _, err := s.workspaceRepo.GetMember(ctx, workspaceID, userID); err != nil {
+		return "", err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	user.DefaultWorkspaceID = workspaceID
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return "", err
+	}
+
+	return utils.GenerateToken(userID, workspaceID, s.jwtCfg)
+}
+
+// Invite 创建工作空间邀请，只有所有者和管理员可以邀请
+func (s *WorkspaceService) Invite(ctx context.Context, userID, workspaceID uint, req *workspace.InviteRequest) (*models.WorkspaceInvite, error) {
+	if err := s.requireManager(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	role := req.Role
+	if role == "" {
+		role = models.WorkspaceRoleMember
+	}
+
+	invite := &models.WorkspaceInvite{
+		WorkspaceID: workspaceID,
+		Email:       req.Email,
+		Role:        role,
+		Token:       uuid.New().String(),
+		InvitedBy:   userID,
+		ExpiresAt:   time.Now().Add(inviteTTL),
+	}
+	if err := s.workspaceRepo.CreateInvite(ctx, invite); err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+// AcceptInvite 接受邀请加入工作空间
+// 邀请限定了邮箱时，只有邮箱匹配的用户才能接受
+func (s *WorkspaceService) AcceptInvite(ctx context.Context, userID uint, token string) (*models.Workspace, error) {
+	invite, err := s.workspaceRepo.GetInviteByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if invite.AcceptedAt != nil || time.Now().After(invite.ExpiresAt) {
+		return nil, errors.ErrInviteExpired
+	}
+
+	if invite.Email != "" {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if user.Email != invite.Email {
+			return nil, errors.ErrForbidden
+		}
+	}
+
+	// 已经是成员时只标记邀请为已使用
+	_, err = s.workspaceRepo.GetMember(ctx, invite.WorkspaceID, userID)
+	alreadyMember := err == nil
+	if err != nil && err != errors.ErrNotWorkspaceMember {
+		return nil, err
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if !alreadyMember {
+			member := &models.WorkspaceMember{
+				WorkspaceID: invite.WorkspaceID,
+				UserID:      userID,
+				Role:        invite.Role,
+			}
+			if err := s.workspaceRepo.AddMember(ctx, member); err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		invite.AcceptedAt = &now
+		return s.workspaceRepo.UpdateInvite(ctx, invite)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.workspaceRepo.GetByID(ctx, invite.WorkspaceID)
+}
+
+// ListMembers 获取工作空间成员列表，只有成员可以查看
+func (s *WorkspaceService) ListMembers(ctx context.Context, userID, workspaceID uint) ([]*models.WorkspaceMember, error) {
+	if _, err := s.workspaceRepo.GetMember(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	return s.workspaceRepo.ListMembers(ctx, workspaceID)
+}
+
+// RemoveMember 移除工作空间成员
+// 所有者和管理员可以移除他人，任何成员都可以移除自己（退出），但所有者不能被移除
+func (s *WorkspaceService) RemoveMember(ctx context.Context, userID, workspaceID, memberID uint) error {
+	if memberID != userID {
+		if err := s.requireManager(ctx, workspaceID, userID); err != nil {
+			return err
+		}
+	}
+
+	member, err := s.workspaceRepo.GetMember(ctx, workspaceID, memberID)
+	if err != nil {
+		return err
+	}
+	if member.Role == models.WorkspaceRoleOwner {
+		return errors.ErrForbidden
+	}
+
+	// 被移除的用户若以该工作空间为默认工作空间，改回其个人工作空间，避免再次登录时仍签发该工作空间的令牌
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.workspaceRepo.RemoveMember(ctx, workspaceID, memberID); err != nil {
+			return err
+		}
+		user, err := s.userRepo.GetByID(ctx, memberID)
+		if err != nil {
+			return err
+		}
+		if user.DefaultWorkspaceID != workspaceID {
+			return nil
+		}
+		if user.DefaultWorkspaceID, err = personalWorkspaceID(ctx, s.workspaceRepo, memberID); err != nil {
+			return err
+		}
+		return s.userRepo.Update(ctx, user)
+	})
+}
+
+// CheckMember 校验用户是否仍是工作空间成员，不是成员时返回 ErrNotWorkspaceMember
+func (s *WorkspaceService) CheckMember(ctx context.Context, workspaceID, userID uint) error {
+	_, err := s.workspaceRepo.GetMember(ctx, workspaceID, userID)
+	return err
+}
+
+// requireManager 校验用户是否为工作空间的所有者或管理员
+func (s *WorkspaceService) requireManager(ctx context.Context, workspaceID, userID uint) error {
+	member, err := s.workspaceRepo.GetMember(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !member.CanManage() {
+		return errors.ErrForbidden
+	}
+	return nil
+}
+
+// personalWorkspaceID 返回用户的个人工作空间，即用户加入的、由其本人创建的最早的工作空间
+// 没有这样的工作空间时返回 0，用户下次登录时会补建
+func personalWorkspaceID(ctx context.Context, repo repository.WorkspaceRepository, userID uint) (uint, error) {
+	workspaces, err := repo.ListByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	var id uint
+	for _, ws := range workspaces {
+		if ws.OwnerID == userID && (id == 0 || ws.ID < id) {
+			id = ws.ID
+		}
+	}
+	return id, nil
+}
+
+// createWorkspace 创建工作空间并将创建者添加为所有者
+// 调用方负责将其放在事务中执行
+func createWorkspace(ctx context.Context, repo repository.WorkspaceRepository, ownerID uint, name string) (*models.Workspace, error) {
+	ws := &models.Workspace{
+		Name:    name,
+		OwnerID: ownerID,
+	}
+	if err := repo.Create(ctx, ws); err != nil {
+		return nil, err
+	}
+
+	member := &models.WorkspaceMember{
+		WorkspaceID: ws.ID,
+		UserID:      ownerID,
+		Role:        models.WorkspaceRoleOwner,
+	}
+	if err := repo.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
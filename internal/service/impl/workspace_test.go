@@ -0,0 +1,237 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"todo/api/v1/dto/auth"
+	"todo/api/v1/dto/workspace"
+	"todo/internal/models"
+	"todo/pkg/config"
+	"todo/pkg/errors"
+	"todo/pkg/utils"
+)
+
+// nopTransactor 模拟事务执行器，直接执行传入的函数
+type nopTransactor struct{}
+
+func (nopTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// mockWorkspaceRepo 模拟工作空间仓储接口
+type mockWorkspaceRepo struct {
+	workspaces map[uint]*models.Workspace
+	members    []*models.WorkspaceMember
+	invites    map[string]*models.WorkspaceInvite
+	seq        uint
+}
+
+// newMockWorkspaceRepo 创建一个新的工作空间仓储mock对象
+func newMockWorkspaceRepo() *mockWorkspaceRepo {
+	return &mockWorkspaceRepo{
+		workspaces: make(map[uint]*models.Workspace),
+		invites:    make(map[string]*models.WorkspaceInvite),
+		seq:        1,
+	}
+}
+
+func (m *mockWorkspaceRepo) Create(ctx context.Context, ws *models.Workspace) error {
+	ws.ID = m.seq
+	m.seq++
+	m.workspaces[ws.ID] = ws
+	return nil
+}
+
+func (m *mockWorkspaceRepo) GetByID(ctx context.Context, id uint) (*models.Workspace, error) {
+	ws, ok := m.workspaces[id]
+	if !ok {
+		return nil, errors.ErrWorkspaceNotFound
+	}
+	return ws, nil
+}
+
+func (m *mockWorkspaceRepo) ListByUserID(ctx context.Context, userID uint) ([]*models.Workspace, error) {
+	var result []*models.Workspace
+	for _, member := range m.members {
+		if member.UserID == userID {
+			result = append(result, m.workspaces[member.WorkspaceID])
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWorkspaceRepo) AddMember(ctx context.Context, member *models.WorkspaceMember) error {
+	m.members = append(m.members, member)
+	return nil
+}
+
+func (m *mockWorkspaceRepo) GetMember(ctx context.Context, workspaceID, userID uint) (*models.WorkspaceMember, error) {
+	for _, member := range m.members {
+		if member.WorkspaceID == workspaceID && member.UserID == userID {
+			return member, nil
+		}
+	}
+	return nil, errors.ErrNotWorkspaceMember
+}
+
+func (m *mockWorkspaceRepo) ListMembers(ctx context.Context, workspaceID uint) ([]*models.WorkspaceMember, error) {
+	var result []*models.WorkspaceMember
+	for _, member := range m.members {
+		if member.WorkspaceID == workspaceID {
+			result = append(result, member)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWorkspaceRepo) RemoveMember(ctx context.Context, workspaceID, userID uint) error {
+	for i, member := range m.members {
+		if member.WorkspaceID == workspaceID && member.UserID == userID {
+			m.members = append(m.members[:i], m.members[i+1:]...)
+			return nil
+		}
+	}
+	return errors.ErrNotWorkspaceMember
+}
+
+func (m *mockWorkspaceRepo) CreateInvite(ctx context.Context, invite *models.WorkspaceInvite) error {
+	m.invites[invite.Token] = invite
+	return nil
+}
+
+func (m *mockWorkspaceRepo) GetInviteByToken(ctx context.Context, token string) (*models.WorkspaceInvite, error) {
+	invite, ok := m.invites[token]
+	if !ok {
+		return nil, errors.ErrInviteNotFound
+	}
+	return invite, nil
+}
+
+func (m *mockWorkspaceRepo) UpdateInvite(ctx context.Context, invite *models.WorkspaceInvite) error {
+	m.invites[invite.Token] = invite
+	return nil
+}
+
+func (m *mockWorkspaceRepo) ClaimLegacyData(ctx context.Context, userID, workspaceID uint) error {
+	return nil
+}
+
+// TestWorkspaceService_InviteFlow 测试邀请、接受邀请和切换工作空间的完整流程
+func TestWorkspaceService_InviteFlow(t *testing.T) {
+	ctx := context.Background()
+	userRepo := newMockUserRepo()
+	owner := &models.User{Base: models.Base{ID: 1}, Username: "owner", Email: "owner@example.com"}
+	guest := &models.User{Base: models.Base{ID: 2}, Username: "guest", Email: "guest@example.com"}
+	_ = userRepo.Create(ctx, owner)
+	_ = userRepo.Create(ctx, guest)
+
+	svc := NewWorkspaceService(newMockWorkspaceRepo(), userRepo, nopTransactor{}, &config.JWTConfig{
+		Secret:      "test_secret",
+		ExpireHours: 1,
+	})
+
+	ws, err := svc.Create(ctx, owner.ID, &workspace.CreateRequest{Name: "团队"})
+	if err != nil {
+		t.Fatalf("Create() 错误 = %v", err)
+	}
+
+	// 非成员不能切换到该工作空间
+	if _, err := svc.Switch(ctx, guest.ID, ws.ID); err != errors.ErrNotWorkspaceMember {
+		t.Fatalf("Switch() 错误 = %v, 期望错误 %v", err, errors.ErrNotWorkspaceMember)
+	}
+
+	// 非成员不能发出邀请
+	if _, err := svc.Invite(ctx, guest.ID, ws.ID, &workspace.InviteRequest{}); err != errors.ErrNotWorkspaceMember {
+		t.Fatalf("Invite() 错误 = %v, 期望错误 %v", err, errors.ErrNotWorkspaceMember)
+	}
+
+	// 限定邮箱的邀请不能被其他用户接受
+	invite, err := svc.Invite(ctx, owner.ID, ws.ID, &workspace.InviteRequest{Email: "someone@example.com"})
+	if err != nil {
+		t.Fatalf("Invite() 错误 = %v", err)
+	}
+	if _, err := svc.AcceptInvite(ctx, guest.ID, invite.Token); err != errors.ErrForbidden {
+		t.Fatalf("AcceptInvite() 错误 = %v, 期望错误 %v", err, errors.ErrForbidden)
+	}
+
+	invite, err = svc.Invite(ctx, owner.ID, ws.ID, &workspace.InviteRequest{Email: guest.Email})
+	if err != nil {
+		t.Fatalf("Invite() 错误 = %v", err)
+	}
+	if _, err := svc.AcceptInvite(ctx, guest.ID, invite.Token); err != nil {
+		t.Fatalf("AcceptInvite() 错误 = %v", err)
+	}
+
+	// 邀请只能使用一次
+	if _, err := svc.AcceptInvite(ctx, guest.ID, invite.Token); err != errors.ErrInviteExpired {
+		t.Fatalf("AcceptInvite() 错误 = %v, 期望错误 %v", err, errors.ErrInviteExpired)
+	}
+
+	if _, err := svc.Switch(ctx, guest.ID, ws.ID); err != nil {
+		t.Fatalf("Switch() 错误 = %v", err)
+	}
+	if guest.DefaultWorkspaceID != ws.ID {
+		t.Errorf("切换后默认工作空间 = %d, 期望 %d", guest.DefaultWorkspaceID, ws.ID)
+	}
+
+	// 所有者不能被移除
+	if err := svc.RemoveMember(ctx, guest.ID, ws.ID, owner.ID); err != errors.ErrForbidden {
+		t.Errorf("RemoveMember() 错误 = %v, 期望错误 %v", err, errors.ErrForbidden)
+	}
+}
+
+// TestWorkspaceService_RemoveMember 测试被移除的成员不能再通过旧令牌或重新登录访问工作空间
+func TestWorkspaceService_RemoveMember(t *testing.T) {
+	ctx := context.Background()
+	jwtCfg := &config.JWTConfig{Secret: "test_secret", ExpireHours: 1}
+	userRepo := newMockUserRepo()
+	workspaceRepo := newMockWorkspaceRepo()
+	svc := NewWorkspaceService(workspaceRepo, userRepo, nopTransactor{}, jwtCfg)
+	authService := NewAuthService(userRepo, workspaceRepo, nopTransactor{}, jwtCfg)
+
+	owner := &models.User{Base: models.Base{ID: 1}, Username: "owner"}
+	guest := &models.User{Base: models.Base{ID: 2}, Username: "guest"}
+	for _, user := range []*models.User{owner, guest} {
+		_ = user.SetPassword("password123")
+		_ = userRepo.Create(ctx, user)
+		personal, _ := svc.Create(ctx, user.ID, &workspace.CreateRequest{Name: user.Username})
+		user.DefaultWorkspaceID = personal.ID
+	}
+	personal := guest.DefaultWorkspaceID
+
+	team, _ := svc.Create(ctx, owner.ID, &workspace.CreateRequest{Name: "团队"})
+	invite, _ := svc.Invite(ctx, owner.ID, team.ID, &workspace.InviteRequest{})
+	if _, err := svc.AcceptInvite(ctx, guest.ID, invite.Token); err != nil {
+		t.Fatalf("AcceptInvite() 错误 = %v", err)
+	}
+	token, err := svc.Switch(ctx, guest.ID, team.ID)
+	if err != nil {
+		t.Fatalf("Switch() 错误 = %v", err)
+	}
+	if err := svc.RemoveMember(ctx, owner.ID, team.ID, guest.ID); err != nil {
+		t.Fatalf("RemoveMember() 错误 = %v", err)
+	}
+
+	// 旧令牌仍指向团队工作空间，认证中间件的成员校验会拒绝它
+	claims, err := utils.ParseToken(token, jwtCfg)
+	if err != nil || claims.WorkspaceID != team.ID {
+		t.Fatalf("ParseToken() = %+v, 错误 = %v", claims, err)
+	}
+	if err := svc.CheckMember(ctx, claims.WorkspaceID, claims.UserID); err != errors.ErrNotWorkspaceMember {
+		t.Errorf("CheckMember() 错误 = %v, 期望错误 %v", err, errors.ErrNotWorkspaceMember)
+	}
+
+	// 重新登录只能进入个人工作空间
+	if guest.DefaultWorkspaceID != personal {
+		t.Errorf("移除后默认工作空间 = %d, 期望 %d", guest.DefaultWorkspaceID, personal)
+	}
+	guest.DefaultWorkspaceID = team.ID // 模拟移除前遗留的默认工作空间
+	token, info, err := authService.Login(ctx, &auth.LoginRequest{Username: "guest", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() 错误 = %v", err)
+	}
+	claims, _ = utils.ParseToken(token, jwtCfg)
+	if info.WorkspaceID != personal || claims.WorkspaceID != personal {
+		t.Errorf("Login() 工作空间 = %d, 令牌工作空间 = %d, 期望 %d", info.WorkspaceID, claims.WorkspaceID, personal)
+	}
+}
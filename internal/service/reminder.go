@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 	"todo/api/v1/dto/reminder"
 	"todo/internal/models"
 )
@@ -24,4 +25,7 @@ type ReminderService interface {
 
 	// Delete 删除提醒
 	Delete(ctx context.Context, id, userID uint) error
+
+	// FireDue 发送所有提醒时间不晚于 now 的提醒，返回发送数量，由后台提醒任务调用
+	FireDue(ctx context.Context, now time.Time) (int, error)
 }
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"todo/api/v1/dto/search"
+)
+
+// SearchService 全文搜索服务接口
+type SearchService interface {
+	// Search 在标题、描述和评论中搜索待办事项，按相关度降序返回并附带高亮摘要
+	Search(ctx context.Context, userID uint, req *search.Request) (*search.Response, error)
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 	"todo/api/v1/dto/category"
 	"todo/api/v1/dto/reminder"
 	"todo/api/v1/dto/todo"
@@ -9,6 +11,8 @@ import (
 	"todo/internal/repository"
 	"todo/internal/service/impl"
 	"todo/pkg/config"
+	"todo/pkg/notify"
+	"todo/pkg/storage"
 
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
@@ -17,30 +21,169 @@ import (
 // NewAuthService 创建新的认证服务实例
 func NewAuthService(db *gorm.DB, rdb *redis.Client, jwtCfg *config.JWTConfig) AuthService {
 	userRepo := repository.NewUserRepository(db)
-	return impl.NewAuthService(userRepo, jwtCfg)
+	workspaceRepo := repository.NewWorkspaceRepository(db)
+	return impl.NewAuthService(userRepo, workspaceRepo, repository.NewTransactor(db), jwtCfg)
+}
+
+// NewWorkspaceService 创建新的工作空间服务实例
+func NewWorkspaceService(db *gorm.DB, jwtCfg *config.JWTConfig) WorkspaceService {
+	workspaceRepo := repository.NewWorkspaceRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	return impl.NewWorkspaceService(workspaceRepo, userRepo, repository.NewTransactor(db), jwtCfg)
 }
 
 // NewTodoService 创建新的待办事项服务实例
-func NewTodoService(db *gorm.DB) TodoService {
+// notifier: 待办事项不再被阻塞时用于通知其所有者
+// events: 待办事项和提醒变更后用于发布 webhook 事件
+// comments, attachments, timeEntries: 永久删除待办事项时用于清理其评论、附件和时间记录
+func NewTodoService(db *gorm.DB, notifier notify.Notifier, events impl.EventPublisher, comments CommentService,
+	attachments AttachmentService, timeEntries TimeEntryService) TodoService {
+	todoRepo := repository.NewTodoRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	historyRepo := repository.NewHistoryRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	statusRepo := repository.NewStatusRepository(db)
+	depRepo := repository.NewDependencyRepository(db)
+	svc := impl.NewTodoService(todoRepo, reminderRepo, categoryRepo, statusRepo, depRepo, historyRepo,
+		repository.NewTransactor(db), notifier, comments, attachments, timeEntries)
+	svc.SetEventPublisher(events)
+	return svc
+}
+
+// NewTimeEntryService 创建新的时间记录服务实例
+func NewTimeEntryService(db *gorm.DB) TimeEntryService {
+	return impl.NewTimeEntryService(repository.NewTimeEntryRepository(db), repository.NewTodoRepository(db),
+		repository.NewCategoryRepository(db), repository.NewTransactor(db))
+}
+
+// NewDependencyService 创建新的待办事项依赖关系服务实例
+func NewDependencyService(db *gorm.DB, notifier notify.Notifier) DependencyService {
+	return impl.NewDependencyService(repository.NewDependencyRepository(db), repository.NewTodoRepository(db),
+		repository.NewTransactor(db), notifier)
+}
+
+// NewSearchService 创建新的全文搜索服务实例
+func NewSearchService(db *gorm.DB) SearchService {
+	return impl.NewSearchService(repository.NewSearchIndex(db), repository.NewCategoryRepository(db))
+}
+
+// NewFilterService 创建新的过滤条件服务实例
+func NewFilterService(db *gorm.DB) FilterService {
+	return impl.NewFilterService(repository.NewSavedFilterRepository(db), repository.NewTodoRepository(db), repository.NewCategoryRepository(db))
+}
+
+// NewStatusService 创建新的看板工作流状态服务实例
+func NewStatusService(db *gorm.DB, notifier notify.Notifier, events impl.EventPublisher) StatusService {
+	todoRepo := repository.NewTodoRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	statusRepo := repository.NewStatusRepository(db)
+	tx := repository.NewTransactor(db)
+	// 修改或删除状态只会调整待办事项的状态，不涉及永久删除，因此无需资源清理
+	todos := impl.NewTodoService(todoRepo, repository.NewReminderRepository(db), categoryRepo, statusRepo,
+		repository.NewDependencyRepository(db), repository.NewHistoryRepository(db), tx, notifier)
+	todos.SetEventPublisher(events)
+	return impl.NewStatusService(statusRepo, todoRepo, categoryRepo, todos, tx)
+}
+
+// NewTemplateService 创建新的待办事项模板服务实例
+func NewTemplateService(db *gorm.DB, notifier notify.Notifier, events impl.EventPublisher) TemplateService {
+	todoRepo := repository.NewTodoRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	depRepo := repository.NewDependencyRepository(db)
+	historyRepo := repository.NewHistoryRepository(db)
+	tx := repository.NewTransactor(db)
+	// 实例化模板只会创建待办事项，不涉及永久删除，因此无需资源清理
+	todos := impl.NewTodoService(todoRepo, reminderRepo, categoryRepo, repository.NewStatusRepository(db), depRepo,
+		historyRepo, tx, notifier)
+	todos.SetEventPublisher(events)
+	reminders := impl.NewReminderService(reminderRepo, todoRepo, historyRepo, tx)
+	reminders.SetEventPublisher(events)
+	return impl.NewTemplateService(repository.NewTemplateRepository(db), categoryRepo, depRepo, todos, reminders, tx)
+}
+
+// NewCalendarService 创建新的日历导出与订阅服务实例
+func NewCalendarService(db *gorm.DB) CalendarService {
+	return impl.NewCalendarService(repository.NewCalendarFeedRepository(db), repository.NewWorkspaceRepository(db),
+		repository.NewTodoRepository(db), repository.NewReminderRepository(db), repository.NewCategoryRepository(db))
+}
+
+// NewCalDAVService 创建新的 CalDAV 同步服务实例
+func NewCalDAVService(db *gorm.DB, notifier notify.Notifier, events impl.EventPublisher) CalDAVService {
+	todoRepo := repository.NewTodoRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	historyRepo := repository.NewHistoryRepository(db)
+	tx := repository.NewTransactor(db)
+	// 通过 CalDAV 删除只会把待办事项移入回收站，不涉及永久删除，因此无需资源清理
+	todos := impl.NewTodoService(todoRepo, reminderRepo, categoryRepo, repository.NewStatusRepository(db),
+		repository.NewDependencyRepository(db), historyRepo, tx, notifier)
+	todos.SetEventPublisher(events)
+	reminders := impl.NewReminderService(reminderRepo, todoRepo, historyRepo, tx)
+	reminders.SetEventPublisher(events)
+	return impl.NewCalDAVService(todoRepo, reminderRepo, categoryRepo, todos, reminders, tx)
+}
+
+// NewBackupService 创建新的账户数据导出与导入服务实例
+// tasks: 执行导出任务的后台任务队列
+func NewBackupService(db *gorm.DB, blobs storage.BlobStore, tasks impl.TaskEnqueuer, notifier notify.Notifier) BackupService {
+	todoRepo := repository.NewTodoRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	tx := repository.NewTransactor(db)
+	// 导入只会创建待办事项，不涉及永久删除，因此无需资源清理
+	todos := impl.NewTodoService(todoRepo, reminderRepo, categoryRepo, repository.NewStatusRepository(db),
+		repository.NewDependencyRepository(db), repository.NewHistoryRepository(db), tx, notifier)
+	return impl.NewBackupService(repository.NewDataExportRepository(db), repository.NewUserRepository(db), todoRepo,
+		reminderRepo, categoryRepo, repository.NewCommentRepository(db), todos, blobs, tasks, tx)
+}
+
+// NewWebhookService 创建新的 webhook 服务实例
+// tasks: 执行投递的后台任务队列
+func NewWebhookService(db *gorm.DB, tasks impl.TaskEnqueuer, cfg *config.WebhookConfig) WebhookService {
+	return impl.NewWebhookService(repository.NewWebhookRepository(db), tasks, cfg)
+}
+
+// NewAttachmentService 创建新的附件服务实例
+func NewAttachmentService(db *gorm.DB, blobs storage.BlobStore, cfg *config.AttachmentConfig) AttachmentService {
+	attachmentRepo := repository.NewAttachmentRepository(db)
 	todoRepo := repository.NewTodoRepository(db)
-	return impl.NewTodoService(todoRepo)
+	return impl.NewAttachmentService(attachmentRepo, todoRepo, blobs, cfg)
 }
 
 // NewCategoryService 创建新的分类服务实例
-func NewCategoryService(db *gorm.DB) CategoryService {
+func NewCategoryService(db *gorm.DB, notifier notify.Notifier, events impl.EventPublisher) CategoryService {
 	categoryRepo := repository.NewCategoryRepository(db)
-	svc := impl.NewCategoryService(categoryRepo)
+	historyRepo := repository.NewHistoryRepository(db)
+	tx := repository.NewTransactor(db)
+	// 删除分类时只会把待办事项移入回收站，不涉及永久删除，因此无需资源清理
+	todos := impl.NewTodoService(repository.NewTodoRepository(db), repository.NewReminderRepository(db), categoryRepo,
+		repository.NewStatusRepository(db), repository.NewDependencyRepository(db), historyRepo, tx, notifier)
+	todos.SetEventPublisher(events)
+	svc := impl.NewCategoryService(categoryRepo, todos, historyRepo, tx)
 	return &categoryServiceWrapper{svc}
 }
 
 // NewReminderService 创建新的提醒服务实例
-func NewReminderService(db *gorm.DB) ReminderService {
+// events: 提醒变更和到期后用于发布 webhook 事件
+func NewReminderService(db *gorm.DB, events impl.EventPublisher) ReminderService {
 	reminderRepo := repository.NewReminderRepository(db)
 	todoRepo := repository.NewTodoRepository(db)
-	svc := impl.NewReminderService(reminderRepo, todoRepo)
+	historyRepo := repository.NewHistoryRepository(db)
+	svc := impl.NewReminderService(reminderRepo, todoRepo, historyRepo, repository.NewTransactor(db))
+	svc.SetEventPublisher(events)
 	return &reminderServiceWrapper{svc}
 }
 
+// NewCommentService 创建新的评论服务实例
+func NewCommentService(db *gorm.DB, notifier notify.Notifier) CommentService {
+	commentRepo := repository.NewCommentRepository(db)
+	todoRepo := repository.NewTodoRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	workspaceRepo := repository.NewWorkspaceRepository(db)
+	return impl.NewCommentService(commentRepo, todoRepo, userRepo, workspaceRepo, repository.NewTransactor(db), notifier)
+}
+
 // Wrapper types
 type todoServiceWrapper struct {
 	svc *impl.TodoService
@@ -62,8 +205,8 @@ func (w *todoServiceWrapper) Create(ctx context.Context, userID uint, req *todo.
 	return w.svc.Create(ctx, userID, req)
 }
 
-func (w *todoServiceWrapper) List(ctx context.Context, userID uint) ([]*models.Todo, error) {
-	return w.svc.List(ctx, userID)
+func (w *todoServiceWrapper) List(ctx context.Context, userID uint, req *todo.ListRequest) ([]*models.Todo, error) {
+	return w.svc.List(ctx, userID, req)
 }
 
 func (w *todoServiceWrapper) Get(ctx context.Context, id, userID uint) (*models.Todo, error) {
@@ -78,6 +221,62 @@ func (w *todoServiceWrapper) Delete(ctx context.Context, id, userID uint) error
 	return w.svc.Delete(ctx, id, userID)
 }
 
+func (w *todoServiceWrapper) Bulk(ctx context.Context, userID uint, req *todo.BulkRequest) (*todo.BulkResponse, error) {
+	return w.svc.Bulk(ctx, userID, req)
+}
+
+func (w *todoServiceWrapper) Move(ctx context.Context, id, userID uint, req *todo.MoveRequest) error {
+	return w.svc.Move(ctx, id, userID, req)
+}
+
+func (w *todoServiceWrapper) ExportCSV(ctx context.Context, userID uint, req *todo.ListRequest, out io.Writer) error {
+	return w.svc.ExportCSV(ctx, userID, req, out)
+}
+
+func (w *todoServiceWrapper) ImportCSV(ctx context.Context, userID uint, r io.Reader, req *todo.ImportRequest) (*todo.ImportResponse, error) {
+	return w.svc.ImportCSV(ctx, userID, r, req)
+}
+
+func (w *todoServiceWrapper) Duplicate(ctx context.Context, id, userID uint, req *todo.DuplicateRequest) (*models.Todo, error) {
+	return w.svc.Duplicate(ctx, id, userID, req)
+}
+
+func (w *todoServiceWrapper) Archive(ctx context.Context, id, userID uint) error {
+	return w.svc.Archive(ctx, id, userID)
+}
+
+func (w *todoServiceWrapper) Unarchive(ctx context.Context, id, userID uint) error {
+	return w.svc.Unarchive(ctx, id, userID)
+}
+
+func (w *todoServiceWrapper) ArchiveCompleted(ctx context.Context, before time.Time) (int, error) {
+	return w.svc.ArchiveCompleted(ctx, before)
+}
+
+func (w *todoServiceWrapper) ListTrash(ctx context.Context, userID uint) ([]*models.Todo, error) {
+	return w.svc.ListTrash(ctx, userID)
+}
+
+func (w *todoServiceWrapper) Restore(ctx context.Context, id, userID uint) error {
+	return w.svc.Restore(ctx, id, userID)
+}
+
+func (w *todoServiceWrapper) Purge(ctx context.Context, id, userID uint) error {
+	return w.svc.Purge(ctx, id, userID)
+}
+
+func (w *todoServiceWrapper) PurgeExpired(ctx context.Context, before time.Time) (int, error) {
+	return w.svc.PurgeExpired(ctx, before)
+}
+
+func (w *todoServiceWrapper) History(ctx context.Context, id, userID uint) ([]*models.ChangeLog, error) {
+	return w.svc.History(ctx, id, userID)
+}
+
+func (w *todoServiceWrapper) Revert(ctx context.Context, id, userID, changeID uint) error {
+	return w.svc.Revert(ctx, id, userID, changeID)
+}
+
 // CategoryService wrapper implementations
 func (w *categoryServiceWrapper) Create(ctx context.Context, userID uint, req *category.CreateRequest) (uint, error) {
 	return w.svc.Create(ctx, userID, req)
@@ -87,6 +286,14 @@ func (w *categoryServiceWrapper) List(ctx context.Context, userID uint) ([]*mode
 	return w.svc.List(ctx, userID)
 }
 
+func (w *categoryServiceWrapper) Tree(ctx context.Context, userID uint) ([]*models.Category, error) {
+	return w.svc.Tree(ctx, userID)
+}
+
+func (w *categoryServiceWrapper) Move(ctx context.Context, id, userID uint, parentID *uint) error {
+	return w.svc.Move(ctx, userID, id, parentID)
+}
+
 func (w *categoryServiceWrapper) Get(ctx context.Context, id, userID uint) (*models.Category, error) {
 	return w.svc.Get(ctx, id, userID)
 }
@@ -95,8 +302,8 @@ func (w *categoryServiceWrapper) Update(ctx context.Context, id, userID uint, re
 	return w.svc.Update(ctx, userID, id, req)
 }
 
-func (w *categoryServiceWrapper) Delete(ctx context.Context, id, userID uint) error {
-	return w.svc.Delete(ctx, userID, id)
+func (w *categoryServiceWrapper) Delete(ctx context.Context, id, userID uint, req *category.DeleteRequest) (int, error) {
+	return w.svc.Delete(ctx, userID, id, req)
 }
 
 // ReminderService wrapper implementations
@@ -119,3 +326,7 @@ func (w *reminderServiceWrapper) Update(ctx context.Context, id, userID uint, re
 func (w *reminderServiceWrapper) Delete(ctx context.Context, id, userID uint) error {
 	return w.svc.Delete(ctx, id, userID)
 }
+
+func (w *reminderServiceWrapper) FireDue(ctx context.Context, now time.Time) (int, error) {
+	return w.svc.FireDue(ctx, now)
+}
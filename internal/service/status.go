@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"todo/api/v1/dto/status"
+	"todo/internal/models"
+)
+
+// StatusService 看板工作流状态服务接口
+type StatusService interface {
+	// Create 创建工作流状态，新状态排在看板最右侧
+	Create(ctx context.Context, userID uint, req *status.CreateRequest) (*models.Status, error)
+
+	// List 获取用户的工作流状态，按看板顺序排列
+	List(ctx context.Context, userID uint) ([]*models.Status, error)
+
+	// Update 更新工作流状态，修改是否为终止状态时同步其中待办事项的完成状态
+	Update(ctx context.Context, id, userID uint, req *status.UpdateRequest) (*models.Status, error)
+
+	// Reorder 调整看板列顺序
+	Reorder(ctx context.Context, userID uint, req *status.ReorderRequest) ([]*models.Status, error)
+
+	// Delete 删除工作流状态，按 req 处理其中的待办事项，返回受影响的待办事项数量
+	Delete(ctx context.Context, id, userID uint, req *status.DeleteRequest) (int, error)
+
+	// Board 获取按工作流状态分列的看板
+	Board(ctx context.Context, userID uint, req *status.BoardRequest) (*status.BoardResponse, error)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"todo/api/v1/dto/template"
+	"todo/internal/models"
+)
+
+// TemplateService 待办事项模板服务接口
+type TemplateService interface {
+	// Create 创建模板
+	Create(ctx context.Context, userID uint, req *template.CreateRequest) (*models.Template, error)
+
+	// List 获取用户的模板，按名称排列
+	List(ctx context.Context, userID uint) ([]*models.Template, error)
+
+	// Get 获取模板详情
+	Get(ctx context.Context, id, userID uint) (*models.Template, error)
+
+	// Update 更新模板
+	Update(ctx context.Context, id, userID uint, req *template.UpdateRequest) (*models.Template, error)
+
+	// Delete 删除模板，已经创建的待办事项不受影响
+	Delete(ctx context.Context, id, userID uint) error
+
+	// FromTodo 将待办事项及其阻塞项保存为模板
+	FromTodo(ctx context.Context, userID, todoID uint, req *template.FromTodoRequest) (*models.Template, error)
+
+	// Instantiate 按基准时间在一个事务中创建模板中的所有待办事项
+	Instantiate(ctx context.Context, id, userID uint, req *template.InstantiateRequest) ([]uint, error)
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"todo/api/v1/dto/timeentry"
+	"todo/internal/models"
+)
+
+// TimeEntryService 时间记录服务接口
+type TimeEntryService interface {
+	// Start 为待办事项启动计时器，用户正在运行的其他计时器会先被停止
+	Start(ctx context.Context, userID, todoID uint, req *timeentry.StartRequest) (*models.TimeEntry, error)
+
+	// Stop 停止待办事项正在运行的计时器
+	Stop(ctx context.Context, userID, todoID uint) (*models.TimeEntry, error)
+
+	// Running 获取用户正在运行的计时器，没有时返回 nil
+	Running(ctx context.Context, userID uint) (*models.TimeEntry, error)
+
+	// Add 手动录入一段时间
+	Add(ctx context.Context, userID, todoID uint, req *timeentry.CreateRequest) (*models.TimeEntry, error)
+
+	// List 获取待办事项的时间记录及总时长
+	List(ctx context.Context, userID, todoID uint) (*timeentry.ListResponse, error)
+
+	// Delete 删除待办事项的一条时间记录
+	Delete(ctx context.Context, userID, todoID, entryID uint) error
+
+	// Report 按分类汇总用户在日期范围内记录的时间
+	Report(ctx context.Context, userID uint, req *timeentry.ReportRequest) (*timeentry.ReportResponse, error)
+
+	// CleanupTodo 删除待办事项的所有时间记录
+	CleanupTodo(ctx context.Context, todoID uint) error
+}
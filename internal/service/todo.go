@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 	"todo/api/v1/dto/todo"
 	"todo/internal/models"
 )
@@ -11,8 +13,11 @@ type TodoService interface {
 	// Create 创建待办事项
 	Create(ctx context.Context, userID uint, req *todo.CreateRequest) (uint, error)
 
-	// List 获取用户的待办事项列表
-	List(ctx context.Context, userID uint) ([]*models.Todo, error)
+	// QuickAdd 解析一行自然语言文本并创建待办事项及其提醒，试运行时只返回解析结果
+	QuickAdd(ctx context.Context, userID uint, req *todo.QuickAddRequest) (*todo.QuickAddResponse, error)
+
+	// List 获取用户的待办事项列表，默认不包含已归档的待办事项
+	List(ctx context.Context, userID uint, req *todo.ListRequest) ([]*models.Todo, error)
 
 	// Get 获取单个待办事项详情
 	Get(ctx context.Context, id, userID uint) (*models.Todo, error)
@@ -20,6 +25,60 @@ type TodoService interface {
 	// Update 更新待办事项
 	Update(ctx context.Context, id, userID uint, req *todo.UpdateRequest) error
 
-	// Delete 删除待办事项
+	// Bulk 对一组待办事项执行同一操作，全部在同一事务中处理，任一失败时整体回滚
+	Bulk(ctx context.Context, userID uint, req *todo.BulkRequest) (*todo.BulkResponse, error)
+
+	// Move 将待办事项拖动到同一分类中另一个待办事项之前或之后
+	Move(ctx context.Context, id, userID uint, req *todo.MoveRequest) error
+
+	// ExportCSV 将匹配列表查询参数的所有待办事项以 CSV 写入 w，查询参数无效时在写入之前返回错误
+	ExportCSV(ctx context.Context, userID uint, req *todo.ListRequest, w io.Writer) error
+
+	// ImportCSV 逐行读取 CSV 并创建待办事项，校验失败的行被跳过并记录在结果中
+	ImportCSV(ctx context.Context, userID uint, r io.Reader, req *todo.ImportRequest) (*todo.ImportResponse, error)
+
+	// ExportTodoTxt 将匹配列表查询参数的所有待办事项以 todo.txt 格式写入 w
+	ExportTodoTxt(ctx context.Context, userID uint, req *todo.ListRequest, w io.Writer) error
+
+	// ImportTodoTxt 逐行读取 todo.txt 并创建待办事项，校验失败的行被跳过并记录在结果中
+	ImportTodoTxt(ctx context.Context, userID uint, r io.Reader, req *todo.ImportRequest) (*todo.ImportResponse, error)
+
+	// ExportMarkdown 将匹配列表查询参数的所有待办事项以按分类分组的 Markdown 任务列表写入 w
+	ExportMarkdown(ctx context.Context, userID uint, req *todo.ListRequest, w io.Writer) error
+
+	// ImportMarkdown 读取 Markdown 任务列表并创建待办事项，校验失败的任务被跳过并记录在结果中
+	ImportMarkdown(ctx context.Context, userID uint, r io.Reader, req *todo.ImportRequest) (*todo.ImportResponse, error)
+
+	// Duplicate 复制待办事项及其提醒、分类和标签，返回新建的副本
+	Duplicate(ctx context.Context, id, userID uint, req *todo.DuplicateRequest) (*models.Todo, error)
+
+	// Archive 归档待办事项
+	Archive(ctx context.Context, id, userID uint) error
+
+	// Unarchive 取消归档
+	Unarchive(ctx context.Context, id, userID uint) error
+
+	// ArchiveCompleted 归档所有完成时间早于 before 的待办事项，返回归档数量
+	ArchiveCompleted(ctx context.Context, before time.Time) (int, error)
+
+	// Delete 删除待办事项（移入回收站）
 	Delete(ctx context.Context, id, userID uint) error
+
+	// ListTrash 获取回收站中的待办事项
+	ListTrash(ctx context.Context, userID uint) ([]*models.Todo, error)
+
+	// Restore 从回收站恢复待办事项及其提醒
+	Restore(ctx context.Context, id, userID uint) error
+
+	// Purge 永久删除待办事项及其关联数据
+	Purge(ctx context.Context, id, userID uint) error
+
+	// PurgeExpired 永久删除所有删除时间早于 before 的待办事项，返回清理数量
+	PurgeExpired(ctx context.Context, before time.Time) (int, error)
+
+	// History 获取待办事项的变更历史
+	History(ctx context.Context, id, userID uint) ([]*models.ChangeLog, error)
+
+	// Revert 将待办事项回滚到指定变更记录对应的版本
+	Revert(ctx context.Context, id, userID, changeID uint) error
 }
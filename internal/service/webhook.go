@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"todo/api/v1/dto/webhook"
+	"todo/internal/models"
+)
+
+// WebhookService webhook 端点管理与事件投递服务接口
+type WebhookService interface {
+	// Create 注册 webhook 端点，返回只出现这一次的签名密钥
+	Create(ctx context.Context, userID uint, req *webhook.CreateRequest) (*webhook.CreateResponse, error)
+
+	// List 获取用户在当前工作空间中的所有 webhook 端点
+	List(ctx context.Context, userID uint) ([]*models.Webhook, error)
+
+	// Update 修改 webhook 端点的地址、订阅的事件或启用状态
+	Update(ctx context.Context, id, userID uint, req *webhook.UpdateRequest) (*models.Webhook, error)
+
+	// Delete 删除 webhook 端点及其投递日志
+	Delete(ctx context.Context, id, userID uint) error
+
+	// Deliveries 获取 webhook 端点最近的投递日志
+	Deliveries(ctx context.Context, id, userID uint) ([]*models.WebhookDelivery, error)
+
+	// Ping 向 webhook 端点发送一次测试事件
+	Ping(ctx context.Context, id, userID uint) error
+
+	// Publish 将事件投递给用户订阅了该事件的所有已启用端点，投递在后台任务队列中进行
+	Publish(ctx context.Context, userID uint, event string, data json.RawMessage)
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"todo/api/v1/dto/workspace"
+	"todo/internal/models"
+)
+
+// WorkspaceService 工作空间服务接口
+type WorkspaceService interface {
+	// Create 创建工作空间，创建者成为所有者
+	Create(ctx context.Context, userID uint, req *workspace.CreateRequest) (*models.Workspace, error)
+
+	// List 获取用户加入的工作空间列表
+	List(ctx context.Context, userID uint) ([]*models.Workspace, error)
+
+	// Switch 切换到指定工作空间，返回携带新工作空间的JWT令牌
+	Switch(ctx context.Context, userID, workspaceID uint) (string, error)
+
+	// Invite 邀请成员加入工作空间
+	Invite(ctx context.Context, userID, workspaceID uint, req *workspace.InviteRequest) (*models.WorkspaceInvite, error)
+
+	// AcceptInvite 接受邀请并加入工作空间
+	AcceptInvite(ctx context.Context, userID uint, token string) (*models.Workspace, error)
+
+	// ListMembers 获取工作空间成员列表
+	ListMembers(ctx context.Context, userID, workspaceID uint) ([]*models.WorkspaceMember, error)
+
+	// RemoveMember 移除工作空间成员
+	RemoveMember(ctx context.Context, userID, workspaceID, memberID uint) error
+
+	// CheckMember 校验用户是否仍是工作空间成员，由认证中间件在每次请求时调用
+	CheckMember(ctx context.Context, workspaceID, userID uint) error
+}
@@ -0,0 +1,29 @@
+// Package tenant 在请求上下文中传递当前工作空间（租户）信息
+package tenant
+
+import "context"
+
+// workspaceKey 上下文中存放工作空间ID的键
+type workspaceKey struct{}
+
+// WithWorkspaceID 返回携带工作空间ID的新上下文
+//
+// Parameters:
+//   - ctx: 父上下文
+//   - workspaceID: 当前请求所属的工作空间ID
+//
+// Returns:
+//   - context.Context: 携带工作空间ID的上下文
+func WithWorkspaceID(ctx context.Context, workspaceID uint) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, workspaceID)
+}
+
+// WorkspaceIDFromContext 从上下文中读取工作空间ID
+//
+// Returns:
+//   - uint: 工作空间ID
+//   - bool: 上下文中是否存在有效的工作空间ID
+func WorkspaceIDFromContext(ctx context.Context) (uint, bool) {
+	workspaceID, ok := ctx.Value(workspaceKey{}).(uint)
+	return workspaceID, ok && workspaceID != 0
+}
@@ -52,6 +52,54 @@ type JWTConfig struct {
 	Issuer      string `mapstructure:"issuer"`       // JWT签发者
 }
 
+// S3Config S3 兼容对象存储配置
+type S3Config struct {
+	Endpoint  string `mapstructure:"endpoint"`   // 服务地址，例如 http://localhost:9000
+	Region    string `mapstructure:"region"`     // 区域，MinIO 可使用默认的 us-east-1
+	Bucket    string `mapstructure:"bucket"`     // 存储桶名称
+	AccessKey string `mapstructure:"access_key"` // 访问密钥ID
+	SecretKey string `mapstructure:"secret_key"` // 访问密钥
+}
+
+// StorageConfig 附件存储配置
+type StorageConfig struct {
+	Driver   string   `mapstructure:"driver"`    // 存储驱动（local/s3）
+	LocalDir string   `mapstructure:"local_dir"` // 本地存储根目录
+	S3       S3Config `mapstructure:"s3"`        // S3 兼容存储配置
+}
+
+// AttachmentConfig 附件上传限制配置
+type AttachmentConfig struct {
+	MaxSize      int64    `mapstructure:"max_size"`      // 单个附件的最大字节数
+	AllowedTypes []string `mapstructure:"allowed_types"` // 允许的 MIME 类型，以 "/" 结尾表示前缀匹配（如 image/）
+}
+
+// TrashConfig 回收站配置
+type TrashConfig struct {
+	Retention     time.Duration `mapstructure:"retention"`      // 回收站保留时长，超过后永久删除
+	PurgeInterval time.Duration `mapstructure:"purge_interval"` // 后台清理任务的执行间隔
+}
+
+// ArchiveConfig 自动归档配置
+type ArchiveConfig struct {
+	AutoAfter time.Duration `mapstructure:"auto_after"` // 完成多久之后自动归档，0 表示不自动归档
+	Interval  time.Duration `mapstructure:"interval"`   // 后台归档任务的执行间隔
+}
+
+// ReminderConfig 提醒配置
+type ReminderConfig struct {
+	Interval time.Duration `mapstructure:"interval"` // 后台检查到期提醒的间隔
+}
+
+// WebhookConfig webhook 投递配置
+type WebhookConfig struct {
+	Timeout      time.Duration `mapstructure:"timeout"`       // 单次投递请求的超时时间
+	MaxAttempts  int           `mapstructure:"max_attempts"`  // 每个事件最多尝试投递的次数（包括第一次），等待中的重试在服务重启后丢失
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"` // 第一次重试前的等待时间，之后每次翻倍
+	DisableAfter int           `mapstructure:"disable_after"` // 连续多少个事件投递失败（重试耗尽）后自动停用端点
+	AllowPrivate bool          `mapstructure:"allow_private"` // 是否允许投递到回环、内网等非公网地址，仅用于本地开发
+}
+
 // Config 应用配置
 // 配置加载优先级（从高到低）：
 // 1. 环境变量（例如：DB_HOST, REDIS_PORT）
@@ -64,6 +112,12 @@ type Config struct {
 	Redis     RedisConfig  `mapstructure:"redis"`
 	Logger    LoggerConfig `mapstructure:"logger"`
 	JWT       JWTConfig    `mapstructure:"jwt"`
+	Storage    StorageConfig    `mapstructure:"storage"`
+	Attachment AttachmentConfig `mapstructure:"attachment"`
+	Trash      TrashConfig      `mapstructure:"trash"`
+	Archive    ArchiveConfig    `mapstructure:"archive"`
+	Reminder   ReminderConfig   `mapstructure:"reminder"`
+	Webhook    WebhookConfig    `mapstructure:"webhook"`
 	RateLimit struct {
 		RequestsPerSecond float64 `mapstructure:"requests_per_second"` // 每秒请求限制
 		Burst             int     `mapstructure:"burst"`               // 突发请求限制
@@ -135,6 +189,29 @@ func setDefaults() {
 
 	viper.SetDefault("jwt.expire_hours", 1)
 	viper.SetDefault("jwt.issuer", "todo_app")
+
+	viper.SetDefault("storage.driver", "local")
+	viper.SetDefault("storage.local_dir", "data/attachments")
+
+	viper.SetDefault("attachment.max_size", 10<<20)
+	viper.SetDefault("attachment.allowed_types", []string{"image/", "text/plain", "application/pdf", "application/zip"})
+
+	viper.SetDefault("trash.retention", "720h")
+	viper.SetDefault("trash.purge_interval", "1h")
+
+	viper.SetDefault("archive.auto_after", "168h")
+	viper.SetDefault("archive.interval", "1h")
+
+	viper.SetDefault("task_queue.buffer_size", 100)
+	viper.SetDefault("task_queue.workers", 2)
+
+	viper.SetDefault("reminder.interval", "1m")
+
+	viper.SetDefault("webhook.timeout", "10s")
+	viper.SetDefault("webhook.max_attempts", 5)
+	viper.SetDefault("webhook.retry_backoff", "30s")
+	viper.SetDefault("webhook.disable_after", 5)
+	viper.SetDefault("webhook.allow_private", false)
 }
 
 // processEnvVars 处理环境变量替换
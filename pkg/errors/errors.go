@@ -14,6 +14,64 @@ var (
 	ErrTodoNotFound     = errors.New("待办事项不存在")
 	ErrCategoryNotFound = errors.New("分类不存在")
 	ErrReminderNotFound = errors.New("提醒不存在")
+	ErrCommentNotFound  = errors.New("评论不存在")
+	ErrCategoryCycle    = errors.New("不能将分类移动到自身或其下级分类之下")
+	ErrBulkFailed       = errors.New("部分待办事项处理失败，批量操作已全部回滚")
+	ErrBulkTooMany      = errors.New("匹配的待办事项超过单次批量操作的上限")
+	ErrInvalidCSV       = errors.New("无效的 CSV：缺少表头、没有 title 列或列映射无效")
+	ErrTooManyTags      = errors.New("待办事项的标签数量超过上限")
+
+	// 依赖关系相关错误
+	ErrDependencyNotFound = errors.New("依赖关系不存在")
+	ErrDependencyExists   = errors.New("依赖关系已存在")
+	ErrDependencyCycle    = errors.New("依赖关系不能形成环")
+	ErrTodoBlocked        = errors.New("待办事项被未完成的待办事项阻塞，不能标记为已完成")
+
+	// 时间记录相关错误
+	ErrTimeEntryNotFound = errors.New("时间记录不存在")
+	ErrTimerNotRunning   = errors.New("该待办事项没有正在运行的计时器")
+
+	// 模板相关错误
+	ErrTemplateNotFound = errors.New("模板不存在")
+	ErrTemplateTooLarge = errors.New("模板中的待办事项超过上限")
+
+	// 日历订阅与同步相关错误
+	ErrCalendarFeedNotFound = errors.New("日历订阅不存在")
+	ErrInvalidICalendar     = errors.New("无效的 iCalendar 数据：缺少待办事项、格式错误或字段超出限制")
+	ErrPreconditionFailed   = errors.New("资源已被修改，请重新获取后再试")
+
+	// 数据导出与导入相关错误
+	ErrDataExportNotFound = errors.New("数据导出不存在")
+	ErrDataExportNotReady = errors.New("数据导出尚未完成")
+	ErrInvalidArchive     = errors.New("无效的数据归档：格式错误、版本不受支持、字段超出限制或引用了不存在的数据")
+	ErrAccountNotEmpty    = errors.New("当前账户已有分类或待办事项，只能导入到空账户")
+
+	// webhook 相关错误
+	ErrWebhookNotFound = errors.New("webhook 不存在")
+	ErrInvalidWebhook  = errors.New("无效的 webhook：地址必须是 http 或 https，事件类型必须是支持的类型")
+
+	// 过滤条件相关错误
+	ErrFilterNotFound = errors.New("过滤条件不存在")
+
+	// 工作流状态相关错误
+	ErrStatusNotFound = errors.New("工作流状态不存在")
+	ErrStatusExists   = errors.New("同名的工作流状态已存在")
+	ErrWIPLimit       = errors.New("该状态的待办事项数量已达到在制品上限")
+
+	// 变更历史相关错误
+	ErrChangeNotFound = errors.New("变更记录不存在")
+
+	// 附件相关错误
+	ErrAttachmentNotFound = errors.New("附件不存在")
+	ErrAttachmentTooLarge = errors.New("附件大小超出限制")
+	ErrAttachmentType     = errors.New("不支持的附件类型")
+
+	// 工作空间相关错误
+	ErrWorkspaceRequired  = errors.New("缺少工作空间上下文")
+	ErrWorkspaceNotFound  = errors.New("工作空间不存在")
+	ErrNotWorkspaceMember = errors.New("不是该工作空间的成员")
+	ErrInviteNotFound     = errors.New("邀请不存在")
+	ErrInviteExpired      = errors.New("邀请已过期或已被使用")
 
 	// 权限相关错误
 	ErrUnauthorized = errors.New("未经授权的访问")
@@ -0,0 +1,175 @@
+// Package ical 生成和解析 iCalendar（RFC 5545）格式的日历数据
+//
+// 只支持同步待办事项需要的部分：VCALENDAR 中的 VTODO 组件及其 VALARM 提醒。
+// 输出的每一行以 CRLF 结尾，超过 75 个字节的内容行按规范折行（不会拆开多字节字符），
+// 文本值中的反斜杠、分号、逗号和换行会被转义，所有时间都以 UTC 输出。
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 待办事项状态取值
+const (
+	StatusNeedsAction = "NEEDS-ACTION" // 未完成
+	StatusCompleted   = "COMPLETED"    // 已完成
+)
+
+// maxLineOctets 内容行的最大字节数（不含 CRLF），超过时需要折行
+const maxLineOctets = 75
+
+// dateTimeLayout UTC 时间的格式
+const dateTimeLayout = "20060102T150405Z"
+
+// Alarm VALARM 组件，在指定时间以显示消息的方式提醒
+type Alarm struct {
+	Trigger     time.Time // 触发时间
+	Description string    // 提醒内容
+}
+
+// Todo VTODO 组件
+type Todo struct {
+	UID          string     // 全局唯一标识，同一待办事项每次导出必须相同
+	Stamp        time.Time  // DTSTAMP，应为最后修改时间，使未变化的内容每次输出相同
+	Created      time.Time  // 创建时间
+	LastModified time.Time  // 最后修改时间
+	Summary      string     // 标题
+	Description  string     // 描述，为空时不输出
+	Start        *time.Time // 开始时间，为空时不输出
+	Due          *time.Time // 截止时间，为空时不输出
+	Status       string     // 状态：StatusNeedsAction 或 StatusCompleted
+	Completed    *time.Time // 完成时间，为空时不输出
+	Priority     int        // 优先级：1（最高）到 9（最低），0 表示未定义
+	Categories   []string   // 分类和标签
+	Alarms       []Alarm    // 提醒
+}
+
+// Writer 以流的形式写出日历：先写日历头，再逐个写入待办事项，最后 Close 写入日历尾
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewWriter 创建日历写入器并写出日历头
+//
+// Parameters:
+//   - w: 输出目标
+//   - prodID: 生成日历的产品标识（PRODID）
+//   - name: 日历名称，日历客户端订阅时作为默认显示名称，为空时不输出
+func NewWriter(w io.Writer, prodID, name string) *Writer {
+	cw := &Writer{w: bufio.NewWriter(w)}
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", prodID)
+	cw.line("CALSCALE", "GREGORIAN")
+	if name != "" {
+		cw.line("X-WR-CALNAME", escape(name))
+	}
+	return cw
+}
+
+// WriteTodo 写出一个 VTODO 组件
+// 返回之前发生的第一个写入错误
+func (cw *Writer) WriteTodo(t *Todo) error {
+	cw.line("BEGIN", "VTODO")
+	cw.line("UID", escape(t.UID))
+	cw.line("DTSTAMP", formatTime(t.Stamp))
+	cw.line("CREATED", formatTime(t.Created))
+	cw.line("LAST-MODIFIED", formatTime(t.LastModified))
+	cw.line("SUMMARY", escape(t.Summary))
+	if t.Description != "" {
+		cw.line("DESCRIPTION", escape(t.Description))
+	}
+	if t.Start != nil {
+		cw.line("DTSTART", formatTime(*t.Start))
+	}
+	if t.Due != nil {
+		cw.line("DUE", formatTime(*t.Due))
+	}
+	if t.Status != "" {
+		cw.line("STATUS", t.Status)
+	}
+	if t.Completed != nil {
+		cw.line("COMPLETED", formatTime(*t.Completed))
+		cw.line("PERCENT-COMPLETE", "100")
+	}
+	if t.Priority > 0 {
+		cw.line("PRIORITY", strconv.Itoa(t.Priority))
+	}
+	if len(t.Categories) > 0 {
+		values := make([]string, len(t.Categories))
+		for i, c := range t.Categories {
+			values[i] = escape(c)
+		}
+		cw.line("CATEGORIES", strings.Join(values, ","))
+	}
+	for _, a := range t.Alarms {
+		cw.line("BEGIN", "VALARM")
+		cw.line("ACTION", "DISPLAY")
+		cw.line("TRIGGER;VALUE=DATE-TIME", formatTime(a.Trigger))
+		cw.line("DESCRIPTION", escape(a.Description))
+		cw.line("END", "VALARM")
+	}
+	cw.line("END", "VTODO")
+	return cw.err
+}
+
+// Flush 把缓冲的内容写入底层输出
+func (cw *Writer) Flush() error {
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.err
+}
+
+// Close 写出日历尾并刷新缓冲，不会关闭底层输出
+func (cw *Writer) Close() error {
+	cw.line("END", "VCALENDAR")
+	return cw.Flush()
+}
+
+// line 写出一个内容行，超过 maxLineOctets 时折行，续行以一个空格开头
+func (cw *Writer) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+	s := name + ":" + value
+	limit := maxLineOctets
+	for len(s) > limit {
+		// 在不超过上限的最后一个字符边界处折行
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		cw.write(s[:cut])
+		cw.write("\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1 // 续行开头的空格占一个字节
+	}
+	cw.write(s)
+	cw.write("\r\n")
+}
+
+func (cw *Writer) write(s string) {
+	if cw.err == nil {
+		_, cw.err = cw.w.WriteString(s)
+	}
+}
+
+// textEscaper 按 RFC 5545 3.3.11 转义 TEXT 值
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escape 转义文本值
+func escape(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// formatTime 以 UTC 格式化时间
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
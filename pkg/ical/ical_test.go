@@ -0,0 +1,202 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// TestWriter 测试写出包含提醒的待办事项
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "-//todo//test//ZH", "我的待办")
+
+	created := time.Date(2024, 1, 2, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	due := created.Add(48 * time.Hour)
+	done := created.Add(time.Hour)
+	err := w.WriteTodo(&Todo{
+		UID:          "todo-1@todo",
+		Stamp:        created,
+		Created:      created,
+		LastModified: created,
+		Summary:      "买菜; 做饭, 洗碗",
+		Description:  "第一行\n第二行",
+		Due:          &due,
+		Status:       StatusCompleted,
+		Completed:    &done,
+		Priority:     1,
+		Categories:   []string{"家务", "a,b"},
+		Alarms:       []Alarm{{Trigger: due.Add(-time.Hour), Description: "买菜"}},
+	})
+	if err != nil {
+		t.Fatalf("WriteTodo() 错误 = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() 错误 = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:我的待办\r\n",
+		"UID:todo-1@todo\r\n",
+		"DTSTAMP:20240102T000000Z\r\n",
+		`SUMMARY:买菜\; 做饭\, 洗碗` + "\r\n",
+		`DESCRIPTION:第一行\n第二行` + "\r\n",
+		"DUE:20240104T000000Z\r\n",
+		"STATUS:COMPLETED\r\nCOMPLETED:20240102T010000Z\r\nPERCENT-COMPLETE:100\r\n",
+		"PRIORITY:1\r\n",
+		`CATEGORIES:家务,a\,b` + "\r\n",
+		"BEGIN:VALARM\r\nACTION:DISPLAY\r\nTRIGGER;VALUE=DATE-TIME:20240103T230000Z\r\n",
+		"END:VTODO\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("输出中缺少 %q\n%s", want, out)
+		}
+	}
+}
+
+// TestWriter_Fold 测试长内容行按字节折行且不拆开多字节字符
+func TestWriter_Fold(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "-//todo//test//ZH", "")
+	summary := strings.Repeat("待办", 40)
+	if err := w.WriteTodo(&Todo{UID: "x", Summary: summary}); err != nil {
+		t.Fatalf("WriteTodo() 错误 = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() 错误 = %v", err)
+	}
+
+	var unfolded []string
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("内容行超过 %d 字节: %q", maxLineOctets, line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("折行拆开了多字节字符: %q", line)
+		}
+		if strings.HasPrefix(line, " ") {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		unfolded = append(unfolded, line)
+	}
+
+	found := false
+	for _, line := range unfolded {
+		if line == "SUMMARY:"+summary {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("展开折行后没有找到原始标题:\n%s", buf.String())
+	}
+}
+
+// TestParseTodo 测试解析客户端上传的待办事项
+func TestParseTodo(t *testing.T) {
+	input := "BEGIN:VCALENDAR\n" +
+		"VERSION:2.0\n" +
+		"PRODID:-//Mozilla.org/NONSGML Mozilla Calendar V1.1//EN\n" +
+		"BEGIN:VTIMEZONE\nTZID:Asia/Shanghai\nEND:VTIMEZONE\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:3f5b2a\r\n" +
+		"SUMMARY:买菜\\; 做饭\\, 洗\r\n" +
+		" 碗\r\n" +
+		"DESCRIPTION:第一行\\n第二行\r\n" +
+		"DUE;TZID=Asia/Shanghai:20240607T180000\r\n" +
+		"DTSTART;VALUE=DATE:20240601\r\n" +
+		"PRIORITY:2\r\n" +
+		"CATEGORIES:家务,a\\,b\r\n" +
+		"COMPLETED:20240606T010203Z\r\n" +
+		"X-APPLE-SORT-ORDER:1\r\n" +
+		"BEGIN:VALARM\r\nACTION:DISPLAY\r\nTRIGGER;RELATED=END:-PT1H30M\r\nEND:VALARM\r\n" +
+		"BEGIN:VALARM\r\nACTION:DISPLAY\r\nTRIGGER;VALUE=DATE-TIME:20240605T000000Z\r\nEND:VALARM\r\n" +
+		"BEGIN:VALARM\r\nACTION:DISPLAY\r\nTRIGGER:P1D\r\nEND:VALARM\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	todo, err := ParseTodo(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseTodo() 错误 = %v", err)
+	}
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	due := time.Date(2024, 6, 7, 18, 0, 0, 0, shanghai)
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)
+	if todo.UID != "3f5b2a" || todo.Summary != "买菜; 做饭, 洗碗" || todo.Description != "第一行\n第二行" {
+		t.Errorf("文本属性 = %q %q %q", todo.UID, todo.Summary, todo.Description)
+	}
+	if todo.Due == nil || !todo.Due.Equal(due) || todo.Start == nil || !todo.Start.Equal(start) {
+		t.Errorf("Due = %v, Start = %v, 期望 %v, %v", todo.Due, todo.Start, due, start)
+	}
+	if todo.Status != StatusCompleted || todo.Completed == nil || todo.Priority != 2 {
+		t.Errorf("Status = %q, Completed = %v, Priority = %d", todo.Status, todo.Completed, todo.Priority)
+	}
+	if len(todo.Categories) != 2 || todo.Categories[0] != "家务" || todo.Categories[1] != "a,b" {
+		t.Errorf("Categories = %q", todo.Categories)
+	}
+	wantAlarms := []time.Time{due.Add(-90 * time.Minute), time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC), start.Add(24 * time.Hour)}
+	if len(todo.Alarms) != len(wantAlarms) {
+		t.Fatalf("Alarms = %v, 期望 %d 个", todo.Alarms, len(wantAlarms))
+	}
+	for i, want := range wantAlarms {
+		if !todo.Alarms[i].Trigger.Equal(want) {
+			t.Errorf("第 %d 个提醒 = %v, 期望 %v", i, todo.Alarms[i].Trigger, want)
+		}
+	}
+}
+
+// TestParseTodo_RoundTrip 测试写出的内容可以被原样解析
+func TestParseTodo_RoundTrip(t *testing.T) {
+	due := time.Date(2024, 6, 7, 10, 0, 0, 0, time.UTC)
+	want := &Todo{
+		UID:        "todo-1@todo",
+		Summary:    strings.Repeat("长标题;", 20),
+		Due:        &due,
+		Status:     StatusNeedsAction,
+		Priority:   5,
+		Categories: []string{"工作", "x,y"},
+		Alarms:     []Alarm{{Trigger: due.Add(-time.Hour), Description: "提醒"}},
+	}
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "-//todo//test//ZH", "")
+	w.WriteTodo(want)
+	w.Close()
+
+	got, err := ParseTodo(&buf)
+	if err != nil {
+		t.Fatalf("ParseTodo() 错误 = %v", err)
+	}
+	if got.UID != want.UID || got.Summary != want.Summary || !got.Due.Equal(due) || got.Status != want.Status ||
+		got.Priority != want.Priority || strings.Join(got.Categories, "|") != "工作|x,y" ||
+		len(got.Alarms) != 1 || !got.Alarms[0].Trigger.Equal(want.Alarms[0].Trigger) {
+		t.Errorf("ParseTodo() = %+v, 期望 %+v", got, want)
+	}
+}
+
+// TestParseTodo_Invalid 测试无效的输入
+func TestParseTodo_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"空内容", "", ErrInvalid},
+		{"没有 VCALENDAR", "BEGIN:VTODO\nEND:VTODO\n", ErrInvalid},
+		{"未闭合", "BEGIN:VCALENDAR\nBEGIN:VTODO\n", ErrInvalid},
+		{"组件不匹配", "BEGIN:VCALENDAR\nBEGIN:VTODO\nEND:VEVENT\nEND:VCALENDAR\n", ErrInvalid},
+		{"缺少冒号", "BEGIN:VCALENDAR\nBEGIN:VTODO\nSUMMARY\nEND:VTODO\nEND:VCALENDAR\n", ErrInvalid},
+		{"无效的时间", "BEGIN:VCALENDAR\nBEGIN:VTODO\nDUE:tomorrow\nEND:VTODO\nEND:VCALENDAR\n", ErrInvalid},
+		{"只有事件", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VEVENT\nEND:VCALENDAR\n", ErrNoTodo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseTodo(strings.NewReader(tt.input)); err != tt.want {
+				t.Errorf("ParseTodo() 错误 = %v, 期望 %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,410 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalid 日历数据无法解析
+var ErrInvalid = errors.New("ical: 无效的日历数据")
+
+// ErrNoTodo 日历中没有 VTODO 组件
+var ErrNoTodo = errors.New("ical: 日历中没有待办事项")
+
+// property 解析后的内容行
+type property struct {
+	name   string            // 属性名，统一为大写
+	params map[string]string // 参数，参数名统一为大写
+	value  string            // 原始值，文本值尚未反转义
+}
+
+// component 解析后的组件，例如 VCALENDAR、VTODO、VALARM
+type component struct {
+	name       string
+	props      []property
+	components []*component
+}
+
+// get 返回第一个指定名称的属性
+func (c *component) get(name string) (property, bool) {
+	for _, p := range c.props {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return property{}, false
+}
+
+// ParseTodo 解析日历中的第一个 VTODO 组件
+// 没有时区的时间（浮动时间）和无法识别的 TZID 按本地时区解释，只有日期的值解释为当天零点；
+// 相对触发时间的提醒按截止时间或开始时间换算为绝对时间，无法换算的提醒被忽略。
+// 不支持的属性（例如 RRULE）被忽略
+//
+// Returns:
+//   - *Todo: 解析结果
+//   - error: 格式错误时返回 ErrInvalid，没有 VTODO 时返回 ErrNoTodo
+func ParseTodo(r io.Reader) (*Todo, error) {
+	root, err := parse(r)
+	if err != nil {
+		return nil, err
+	}
+	if root.name != "VCALENDAR" {
+		return nil, ErrInvalid
+	}
+	for _, c := range root.components {
+		if c.name == "VTODO" {
+			return todoFrom(c)
+		}
+	}
+	return nil, ErrNoTodo
+}
+
+// todoFrom 将 VTODO 组件转换为 Todo
+func todoFrom(c *component) (*Todo, error) {
+	t := &Todo{Status: StatusNeedsAction}
+	for _, p := range c.props {
+		var err error
+		switch p.name {
+		case "UID":
+			t.UID = unescape(p.value)
+		case "SUMMARY":
+			t.Summary = unescape(p.value)
+		case "DESCRIPTION":
+			t.Description = unescape(p.value)
+		case "STATUS":
+			t.Status = strings.ToUpper(p.value)
+		case "PRIORITY":
+			t.Priority, err = strconv.Atoi(p.value)
+		case "CATEGORIES":
+			for _, v := range splitList(p.value) {
+				if v = strings.TrimSpace(unescape(v)); v != "" {
+					t.Categories = append(t.Categories, v)
+				}
+			}
+		case "DTSTAMP":
+			t.Stamp, err = parseTime(p)
+		case "CREATED":
+			t.Created, err = parseTime(p)
+		case "LAST-MODIFIED":
+			t.LastModified, err = parseTime(p)
+		case "DTSTART":
+			t.Start, err = parseTimePtr(p)
+		case "DUE":
+			t.Due, err = parseTimePtr(p)
+		case "COMPLETED":
+			t.Completed, err = parseTimePtr(p)
+		}
+		if err != nil {
+			return nil, ErrInvalid
+		}
+	}
+	if t.Completed != nil {
+		t.Status = StatusCompleted
+	}
+
+	for _, sub := range c.components {
+		if sub.name != "VALARM" {
+			continue
+		}
+		alarm, ok, err := alarmFrom(sub, t)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			t.Alarms = append(t.Alarms, alarm)
+		}
+	}
+	return t, nil
+}
+
+// alarmFrom 将 VALARM 组件转换为绝对触发时间的 Alarm
+// 相对触发时间缺少对应的截止时间或开始时间时返回 ok 为 false
+func alarmFrom(c *component, t *Todo) (Alarm, bool, error) {
+	trigger, found := c.get("TRIGGER")
+	if !found {
+		return Alarm{}, false, nil
+	}
+	alarm := Alarm{Description: t.Summary}
+	if p, ok := c.get("DESCRIPTION"); ok {
+		alarm.Description = unescape(p.value)
+	}
+
+	if strings.EqualFold(trigger.params["VALUE"], "DATE-TIME") {
+		at, err := parseTime(trigger)
+		if err != nil {
+			return Alarm{}, false, ErrInvalid
+		}
+		alarm.Trigger = at
+		return alarm, true, nil
+	}
+
+	offset, err := parseDuration(trigger.value)
+	if err != nil {
+		return Alarm{}, false, ErrInvalid
+	}
+	base := t.Start
+	if strings.EqualFold(trigger.params["RELATED"], "END") || base == nil {
+		base = t.Due
+	}
+	if base == nil {
+		return Alarm{}, false, nil
+	}
+	alarm.Trigger = base.Add(offset)
+	return alarm, true, nil
+}
+
+// parse 读取全部内容行并构建组件树，返回最外层组件
+func parse(r io.Reader) (*component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *component
+	var stack []*component
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		p, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+		switch p.name {
+		case "BEGIN":
+			c := &component{name: strings.ToUpper(p.value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.components = append(parent.components, c)
+			} else if root == nil {
+				root = c
+			} else {
+				return nil, ErrInvalid
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].name != strings.ToUpper(p.value) {
+				return nil, ErrInvalid
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, ErrInvalid
+			}
+			c := stack[len(stack)-1]
+			c.props = append(c.props, p)
+		}
+	}
+	if root == nil || len(stack) > 0 {
+		return nil, ErrInvalid
+	}
+	return root, nil
+}
+
+// unfold 读取内容行并展开折行，兼容只使用 LF 换行的输入
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), 1<<20)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			return nil, ErrInvalid
+		}
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseLine 解析一个内容行：name *(";" param) ":" value
+// 参数值可以用双引号包含分号、冒号和逗号
+func parseLine(line string) (property, error) {
+	p := property{params: make(map[string]string)}
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return p, ErrInvalid
+	}
+	p.name = strings.ToUpper(line[:i])
+
+	for line[i] == ';' {
+		rest := line[i+1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return p, ErrInvalid
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+		var value string
+		var n int
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return p, ErrInvalid
+			}
+			value = rest[1 : end+1]
+			n = end + 2
+		} else {
+			n = strings.IndexAny(rest, ";:")
+			if n < 0 {
+				return p, ErrInvalid
+			}
+			value = rest[:n]
+		}
+		// 多值参数只保留第一个值
+		if j := strings.IndexByte(value, ','); j >= 0 && !strings.HasPrefix(rest, `"`) {
+			value = value[:j]
+		}
+		p.params[name] = value
+		i += 1 + eq + 1 + n
+		if i >= len(line) {
+			return p, ErrInvalid
+		}
+	}
+	if line[i] != ':' {
+		return p, ErrInvalid
+	}
+	p.value = line[i+1:]
+	return p, nil
+}
+
+// unescape 反转义文本值
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// splitList 按未转义的逗号拆分多值属性
+func splitList(s string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(values, s[start:])
+}
+
+// parseTime 解析 DATE-TIME 或 DATE 值
+func parseTime(p property) (time.Time, error) {
+	loc := time.Local
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	value := p.value
+	switch {
+	case strings.EqualFold(p.params["VALUE"], "DATE") || len(value) == len("20060102"):
+		return time.ParseInLocation("20060102", value, loc)
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse(dateTimeLayout, value)
+	default:
+		return time.ParseInLocation("20060102T150405", value, loc)
+	}
+}
+
+func parseTimePtr(p property) (*time.Time, error) {
+	t, err := parseTime(p)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// parseDuration 解析 RFC 5545 3.3.6 的时长，例如 -PT15M、P1D、P1W
+func parseDuration(s string) (time.Duration, error) {
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, ErrInvalid
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T' && !inTime && num == "":
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, ErrInvalid
+		}
+		unit, ok := durationUnit(r, inTime)
+		if !ok {
+			return 0, ErrInvalid
+		}
+		d += time.Duration(n) * unit
+		num = ""
+	}
+	if num != "" {
+		return 0, ErrInvalid
+	}
+	return sign * d, nil
+}
+
+// durationUnit 返回时长中单位字符对应的长度，日期部分和时间部分的单位不同
+func durationUnit(r rune, inTime bool) (time.Duration, bool) {
+	if inTime {
+		switch r {
+		case 'H':
+			return time.Hour, true
+		case 'M':
+			return time.Minute, true
+		case 'S':
+			return time.Second, true
+		}
+		return 0, false
+	}
+	switch r {
+	case 'W':
+		return 7 * 24 * time.Hour, true
+	case 'D':
+		return 24 * time.Hour, true
+	}
+	return 0, false
+}
@@ -0,0 +1,51 @@
+// Package notify 提供站内事件的通知投递能力
+package notify
+
+import (
+	"context"
+	"todo/pkg/logger"
+)
+
+// 通知类型常量
+const (
+	TypeCommentMention = "comment.mention" // 评论中被@提及
+	TypeTodoUnblocked  = "todo.unblocked"  // 待办事项不再被阻塞
+)
+
+// Message 通知消息
+type Message struct {
+	UserID uint   `json:"userId"`           // 接收通知的用户ID
+	Type   string `json:"type"`             // 通知类型
+	Title  string `json:"title"`            // 通知标题
+	Body   string `json:"body"`             // 通知正文
+	TodoID uint   `json:"todoId,omitempty"` // 关联的待办事项ID
+}
+
+// Notifier 通知投递接口
+// 具体投递渠道（邮件、推送等）通过实现该接口接入
+type Notifier interface {
+	// Notify 投递一条通知
+	// ctx: 上下文信息
+	// msg: 通知消息
+	// 返回: error 投递过程中的错误信息
+	Notify(ctx context.Context, msg *Message) error
+}
+
+// logNotifier 将通知写入日志的默认实现
+type logNotifier struct{}
+
+// NewLogNotifier 创建将通知写入日志的通知器
+// 在尚未接入邮件或推送渠道时作为默认实现使用
+func NewLogNotifier() Notifier {
+	return logNotifier{}
+}
+
+func (logNotifier) Notify(ctx context.Context, msg *Message) error {
+	logger.Info().
+		Uint("user_id", msg.UserID).
+		Str("type", msg.Type).
+		Uint("todo_id", msg.TodoID).
+		Str("title", msg.Title).
+		Msg(msg.Body)
+	return nil
+}
@@ -40,6 +40,16 @@ func (q *TaskQueue) AddTask(task Task) {
 	q.tasks <- task
 }
 
+// TryAddTask 在队列未满时加入任务并返回 true，队列已满时不等待，直接返回 false
+func (q *TaskQueue) TryAddTask(task Task) bool {
+	select {
+	case q.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
 func (q *TaskQueue) worker(ctx context.Context) {
 	defer q.wg.Done()
 
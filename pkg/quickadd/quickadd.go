@@ -0,0 +1,590 @@
+// Package quickadd 解析快速添加待办事项时输入的一行自然语言文本
+//
+// 支持英文和中文的日期时间写法，例如 "Call Bob tomorrow 3pm !high #work remind 30m before"
+// 和 "明天下午3点给老王打电话 !高 #工作 提前半小时提醒"。识别出的日期、时间、优先级、#标签和提醒
+// 从文本中去掉，剩余部分作为标题。相对日期按传入的当前时间所在的时区计算。
+package quickadd
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Kind 识别出的片段的类型
+type Kind string
+
+const (
+	KindDue      Kind = "due"      // 日期、时间或相对时间
+	KindPriority Kind = "priority" // 优先级，如 !high、!高
+	KindHashtag  Kind = "hashtag"  // #标签，由调用方决定作为分类还是标签
+	KindReminder Kind = "reminder" // 提前提醒，如 remind 30m before、提前半小时提醒
+)
+
+// Token 从输入中识别出的一个片段
+type Token struct {
+	Kind Kind   `json:"kind"` // 片段类型
+	Text string `json:"text"` // 输入中的原文
+}
+
+// Result 解析结果
+type Result struct {
+	Title        string         // 去掉识别出的片段后的标题
+	Due          *time.Time     // 截止时间，只有日期时为当天零点
+	Priority     string         // 优先级：low、medium、high，为空表示未指定
+	Hashtags     []string       // #标签，不含前缀，按出现顺序
+	RemindBefore *time.Duration // 在截止时间之前多久提醒，为空表示不提醒
+	Tokens       []Token        // 识别出的片段，按在输入中出现的顺序
+}
+
+// eveningHour 今晚、明晚等没有具体时间时使用的钟点
+const eveningHour = 20
+
+// 时段，用于把 12 小时制的钟点换算为 24 小时制
+const (
+	periodNone = iota
+	periodAM
+	periodNoon
+	periodPM
+)
+
+// periods 中文时段和对应的时段
+var periods = map[string]int{
+	"凌晨": periodAM, "早上": periodAM, "早晨": periodAM, "上午": periodAM,
+	"中午": periodNoon,
+	"下午": periodPM, "傍晚": periodPM, "晚上": periodPM,
+}
+
+// priorities 优先级写法和对应的优先级
+var priorities = map[string]string{
+	"high": "high", "h": "high", "1": "high", "高": "high",
+	"medium": "medium", "med": "medium", "m": "medium", "2": "medium", "中": "medium",
+	"low": "low", "l": "low", "3": "low", "低": "low",
+}
+
+// weekdays 英文和中文的星期写法，周一为 0
+var weekdays = map[string]int{
+	"monday": 0, "mon": 0, "tuesday": 1, "tue": 1, "tues": 1, "wednesday": 2, "wed": 2,
+	"thursday": 3, "thu": 3, "thur": 3, "thurs": 3, "friday": 4, "fri": 4,
+	"saturday": 5, "sat": 5, "sunday": 6, "sun": 6,
+	"一": 0, "二": 1, "三": 2, "四": 3, "五": 4, "六": 5, "日": 6, "天": 6,
+}
+
+// months 英文月份写法
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January, "feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March, "apr": time.April, "april": time.April, "may": time.May,
+	"jun": time.June, "june": time.June, "jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August, "sep": time.September, "sept": time.September,
+	"september": time.September, "oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November, "dec": time.December, "december": time.December,
+}
+
+const (
+	enWeekday = `monday|tuesday|wednesday|thursday|friday|saturday|sunday`
+	enWeekAbr = `mon|tues|tue|wed|thurs|thur|thu|fri|sat|sun`
+	enMonth   = `january|february|march|april|may|june|july|august|september|october|november|december|jan|feb|mar|apr|jun|jul|aug|sept|sep|oct|nov|dec`
+	enUnit    = `minutes|minute|mins|min|m|hours|hour|hrs|hr|h|days|day|d|weeks|week|w`
+	cnNum     = `[零一二两三四五六七八九十]{1,3}`
+	cnPeriod  = `凌晨|早上|早晨|上午|中午|下午|傍晚|晚上`
+)
+
+// rule 一条识别规则，apply 返回 false 时该匹配不被采用
+type rule struct {
+	kind  Kind
+	re    *regexp.Regexp
+	group int  // 作为片段的子匹配，0 表示整个匹配
+	all   bool // 是否识别所有匹配，否则只识别第一个被采用的匹配
+	apply func(p *parser, m []string) bool
+}
+
+// rules 按顺序应用的识别规则；先识别的片段不会再被后面的规则匹配
+var rules = []rule{
+	{kind: KindReminder, re: regexp.MustCompile(`(?i)\bremind(?:\s+me)?\s+(\d+|an?|half\s+an)\s*(` + enUnit + `)\s+(?:before|early|earlier|ahead)\b`),
+		apply: func(p *parser, m []string) bool { return p.setReminder(m[1], m[2]) }},
+	{kind: KindReminder, re: regexp.MustCompile(`提前\s*(\d+|` + cnNum + `|半)\s*(分钟|个小时|小时|天)\s*提醒我?`),
+		apply: func(p *parser, m []string) bool { return p.setReminder(m[1], m[2]) }},
+	{kind: KindPriority, re: regexp.MustCompile(`(?i)(?:^|\s)(!(high|medium|med|low|h|m|l|1|2|3|高|中|低))(?:\s|$)`), group: 1,
+		apply: func(p *parser, m []string) bool {
+			p.res.Priority = priorities[strings.ToLower(m[2])]
+			return true
+		}},
+	{kind: KindHashtag, re: regexp.MustCompile(`(?:^|\s)(#([^\s#!]+))`), group: 1, all: true,
+		apply: func(p *parser, m []string) bool {
+			if isNumber(m[2]) {
+				// #123 通常是议题编号
+				return false
+			}
+			p.res.Hashtags = append(p.res.Hashtags, m[2])
+			return true
+		}},
+	{kind: KindDue, re: regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`),
+		apply: func(p *parser, m []string) bool { return p.setDate(atoi(m[1]), time.Month(atoi(m[2])), atoi(m[3])) }},
+	{kind: KindDue, re: regexp.MustCompile(`(?i)\bin\s+(\d+|an?|one|half\s+an)\s*(` + enUnit + `)\b`),
+		apply: func(p *parser, m []string) bool { return p.setRelative(m[1], m[2]) }},
+	{kind: KindDue, re: regexp.MustCompile(`(\d+|` + cnNum + `|半)\s*(分钟|个小时|小时|天|个星期|星期|周)\s*(?:以后|之后|后)`),
+		apply: func(p *parser, m []string) bool { return p.setRelative(m[1], m[2]) }},
+	{kind: KindDue, re: regexp.MustCompile(`(?i)\b(?:(?:due|by|on)\s+)?(day\s+after\s+tomorrow|today|tonight|tomorrow|tmrw|tmr)\b`),
+		apply: func(p *parser, m []string) bool {
+			switch word := strings.ToLower(strings.Join(strings.Fields(m[1]), " ")); word {
+			case "today":
+				return p.setOffset(0)
+			case "tonight":
+				return p.setOffset(0) && p.setEvening()
+			case "day after tomorrow":
+				return p.setOffset(2)
+			default:
+				return p.setOffset(1)
+			}
+		}},
+	{kind: KindDue, re: regexp.MustCompile(`(?i)\bnext\s+week\b`),
+		apply: func(p *parser, m []string) bool { return p.setWeekday("next", 0) }},
+	{kind: KindDue, re: regexp.MustCompile(`(?i)\b(?:(due|by|on|this|next)\s+)?(` + enWeekday + `)\b`),
+		apply: func(p *parser, m []string) bool {
+			return p.setWeekday(strings.ToLower(m[1]), weekdays[strings.ToLower(m[2])])
+		}},
+	{kind: KindDue, re: regexp.MustCompile(`(?i)\b(due|by|on|this|next)\s+(` + enWeekAbr + `)\b`),
+		apply: func(p *parser, m []string) bool {
+			return p.setWeekday(strings.ToLower(m[1]), weekdays[strings.ToLower(m[2])])
+		}},
+	{kind: KindDue, re: regexp.MustCompile(`(?i)\b(?:(?:due|by|on)\s+)?(` + enMonth + `)\.?\s+(\d{1,2})(?:st|nd|rd|th)?\b`),
+		apply: func(p *parser, m []string) bool { return p.setMonthDay(months[strings.ToLower(m[1])], atoi(m[2])) }},
+	{kind: KindDue, re: regexp.MustCompile(`(大后天|后天|明天|明早|明晚|今天|今早|今晚)`),
+		apply: func(p *parser, m []string) bool {
+			switch m[1] {
+			case "今天":
+				return p.setOffset(0)
+			case "今早":
+				return p.setOffset(0) && p.setMorning()
+			case "今晚":
+				return p.setOffset(0) && p.setEvening()
+			case "明早":
+				return p.setOffset(1) && p.setMorning()
+			case "明晚":
+				return p.setOffset(1) && p.setEvening()
+			case "后天":
+				return p.setOffset(2)
+			case "大后天":
+				return p.setOffset(3)
+			default:
+				return p.setOffset(1)
+			}
+		}},
+	{kind: KindDue, re: regexp.MustCompile(`(下个|下|这个|这|本)?(?:周|星期|礼拜)([一二三四五六日天])`),
+		apply: func(p *parser, m []string) bool {
+			which := ""
+			switch m[1] {
+			case "下", "下个":
+				which = "next"
+			case "这", "这个", "本":
+				which = "this"
+			}
+			return p.setWeekday(which, weekdays[m[2]])
+		}},
+	{kind: KindDue, re: regexp.MustCompile(`(下个?(?:周|星期|礼拜))`),
+		apply: func(p *parser, m []string) bool { return p.setWeekday("next", 0) }},
+	{kind: KindDue, re: regexp.MustCompile(`(\d{1,2}|` + cnNum + `)\s*月\s*(\d{1,2}|` + cnNum + `)\s*[日号]`),
+		apply: func(p *parser, m []string) bool {
+			month, ok1 := number(m[1])
+			day, ok2 := number(m[2])
+			return ok1 && ok2 && p.setMonthDay(time.Month(month), day)
+		}},
+	{kind: KindDue, re: regexp.MustCompile(`(` + cnPeriod + `)?\s*(\d{1,2}|` + cnNum + `)\s*[点點](?:\s*(半|一刻|三刻|\d{1,2}|` + cnNum + `)\s*分?)?`),
+		apply: func(p *parser, m []string) bool {
+			hour, ok := number(m[2])
+			if !ok || (m[1] == "" && m[3] == "" && !isNumber(m[2])) {
+				// 没有时段和分钟的中文数字，如“快一点”，通常不是钟点
+				return false
+			}
+			minute := 0
+			switch m[3] {
+			case "":
+			case "半":
+				minute = 30
+			case "一刻":
+				minute = 15
+			case "三刻":
+				minute = 45
+			default:
+				if minute, ok = number(m[3]); !ok {
+					return false
+				}
+			}
+			return p.setClock(periods[m[1]], hour, minute)
+		}},
+	{kind: KindDue, re: regexp.MustCompile(`(?i)(` + cnPeriod + `)?\s*(?:\bat\s+)?\b(\d{1,2}):(\d{2})(?:\s*(a\.m\.|p\.m\.|am\b|pm\b))?`),
+		apply: func(p *parser, m []string) bool {
+			period := periods[m[1]]
+			if m[4] != "" {
+				period = meridiem(m[4])
+			}
+			return p.setClock(period, atoi(m[2]), atoi(m[3]))
+		}},
+	{kind: KindDue, re: regexp.MustCompile(`(?i)(?:\bat\s+)?\b(\d{1,2})\s*(a\.m\.|p\.m\.|am\b|pm\b)`),
+		apply: func(p *parser, m []string) bool { return p.setClock(meridiem(m[2]), atoi(m[1]), 0) }},
+	{kind: KindDue, re: regexp.MustCompile(`(?i)\b(?:at\s+)?(noon|midnight)\b`),
+		apply: func(p *parser, m []string) bool {
+			if strings.EqualFold(m[1], "noon") {
+				return p.setClock(periodNone, 12, 0)
+			}
+			return p.setClock(periodNone, 0, 0)
+		}},
+	{kind: KindDue, re: regexp.MustCompile(`(?i)\bat\s+(\d{1,2})\b`),
+		apply: func(p *parser, m []string) bool { return p.setClock(periodNone, atoi(m[1]), 0) }},
+	{kind: KindDue, re: regexp.MustCompile(`(中午)`),
+		apply: func(p *parser, m []string) bool { return p.setClock(periodNone, 12, 0) }},
+}
+
+// parser 解析过程中的状态
+type parser struct {
+	now     time.Time
+	res     *Result
+	date    *time.Time     // 识别出的日期（当天零点）
+	exact   *time.Time     // 相对当前时间的截止时间，如 in 2 hours
+	clock   *time.Duration // 识别出的钟点，距零点的时长
+	period  int            // 日期短语隐含的时段，如明早
+	evening bool           // 今晚、明晚：没有钟点时使用晚上的默认钟点，钟点按下午解释
+}
+
+// span 输入中一个被识别的片段的字节区间
+type span struct {
+	start, end int
+	kind       Kind
+}
+
+// Parse 解析输入，now 为当前时间，相对日期按 now 所在的时区计算
+// 每类日期时间写法只识别第一个；只有钟点时取今天的该时刻，已经过去时取明天
+func Parse(input string, now time.Time) *Result {
+	p := &parser{now: now, res: &Result{}}
+	masked := []byte(input)
+	var spans []span
+
+	for _, r := range rules {
+		for _, idx := range r.re.FindAllSubmatchIndex(masked, -1) {
+			m := make([]string, len(idx)/2)
+			for i := range m {
+				if idx[2*i] >= 0 {
+					m[i] = string(masked[idx[2*i]:idx[2*i+1]])
+				}
+			}
+			start, end := idx[2*r.group], idx[2*r.group+1]
+			// 匹配两端的空白可能是已识别的片段，不计入本片段
+			for start < end && isSpace(masked[start]) {
+				start++
+			}
+			for end > start && isSpace(masked[end-1]) {
+				end--
+			}
+			if start == end || !r.apply(p, m) {
+				continue
+			}
+			// 已识别的片段替换为空白，避免被后面的规则再次匹配
+			for i := start; i < end; i++ {
+				masked[i] = ' '
+			}
+			spans = append(spans, span{start: start, end: end, kind: r.kind})
+			if !r.all {
+				break
+			}
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	var title strings.Builder
+	prev := 0
+	for _, s := range spans {
+		appendPiece(&title, input[prev:s.start])
+		p.res.Tokens = append(p.res.Tokens, Token{Kind: s.kind, Text: input[s.start:s.end]})
+		prev = s.end
+	}
+	appendPiece(&title, input[prev:])
+	p.res.Title = strings.TrimSpace(title.String())
+	p.res.Due = p.due()
+	return p.res
+}
+
+// appendPiece 把去掉片段后剩下的一段文本接到标题后面
+// 两段之间是汉字时直接相连，否则以一个空格分隔；多个空白合并为一个
+func appendPiece(b *strings.Builder, piece string) {
+	piece = strings.Join(strings.Fields(piece), " ")
+	if piece == "" {
+		return
+	}
+	if b.Len() > 0 {
+		last := []rune(b.String())
+		first := []rune(piece)[0]
+		if !unicode.Is(unicode.Han, last[len(last)-1]) && !unicode.Is(unicode.Han, first) {
+			b.WriteByte(' ')
+		}
+	}
+	b.WriteString(piece)
+}
+
+// due 组合识别出的日期、钟点和相对时间
+func (p *parser) due() *time.Time {
+	if p.exact != nil {
+		return p.exact
+	}
+	loc := p.now.Location()
+	if p.date == nil && p.clock == nil {
+		return nil
+	}
+	if p.clock == nil {
+		due := *p.date
+		if p.evening {
+			due = due.Add(eveningHour * time.Hour)
+		}
+		return &due
+	}
+	if p.date != nil {
+		due := p.date.Add(*p.clock)
+		return &due
+	}
+	today := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, loc)
+	due := today.Add(*p.clock)
+	if due.Before(p.now) {
+		due = today.AddDate(0, 0, 1).Add(*p.clock)
+	}
+	return &due
+}
+
+// today 当前时区的今天零点
+func (p *parser) today() time.Time {
+	return time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+}
+
+// setOffset 设置为今天之后第 days 天
+func (p *parser) setOffset(days int) bool {
+	if p.date != nil || p.exact != nil {
+		return false
+	}
+	d := p.today().AddDate(0, 0, days)
+	p.date = &d
+	return true
+}
+
+// setMorning 标记日期短语表示早上，如明早
+func (p *parser) setMorning() bool {
+	p.period = periodAM
+	return true
+}
+
+// setEvening 标记日期短语表示晚上，如今晚、明晚
+func (p *parser) setEvening() bool {
+	p.evening = true
+	return true
+}
+
+// setDate 设置为指定日期，日期无效时不采用
+func (p *parser) setDate(year int, month time.Month, day int) bool {
+	if p.date != nil || p.exact != nil {
+		return false
+	}
+	d := time.Date(year, month, day, 0, 0, 0, 0, p.now.Location())
+	if d.Year() != year || d.Month() != month || d.Day() != day {
+		return false
+	}
+	p.date = &d
+	return true
+}
+
+// setMonthDay 设置为今年的指定日期，已经过去时为明年的该日期
+func (p *parser) setMonthDay(month time.Month, day int) bool {
+	year := p.now.Year()
+	if time.Date(year, month, day, 0, 0, 0, 0, p.now.Location()).Before(p.today()) {
+		year++
+	}
+	return p.setDate(year, month, day)
+}
+
+// setWeekday 设置为星期几，weekday 以周一为 0
+// which 为 next 时是下周的该天；为 this 时是本周（周一开始）的该天；否则是今天之后最近的该天
+func (p *parser) setWeekday(which string, weekday int) bool {
+	today := (int(p.now.Weekday()) + 6) % 7
+	var days int
+	switch which {
+	case "next":
+		days = 7 - today + weekday
+	case "this":
+		days = weekday - today
+	default:
+		days = (weekday - today + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+	}
+	return p.setOffset(days)
+}
+
+// setRelative 设置为相对当前时间的截止时间；以天或周为单位时只确定日期
+func (p *parser) setRelative(amount, unit string) bool {
+	if p.date != nil || p.exact != nil {
+		return false
+	}
+	d, ok := duration(amount, unit)
+	if !ok {
+		return false
+	}
+	if d%(24*time.Hour) == 0 {
+		return p.setOffset(int(d / (24 * time.Hour)))
+	}
+	exact := p.now.Add(d)
+	p.exact = &exact
+	return true
+}
+
+// setClock 设置钟点，period 用于把 12 小时制换算为 24 小时制
+func (p *parser) setClock(period, hour, minute int) bool {
+	if p.clock != nil || p.exact != nil || hour > 23 || minute > 59 {
+		return false
+	}
+	if period == periodNone {
+		period = p.period
+		if p.evening {
+			period = periodPM
+		}
+	}
+	switch {
+	case period == periodAM && hour == 12:
+		hour = 0
+	case period == periodNoon && hour < 6:
+		hour += 12
+	case period == periodPM && hour < 12:
+		hour += 12
+	}
+	clock := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+	p.clock = &clock
+	return true
+}
+
+// setReminder 设置提前提醒的时长
+func (p *parser) setReminder(amount, unit string) bool {
+	if p.res.RemindBefore != nil {
+		return false
+	}
+	d, ok := duration(amount, unit)
+	if !ok || d <= 0 {
+		return false
+	}
+	p.res.RemindBefore = &d
+	return true
+}
+
+// duration 把数量和单位换算为时长，支持阿拉伯数字、中文数字、a/an/one 和半
+func duration(amount, unit string) (time.Duration, bool) {
+	unit = strings.ToLower(unit)
+	amount = strings.ToLower(strings.Join(strings.Fields(amount), " "))
+
+	var base time.Duration
+	switch unit {
+	case "minutes", "minute", "mins", "min", "m", "分钟":
+		base = time.Minute
+	case "hours", "hour", "hrs", "hr", "h", "小时", "个小时":
+		base = time.Hour
+	case "days", "day", "d", "天":
+		base = 24 * time.Hour
+	case "weeks", "week", "w", "周", "星期", "个星期":
+		base = 7 * 24 * time.Hour
+	default:
+		return 0, false
+	}
+
+	switch amount {
+	case "a", "an", "one":
+		return base, true
+	case "half an", "半":
+		// 只有半小时是常见写法，半天、半周没有明确的含义
+		return base / 2, base == time.Hour
+	}
+	n, ok := number(amount)
+	if !ok || n <= 0 {
+		return 0, false
+	}
+	return time.Duration(n) * base, true
+}
+
+// meridiem 把 am、pm 换算为时段
+func meridiem(s string) int {
+	if strings.HasPrefix(strings.ToLower(s), "p") {
+		return periodPM
+	}
+	return periodAM
+}
+
+// cnDigits 中文数字
+var cnDigits = map[rune]int{'零': 0, '一': 1, '二': 2, '两': 2, '三': 3, '四': 4, '五': 5, '六': 6, '七': 7, '八': 8, '九': 9}
+
+// number 解析阿拉伯数字或九十九以内的中文数字
+func number(s string) (int, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, true
+	}
+	runes := []rune(s)
+	if len(runes) == 0 || len(runes) > 3 {
+		return 0, false
+	}
+	tens, ones := 0, 0
+	ten := -1
+	for i, r := range runes {
+		if r == '十' {
+			if ten >= 0 {
+				return 0, false
+			}
+			ten = i
+		}
+	}
+	if ten < 0 {
+		if len(runes) != 1 {
+			return 0, false
+		}
+		d, ok := cnDigits[runes[0]]
+		return d, ok
+	}
+	switch ten {
+	case 0:
+		tens = 1
+	case 1:
+		d, ok := cnDigits[runes[0]]
+		if !ok || d == 0 {
+			return 0, false
+		}
+		tens = d
+	default:
+		return 0, false
+	}
+	if ten < len(runes)-1 {
+		if ten != len(runes)-2 {
+			return 0, false
+		}
+		d, ok := cnDigits[runes[len(runes)-1]]
+		if !ok || d == 0 {
+			return 0, false
+		}
+		ones = d
+	}
+	return tens*10 + ones, true
+}
+
+// atoi 解析正则表达式已经保证是数字的子匹配
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// isSpace 判断字节是否是 ASCII 空白
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// isNumber 判断字符串是否只由数字组成
+func isNumber(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,189 @@
+package quickadd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// zone 测试使用的固定时区
+var zone = time.FixedZone("UTC+8", 8*3600)
+
+// now 测试使用的当前时间：2024-06-05（星期三）10:00
+var now = time.Date(2024, 6, 5, 10, 0, 0, 0, zone)
+
+func at(month time.Month, day, hour, minute int) *time.Time {
+	t := time.Date(2024, month, day, hour, minute, 0, 0, zone)
+	return &t
+}
+
+func minutes(n int) *time.Duration {
+	d := time.Duration(n) * time.Minute
+	return &d
+}
+
+// TestParse 测试解析英文和中文的快速添加文本
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Result
+	}{
+		{
+			name:  "英文完整示例",
+			input: "Call Bob tomorrow 3pm !high #work remind 30m before",
+			want: Result{Title: "Call Bob", Due: at(6, 6, 15, 0), Priority: "high", Hashtags: []string{"work"},
+				RemindBefore: minutes(30), Tokens: []Token{{KindDue, "tomorrow"}, {KindDue, "3pm"},
+					{KindPriority, "!high"}, {KindHashtag, "#work"}, {KindReminder, "remind 30m before"}}},
+		},
+		{
+			name:  "中文完整示例",
+			input: "明天下午3点半给老王打电话 !高 #工作 提前半小时提醒",
+			want: Result{Title: "给老王打电话", Due: at(6, 6, 15, 30), Priority: "high", Hashtags: []string{"工作"},
+				RemindBefore: minutes(30), Tokens: []Token{{KindDue, "明天"}, {KindDue, "下午3点半"},
+					{KindPriority, "!高"}, {KindHashtag, "#工作"}, {KindReminder, "提前半小时提醒"}}},
+		},
+		{
+			name:  "只有文本",
+			input: "  买  菜 ",
+			want:  Result{Title: "买 菜"},
+		},
+		{
+			name:  "议题编号不是标签",
+			input: "修复 #123 !low",
+			want:  Result{Title: "修复 #123", Priority: "low", Tokens: []Token{{KindPriority, "!low"}}},
+		},
+		{
+			name:  "只有钟点且已经过去时为明天",
+			input: "开会 9:30",
+			want:  Result{Title: "开会", Due: at(6, 6, 9, 30), Tokens: []Token{{KindDue, "9:30"}}},
+		},
+		{
+			name:  "只有钟点且未过去时为今天",
+			input: "submit report at 5 pm",
+			want:  Result{Title: "submit report", Due: at(6, 5, 17, 0), Tokens: []Token{{KindDue, "at 5 pm"}}},
+		},
+		{
+			name:  "今晚没有钟点使用默认钟点",
+			input: "watch movie tonight",
+			want:  Result{Title: "watch movie", Due: at(6, 5, 20, 0), Tokens: []Token{{KindDue, "tonight"}}},
+		},
+		{
+			name:  "明晚的钟点按晚上理解",
+			input: "明晚8点聚餐",
+			want:  Result{Title: "聚餐", Due: at(6, 6, 20, 0), Tokens: []Token{{KindDue, "明晚"}, {KindDue, "8点"}}},
+		},
+		{
+			name:  "相对分钟为精确时间",
+			input: "take medicine in 45 minutes",
+			want:  Result{Title: "take medicine", Due: at(6, 5, 10, 45), Tokens: []Token{{KindDue, "in 45 minutes"}}},
+		},
+		{
+			name:  "中文相对天数只有日期",
+			input: "三天后交房租",
+			want:  Result{Title: "交房租", Due: at(6, 8, 0, 0), Tokens: []Token{{KindDue, "三天后"}}},
+		},
+		{
+			name:  "英文星期为今天之后最近的一天",
+			input: "gym wednesday 7am",
+			want: Result{Title: "gym", Due: at(6, 12, 7, 0),
+				Tokens: []Token{{KindDue, "wednesday"}, {KindDue, "7am"}}},
+		},
+		{
+			name:  "下周五",
+			input: "下周五 上午10点 周会",
+			want:  Result{Title: "周会", Due: at(6, 14, 10, 0), Tokens: []Token{{KindDue, "下周五"}, {KindDue, "上午10点"}}},
+		},
+		{
+			name:  "本周五",
+			input: "本周五交周报",
+			want:  Result{Title: "交周报", Due: at(6, 7, 0, 0), Tokens: []Token{{KindDue, "本周五"}}},
+		},
+		{
+			name:  "星期缩写需要介词",
+			input: "buy sat dish by fri",
+			want:  Result{Title: "buy sat dish", Due: at(6, 7, 0, 0), Tokens: []Token{{KindDue, "by fri"}}},
+		},
+		{
+			name:  "英文月份日期已经过去时为明年",
+			input: "renew passport due Jan 15th",
+			want: Result{Title: "renew passport", Due: func() *time.Time {
+				t := time.Date(2025, 1, 15, 0, 0, 0, 0, zone)
+				return &t
+			}(), Tokens: []Token{{KindDue, "due Jan 15th"}}},
+		},
+		{
+			name:  "中文月日和一刻",
+			input: "6月20号晚上七点一刻 看演出",
+			want: Result{Title: "看演出", Due: at(6, 20, 19, 15),
+				Tokens: []Token{{KindDue, "6月20号"}, {KindDue, "晚上七点一刻"}}},
+		},
+		{
+			name:  "ISO 日期和中午",
+			input: "2024-07-01 noon lunch with team",
+			want: Result{Title: "lunch with team", Due: at(7, 1, 12, 0),
+				Tokens: []Token{{KindDue, "2024-07-01"}, {KindDue, "noon"}}},
+		},
+		{
+			name:  "中文数字不带时段不是钟点",
+			input: "多喝一点水",
+			want:  Result{Title: "多喝一点水"},
+		},
+		{
+			name:  "只识别第一个日期",
+			input: "today or tomorrow",
+			want:  Result{Title: "or tomorrow", Due: at(6, 5, 0, 0), Tokens: []Token{{KindDue, "today"}}},
+		},
+		{
+			name:  "提前一小时提醒",
+			input: "remind me an hour before dentist tomorrow 2pm",
+			want: Result{Title: "dentist", Due: at(6, 6, 14, 0), RemindBefore: minutes(60),
+				Tokens: []Token{{KindReminder, "remind me an hour before"}, {KindDue, "tomorrow"}, {KindDue, "2pm"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.input, now)
+			if got.Title != tt.want.Title {
+				t.Errorf("Title = %q, want %q", got.Title, tt.want.Title)
+			}
+			if !equalTime(got.Due, tt.want.Due) {
+				t.Errorf("Due = %v, want %v", got.Due, tt.want.Due)
+			}
+			if got.Priority != tt.want.Priority {
+				t.Errorf("Priority = %q, want %q", got.Priority, tt.want.Priority)
+			}
+			if !reflect.DeepEqual(got.Hashtags, tt.want.Hashtags) {
+				t.Errorf("Hashtags = %v, want %v", got.Hashtags, tt.want.Hashtags)
+			}
+			if !reflect.DeepEqual(got.RemindBefore, tt.want.RemindBefore) {
+				t.Errorf("RemindBefore = %v, want %v", got.RemindBefore, tt.want.RemindBefore)
+			}
+			if !reflect.DeepEqual(got.Tokens, tt.want.Tokens) {
+				t.Errorf("Tokens = %v, want %v", got.Tokens, tt.want.Tokens)
+			}
+		})
+	}
+}
+
+// TestNumber 测试中文数字解析
+func TestNumber(t *testing.T) {
+	tests := map[string]int{"7": 7, "三": 3, "两": 2, "十": 10, "十二": 12, "二十": 20, "二十三": 23}
+	for in, want := range tests {
+		if got, ok := number(in); !ok || got != want {
+			t.Errorf("number(%q) = %d, %v, want %d", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"", "十十", "零十", "一二", "百"} {
+		if _, ok := number(in); ok {
+			t.Errorf("number(%q) should fail", in)
+		}
+	}
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
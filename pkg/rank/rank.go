@@ -0,0 +1,122 @@
+// Package rank 生成用于手动排序的字典序排名
+//
+// 排名是 36 进制（0-9a-z）的小数部分，例如 "i" 表示 18/36。任意两个排名之间总能生成新的排名，
+// 因此移动一个元素只需要改写这一行。排名只包含小写字母和数字，在大小写不敏感的排序规则下顺序不变。
+// 反复在同一位置插入会使排名变长，超过 MaxLength 时应使用 Spread 重新分配整组排名。
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+// digits 排名使用的字符，按字典序排列
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+// MaxLength 建议的排名最大长度，超过时应重新分配
+const MaxLength = 32
+
+// ErrInvalidRange 下界不小于上界或排名格式无效
+var ErrInvalidRange = errors.New("rank: 无效的排名范围")
+
+// Between 返回严格位于 lower 和 upper 之间的排名
+// lower 为空表示没有下界，upper 为空表示没有上界
+func Between(lower, upper string) (string, error) {
+	if !valid(lower) || !valid(upper) || (upper != "" && lower >= upper) {
+		return "", ErrInvalidRange
+	}
+	return midpoint(lower, upper), nil
+}
+
+// Spread 返回 n 个均匀分布的递增排名，占用前半个区间，为追加到末尾留出空间
+func Spread(n int) []string {
+	width, capacity := 1, base
+	for capacity < 4*(n+1) {
+		width++
+		capacity *= base
+	}
+	step := capacity / (2 * (n + 1))
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		ranks[i] = format((i+1)*step, width)
+	}
+	return ranks
+}
+
+// valid 判断排名格式：只包含 digits 中的字符且不以 0 结尾；空字符串表示不限
+func valid(r string) bool {
+	if r == "" {
+		return true
+	}
+	if r[len(r)-1] == '0' {
+		return false
+	}
+	for i := 0; i < len(r); i++ {
+		if strings.IndexByte(digits, r[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// midpoint 返回 a 和 b 之间的排名，b 为空表示上界为 1
+// 要求 a < b 且两者都不以 0 结尾
+func midpoint(a, b string) string {
+	if b != "" {
+		// 跳过公共前缀，a 较短时按末尾补 0 比较
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	da := 0
+	if a != "" {
+		da = strings.IndexByte(digits, a[0])
+	}
+	db := base
+	if b != "" {
+		db = strings.IndexByte(digits, b[0])
+	}
+	if db-da > 1 {
+		return string(digits[(da+db+1)/2])
+	}
+
+	// 首位相邻：b 有更多位时取 b 的首位即可，否则在 a 的首位之后继续取中点
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[da]) + midpoint(rest, "")
+}
+
+// digitAt 返回 s 第 i 位的字符，超出长度时视为 0
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return digits[0]
+}
+
+// format 将 v 格式化为 width 位的 36 进制小数部分，并去掉末尾的 0
+func format(v, width int) string {
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = digits[v%base]
+		v /= base
+	}
+	return strings.TrimRight(string(buf), "0")
+}
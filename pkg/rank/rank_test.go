@@ -0,0 +1,86 @@
+package rank
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// TestBetween 测试在各种边界之间生成排名
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		lower, upper string
+	}{
+		{"", ""},
+		{"", "i"},
+		{"i", ""},
+		{"a", "b"},
+		{"a", "a1"},
+		{"az", "b"},
+		{"zz", ""},
+		{"", "01"},
+		{"0001", "0002"},
+	}
+
+	for _, tt := range tests {
+		got, err := Between(tt.lower, tt.upper)
+		if err != nil {
+			t.Errorf("Between(%q, %q) 错误 = %v", tt.lower, tt.upper, err)
+			continue
+		}
+		if !valid(got) || got == "" || got <= tt.lower || (tt.upper != "" && got >= tt.upper) {
+			t.Errorf("Between(%q, %q) = %q, 不在范围内", tt.lower, tt.upper, got)
+		}
+	}
+}
+
+// TestBetweenInvalid 测试无效的范围
+func TestBetweenInvalid(t *testing.T) {
+	for _, tt := range [][2]string{{"b", "a"}, {"a", "a"}, {"a0", ""}, {"A", ""}, {"", "-"}} {
+		if _, err := Between(tt[0], tt[1]); err != ErrInvalidRange {
+			t.Errorf("Between(%q, %q) 错误 = %v, 期望 %v", tt[0], tt[1], err, ErrInvalidRange)
+		}
+	}
+}
+
+// TestBetweenRepeated 测试反复插入后顺序保持正确
+func TestBetweenRepeated(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	ranks := []string{}
+	for i := 0; i < 500; i++ {
+		pos := r.Intn(len(ranks) + 1)
+		lower, upper := "", ""
+		if pos > 0 {
+			lower = ranks[pos-1]
+		}
+		if pos < len(ranks) {
+			upper = ranks[pos]
+		}
+		got, err := Between(lower, upper)
+		if err != nil {
+			t.Fatalf("Between(%q, %q) 错误 = %v", lower, upper, err)
+		}
+		ranks = append(ranks[:pos], append([]string{got}, ranks[pos:]...)...)
+	}
+	if !sort.StringsAreSorted(ranks) {
+		t.Error("排名顺序错误")
+	}
+}
+
+// TestSpread 测试均匀分配的排名递增且有效
+func TestSpread(t *testing.T) {
+	for _, n := range []int{0, 1, 8, 35, 1000} {
+		ranks := Spread(n)
+		if len(ranks) != n {
+			t.Fatalf("Spread(%d) 数量 = %d", n, len(ranks))
+		}
+		for i, r := range ranks {
+			if r == "" || !valid(r) || (i > 0 && ranks[i-1] >= r) {
+				t.Fatalf("Spread(%d)[%d] = %q, 无效或未递增", n, i, r)
+			}
+		}
+		if n > 0 && ranks[n-1] >= "i" {
+			t.Errorf("Spread(%d) 最后一个排名 = %q, 应位于前半个区间", n, ranks[n-1])
+		}
+	}
+}
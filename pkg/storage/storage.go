@@ -0,0 +1,63 @@
+// Package storage 提供可插拔的二进制对象（附件）存储
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"todo/pkg/config"
+)
+
+// ErrBlobNotFound 对象不存在
+var ErrBlobNotFound = errors.New("对象不存在")
+
+// 存储驱动名称
+const (
+	DriverLocal = "local" // 本地文件系统
+	DriverS3    = "s3"    // S3 兼容的对象存储（AWS S3、MinIO 等）
+)
+
+// BlobStore 二进制对象存储接口
+// key 使用 "/" 分隔的相对路径，由调用方保证唯一
+type BlobStore interface {
+	// Put 写入对象
+	// ctx: 上下文信息
+	// key: 对象键
+	// r: 对象内容
+	// size: 对象大小（字节）
+	// contentType: 对象的 MIME 类型
+	// 返回: error 写入过程中的错误信息
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get 读取对象，调用方负责关闭返回的 ReadCloser
+	// ctx: 上下文信息
+	// key: 对象键
+	// 返回: (io.ReadCloser, error) 对象内容和可能的错误，对象不存在时返回 ErrBlobNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete 删除对象，对象不存在时不返回错误
+	// ctx: 上下文信息
+	// key: 对象键
+	// 返回: error 删除过程中的错误信息
+	Delete(ctx context.Context, key string) error
+}
+
+// New 根据配置创建对象存储实例
+//
+// Parameters:
+//   - cfg: 存储配置
+//
+// Returns:
+//   - BlobStore: 对象存储实例
+//   - error: 配置无效时返回错误
+func New(cfg *config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocalStore(cfg.LocalDir)
+	case DriverS3:
+		return NewS3Store(&cfg.S3)
+	default:
+		return nil, fmt.Errorf("不支持的存储驱动: %s", cfg.Driver)
+	}
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"todo/pkg/config"
+)
+
+// fakeS3 模拟 S3 兼容服务的最小实现，用于替代本地 MinIO 进行测试
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	t       *testing.T
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=minio/") ||
+		!strings.Contains(auth, "/us-east-1/s3/aws4_request") ||
+		r.Header.Get("X-Amz-Date") == "" {
+		f.t.Errorf("请求缺少有效的签名: %q", auth)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = data
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// testBlobStore 对任意 BlobStore 实现执行写入、读取和删除的通用测试
+func testBlobStore(t *testing.T, store BlobStore) {
+	ctx := context.Background()
+	key := "todos/1/报告 v1.txt"
+	content := []byte("hello attachment")
+
+	if err := store.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put() 错误 = %v", err)
+	}
+
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() 错误 = %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if !bytes.Equal(got, content) {
+		t.Errorf("Get() 内容 = %q, 期望 %q", got, content)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() 错误 = %v", err)
+	}
+	if _, err := store.Get(ctx, key); err != ErrBlobNotFound {
+		t.Errorf("删除后 Get() 错误 = %v, 期望 %v", err, ErrBlobNotFound)
+	}
+	// 删除不存在的对象不报错
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("重复 Delete() 错误 = %v", err)
+	}
+}
+
+// TestLocalStore 测试本地文件系统存储
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore() 错误 = %v", err)
+	}
+	testBlobStore(t, store)
+
+	// 对象键不能跳出存储根目录
+	path, err := store.path("../../etc/passwd")
+	if err != nil || !strings.HasPrefix(path, store.root) {
+		t.Errorf("path() = %q, %v, 期望位于 %q 之下", path, err, store.root)
+	}
+}
+
+// TestS3Store 测试 S3 兼容存储
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte), t: t}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3Store(&config.S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "attachments",
+		AccessKey: "minio",
+		SecretKey: "minio123",
+	})
+	if err != nil {
+		t.Fatalf("NewS3Store() 错误 = %v", err)
+	}
+	testBlobStore(t, store)
+}
+
+// TestS3Store_Signature 测试相同请求在相同时间产生稳定的签名，且签名随密钥变化
+func TestS3Store_Signature(t *testing.T) {
+	newStore := func(secret string) *S3Store {
+		store, _ := NewS3Store(&config.S3Config{
+			Endpoint:  "http://localhost:9000",
+			Bucket:    "attachments",
+			AccessKey: "minio",
+			SecretKey: secret,
+		})
+		return store
+	}
+	sign := func(store *S3Store) string {
+		req, _ := store.newRequest(context.Background(), http.MethodGet, "todos/1/a b.png", nil)
+		store.sign(req, store.now().UTC().Truncate(24*time.Hour))
+		return req.Header.Get("Authorization")
+	}
+
+	a := newStore("secret-a")
+	if sign(a) != sign(a) {
+		t.Error("相同请求的签名不一致")
+	}
+	if sign(a) == sign(newStore("secret-b")) {
+		t.Error("不同密钥产生了相同的签名")
+	}
+}
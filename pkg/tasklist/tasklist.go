@@ -0,0 +1,236 @@
+// Package tasklist 读写 GitHub 风格 Markdown（GFM）中的任务列表
+//
+// 每个 "- [ ] 文本" 或 "- [x] 文本" 列表项是一个任务，任务下方缩进的非列表行是任务的说明，
+// 任务属于它之前最近的二级及以下标题，一级标题视为文档标题，其后的任务不属于任何标题。
+// 嵌套的任务被展开为独立的任务，其他 Markdown 内容被忽略。
+package tasklist
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// noteIndent 写出说明时每行的缩进，使说明在渲染后仍属于上方的列表项
+const noteIndent = "  "
+
+// sectionPrefix 写出标题使用的级别
+const sectionPrefix = "## "
+
+// Item 一个任务
+type Item struct {
+	Section string // 所在标题的文本，第一个标题之前的任务为空
+	Done    bool   // 是否已勾选
+	Text    string // 复选框之后的文本
+	Note    string // 任务下方缩进的说明，多行以换行连接
+	Line    int    // 任务所在的行号，从 1 开始；写出时忽略
+}
+
+// Reader 逐个读取任务，不会把整个文档读入内存
+type Reader struct {
+	s       *bufio.Scanner
+	line    int
+	section string
+	unread  *string // 读取上一个任务时多读的一行
+}
+
+// NewReader 创建任务读取器
+func NewReader(r io.Reader) *Reader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 4096), 1<<20)
+	return &Reader{s: s}
+}
+
+// Read 读取下一个任务，没有更多任务时返回 io.EOF
+func (r *Reader) Read() (*Item, error) {
+	var item *Item
+	var note []string
+	blanks := 0
+	finish := func() *Item {
+		item.Note = strings.Join(note, "\n")
+		return item
+	}
+
+	for {
+		line, ok := r.next()
+		if !ok {
+			if err := r.s.Err(); err != nil {
+				return nil, err
+			}
+			if item != nil {
+				return finish(), nil
+			}
+			return nil, io.EOF
+		}
+
+		if done, text, isTask := parseTask(line); isTask {
+			if item != nil {
+				r.unread = &line
+				r.line--
+				return finish(), nil
+			}
+			item = &Item{Section: r.section, Done: done, Text: text, Line: r.line}
+			continue
+		}
+		if item != nil {
+			if strings.TrimSpace(line) == "" {
+				blanks++
+				continue
+			}
+			if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+				// 说明中间的空行只有在后面还有说明时才保留
+				for ; blanks > 0; blanks-- {
+					note = append(note, "")
+				}
+				note = append(note, dedent(line))
+				continue
+			}
+			r.unread = &line
+			r.line--
+			return finish(), nil
+		}
+		if level, heading, ok := parseHeading(line); ok {
+			if level == 1 {
+				heading = ""
+			}
+			r.section = heading
+		}
+	}
+}
+
+// next 返回下一行，优先返回多读的一行
+func (r *Reader) next() (string, bool) {
+	r.line++
+	if r.unread != nil {
+		line := *r.unread
+		r.unread = nil
+		return line, true
+	}
+	if !r.s.Scan() {
+		return "", false
+	}
+	line := strings.TrimSuffix(r.s.Text(), "\r")
+	if r.line == 1 {
+		line = strings.TrimPrefix(line, "\ufeff") // 编辑器保存的 UTF-8 BOM
+	}
+	return line, true
+}
+
+// Writer 写出任务列表，任务按所在标题分组
+type Writer struct {
+	w       *bufio.Writer
+	err     error
+	written bool
+	section string
+}
+
+// NewWriter 创建任务列表写入器
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write 写出一个任务
+// 任务的标题与上一个任务不同时先写出二级标题，因此同一标题的任务应连续写出，没有标题的任务应最先写出。
+// 文本中的换行被替换为空格，说明的每一行缩进两个空格
+func (w *Writer) Write(item *Item) error {
+	if item.Section != w.section {
+		if w.written {
+			w.write("\n")
+		}
+		w.write(sectionPrefix + singleLine(item.Section) + "\n\n")
+		w.section = item.Section
+	}
+	w.written = true
+
+	box := "[ ]"
+	if item.Done {
+		box = "[x]"
+	}
+	w.write("- " + box + " " + singleLine(item.Text) + "\n")
+	if note := strings.TrimRight(strings.ReplaceAll(item.Note, "\r\n", "\n"), "\n"); note != "" {
+		for _, line := range strings.Split(note, "\n") {
+			if strings.TrimSpace(line) == "" {
+				w.write("\n")
+				continue
+			}
+			w.write(noteIndent + line + "\n")
+		}
+	}
+	return w.err
+}
+
+// Flush 把缓冲的内容写入底层输出
+func (w *Writer) Flush() error {
+	if w.err == nil {
+		w.err = w.w.Flush()
+	}
+	return w.err
+}
+
+func (w *Writer) write(s string) {
+	if w.err == nil {
+		_, w.err = w.w.WriteString(s)
+	}
+}
+
+// parseTask 解析任务列表项：无序（-、*、+）或有序（1.、1)）列表标记，随后是 [ ]、[x] 或 [X]
+func parseTask(line string) (bool, string, bool) {
+	s := strings.TrimLeft(line, " \t")
+	switch {
+	case strings.HasPrefix(s, "- "), strings.HasPrefix(s, "* "), strings.HasPrefix(s, "+ "):
+		s = s[2:]
+	default:
+		i := 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == 0 || i+1 >= len(s) || (s[i] != '.' && s[i] != ')') || s[i+1] != ' ' {
+			return false, "", false
+		}
+		s = s[i+2:]
+	}
+	s = strings.TrimLeft(s, " ")
+	if len(s) < 3 || s[0] != '[' || s[2] != ']' || (len(s) > 3 && s[3] != ' ' && s[3] != '\t') {
+		return false, "", false
+	}
+	switch s[1] {
+	case ' ':
+		return false, strings.TrimSpace(s[3:]), true
+	case 'x', 'X':
+		return true, strings.TrimSpace(s[3:]), true
+	}
+	return false, "", false
+}
+
+// parseHeading 解析 ATX 标题，返回级别和去掉 # 及结尾 # 序列后的文本
+func parseHeading(line string) (int, string, bool) {
+	s := strings.TrimLeft(line, " ")
+	level := 0
+	for level < len(s) && s[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(s) && s[level] != ' ' && s[level] != '\t') {
+		return 0, "", false
+	}
+	text := strings.TrimSpace(s[level:])
+	if trimmed := strings.TrimRight(text, "#"); trimmed == "" || strings.HasSuffix(trimmed, " ") {
+		text = strings.TrimSpace(trimmed)
+	}
+	return level, text, true
+}
+
+// dedent 去掉说明行的缩进，最多去掉写出时添加的两个空格或一个制表符
+func dedent(line string) string {
+	if strings.HasPrefix(line, "\t") {
+		return line[1:]
+	}
+	for i := 0; i < len(noteIndent) && strings.HasPrefix(line, " "); i++ {
+		line = line[1:]
+	}
+	return line
+}
+
+// singleLine 将文本中的换行替换为空格
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
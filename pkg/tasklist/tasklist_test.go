@@ -0,0 +1,103 @@
+package tasklist
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readAll 读取文档中的所有任务
+func readAll(t *testing.T, doc string) []Item {
+	t.Helper()
+	r := NewReader(strings.NewReader(doc))
+	var items []Item
+	for {
+		item, err := r.Read()
+		if err == io.EOF {
+			return items
+		}
+		if err != nil {
+			t.Fatalf("Read() 错误 = %v", err)
+		}
+		items = append(items, *item)
+	}
+}
+
+// TestReader 测试读取标题、嵌套任务、说明和非任务内容，一级标题不作为任务的分组
+func TestReader(t *testing.T) {
+	doc := "# 我的待办\r\n" +
+		"\n" +
+		"随便写的一段话\n" +
+		"- [ ] 买牛奶\n" +
+		"- 不是任务的列表项\n" +
+		"## Work ##\n" +
+		"* [X] 写周报\n" +
+		"  第一行说明\n" +
+		"\n" +
+		"  第二段说明\n" +
+		"\n" +
+		"  - [ ] 嵌套的任务\n" +
+		"1. [ ] 有序列表中的任务\n" +
+		"- [] 格式不对\n" +
+		"- [x]紧挨着的文本\n"
+
+	want := []Item{
+		{Text: "买牛奶", Line: 4},
+		{Section: "Work", Done: true, Text: "写周报", Note: "第一行说明\n\n第二段说明", Line: 7},
+		{Section: "Work", Text: "嵌套的任务", Line: 12},
+		{Section: "Work", Text: "有序列表中的任务", Line: 13},
+	}
+	if got := readAll(t, doc); !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %+v, 期望 %+v", got, want)
+	}
+}
+
+// TestWriter 测试写出的任务列表可以原样读回
+func TestWriter(t *testing.T) {
+	items := []Item{
+		{Text: "买牛奶"},
+		{Section: "Work", Done: true, Text: "写周报 #例行", Note: "第一行\n\n  缩进的第二行\n"},
+		{Section: "Work", Text: "开会"},
+		{Section: "家务", Text: "洗碗\n擦桌子"},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for i := range items {
+		if err := w.Write(&items[i]); err != nil {
+			t.Fatalf("Write() 错误 = %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() 错误 = %v", err)
+	}
+
+	wantDoc := "- [ ] 买牛奶\n" +
+		"\n## Work\n\n" +
+		"- [x] 写周报 #例行\n" +
+		"  第一行\n" +
+		"\n" +
+		"    缩进的第二行\n" +
+		"- [ ] 开会\n" +
+		"\n## 家务\n\n" +
+		"- [ ] 洗碗 擦桌子\n"
+	if buf.String() != wantDoc {
+		t.Fatalf("输出 = %q, 期望 %q", buf.String(), wantDoc)
+	}
+
+	got := readAll(t, buf.String())
+	if len(got) != len(items) {
+		t.Fatalf("读回 %d 个任务, 期望 %d", len(got), len(items))
+	}
+	for i, item := range got {
+		want := items[i]
+		want.Text = singleLine(want.Text)
+		want.Note = strings.TrimRight(want.Note, "\n")
+		item.Line = 0
+		if !reflect.DeepEqual(item, want) {
+			t.Errorf("第 %d 个任务 = %+v, 期望 %+v", i, item, want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Package todotxt 解析和生成 todo.txt 格式（https://github.com/todotxt/todo.txt）的任务行
+//
+// 一行表示一个任务：已完成的任务以 "x " 开头，随后依次是可选的优先级 "(A)"、完成日期和创建日期，
+// 其余部分是任务描述，其中的 +project、@context 和 key:value 被单独解析。
+// 已完成任务的优先级按惯例写作 pri:A。
+//
+// 格式本身没有转义机制。本包约定以反斜杠开头的单词是普通描述：生成任务行时，描述中会被解析为
+// +project、@context、key:value 的单词，开头会被解析为完成标记、优先级或日期的单词，
+// 以及本身以反斜杠开头的单词，都在前面加上一个反斜杠；解析时去掉单词开头的一个反斜杠。
+package todotxt
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// dateLayout 日期的格式
+const dateLayout = "2006-01-02"
+
+// priorityKey 已完成任务保存优先级使用的键
+const priorityKey = "pri"
+
+// Task 一个 todo.txt 任务
+type Task struct {
+	Done      bool              // 是否已完成
+	Priority  byte              // 优先级 'A' 到 'Z'，0 表示没有优先级
+	Completed *time.Time        // 完成日期，只对已完成的任务输出
+	Created   *time.Time        // 创建日期
+	Text      string            // 去掉项目、上下文和键值对之后的描述
+	Projects  []string          // +project，不含前缀
+	Contexts  []string          // @context，不含前缀
+	Values    map[string]string // key:value 形式的附加属性
+}
+
+// Parse 解析一行任务，行首和行尾的空白被忽略
+// 不符合格式的日期和优先级作为描述的一部分；键只能由字母、数字、下划线和连字符组成且以字母开头，
+// 值不能以 / 开头，因此描述中的时间（10:30）和网址不会被当作键值对
+func Parse(line string) Task {
+	var t Task
+	words := strings.Fields(line)
+	if len(words) > 0 && words[0] == "x" {
+		t.Done = true
+		words = words[1:]
+	}
+	if len(words) > 0 && isPriority(words[0]) {
+		t.Priority = words[0][1]
+		words = words[1:]
+	}
+	if d, ok := parseDate(words); ok {
+		words = words[1:]
+		if t.Done {
+			// 已完成的任务中第一个日期是完成日期，第二个才是创建日期
+			t.Completed = &d
+			if created, ok := parseDate(words); ok {
+				t.Created = &created
+				words = words[1:]
+			}
+		} else {
+			t.Created = &d
+		}
+	}
+
+	text := make([]string, 0, len(words))
+	for _, w := range words {
+		switch {
+		case len(w) > 1 && w[0] == '\\':
+			text = append(text, w[1:])
+		case len(w) > 1 && w[0] == '+':
+			t.Projects = append(t.Projects, w[1:])
+		case len(w) > 1 && w[0] == '@':
+			t.Contexts = append(t.Contexts, w[1:])
+		default:
+			key, value, ok := splitValue(w)
+			if !ok {
+				text = append(text, w)
+				continue
+			}
+			if t.Values == nil {
+				t.Values = make(map[string]string)
+			}
+			t.Values[key] = value
+		}
+	}
+	t.Text = strings.Join(text, " ")
+
+	if p := t.Values[priorityKey]; t.Priority == 0 && len(p) == 1 && p[0] >= 'A' && p[0] <= 'Z' {
+		t.Priority = p[0]
+		delete(t.Values, priorityKey)
+		if len(t.Values) == 0 {
+			t.Values = nil
+		}
+	}
+	return t
+}
+
+// String 生成任务行
+// 项目、上下文和键值对（按键排序）依次追加在描述之后，描述中的特殊单词加上反斜杠转义；已完成任务的优先级以 pri 键输出，
+// 创建日期只在未完成或有完成日期时输出，与格式规范一致
+func (t Task) String() string {
+	var parts []string
+	values := t.Values
+	if t.Done {
+		parts = append(parts, "x")
+		if t.Priority != 0 {
+			values = make(map[string]string, len(t.Values)+1)
+			for k, v := range t.Values {
+				values[k] = v
+			}
+			values[priorityKey] = string(t.Priority)
+		}
+		if t.Completed != nil {
+			parts = append(parts, t.Completed.Format(dateLayout))
+			if t.Created != nil {
+				parts = append(parts, t.Created.Format(dateLayout))
+			}
+		}
+	} else {
+		if t.Priority != 0 {
+			parts = append(parts, "("+string(t.Priority)+")")
+		}
+		if t.Created != nil {
+			parts = append(parts, t.Created.Format(dateLayout))
+		}
+	}
+
+	for i, w := range strings.Fields(t.Text) {
+		parts = append(parts, escapeWord(w, i == 0))
+	}
+	for _, p := range t.Projects {
+		parts = append(parts, "+"+p)
+	}
+	for _, c := range t.Contexts {
+		parts = append(parts, "@"+c)
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, k+":"+values[k])
+	}
+	return strings.Join(parts, " ")
+}
+
+// escapeWord 在描述中会被解析为其他字段的单词前加上反斜杠，first 表示是否为描述的第一个单词
+func escapeWord(w string, first bool) string {
+	_, _, isValue := splitValue(w)
+	_, isDate := parseDate([]string{w})
+	special := len(w) > 1 && (w[0] == '\\' || w[0] == '+' || w[0] == '@')
+	if special || isValue || (first && (w == "x" || isPriority(w) || isDate)) {
+		return `\` + w
+	}
+	return w
+}
+
+// isPriority 判断是否为 (A) 形式的优先级
+func isPriority(w string) bool {
+	return len(w) == 3 && w[0] == '(' && w[2] == ')' && w[1] >= 'A' && w[1] <= 'Z'
+}
+
+// parseDate 解析第一个单词是否为日期
+func parseDate(words []string) (time.Time, bool) {
+	if len(words) == 0 || len(words[0]) != len(dateLayout) {
+		return time.Time{}, false
+	}
+	d, err := time.ParseInLocation(dateLayout, words[0], time.Local)
+	return d, err == nil
+}
+
+// splitValue 将 key:value 拆分为键和值
+func splitValue(w string) (string, string, bool) {
+	key, value, ok := strings.Cut(w, ":")
+	if !ok || key == "" || value == "" || strings.HasPrefix(value, "/") {
+		return "", "", false
+	}
+	for i, r := range key {
+		letter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if i == 0 && !letter {
+			return "", "", false
+		}
+		if !letter && !(r >= '0' && r <= '9') && r != '_' && r != '-' {
+			return "", "", false
+		}
+	}
+	return key, value, true
+}
@@ -0,0 +1,101 @@
+package todotxt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) *time.Time {
+	t := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	return &t
+}
+
+// TestParse 测试解析各种形式的任务行
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want Task
+	}{
+		{
+			name: "完整的未完成任务",
+			line: "(A) 2024-06-01 写周报 +Work @例行 due:2024-06-07",
+			want: Task{Priority: 'A', Created: date(2024, 6, 1), Text: "写周报", Projects: []string{"Work"},
+				Contexts: []string{"例行"}, Values: map[string]string{"due": "2024-06-07"}},
+		},
+		{
+			name: "已完成任务的完成日期和创建日期",
+			line: "x 2024-06-08 2024-06-01 写周报 pri:B",
+			want: Task{Done: true, Priority: 'B', Completed: date(2024, 6, 8), Created: date(2024, 6, 1), Text: "写周报"},
+		},
+		{
+			name: "时间和网址不是键值对",
+			line: "10:30 开会 https://example.com/a +",
+			want: Task{Text: "10:30 开会 https://example.com/a +"},
+		},
+		{
+			name: "优先级必须在行首",
+			line: "买菜 (A)",
+			want: Task{Text: "买菜 (A)"},
+		},
+		{
+			name: "无效的日期作为描述",
+			line: "2024-13-01 买菜",
+			want: Task{Text: "2024-13-01 买菜"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.line); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, 期望 %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTask_String 测试生成任务行以及生成的任务行可以解析回相同的任务
+func TestTask_String(t *testing.T) {
+	tests := []struct {
+		task Task
+		want string
+	}{
+		{
+			task: Task{Priority: 'A', Created: date(2024, 6, 1), Text: "写周报", Projects: []string{"Work"},
+				Contexts: []string{"例行", "写作"}, Values: map[string]string{"due": "2024-06-07", "est": "30"}},
+			want: "(A) 2024-06-01 写周报 +Work @例行 @写作 due:2024-06-07 est:30",
+		},
+		{
+			task: Task{Done: true, Priority: 'C', Completed: date(2024, 6, 8), Created: date(2024, 6, 1), Text: "买菜"},
+			want: "x 2024-06-08 2024-06-01 买菜 pri:C",
+		},
+		{
+			// 没有完成日期时不能输出创建日期，否则创建日期会被当作完成日期
+			task: Task{Done: true, Created: date(2024, 6, 1), Text: "买菜"},
+			want: "x 买菜",
+		},
+		{
+			// 描述中会被解析为其他字段的单词加上反斜杠转义
+			task: Task{Text: `x 提醒 +1 @张三 note:明天 \\server 2024-06-01`},
+			want: `\x 提醒 \+1 \@张三 \note:明天 \\\server 2024-06-01`,
+		},
+		{
+			task: Task{Priority: 'B', Text: "(A) 2024-06-01 开头"},
+			want: `(B) \(A) 2024-06-01 开头`,
+		},
+	}
+	for _, tt := range tests {
+		got := tt.task.String()
+		if got != tt.want {
+			t.Errorf("String() = %q, 期望 %q", got, tt.want)
+		}
+		parsed := Parse(got)
+		if parsed.Text != strings.Join(strings.Fields(tt.task.Text), " ") {
+			t.Errorf("Parse(%q).Text = %q, 期望 %q", got, parsed.Text, tt.task.Text)
+		}
+		if parsed.String() != got {
+			t.Errorf("Parse(%q).String() = %q，往返后不一致", got, parsed.String())
+		}
+	}
+}
@@ -8,13 +8,15 @@ import (
 )
 
 type Claims struct {
-	UserID uint `json:"user_id"`
+	UserID      uint `json:"user_id"`
+	WorkspaceID uint `json:"workspace_id"` // 当前所在的工作空间（租户）
 	jwt.StandardClaims
 }
 
-func GenerateToken(userID uint, cfg *config.JWTConfig) (string, error) {
+func GenerateToken(userID, workspaceID uint, cfg *config.JWTConfig) (string, error) {
 	claims := Claims{
-		UserID: userID,
+		UserID:      userID,
+		WorkspaceID: workspaceID,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(time.Hour * time.Duration(cfg.ExpireHours)).Unix(),
 			IssuedAt:  time.Now().Unix(),
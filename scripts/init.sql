@@ -14,17 +14,61 @@ CREATE TABLE IF NOT EXISTS users (
     username VARCHAR(32) NOT NULL UNIQUE,
     password VARCHAR(128) NOT NULL,
     email VARCHAR(128) NOT NULL,
+    default_workspace_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP NULL
 );
 
+-- 创建工作空间表
+CREATE TABLE IF NOT EXISTS workspaces (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    owner_id BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT fk_workspaces_owner FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+
+-- 创建工作空间成员表
+CREATE TABLE IF NOT EXISTS workspace_members (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    role VARCHAR(16) NOT NULL DEFAULT 'member' COMMENT 'owner/admin/member',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE KEY idx_workspace_members_ws_user (workspace_id, user_id),
+    CONSTRAINT fk_workspace_members_workspace FOREIGN KEY (workspace_id) REFERENCES workspaces(id),
+    CONSTRAINT fk_workspace_members_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- 创建工作空间邀请表
+CREATE TABLE IF NOT EXISTS workspace_invites (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    email VARCHAR(128),
+    role VARCHAR(16) NOT NULL DEFAULT 'member',
+    token VARCHAR(64) NOT NULL UNIQUE,
+    invited_by BIGINT UNSIGNED NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT fk_workspace_invites_workspace FOREIGN KEY (workspace_id) REFERENCES workspaces(id)
+);
+
 -- 创建分类表
 CREATE TABLE IF NOT EXISTS categories (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     name VARCHAR(32) NOT NULL,
     color VARCHAR(7),
+    workspace_id BIGINT UNSIGNED NOT NULL,
     user_id BIGINT UNSIGNED NOT NULL,
+    parent_id BIGINT UNSIGNED NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP NULL,
@@ -38,6 +82,16 @@ CREATE TABLE IF NOT EXISTS todos (
     description TEXT,
     completed BOOLEAN DEFAULT FALSE,
     priority VARCHAR(10) DEFAULT 'medium',
+    completed_at TIMESTAMP NULL,
+    archived BOOLEAN DEFAULT FALSE,
+    archived_at TIMESTAMP NULL,
+    due_date TIMESTAMP NULL,
+    position VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',
+    status_id BIGINT UNSIGNED NULL,
+    estimate INT NULL COMMENT '预估耗时（分钟）',
+    calendar_uid VARCHAR(255) NOT NULL DEFAULT '' COMMENT '通过 CalDAV 创建时客户端指定的 UID',
+    calendar_name VARCHAR(255) NOT NULL DEFAULT '' COMMENT '通过 CalDAV 创建时的资源名',
+    workspace_id BIGINT UNSIGNED NOT NULL,
     user_id BIGINT UNSIGNED NOT NULL,
     category_id BIGINT UNSIGNED,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -50,6 +104,7 @@ CREATE TABLE IF NOT EXISTS todos (
 -- 创建提醒表
 CREATE TABLE IF NOT EXISTS reminders (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
     todo_id BIGINT UNSIGNED NOT NULL,
     remind_at TIMESTAMP NOT NULL,
     remind_type VARCHAR(10) NOT NULL COMMENT 'once/daily/weekly',
@@ -63,12 +118,259 @@ CREATE TABLE IF NOT EXISTS reminders (
     CONSTRAINT chk_notify_type CHECK (notify_type IN ('email', 'push'))
 );
 
+-- 创建评论表
+CREATE TABLE IF NOT EXISTS comments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    todo_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    body TEXT NOT NULL,
+    edited_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_comments_workspace_id (workspace_id),
+    INDEX idx_comments_todo_id (todo_id),
+    CONSTRAINT fk_comments_todo FOREIGN KEY (todo_id) REFERENCES todos(id),
+    CONSTRAINT fk_comments_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- 创建评论编辑历史表
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    comment_id BIGINT UNSIGNED NOT NULL,
+    body TEXT NOT NULL,
+    edited_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_comment_revisions_comment_id (comment_id),
+    CONSTRAINT fk_comment_revisions_comment FOREIGN KEY (comment_id) REFERENCES comments(id)
+);
+
+-- 创建附件表
+CREATE TABLE IF NOT EXISTS attachments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    todo_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(128) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_attachments_workspace_id (workspace_id),
+    INDEX idx_attachments_todo_id (todo_id),
+    CONSTRAINT fk_attachments_todo FOREIGN KEY (todo_id) REFERENCES todos(id)
+);
+
+-- 创建变更历史表（只追加）
+CREATE TABLE IF NOT EXISTS change_logs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    entity_type VARCHAR(16) NOT NULL,
+    entity_id BIGINT UNSIGNED NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    actor_id BIGINT UNSIGNED NOT NULL,
+    changes JSON,
+    snapshot JSON,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_change_logs_workspace_id (workspace_id),
+    INDEX idx_change_logs_entity (entity_type, entity_id)
+);
+
+-- 创建标签表
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_tags_owner_name (workspace_id, user_id, name)
+);
+
+-- 创建待办事项与标签的关联表
+CREATE TABLE IF NOT EXISTS todo_tags (
+    todo_id BIGINT UNSIGNED NOT NULL,
+    tag_id BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (todo_id, tag_id),
+    CONSTRAINT fk_todo_tags_todo FOREIGN KEY (todo_id) REFERENCES todos(id),
+    CONSTRAINT fk_todo_tags_tag FOREIGN KEY (tag_id) REFERENCES tags(id)
+);
+
+-- 创建保存的过滤条件表
+CREATE TABLE IF NOT EXISTS saved_filters (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    definition JSON,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_saved_filters_workspace_id (workspace_id),
+    INDEX idx_saved_filters_user_id (user_id)
+);
+
+-- 创建看板工作流状态表
+CREATE TABLE IF NOT EXISTS statuses (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(32) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    terminal BOOLEAN NOT NULL DEFAULT FALSE,
+    wip_limit INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_statuses_owner_name (workspace_id, user_id, name)
+);
+
+-- 创建待办事项依赖关系表，todo_id 被 blocker_id 阻塞
+CREATE TABLE IF NOT EXISTS dependencies (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    todo_id BIGINT UNSIGNED NOT NULL,
+    blocker_id BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_dependencies_pair (todo_id, blocker_id),
+    INDEX idx_dependencies_workspace_id (workspace_id),
+    INDEX idx_dependencies_blocker_id (blocker_id),
+    CONSTRAINT fk_dependencies_todo FOREIGN KEY (todo_id) REFERENCES todos(id),
+    CONSTRAINT fk_dependencies_blocker FOREIGN KEY (blocker_id) REFERENCES todos(id)
+);
+
+-- 创建时间记录表，ended_at 为空表示计时器正在运行
+CREATE TABLE IF NOT EXISTS time_entries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    todo_id BIGINT UNSIGNED NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP NULL,
+    seconds BIGINT NOT NULL DEFAULT 0,
+    note VARCHAR(256),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_time_entries_workspace_id (workspace_id),
+    INDEX idx_time_entries_user_started (user_id, started_at),
+    INDEX idx_time_entries_todo_id (todo_id),
+    CONSTRAINT fk_time_entries_user FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_time_entries_todo FOREIGN KEY (todo_id) REFERENCES todos(id)
+);
+
+-- 创建待办事项模板表，items 以 JSON 保存待办事项及其子任务
+CREATE TABLE IF NOT EXISTS templates (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    description VARCHAR(256),
+    items JSON,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_templates_workspace_id (workspace_id),
+    INDEX idx_templates_user_id (user_id),
+    CONSTRAINT fk_templates_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- 创建日历订阅表，每个用户在每个工作空间中最多一个订阅，撤销时物理删除
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    token VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE INDEX idx_calendar_feeds_owner (workspace_id, user_id),
+    UNIQUE INDEX idx_calendar_feeds_token (token),
+    CONSTRAINT fk_calendar_feeds_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- 创建数据导出任务表，每个用户在每个工作空间中只保留最近一次导出
+CREATE TABLE IF NOT EXISTS data_exports (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    storage_key VARCHAR(255),
+    size BIGINT NOT NULL DEFAULT 0,
+    error VARCHAR(255),
+    finished_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_data_exports_owner (workspace_id, user_id),
+    CONSTRAINT fk_data_exports_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- 创建 webhook 端点表
+CREATE TABLE IF NOT EXISTS webhooks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    url VARCHAR(512) NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    events JSON,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    failures INT NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_webhooks_owner (workspace_id, user_id),
+    CONSTRAINT fk_webhooks_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- 创建 webhook 投递日志表
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    webhook_id BIGINT UNSIGNED NOT NULL,
+    delivery_id VARCHAR(36) NOT NULL,
+    event VARCHAR(32) NOT NULL,
+    attempt INT NOT NULL,
+    payload TEXT,
+    status_code INT NOT NULL DEFAULT 0,
+    success BOOLEAN NOT NULL DEFAULT FALSE,
+    error VARCHAR(255),
+    duration BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_webhook_deliveries_webhook_id (webhook_id),
+    CONSTRAINT fk_webhook_deliveries_webhook FOREIGN KEY (webhook_id) REFERENCES webhooks(id)
+);
+
 -- 添加索引
+CREATE INDEX idx_categories_workspace_id ON categories(workspace_id);
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+CREATE INDEX idx_todos_workspace_id ON todos(workspace_id);
+CREATE INDEX idx_todos_archived ON todos(archived);
+CREATE INDEX idx_todos_due_date ON todos(due_date);
+CREATE INDEX idx_todos_category_position ON todos(category_id, position);
+CREATE INDEX idx_todos_status_id ON todos(status_id);
+CREATE INDEX idx_todos_calendar_name ON todos(calendar_name);
+CREATE INDEX idx_reminders_workspace_id ON reminders(workspace_id);
 CREATE INDEX idx_reminders_todo_id ON reminders(todo_id);
 CREATE INDEX idx_reminders_remind_at ON reminders(remind_at);
 CREATE INDEX idx_reminders_todo_remind ON reminders(todo_id, deleted_at);
 CREATE INDEX idx_reminders_remind_status ON reminders(remind_at, status, deleted_at);
 
+-- 全文索引，使用 ngram 解析器以支持中文分词
+CREATE FULLTEXT INDEX idx_todos_fulltext ON todos(title, description) WITH PARSER ngram;
+CREATE FULLTEXT INDEX idx_comments_fulltext ON comments(body) WITH PARSER ngram;
+
 -- 恢复 SQL 模式
 SET SQL_MODE=@OLD_SQL_MODE;
 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;